@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
 	"github.com/hpinc/go3mf/spec"
 )
 
@@ -29,6 +30,13 @@ var (
 
 func init() {
 	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrLatticeObjType:       {ID: "BEAM001", Name: "LatticeObjType", Severity: reporting.SeverityError},
+		ErrLatticeClippedNoMesh: {ID: "BEAM002", Name: "LatticeClippedNoMesh", Severity: reporting.SeverityError},
+		ErrLatticeInvalidMesh:   {ID: "BEAM003", Name: "LatticeInvalidMesh", Severity: reporting.SeverityError},
+		ErrLatticeSameVertex:    {ID: "BEAM004", Name: "LatticeSameVertex", Severity: reporting.SeverityError},
+		ErrLatticeBeamR2:        {ID: "BEAM005", Name: "LatticeBeamR2", Severity: reporting.SeverityError},
+	})
 }
 
 type Spec struct{}
@@ -115,6 +123,57 @@ func GetBeamLattice(mesh *go3mf.Mesh) *BeamLattice {
 	return nil
 }
 
+// Scale multiplies the default radius, the minimum length and
+// the beams radius by factor.
+func (b *BeamLattice) Scale(factor float32) {
+	b.MinLength *= factor
+	b.Radius *= factor
+	for i := range b.Beams.Beam {
+		beam := &b.Beams.Beam[i]
+		beam.Radius[0] *= factor
+		beam.Radius[1] *= factor
+	}
+}
+
+// RemapVertices updates the beam nodes after the mesh vertices change,
+// being remap[i] the new index of vertex i.
+// Beams whose nodes become the same vertex are removed, and so are
+// their references in the beam sets.
+func (b *BeamLattice) RemapVertices(remap []uint32) {
+	beams := make([]uint32, len(b.Beams.Beam))
+	n := 0
+	for i, beam := range b.Beams.Beam {
+		for j, v := range beam.Indices {
+			if int(v) < len(remap) {
+				beam.Indices[j] = remap[v]
+			}
+		}
+		if beam.Indices[0] == beam.Indices[1] {
+			beams[i] = spec.RemovedIndex
+			continue
+		}
+		beams[i] = uint32(n)
+		b.Beams.Beam[n] = beam
+		n++
+	}
+	if n == len(b.Beams.Beam) {
+		return
+	}
+	b.Beams.Beam = b.Beams.Beam[:n]
+	for i := range b.BeamSets.BeamSet {
+		set := &b.BeamSets.BeamSet[i]
+		refs := set.Refs[:0]
+		for _, r := range set.Refs {
+			if int(r) >= len(beams) {
+				refs = append(refs, r)
+			} else if beams[r] != spec.RemovedIndex {
+				refs = append(refs, beams[r])
+			}
+		}
+		set.Refs = refs
+	}
+}
+
 // BeamSet defines a set of beams.
 type BeamSet struct {
 	Refs       []uint32
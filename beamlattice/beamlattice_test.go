@@ -11,6 +11,7 @@ import (
 )
 
 var _ spec.Marshaler = new(BeamLattice)
+var _ spec.Scaler = new(BeamLattice)
 var _ spec.ChildElementDecoder = new(beamLatticeDecoder)
 var _ spec.ChildElementDecoder = new(beamsDecoder)
 var _ spec.ChildElementDecoder = new(beamSetsDecoder)
@@ -99,3 +100,42 @@ func TestClipMode_String(t *testing.T) {
 		})
 	}
 }
+
+func TestBeamLattice_Scale(t *testing.T) {
+	b := &BeamLattice{MinLength: 0.5, Radius: 1, Beams: Beams{Beam: []Beam{
+		{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 2}},
+		{Indices: [2]uint32{1, 2}},
+	}}}
+	want := &BeamLattice{MinLength: 1, Radius: 2, Beams: Beams{Beam: []Beam{
+		{Indices: [2]uint32{0, 1}, Radius: [2]float32{2, 4}},
+		{Indices: [2]uint32{1, 2}},
+	}}}
+	b.Scale(2)
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("BeamLattice.Scale() = %v, want %v", b, want)
+	}
+}
+
+func TestBeamLattice_RemapVertices(t *testing.T) {
+	b := &BeamLattice{
+		Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 2}},
+			{Indices: [2]uint32{1, 2}},
+			{Indices: [2]uint32{2, 3}},
+			{Indices: [2]uint32{0, 9}},
+		}},
+		BeamSets: BeamSets{BeamSet: []BeamSet{{Name: "a", Refs: []uint32{0, 1, 2, 3, 7}}}},
+	}
+	b.RemapVertices([]uint32{0, 1, 1, 2})
+	want := &BeamLattice{
+		Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 2}},
+			{Indices: [2]uint32{1, 2}},
+			{Indices: [2]uint32{0, 9}},
+		}},
+		BeamSets: BeamSets{BeamSet: []BeamSet{{Name: "a", Refs: []uint32{0, 1, 2, 7}}}},
+	}
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("BeamLattice.RemapVertices() = %v, want %v", b, want)
+	}
+}
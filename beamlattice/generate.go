@@ -0,0 +1,328 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package beamlattice
+
+import (
+	"errors"
+	"math"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/bvh"
+)
+
+// Generator errors.
+var (
+	ErrGeneratorCellSize = errors.New("beamlattice: cell size must be greater than zero")
+	ErrGeneratorRadius   = errors.New("beamlattice: beam radius must be greater than zero")
+	ErrGeneratorEmpty    = errors.New("beamlattice: the lattice does not contain any beam")
+)
+
+// DefaultMinLength is the minimum beam length assigned to generated lattices
+// when the generator does not define it.
+const DefaultMinLength = 0.0001
+
+// maxGeneratorCells limits the number of unit cells of a generated lattice.
+const maxGeneratorCells = 1 << 24
+
+// UnitCell defines the topology of the repeating cell of a lattice.
+type UnitCell uint8
+
+// Supported unit cells.
+const (
+	// CellCubic connects the cell corners along the cube edges.
+	CellCubic UnitCell = iota
+	// CellBCC connects the cell center with the corners.
+	CellBCC
+	// CellFCC connects the face centers with the face corners.
+	CellFCC
+	// CellOctet adds to CellFCC the octahedron between adjacent face centers.
+	CellOctet
+	// CellDiamond connects four tetrahedral sites with their nearest corners and face centers.
+	CellDiamond
+)
+
+func (c UnitCell) String() string {
+	return map[UnitCell]string{
+		CellCubic:   "cubic",
+		CellBCC:     "bcc",
+		CellFCC:     "fcc",
+		CellOctet:   "octet",
+		CellDiamond: "diamond",
+	}[c]
+}
+
+// cellNode is a node position in quarters of the cell size.
+type cellNode [3]int
+
+// cellBeams returns the beams of a unit cell.
+// Beams on the cell faces are shared with the neighbor cells
+// and are deduplicated by the generator.
+func cellBeams(c UnitCell) [][2]cellNode {
+	var beams [][2]cellNode
+	corners := func(f func(x, y, z int)) {
+		for x := 0; x <= 4; x += 4 {
+			for y := 0; y <= 4; y += 4 {
+				for z := 0; z <= 4; z += 4 {
+					f(x, y, z)
+				}
+			}
+		}
+	}
+	// faceCenters lists the center of each face together with its corners.
+	faceCenters := func(f func(center cellNode, corners [4]cellNode)) {
+		for axis := 0; axis < 3; axis++ {
+			u, v := (axis+1)%3, (axis+2)%3
+			for side := 0; side <= 4; side += 4 {
+				var center cellNode
+				center[axis], center[u], center[v] = side, 2, 2
+				var cs [4]cellNode
+				for i, uv := range [4][2]int{{0, 0}, {4, 0}, {4, 4}, {0, 4}} {
+					cs[i][axis], cs[i][u], cs[i][v] = side, uv[0], uv[1]
+				}
+				f(center, cs)
+			}
+		}
+	}
+	switch c {
+	case CellBCC:
+		corners(func(x, y, z int) {
+			beams = append(beams, [2]cellNode{{2, 2, 2}, {x, y, z}})
+		})
+	case CellFCC, CellOctet:
+		var centers []cellNode
+		faceCenters(func(center cellNode, cs [4]cellNode) {
+			centers = append(centers, center)
+			for _, corner := range cs {
+				beams = append(beams, [2]cellNode{center, corner})
+			}
+		})
+		if c == CellOctet {
+			for i, c1 := range centers {
+				for _, c2 := range centers[i+1:] {
+					// Adjacent face centers are at a distance of 2*sqrt(2) quarters.
+					if sqDist(c1, c2) == 8 {
+						beams = append(beams, [2]cellNode{c1, c2})
+					}
+				}
+			}
+		}
+	case CellDiamond:
+		for _, site := range []cellNode{{1, 1, 1}, {3, 3, 1}, {3, 1, 3}, {1, 3, 3}} {
+			// All the sites have the same nearest neighbor directions.
+			for _, d := range [4]cellNode{{-1, -1, -1}, {1, 1, -1}, {1, -1, 1}, {-1, 1, 1}} {
+				beams = append(beams, [2]cellNode{site, {site[0] + d[0], site[1] + d[1], site[2] + d[2]}})
+			}
+		}
+	default:
+		corners(func(x, y, z int) {
+			for axis := 0; axis < 3; axis++ {
+				n := cellNode{x, y, z}
+				if n[axis] == 0 {
+					m := n
+					m[axis] = 4
+					beams = append(beams, [2]cellNode{n, m})
+				}
+			}
+		})
+	}
+	return beams
+}
+
+func sqDist(a, b cellNode) int {
+	dx, dy, dz := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dx*dx + dy*dy + dz*dz
+}
+
+// A Region groups the generated beams whose middle point is inside Box
+// in a BeamSet with the same name and identifier.
+type Region struct {
+	Name       string
+	Identifier string
+	Box        go3mf.Box
+}
+
+// A Generator creates beam lattices repeating a unit cell,
+// as used to lightweight parts.
+type Generator struct {
+	// Cell is the unit cell topology.
+	Cell UnitCell
+	// CellSize is the edge length of the cubic cell.
+	CellSize float32
+	// Radius is the radius of the beams.
+	Radius float32
+	// Grading, if not nil, returns the beam radius at each node,
+	// overriding Radius. The lattice Radius is still used as the default radius.
+	Grading func(go3mf.Point3D) float32
+	// Interior only keeps the beams whose nodes are inside the mesh
+	// or on its surface. Otherwise the lattice fills the mesh bounding box.
+	Interior bool
+	// CapMode of the beams.
+	CapMode CapMode
+	// MinLength of the lattice, DefaultMinLength if zero.
+	MinLength float32
+	// Regions define the beam sets.
+	Regions []Region
+}
+
+// Generate returns a beam lattice filling mesh with the unit cell,
+// starting at the minimum corner of the mesh bounding box.
+// The lattice nodes are appended to the mesh vertices,
+// but the lattice is not attached to the mesh.
+func (g *Generator) Generate(mesh *go3mf.Mesh) (*BeamLattice, error) {
+	if !(g.CellSize > 0) {
+		return nil, ErrGeneratorCellSize
+	}
+	if !(g.Radius > 0) {
+		return nil, ErrGeneratorRadius
+	}
+	if len(mesh.Vertices.Vertex) == 0 {
+		return nil, ErrGeneratorEmpty
+	}
+	box := mesh.BoundingBox()
+	var cells [3]int
+	total := 1.0
+	for i := range cells {
+		n := math.Max(1, math.Ceil(float64(box.Max[i]-box.Min[i])/float64(g.CellSize)))
+		if total *= n; total > maxGeneratorCells {
+			return nil, ErrGeneratorCellSize
+		}
+		cells[i] = int(n)
+	}
+	b := &BeamLattice{Radius: g.Radius, MinLength: g.MinLength, CapMode: g.CapMode}
+	if b.MinLength == 0 {
+		b.MinLength = DefaultMinLength
+	}
+	gen := &generation{
+		g: g, mesh: mesh, lattice: b, origin: box.Min,
+		nodes:  make(map[cellNode]uint32),
+		inside: make(map[cellNode]bool),
+		beams:  make(map[[2]cellNode]struct{}),
+	}
+	if g.Interior {
+		// The tree indexes a copy of the mesh, as the nodes are appended to the original one.
+		gen.tree = bvh.New(&go3mf.Mesh{Vertices: mesh.Vertices, Triangles: mesh.Triangles})
+	}
+	unit := cellBeams(g.Cell)
+	for x := 0; x < cells[0]; x++ {
+		for y := 0; y < cells[1]; y++ {
+			for z := 0; z < cells[2]; z++ {
+				offset := cellNode{x * 4, y * 4, z * 4}
+				for _, beam := range unit {
+					gen.addBeam(beam[0].add(offset), beam[1].add(offset))
+				}
+			}
+		}
+	}
+	if len(b.Beams.Beam) == 0 {
+		return nil, ErrGeneratorEmpty
+	}
+	gen.addSets()
+	return b, nil
+}
+
+// Fill generates the lattice and attaches it to the mesh,
+// replacing any previous beam lattice.
+func (g *Generator) Fill(mesh *go3mf.Mesh) (*BeamLattice, error) {
+	b, err := g.Generate(mesh)
+	if err != nil {
+		return nil, err
+	}
+	for i, a := range mesh.Any {
+		if _, ok := a.(*BeamLattice); ok {
+			mesh.Any[i] = b
+			return b, nil
+		}
+	}
+	mesh.Any = append(mesh.Any, b)
+	return b, nil
+}
+
+func (n cellNode) add(o cellNode) cellNode {
+	return cellNode{n[0] + o[0], n[1] + o[1], n[2] + o[2]}
+}
+
+// generation holds the state of a lattice being generated.
+type generation struct {
+	g       *Generator
+	mesh    *go3mf.Mesh
+	lattice *BeamLattice
+	origin  go3mf.Point3D
+	tree    *bvh.Tree
+	nodes   map[cellNode]uint32
+	inside  map[cellNode]bool
+	beams   map[[2]cellNode]struct{}
+}
+
+func (gen *generation) position(n cellNode) go3mf.Point3D {
+	q := gen.g.CellSize / 4
+	return gen.origin.Add(go3mf.Point3D{float32(n[0]) * q, float32(n[1]) * q, float32(n[2]) * q})
+}
+
+func (gen *generation) isInside(n cellNode) bool {
+	if !gen.g.Interior {
+		return true
+	}
+	in, ok := gen.inside[n]
+	if !ok {
+		in = isInside(gen.tree, gen.position(n))
+		gen.inside[n] = in
+	}
+	return in
+}
+
+func (gen *generation) node(n cellNode) uint32 {
+	if i, ok := gen.nodes[n]; ok {
+		return i
+	}
+	gen.mesh.Vertices.Vertex = append(gen.mesh.Vertices.Vertex, gen.position(n))
+	i := uint32(len(gen.mesh.Vertices.Vertex) - 1)
+	gen.nodes[n] = i
+	return i
+}
+
+func (gen *generation) addBeam(n1, n2 cellNode) {
+	if n2[0] < n1[0] || n2[0] == n1[0] && (n2[1] < n1[1] || n2[1] == n1[1] && n2[2] < n1[2]) {
+		n1, n2 = n2, n1
+	}
+	key := [2]cellNode{n1, n2}
+	if _, ok := gen.beams[key]; ok {
+		return
+	}
+	gen.beams[key] = struct{}{}
+	if !gen.isInside(n1) || !gen.isInside(n2) {
+		return
+	}
+	beam := Beam{
+		Indices: [2]uint32{gen.node(n1), gen.node(n2)},
+		Radius:  [2]float32{gen.g.Radius, gen.g.Radius},
+		CapMode: [2]CapMode{gen.g.CapMode, gen.g.CapMode},
+	}
+	if gen.g.Grading != nil {
+		beam.Radius = [2]float32{gen.g.Grading(gen.position(n1)), gen.g.Grading(gen.position(n2))}
+	}
+	gen.lattice.Beams.Beam = append(gen.lattice.Beams.Beam, beam)
+}
+
+func (gen *generation) addSets() {
+	vs := gen.mesh.Vertices.Vertex
+	for _, r := range gen.g.Regions {
+		set := BeamSet{Name: r.Name, Identifier: r.Identifier}
+		for i, beam := range gen.lattice.Beams.Beam {
+			mid := vs[beam.Indices[0]].Add(vs[beam.Indices[1]]).Mul(0.5)
+			if boxContains(r.Box, mid) {
+				set.Refs = append(set.Refs, uint32(i))
+			}
+		}
+		gen.lattice.BeamSets.BeamSet = append(gen.lattice.BeamSets.BeamSet, set)
+	}
+}
+
+func boxContains(b go3mf.Box, p go3mf.Point3D) bool {
+	for i := range p {
+		if p[i] < b.Min[i] || p[i] > b.Max[i] {
+			return false
+		}
+	}
+	return true
+}
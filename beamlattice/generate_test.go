@@ -0,0 +1,189 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package beamlattice
+
+import (
+	"math"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+)
+
+func TestUnitCell_String(t *testing.T) {
+	tests := []struct {
+		name string
+		c    UnitCell
+	}{
+		{"cubic", CellCubic},
+		{"bcc", CellBCC},
+		{"fcc", CellFCC},
+		{"octet", CellOctet},
+		{"diamond", CellDiamond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.c.String(); got != tt.name {
+				t.Errorf("UnitCell.String() = %v, want %v", got, tt.name)
+			}
+		})
+	}
+}
+
+func Test_cellBeams(t *testing.T) {
+	tests := []struct {
+		c      UnitCell
+		beams  int
+		length int // squared length in quarters
+	}{
+		{CellCubic, 12, 16},
+		{CellBCC, 8, 12},
+		{CellFCC, 24, 8},
+		{CellOctet, 36, 8},
+		{CellDiamond, 16, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.c.String(), func(t *testing.T) {
+			got := cellBeams(tt.c)
+			if len(got) != tt.beams {
+				t.Errorf("cellBeams() = %d beams, want %d", len(got), tt.beams)
+			}
+			for _, b := range got {
+				if l := sqDist(b[0], b[1]); l != tt.length {
+					t.Errorf("cellBeams() beam %v length = %d, want %d", b, l, tt.length)
+				}
+				for _, n := range b {
+					for _, c := range n {
+						if c < 0 || c > 4 {
+							t.Errorf("cellBeams() node %v is outside the cell", n)
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	box := createClipModel().Resources.Objects[0].Mesh
+	tests := []struct {
+		name    string
+		g       *Generator
+		beams   int
+		wantErr error
+	}{
+		{"noCellSize", &Generator{Radius: 1}, 0, ErrGeneratorCellSize},
+		{"noRadius", &Generator{CellSize: 1}, 0, ErrGeneratorRadius},
+		{"tooManyCells", &Generator{CellSize: 1e-4, Radius: 1}, 0, ErrGeneratorCellSize},
+		// 2x2x2 cells share the inner edges: 3*2*3*3 beams.
+		{"cubic", &Generator{CellSize: 5, Radius: 1}, 54, nil},
+		{"bcc", &Generator{Cell: CellBCC, CellSize: 5, Radius: 1}, 64, nil},
+		// 12 beams per cell face, 36 faces.
+		{"fcc", &Generator{Cell: CellFCC, CellSize: 5, Radius: 1}, 144, nil},
+		{"diamond", &Generator{Cell: CellDiamond, CellSize: 5, Radius: 1}, 128, nil},
+		{"round", &Generator{CellSize: 6, Radius: 1}, 54, nil},
+		// The nodes on the surface are kept.
+		{"interior", &Generator{Cell: CellBCC, CellSize: 5, Radius: 1, Interior: true}, 64, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mesh := &go3mf.Mesh{Vertices: box.Vertices, Triangles: box.Triangles}
+			mesh.Vertices.Vertex = append([]go3mf.Point3D(nil), box.Vertices.Vertex...)
+			got, err := tt.g.Generate(mesh)
+			if err != tt.wantErr {
+				t.Fatalf("Generator.Generate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Beams.Beam) != tt.beams {
+				t.Errorf("Generator.Generate() beams = %d, want %d", len(got.Beams.Beam), tt.beams)
+			}
+			if got.MinLength != DefaultMinLength || got.Radius != tt.g.Radius {
+				t.Errorf("Generator.Generate() lattice = %v", got)
+			}
+			for i, b := range got.Beams.Beam {
+				if b.Indices[0] == b.Indices[1] || int(b.Indices[0]) >= len(mesh.Vertices.Vertex) || int(b.Indices[1]) >= len(mesh.Vertices.Vertex) {
+					t.Errorf("Generator.Generate() beam %d indices = %v", i, b.Indices)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerator_Generate_interior(t *testing.T) {
+	// A tetrahedron with the nodes i+j+k <= 4 inside or on the surface.
+	mesh := &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+		}},
+	}
+	got, err := (&Generator{CellSize: 2.5, Radius: 0.5, Interior: true}).Generate(mesh)
+	if err != nil {
+		t.Fatalf("Generator.Generate() error = %v", err)
+	}
+	if n := len(mesh.Vertices.Vertex) - 4; n != 35 {
+		t.Errorf("Generator.Generate() nodes = %d, want 35", n)
+	}
+	// Each axis has one beam per node with i+j+k <= 3.
+	if want := 3 * 20; len(got.Beams.Beam) != want {
+		t.Errorf("Generator.Generate() beams = %d, want %d", len(got.Beams.Beam), want)
+	}
+	outside := &go3mf.Mesh{Vertices: mesh.Vertices, Triangles: mesh.Triangles}
+	outside.Vertices.Vertex = outside.Vertices.Vertex[:4]
+	if _, err := (&Generator{Cell: CellBCC, CellSize: 20, Radius: 0.5, Interior: true}).Generate(outside); err != ErrGeneratorEmpty {
+		t.Errorf("Generator.Generate() error = %v, want %v", err, ErrGeneratorEmpty)
+	}
+}
+
+func TestGenerator_Fill(t *testing.T) {
+	mesh := createClipModel().Resources.Objects[0].Mesh
+	mesh.Any = append(mesh.Any, new(BeamLattice))
+	g := &Generator{
+		CellSize: 5, Radius: 1, CapMode: CapModeButt, MinLength: 0.1,
+		Grading: func(p go3mf.Point3D) float32 { return 1 + (p[2]+5)/10 },
+		Regions: []Region{
+			{Name: "bottom", Identifier: "b", Box: go3mf.Box{Min: go3mf.Point3D{-5, -5, -5}, Max: go3mf.Point3D{5, 5, -5}}},
+			{Name: "none", Box: go3mf.Box{Min: go3mf.Point3D{10, 10, 10}, Max: go3mf.Point3D{20, 20, 20}}},
+		},
+	}
+	got, err := g.Fill(mesh)
+	if err != nil {
+		t.Fatalf("Generator.Fill() error = %v", err)
+	}
+	if len(mesh.Any) != 1 || GetBeamLattice(mesh) != got {
+		t.Fatalf("Generator.Fill() any = %v", mesh.Any)
+	}
+	if got.MinLength != 0.1 || got.CapMode != CapModeButt {
+		t.Errorf("Generator.Fill() lattice = %v", got)
+	}
+	vs := mesh.Vertices.Vertex
+	for _, b := range got.Beams.Beam {
+		for i := range b.Indices {
+			if want := 1 + (vs[b.Indices[i]][2]+5)/10; math.Abs(float64(b.Radius[i]-want)) > 1e-6 {
+				t.Errorf("Generator.Fill() radius = %v, want %v", b.Radius[i], want)
+			}
+		}
+	}
+	var sets []string
+	var refs []int
+	for _, s := range got.BeamSets.BeamSet {
+		sets = append(sets, s.Name+s.Identifier)
+		refs = append(refs, len(s.Refs))
+	}
+	// The bottom face has 12 beams.
+	if diff := deep.Equal(sets, []string{"bottomb", "none"}); diff != nil {
+		t.Errorf("Generator.Fill() sets = %v", diff)
+	}
+	if diff := deep.Equal(refs, []int{12, 0}); diff != nil {
+		t.Errorf("Generator.Fill() refs = %v", diff)
+	}
+	if _, err := new(Generator).Fill(mesh); err != ErrGeneratorCellSize {
+		t.Errorf("Generator.Fill() error = %v, want %v", err, ErrGeneratorCellSize)
+	}
+	if _, err := (&Generator{CellSize: 1, Radius: 1}).Fill(new(go3mf.Mesh)); err != ErrGeneratorEmpty {
+		t.Errorf("Generator.Fill() error = %v, want %v", err, ErrGeneratorEmpty)
+	}
+}
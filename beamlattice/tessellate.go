@@ -0,0 +1,281 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package beamlattice
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/bvh"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+// DefaultSegments is the number of sides of the beams used when
+// the tessellation resolution is not valid.
+const DefaultSegments = 8
+
+// minBeamLength avoids creating degenerated beams.
+const minBeamLength = 1e-6
+
+// Tessellate returns a plain mesh approximating the beam lattice,
+// whose nodes are the vertices of mesh, the mesh that contains the lattice.
+// The mesh triangles are not included.
+//
+// Each beam is a tapered cylinder with segments sides, closed by
+// a flat disk for butt caps and by a half sphere for hemisphere caps.
+// Sphere caps are replaced by a ball centered at the node, whose radius is the
+// biggest radius of the beams with sphere caps that end at the node.
+// Beams and balls are closed and oriented shells, but overlapping shells are
+// not merged, so the result has to be interpreted with the nonzero winding rule.
+//
+// When ClipMode is not ClipNone the beam axes are cut against the clipping mesh
+// and the cut ends are closed with butt caps. The radius of the beams is not
+// taken into account, so the clipped geometry can exceed the clipping mesh by
+// at most the beam radius.
+func (b *BeamLattice) Tessellate(m *go3mf.Model, path string, mesh *go3mf.Mesh, segments int) (*go3mf.Mesh, error) {
+	if segments < 3 {
+		segments = DefaultSegments
+	}
+	var clip *bvh.Tree
+	if b.ClipMode != ClipNone {
+		if b.ClippingMeshID == 0 {
+			return nil, ErrLatticeClippedNoMesh
+		}
+		o, ok := m.FindObject(path, b.ClippingMeshID)
+		if !ok || o.Mesh == nil || GetBeamLattice(o.Mesh) != nil {
+			return nil, ErrLatticeInvalidMesh
+		}
+		clip = bvh.New(o.Mesh)
+	}
+	t := &tessellator{
+		segments: segments,
+		mesh:     new(go3mf.Mesh),
+		balls:    make(map[uint32]float32),
+	}
+	vs := mesh.Vertices.Vertex
+	for _, beam := range b.Beams.Beam {
+		if int(beam.Indices[0]) >= len(vs) || int(beam.Indices[1]) >= len(vs) {
+			return nil, specerr.ErrIndexOutOfBounds
+		}
+		r1, r2 := beam.Radius[0], beam.Radius[1]
+		if r1 == 0 {
+			r1 = b.Radius
+		}
+		if r2 == 0 {
+			r2 = r1
+		}
+		p1, p2 := vs[beam.Indices[0]], vs[beam.Indices[1]]
+		parts := [][2]float32{{0, 1}}
+		if clip != nil {
+			parts = clipSegment(clip, p1, p2, b.ClipMode == ClipInside)
+		}
+		for _, part := range parts {
+			caps := [2]CapMode{CapModeButt, CapModeButt}
+			if part[0] == 0 {
+				caps[0] = beam.CapMode[0]
+				t.addBallRadius(beam.Indices[0], caps[0], r1)
+			}
+			if part[1] == 1 {
+				caps[1] = beam.CapMode[1]
+				t.addBallRadius(beam.Indices[1], caps[1], r2)
+			}
+			d := p2.Sub(p1)
+			t.addBeam(
+				p1.Add(d.Mul(part[0])), p1.Add(d.Mul(part[1])),
+				r1+(r2-r1)*part[0], r1+(r2-r1)*part[1], caps,
+			)
+		}
+	}
+	nodes := make([]uint32, 0, len(t.balls))
+	for i := range t.balls {
+		nodes = append(nodes, i)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i] < nodes[j] })
+	for _, i := range nodes {
+		t.addBall(vs[i], t.balls[i])
+	}
+	return t.mesh, nil
+}
+
+type tessellator struct {
+	segments int
+	mesh     *go3mf.Mesh
+	balls    map[uint32]float32
+}
+
+// ring is a circle of the profile of a shell.
+// Rings with zero radius are represented by a single vertex.
+type ring struct {
+	center go3mf.Point3D
+	radius float32
+}
+
+func (t *tessellator) addBallRadius(node uint32, cap CapMode, r float32) {
+	if cap == CapModeSphere && r > t.balls[node] {
+		t.balls[node] = r
+	}
+}
+
+func (t *tessellator) addBeam(p1, p2 go3mf.Point3D, r1, r2 float32, caps [2]CapMode) {
+	d := p2.Sub(p1)
+	if d.Len() < minBeamLength {
+		return
+	}
+	d = d.Normalize()
+	profile := t.cap(p1, d.Mul(-1), r1, caps[0])
+	for i, j := 0, len(profile)-1; i < j; i, j = i+1, j-1 {
+		profile[i], profile[j] = profile[j], profile[i]
+	}
+	profile = append(profile, t.cap(p2, d, r2, caps[1])...)
+	t.addShell(profile, d)
+}
+
+// cap returns the rings of a beam end, starting at the beam section
+// and finishing at the tip, which has zero radius.
+func (t *tessellator) cap(p, dir go3mf.Point3D, r float32, mode CapMode) []ring {
+	if mode != CapModeHemisphere {
+		return []ring{{p, r}, {p, 0}}
+	}
+	n := t.latitudes()
+	profile := make([]ring, 0, n+1)
+	for i := 0; i <= n; i++ {
+		phi := float64(i) * math.Pi / 2 / float64(n)
+		profile = append(profile, ring{
+			center: p.Add(dir.Mul(r * float32(math.Sin(phi)))),
+			radius: r * float32(math.Cos(phi)),
+		})
+	}
+	profile[n].radius = 0
+	return profile
+}
+
+func (t *tessellator) addBall(p go3mf.Point3D, r float32) {
+	n := 2 * t.latitudes()
+	profile := make([]ring, 0, n+1)
+	for i := 0; i <= n; i++ {
+		phi := -math.Pi/2 + float64(i)*math.Pi/float64(n)
+		profile = append(profile, ring{
+			center: p.Add(go3mf.Point3D{0, 0, r * float32(math.Sin(phi))}),
+			radius: r * float32(math.Cos(phi)),
+		})
+	}
+	profile[0].radius, profile[n].radius = 0, 0
+	t.addShell(profile, go3mf.Point3D{0, 0, 1})
+}
+
+// latitudes returns the number of rings of a quarter of circle.
+func (t *tessellator) latitudes() int {
+	if n := t.segments / 4; n > 1 {
+		return n
+	}
+	return 1
+}
+
+// addShell stitches the profile rings, which are perpendicular to the axis
+// and sorted along it, creating triangles facing outwards.
+// The first and last rings must have zero radius.
+func (t *tessellator) addShell(profile []ring, axis go3mf.Point3D) {
+	u, v := basis(axis)
+	var prev []uint32
+	for _, rg := range profile {
+		var cur []uint32
+		if rg.radius == 0 {
+			cur = []uint32{t.vertex(rg.center)}
+		} else {
+			cur = make([]uint32, t.segments)
+			for i := range cur {
+				a := 2 * math.Pi * float64(i) / float64(t.segments)
+				off := u.Mul(float32(math.Cos(a))).Add(v.Mul(float32(math.Sin(a))))
+				cur[i] = t.vertex(rg.center.Add(off.Mul(rg.radius)))
+			}
+		}
+		switch {
+		case prev == nil:
+		case len(prev) == 1 && len(cur) == 1:
+		case len(prev) == 1:
+			for i := range cur {
+				t.triangle(prev[0], cur[(i+1)%len(cur)], cur[i])
+			}
+		case len(cur) == 1:
+			for i := range prev {
+				t.triangle(prev[i], prev[(i+1)%len(prev)], cur[0])
+			}
+		default:
+			for i := range prev {
+				j := (i + 1) % len(prev)
+				t.triangle(prev[i], prev[j], cur[j])
+				t.triangle(prev[i], cur[j], cur[i])
+			}
+		}
+		prev = cur
+	}
+}
+
+func (t *tessellator) vertex(p go3mf.Point3D) uint32 {
+	t.mesh.Vertices.Vertex = append(t.mesh.Vertices.Vertex, p)
+	return uint32(len(t.mesh.Vertices.Vertex) - 1)
+}
+
+func (t *tessellator) triangle(v1, v2, v3 uint32) {
+	t.mesh.Triangles.Triangle = append(t.mesh.Triangles.Triangle, go3mf.Triangle{V1: v1, V2: v2, V3: v3})
+}
+
+// basis returns two unit vectors perpendicular to axis such that u x v = axis.
+func basis(axis go3mf.Point3D) (u, v go3mf.Point3D) {
+	a := go3mf.Point3D{1, 0, 0}
+	if math.Abs(float64(axis[0])) > 0.9 {
+		a = go3mf.Point3D{0, 1, 0}
+	}
+	u = axis.Cross(a).Normalize()
+	v = axis.Cross(u)
+	return u, v
+}
+
+// clipSegment returns the parts of the segment from p1 to p2,
+// as fractions of its length, which are inside the clipping mesh,
+// or outside if inside is false.
+func clipSegment(clip *bvh.Tree, p1, p2 go3mf.Point3D, inside bool) [][2]float32 {
+	d := p2.Sub(p1)
+	var cuts []float32
+	if length := d.Len(); length >= minBeamLength {
+		// Each cast starts past the previous hit, so the triangles
+		// sharing an edge or a vertex produce a single cut.
+		dir := d.Mul(1 / length)
+		for from := float32(0); ; {
+			hit, ok := clip.Intersect(bvh.Ray{Origin: p1.Add(dir.Mul(from)), Direction: dir})
+			f := from + hit.Distance
+			if !ok || f >= length {
+				break
+			}
+			if f > 0 {
+				cuts = append(cuts, f/length)
+			}
+			from = f + surfaceTolerance
+		}
+	}
+	cuts = append(cuts, 1)
+	var parts [][2]float32
+	start := float32(0)
+	for _, f := range cuts {
+		if f-start > 0 && isInside(clip, p1.Add(d.Mul((start+f)/2))) == inside {
+			parts = append(parts, [2]float32{start, f})
+		}
+		start = f
+	}
+	return parts
+}
+
+// surfaceTolerance is the distance at which points are considered
+// to be on the surface of a mesh.
+const surfaceTolerance = 1e-4
+
+// isInside returns true if p is inside the closed mesh indexed by tree or on its surface.
+func isInside(tree *bvh.Tree, p go3mf.Point3D) bool {
+	if tree.Inside(p) {
+		return true
+	}
+	hit, ok := tree.ClosestPoint(p)
+	return ok && hit.Distance <= surfaceTolerance
+}
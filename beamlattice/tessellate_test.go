@@ -0,0 +1,195 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package beamlattice
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/bvh"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+// nodes returns a mesh with the lattice nodes (0,0,0), (10,0,0) and (10,10,0).
+func nodes() *go3mf.Mesh {
+	return &go3mf.Mesh{Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {10, 10, 0}}}}
+}
+
+func createClipModel() *go3mf.Model {
+	m := new(go3mf.Model)
+	box := new(go3mf.Mesh)
+	for _, v := range []go3mf.Point3D{
+		{-5, -5, -5}, {5, -5, -5}, {5, 5, -5}, {-5, 5, -5},
+		{-5, -5, 5}, {5, -5, 5}, {5, 5, 5}, {-5, 5, 5},
+	} {
+		box.Vertices.Vertex = append(box.Vertices.Vertex, v)
+	}
+	for _, t := range [][3]uint32{
+		{0, 2, 1}, {0, 3, 2}, {4, 5, 6}, {4, 6, 7}, {0, 1, 5}, {0, 5, 4},
+		{1, 2, 6}, {1, 6, 5}, {2, 3, 7}, {2, 7, 6}, {3, 0, 4}, {3, 4, 7},
+	} {
+		box.Triangles.Triangle = append(box.Triangles.Triangle, go3mf.Triangle{V1: t[0], V2: t[1], V3: t[2]})
+	}
+	m.Resources.Objects = append(m.Resources.Objects, &go3mf.Object{ID: 1, Mesh: box})
+	return m
+}
+
+func TestBeamLattice_Tessellate(t *testing.T) {
+	butt := [2]CapMode{CapModeButt, CapModeButt}
+	// Volume of a cylinder with an octagonal section.
+	octagon := 4 * math.Sin(math.Pi/4)
+	tests := []struct {
+		name     string
+		b        *BeamLattice
+		segments int
+		volume   float64
+		minX     float32
+		maxX     float32
+	}{
+		{"butt", &BeamLattice{Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}, CapMode: butt},
+		}}}, 8, octagon * 10, 0, 10},
+		{"defaultRadius", &BeamLattice{Radius: 2, Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, CapMode: butt},
+		}}}, 0, octagon * 4 * 10, 0, 10},
+		{"tapered", &BeamLattice{Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 2}, CapMode: butt},
+		}}}, 8, octagon * 10 * (1 + 2 + 4) / 3, 0, 10},
+		{"hemisphere", &BeamLattice{Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}, CapMode: [2]CapMode{CapModeHemisphere, CapModeButt}},
+		}}}, 8, 0, -1, 10},
+		{"sphere", &BeamLattice{Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}},
+			{Indices: [2]uint32{1, 2}, Radius: [2]float32{1, 1}},
+		}}}, 8, 0, -1, 11},
+		{"clipInside", &BeamLattice{ClipMode: ClipInside, ClippingMeshID: 1, Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}, CapMode: butt},
+			{Indices: [2]uint32{1, 2}, Radius: [2]float32{1, 1}, CapMode: butt},
+		}}}, 8, octagon * 5, 0, 5},
+		{"clipOutside", &BeamLattice{ClipMode: ClipOutside, ClippingMeshID: 1, Beams: Beams{Beam: []Beam{
+			{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}, CapMode: butt},
+		}}}, 8, octagon * 5, 5, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.b.Tessellate(createClipModel(), "", nodes(), tt.segments)
+			if err != nil {
+				t.Fatalf("BeamLattice.Tessellate() error = %v", err)
+			}
+			if err := got.ValidateCoherency(); err != nil {
+				t.Errorf("BeamLattice.Tessellate() is not coherent: %v", err)
+			}
+			vol := got.Volume()
+			if vol <= 0 {
+				t.Errorf("BeamLattice.Tessellate() volume = %v, want positive", vol)
+			}
+			if tt.volume != 0 && math.Abs(vol-tt.volume) > 1e-3 {
+				t.Errorf("BeamLattice.Tessellate() volume = %v, want %v", vol, tt.volume)
+			}
+			box := got.BoundingBox()
+			if math.Abs(float64(box.Min[0]-tt.minX)) > 1e-5 || math.Abs(float64(box.Max[0]-tt.maxX)) > 1e-5 {
+				t.Errorf("BeamLattice.Tessellate() x range = [%v, %v], want [%v, %v]", box.Min[0], box.Max[0], tt.minX, tt.maxX)
+			}
+		})
+	}
+}
+
+func TestBeamLattice_Tessellate_sphere(t *testing.T) {
+	b := &BeamLattice{Beams: Beams{Beam: []Beam{
+		{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 2}},
+		{Indices: [2]uint32{1, 2}, Radius: [2]float32{1, 1}, CapMode: [2]CapMode{CapModeSphere, CapModeButt}},
+	}}}
+	got, err := b.Tessellate(new(go3mf.Model), "", nodes(), 8)
+	if err != nil {
+		t.Fatalf("BeamLattice.Tessellate() error = %v", err)
+	}
+	// Two beams and one ball per sphere capped node: 2 rings and 2 tips per beam,
+	// 3 rings and 2 poles per ball.
+	if want := 2*(2*8+2) + 2*(3*8+2); len(got.Vertices.Vertex) != want {
+		t.Errorf("BeamLattice.Tessellate() vertices = %d, want %d", len(got.Vertices.Vertex), want)
+	}
+	// The ball at the shared node has the biggest radius.
+	if box := got.BoundingBox(); box.Max[2] != 2 {
+		t.Errorf("BeamLattice.Tessellate() max z = %v, want 2", box.Max[2])
+	}
+}
+
+func TestBeamLattice_Tessellate_error(t *testing.T) {
+	m := createClipModel()
+	m.Resources.Objects = append(m.Resources.Objects, &go3mf.Object{ID: 2, Components: &go3mf.Components{}})
+	beams := Beams{Beam: []Beam{{Indices: [2]uint32{0, 1}, Radius: [2]float32{1, 1}}}}
+	tests := []struct {
+		name string
+		b    *BeamLattice
+		want error
+	}{
+		{"noClippingMesh", &BeamLattice{ClipMode: ClipInside, Beams: beams}, ErrLatticeClippedNoMesh},
+		{"missingClippingMesh", &BeamLattice{ClipMode: ClipInside, ClippingMeshID: 5, Beams: beams}, ErrLatticeInvalidMesh},
+		{"componentsClippingMesh", &BeamLattice{ClipMode: ClipOutside, ClippingMeshID: 2, Beams: beams}, ErrLatticeInvalidMesh},
+		{"index", &BeamLattice{Beams: Beams{Beam: []Beam{{Indices: [2]uint32{0, 3}}}}}, specerr.ErrIndexOutOfBounds},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.b.Tessellate(m, "", nodes(), 8); err != tt.want {
+				t.Errorf("BeamLattice.Tessellate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func Test_clipSegment(t *testing.T) {
+	box := bvh.New(createClipModel().Resources.Objects[0].Mesh)
+	tests := []struct {
+		name   string
+		p1, p2 go3mf.Point3D
+		inside bool
+		want   [][2]float32
+	}{
+		{"inside", go3mf.Point3D{-10, 0, 0}, go3mf.Point3D{10, 0, 0}, true, [][2]float32{{0.25, 0.75}}},
+		{"outside", go3mf.Point3D{-10, 0, 0}, go3mf.Point3D{10, 0, 0}, false, [][2]float32{{0, 0.25}, {0.75, 1}}},
+		{"edges", go3mf.Point3D{-10, -10, 1}, go3mf.Point3D{10, 10, 1}, true, [][2]float32{{0.25, 0.75}}},
+		{"contained", go3mf.Point3D{-1, 0, 0}, go3mf.Point3D{1, 0, 0}, true, [][2]float32{{0, 1}}},
+		{"missed", go3mf.Point3D{-10, 6, 0}, go3mf.Point3D{10, 6, 0}, true, nil},
+		{"fromSurface", go3mf.Point3D{-5, 0, 0}, go3mf.Point3D{15, 0, 0}, true, [][2]float32{{0, 0.5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clipSegment(box, tt.p1, tt.p2, tt.inside)
+			if len(got) != len(tt.want) {
+				t.Fatalf("clipSegment() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(float64(got[i][0]-tt.want[i][0])) > 1e-5 || math.Abs(float64(got[i][1]-tt.want[i][1])) > 1e-5 {
+					t.Errorf("clipSegment() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_isInside(t *testing.T) {
+	box := bvh.New(createClipModel().Resources.Objects[0].Mesh)
+	tests := []struct {
+		name string
+		p    go3mf.Point3D
+		want bool
+	}{
+		{"center", go3mf.Point3D{0, 0, 0}, true},
+		{"outside", go3mf.Point3D{6, 0, 0}, false},
+		{"face", go3mf.Point3D{5, 1, 2}, true},
+		{"oppositeFace", go3mf.Point3D{-5, 1, 2}, true},
+		{"edge", go3mf.Point3D{-5, -5, 1}, true},
+		{"corner", go3mf.Point3D{5, 5, 5}, true},
+		{"nearFace", go3mf.Point3D{-5.00001, 1, 2}, true},
+		{"offFace", go3mf.Point3D{-5.01, 1, 2}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isInside(box, tt.p); got != tt.want {
+				t.Errorf("isInside() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
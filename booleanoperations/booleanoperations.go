@@ -0,0 +1,157 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package booleanoperations implements the 3MF Boolean Operations extension,
+// which defines objects as the result of applying boolean operations
+// to a base object.
+package booleanoperations
+
+import (
+	"errors"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
+	"github.com/hpinc/go3mf/spec"
+)
+
+// Namespace is the canonical name of this extension.
+const Namespace = "http://schemas.3mf.io/3dmanufacturing/booleanoperations/2023/07"
+
+var DefaultExtension = go3mf.Extension{
+	Namespace:  Namespace,
+	LocalName:  "bo",
+	IsRequired: true,
+}
+
+var (
+	ErrBooleanObjectContent = errors.New("an object containing a booleanshape MUST NOT contain a mesh or components")
+	ErrBooleanBaseObject    = errors.New("the base object of a booleanshape MUST be a mesh or a booleanshape object")
+	ErrBooleanOperandObject = errors.New("a boolean operand MUST be a mesh object of type model")
+	ErrBooleanNoOperands    = errors.New("a booleanshape MUST contain at least one boolean operand")
+)
+
+func init() {
+	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrBooleanObjectContent: {ID: "BOOL001", Name: "BooleanObjectContent", Severity: reporting.SeverityError},
+		ErrBooleanBaseObject:    {ID: "BOOL002", Name: "BooleanBaseObject", Severity: reporting.SeverityError},
+		ErrBooleanOperandObject: {ID: "BOOL003", Name: "BooleanOperandObject", Severity: reporting.SeverityError},
+		ErrBooleanNoOperands:    {ID: "BOOL004", Name: "BooleanNoOperands", Severity: reporting.SeverityError},
+	})
+}
+
+type Spec struct{}
+
+// Operation defines the boolean operation applied to the operands.
+type Operation uint8
+
+// Supported operations.
+const (
+	OperationUnion Operation = iota
+	OperationDifference
+	OperationIntersection
+)
+
+func newOperation(s string) (o Operation, ok bool) {
+	o, ok = map[string]Operation{
+		"union":        OperationUnion,
+		"difference":   OperationDifference,
+		"intersection": OperationIntersection,
+	}[s]
+	return
+}
+
+func (o Operation) String() string {
+	return map[Operation]string{
+		OperationUnion:        "union",
+		OperationDifference:   "difference",
+		OperationIntersection: "intersection",
+	}[o]
+}
+
+// BooleanShape defines an object as the result of applying
+// the operation to the base object and the operands, in order.
+type BooleanShape struct {
+	ObjectID  uint32
+	Operation Operation
+	Transform go3mf.Matrix
+	Path      string
+	Operands  []Boolean
+}
+
+// ObjectPath returns the path of the base object, or defaultPath if not set.
+func (b *BooleanShape) ObjectPath(defaultPath string) string {
+	if b.Path != "" {
+		return b.Path
+	}
+	return defaultPath
+}
+
+// HasTransform returns true if the transform is different than the identity.
+func (b *BooleanShape) HasTransform() bool {
+	return hasTransform(b.Transform)
+}
+
+// ObjectContent marks the boolean shape as the content of its object.
+func (b *BooleanShape) ObjectContent() {}
+
+// Scale multiplies the translation of the base and operand transforms by factor.
+func (b *BooleanShape) Scale(factor float32) {
+	b.Transform = scaleTranslation(b.Transform, factor)
+	for i := range b.Operands {
+		b.Operands[i].Transform = scaleTranslation(b.Operands[i].Transform, factor)
+	}
+}
+
+// Boolean defines an operand of a boolean shape.
+type Boolean struct {
+	ObjectID  uint32
+	Transform go3mf.Matrix
+	Path      string
+}
+
+// ObjectPath returns the path of the operand object, or defaultPath if not set.
+func (b *Boolean) ObjectPath(defaultPath string) string {
+	if b.Path != "" {
+		return b.Path
+	}
+	return defaultPath
+}
+
+// HasTransform returns true if the transform is different than the identity.
+func (b *Boolean) HasTransform() bool {
+	return hasTransform(b.Transform)
+}
+
+// GetBooleanShape returns the boolean shape of the object, if any.
+func GetBooleanShape(obj *go3mf.Object) *BooleanShape {
+	for _, a := range obj.Any {
+		if a, ok := a.(*BooleanShape); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+func hasTransform(t go3mf.Matrix) bool {
+	return t != go3mf.Matrix{} && t != go3mf.Identity()
+}
+
+func scaleTranslation(t go3mf.Matrix, factor float32) go3mf.Matrix {
+	if t == (go3mf.Matrix{}) {
+		return t
+	}
+	t[12] *= factor
+	t[13] *= factor
+	t[14] *= factor
+	return t
+}
+
+const (
+	attrBooleanShape = "booleanshape"
+	attrBoolean      = "boolean"
+	attrObjectID     = "objectid"
+	attrOperation    = "operation"
+	attrTransform    = "transform"
+	attrPath         = "path"
+)
@@ -0,0 +1,91 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+var _ spec.Marshaler = new(BooleanShape)
+var _ spec.Scaler = new(BooleanShape)
+var _ spec.ChildElementDecoder = new(booleanShapeDecoder)
+
+func TestOperation_String(t *testing.T) {
+	tests := []struct {
+		name string
+		o    Operation
+	}{
+		{"union", OperationUnion},
+		{"difference", OperationDifference},
+		{"intersection", OperationIntersection},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.o.String(); got != tt.name {
+				t.Errorf("Operation.String() = %v, want %v", got, tt.name)
+			}
+		})
+	}
+}
+
+func Test_newOperation(t *testing.T) {
+	tests := []struct {
+		name   string
+		wantO  Operation
+		wantOk bool
+	}{
+		{"union", OperationUnion, true},
+		{"difference", OperationDifference, true},
+		{"intersection", OperationIntersection, true},
+		{"empty", OperationUnion, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotO, gotOk := newOperation(tt.name)
+			if !reflect.DeepEqual(gotO, tt.wantO) {
+				t.Errorf("newOperation() gotO = %v, want %v", gotO, tt.wantO)
+			}
+			if gotOk != tt.wantOk {
+				t.Errorf("newOperation() gotOk = %v, want %v", gotOk, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestBooleanShape_Scale(t *testing.T) {
+	b := &BooleanShape{Transform: go3mf.Matrix{2, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 2, 3, 1}, Operands: []Boolean{
+		{ObjectID: 1}, {ObjectID: 2, Transform: go3mf.Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, -1, 0, 4, 1}},
+	}}
+	want := &BooleanShape{Transform: go3mf.Matrix{2, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 10, 20, 30, 1}, Operands: []Boolean{
+		{ObjectID: 1}, {ObjectID: 2, Transform: go3mf.Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, -10, 0, 40, 1}},
+	}}
+	b.Scale(10)
+	if !reflect.DeepEqual(b, want) {
+		t.Errorf("BooleanShape.Scale() = %v, want %v", b, want)
+	}
+}
+
+func TestBooleanShape_ObjectPath(t *testing.T) {
+	b := &BooleanShape{Operands: []Boolean{{Path: "/3D/other.model"}}}
+	if got := b.ObjectPath("/3D/3dmodel.model"); got != "/3D/3dmodel.model" {
+		t.Errorf("BooleanShape.ObjectPath() = %v", got)
+	}
+	if got := b.Operands[0].ObjectPath("/3D/3dmodel.model"); got != "/3D/other.model" {
+		t.Errorf("Boolean.ObjectPath() = %v", got)
+	}
+}
+
+func TestGetBooleanShape(t *testing.T) {
+	b := new(BooleanShape)
+	if got := GetBooleanShape(&go3mf.Object{Any: spec.Any{&spec.UnknownTokens{}, b}}); got != b {
+		t.Errorf("GetBooleanShape() = %v, want %v", got, b)
+	}
+	if got := GetBooleanShape(&go3mf.Object{}); got != nil {
+		t.Errorf("GetBooleanShape() = %v, want nil", got)
+	}
+}
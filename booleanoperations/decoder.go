@@ -0,0 +1,113 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func (Spec) NewAttrGroup(xml.Name) spec.AttrGroup {
+	return nil
+}
+
+func (Spec) NewElementDecoder(name xml.Name) spec.GetterElementDecoder {
+	if name.Space == Namespace && name.Local == attrBooleanShape {
+		return new(booleanShapeDecoder)
+	}
+	return nil
+}
+
+type booleanShapeDecoder struct {
+	baseDecoder
+	shape BooleanShape
+}
+
+func (d *booleanShapeDecoder) Element() interface{} {
+	return &d.shape
+}
+
+func (d *booleanShapeDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrObjectID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.shape.ObjectID = uint32(val)
+		case attrOperation:
+			var ok bool
+			d.shape.Operation, ok = newOperation(string(a.Value))
+			if !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrTransform:
+			var ok bool
+			d.shape.Transform, ok = spec.ParseMatrix(string(a.Value))
+			if !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrPath:
+			d.shape.Path = string(a.Value)
+		}
+	}
+	return errs
+}
+
+func (d *booleanShapeDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrBoolean {
+		child = &booleanDecoder{shape: &d.shape}
+		i = len(d.shape.Operands)
+	}
+	return
+}
+
+type booleanDecoder struct {
+	baseDecoder
+	shape *BooleanShape
+}
+
+func (d *booleanDecoder) Start(attrs []spec.XMLAttr) error {
+	var (
+		b    Boolean
+		errs error
+	)
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrObjectID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			b.ObjectID = uint32(val)
+		case attrTransform:
+			var ok bool
+			b.Transform, ok = spec.ParseMatrix(string(a.Value))
+			if !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrPath:
+			b.Path = string(a.Value)
+		}
+	}
+	d.shape.Operands = append(d.shape.Operands, b)
+	return errs
+}
+
+type baseDecoder struct {
+}
+
+func (d *baseDecoder) Start([]spec.XMLAttr) error { return nil }
+func (d *baseDecoder) End()                       {}
@@ -0,0 +1,95 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestDecode(t *testing.T) {
+	shape := &go3mf.Object{ID: 3, Name: "drilled", Any: spec.Any{&BooleanShape{
+		ObjectID: 1, Operation: OperationDifference,
+		Transform: go3mf.Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 5, 0, 0, 1},
+		Operands: []Boolean{
+			{ObjectID: 2},
+			{ObjectID: 2, Transform: go3mf.Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 10, 0, 1}, Path: "/3D/other.model"},
+		},
+	}}}
+	want := &go3mf.Model{Path: "/3D/3dmodel.model", Resources: go3mf.Resources{
+		Objects: []*go3mf.Object{shape},
+	}}
+	want.Extensions = []go3mf.Extension{DefaultExtension}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:bo="http://schemas.3mf.io/3dmanufacturing/booleanoperations/2023/07"
+		requiredextensions="bo">
+		<resources>
+			<object id="3" name="drilled">
+				<bo:booleanshape objectid="1" operation="difference" transform="1 0 0 0 1 0 0 0 1 5 0 0">
+					<bo:boolean objectid="2"/>
+					<bo:boolean objectid="2" transform="1 0 0 0 1 0 0 0 1 0 10 0" path="/3D/other.model"/>
+				</bo:booleanshape>
+			</object>
+		</resources>
+		<build/>
+		</model>
+		`
+	t.Run("base", func(t *testing.T) {
+		if err := go3mf.UnmarshalModel([]byte(rootFile), got); err != nil {
+			t.Errorf("UnmarshalModel() unexpected error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("UnmarshalModel() = %v", diff)
+			return
+		}
+	})
+}
+
+func TestDecode_warns(t *testing.T) {
+	want := []string{
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/booleanshape: %v", &errors.ParseAttrError{Required: true, Name: "objectid"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/booleanshape: %v", &errors.ParseAttrError{Required: false, Name: "operation"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/booleanshape: %v", &errors.ParseAttrError{Required: false, Name: "transform"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/booleanshape/boolean[0]: %v", &errors.ParseAttrError{Required: true, Name: "objectid"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/booleanshape/boolean[1]: %v", &errors.ParseAttrError{Required: false, Name: "transform"}),
+	}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:bo="http://schemas.3mf.io/3dmanufacturing/booleanoperations/2023/07"
+		requiredextensions="bo">
+		<resources>
+			<object id="3">
+				<bo:booleanshape objectid="a" operation="xor" transform="1 0 0">
+					<bo:boolean objectid="-2"/>
+					<bo:boolean objectid="2" transform="0 0 a 0 0 0 0 0 0 0 0 0"/>
+				</bo:booleanshape>
+			</object>
+		</resources>
+		<build/>
+		</model>`
+
+	t.Run("base", func(t *testing.T) {
+		err := go3mf.UnmarshalModel([]byte(rootFile), got)
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		var errs []string
+		for _, err := range err.(*errors.List).Errors {
+			errs = append(errs, err.Error())
+		}
+		if diff := deep.Equal(errs, want); diff != nil {
+			t.Errorf("UnmarshalModel_warn() = %v", diff)
+			return
+		}
+	})
+}
@@ -0,0 +1,57 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestMarshalModel(t *testing.T) {
+	mesh := &go3mf.Object{ID: 1, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+		}},
+	}}
+	shape := &go3mf.Object{ID: 2, Any: spec.Any{&BooleanShape{
+		ObjectID: 1, Operation: OperationIntersection,
+		Transform: go3mf.Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 1},
+		Operands: []Boolean{
+			{ObjectID: 1, Transform: go3mf.Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 1, 1, 1, 1}},
+			{ObjectID: 1, Path: "/3D/3dmodel.model"},
+		},
+	}}}
+	union := &go3mf.Object{ID: 3, Any: spec.Any{&BooleanShape{ObjectID: 2, Operands: []Boolean{{ObjectID: 1}}}}}
+	m := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources: go3mf.Resources{
+			Objects: []*go3mf.Object{mesh, shape, union},
+		},
+	}
+
+	t.Run("base", func(t *testing.T) {
+		b, err := go3mf.MarshalModel(m)
+		if err != nil {
+			t.Errorf("booleanoperations.MarshalModel() error = %v", err)
+			return
+		}
+		newModel := new(go3mf.Model)
+		newModel.Path = m.Path
+		if err := go3mf.UnmarshalModel(b, newModel); err != nil {
+			t.Errorf("booleanoperations.MarshalModel() error decoding = %v, s = %s", err, string(b))
+			return
+		}
+		if diff := deep.Equal(m, newModel); diff != nil {
+			t.Errorf("booleanoperations.MarshalModel() = %v, s = %s", diff, string(b))
+		}
+		if err := newModel.Validate(); err != nil {
+			t.Errorf("booleanoperations.MarshalModel() invalid model = %v", err)
+		}
+	})
+}
@@ -0,0 +1,56 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+func (Spec) Validate(m interface{}, path string, obj interface{}) error {
+	if obj, ok := obj.(*go3mf.Object); ok {
+		return validateObject(m.(*go3mf.Model), path, obj)
+	}
+	return nil
+}
+
+func validateObject(m *go3mf.Model, path string, obj *go3mf.Object) error {
+	bs := GetBooleanShape(obj)
+	if bs == nil {
+		return nil
+	}
+	var errs error
+	if obj.Mesh != nil || obj.Components != nil {
+		errs = errors.Append(errs, ErrBooleanObjectContent)
+	}
+	if bs.ObjectID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrObjectID))
+	} else if bs.ObjectID == obj.ID && bs.ObjectPath(path) == path {
+		errs = errors.Append(errs, errors.ErrRecursion)
+	} else if base, ok := m.FindObject(bs.ObjectPath(path), bs.ObjectID); !ok {
+		errs = errors.Append(errs, errors.ErrMissingResource)
+	} else if base.Mesh == nil && GetBooleanShape(base) == nil {
+		errs = errors.Append(errs, ErrBooleanBaseObject)
+	}
+	if len(bs.Operands) == 0 {
+		errs = errors.Append(errs, ErrBooleanNoOperands)
+	}
+	for i, o := range bs.Operands {
+		var err error
+		if o.ObjectID == 0 {
+			err = errors.NewMissingFieldError(attrObjectID)
+		} else if ref, ok := m.FindObject(o.ObjectPath(path), o.ObjectID); !ok {
+			err = errors.ErrMissingResource
+		} else if ref.Mesh == nil || ref.Type != go3mf.ObjectTypeModel {
+			err = ErrBooleanOperandObject
+		}
+		if err != nil {
+			errs = errors.Append(errs, errors.WrapIndex(err, attrBoolean, i))
+		}
+	}
+	if errs != nil {
+		errs = errors.Wrap(errs, attrBooleanShape)
+	}
+	return errs
+}
@@ -0,0 +1,87 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package booleanoperations
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestValidate(t *testing.T) {
+	tetra := func(id uint32, typ go3mf.ObjectType) *go3mf.Object {
+		return &go3mf.Object{ID: id, Type: typ, Mesh: &go3mf.Mesh{
+			Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}},
+			Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+				{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+			}},
+		}}
+	}
+	tests := []struct {
+		name  string
+		model *go3mf.Model
+		want  []string
+	}{
+		{"error in child", &go3mf.Model{Childs: map[string]*go3mf.ChildModel{
+			"/other.model": {Resources: go3mf.Resources{Objects: []*go3mf.Object{
+				{ID: 1, Any: spec.Any{&BooleanShape{}}},
+			}}},
+		}}, []string{
+			fmt.Sprintf("go3mf: Path: /other.model XPath: /model/resources/object[0]/booleanshape: %v", &errors.MissingFieldError{Name: attrObjectID}),
+			fmt.Sprintf("go3mf: Path: /other.model XPath: /model/resources/object[0]/booleanshape: %v", ErrBooleanNoOperands),
+		}},
+		{"object content", &go3mf.Model{Resources: go3mf.Resources{Objects: []*go3mf.Object{
+			tetra(1, go3mf.ObjectTypeModel),
+			{ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 1}}},
+				Any: spec.Any{&BooleanShape{ObjectID: 1, Operands: []Boolean{{ObjectID: 1}}}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/booleanshape: %v", ErrBooleanObjectContent),
+		}},
+		{"base object", &go3mf.Model{Resources: go3mf.Resources{Objects: []*go3mf.Object{
+			tetra(1, go3mf.ObjectTypeModel),
+			{ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 1}}}},
+			{ID: 3, Any: spec.Any{&BooleanShape{ObjectID: 2, Operands: []Boolean{{ObjectID: 1}}}}},
+			{ID: 4, Any: spec.Any{&BooleanShape{ObjectID: 4, Operands: []Boolean{{ObjectID: 1}}}}},
+			{ID: 5, Any: spec.Any{&BooleanShape{ObjectID: 100, Operands: []Boolean{{ObjectID: 1}}}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[2]/booleanshape: %v", ErrBooleanBaseObject),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape: %v", errors.ErrRecursion),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[4]/booleanshape: %v", errors.ErrMissingResource),
+		}},
+		{"operands", &go3mf.Model{Resources: go3mf.Resources{Objects: []*go3mf.Object{
+			tetra(1, go3mf.ObjectTypeModel),
+			tetra(2, go3mf.ObjectTypeSupport),
+			{ID: 3, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 1}}}},
+			{ID: 4, Any: spec.Any{&BooleanShape{ObjectID: 1, Operands: []Boolean{
+				{ObjectID: 1}, {}, {ObjectID: 2}, {ObjectID: 3}, {ObjectID: 100}, {ObjectID: 1, Path: "/other.model"},
+			}}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape/boolean[1]: %v", &errors.MissingFieldError{Name: attrObjectID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape/boolean[2]: %v", ErrBooleanOperandObject),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape/boolean[3]: %v", ErrBooleanOperandObject),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape/boolean[4]: %v", errors.ErrMissingResource),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/booleanshape/boolean[5]: %v", errors.ErrMissingResource),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Extensions = []go3mf.Extension{DefaultExtension}
+			err := tt.model.Validate()
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			var errs []string
+			for _, err := range err.(*errors.List).Errors {
+				errs = append(errs, err.Error())
+			}
+			if diff := deep.Equal(errs, tt.want); diff != nil {
+				t.Errorf("Validate() = %v", diff)
+			}
+		})
+	}
+}
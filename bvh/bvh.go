@@ -0,0 +1,300 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package bvh implements bounding volume hierarchies over the triangles
+// of meshes, to accelerate ray casting, point-in-mesh and closest point queries.
+//
+// A Tree indexes a single mesh in its own coordinates, and a Scene places
+// trees in the build space using the item and component transforms,
+// so meshes referenced several times are indexed only once.
+package bvh
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+)
+
+// leafSize is the maximum number of primitives of a leaf node.
+const leafSize = 4
+
+// insideRays are the directions used to test if a point is inside a mesh.
+// They are not aligned with the axes to reduce the chances of hitting edges.
+var insideRays = [3]go3mf.Point3D{
+	go3mf.Point3D{0.8017, 0.5345, 0.2673}.Normalize(),
+	go3mf.Point3D{-0.3015, 0.9045, -0.3015}.Normalize(),
+	go3mf.Point3D{0.1826, -0.3651, -0.9129}.Normalize(),
+}
+
+// A Hit is the result of a query.
+type Hit struct {
+	// Distance along the ray for ray casting,
+	// or the euclidean distance for closest point queries.
+	Distance float32
+	// Point is the position of the hit.
+	Point go3mf.Point3D
+	// Normal is the unit normal of the triangle, following its winding order.
+	Normal go3mf.Point3D
+	// Triangle is the index of the triangle in the mesh.
+	Triangle int
+	// Instance is the index of the scene instance, zero for Tree queries.
+	Instance int
+}
+
+// A Tree is a bounding volume hierarchy over the triangles of a mesh.
+// The tree does not copy the mesh, which must not be modified while the tree is in use.
+type Tree struct {
+	mesh *go3mf.Mesh
+	h    hierarchy
+}
+
+// New returns the tree of mesh.
+// Triangles with out of bounds indices are ignored.
+func New(mesh *go3mf.Mesh) *Tree {
+	vs := mesh.Vertices.Vertex
+	boxes := make([]go3mf.Box, 0, len(mesh.Triangles.Triangle))
+	ids := make([]int32, 0, len(mesh.Triangles.Triangle))
+	for i, tr := range mesh.Triangles.Triangle {
+		if int(tr.V1) >= len(vs) || int(tr.V2) >= len(vs) || int(tr.V3) >= len(vs) {
+			continue
+		}
+		boxes = append(boxes, extendBox(extendBox(extendBox(emptyBox(), vs[tr.V1]), vs[tr.V2]), vs[tr.V3]))
+		ids = append(ids, int32(i))
+	}
+	return &Tree{mesh: mesh, h: buildHierarchy(boxes, ids)}
+}
+
+// Mesh returns the indexed mesh.
+func (t *Tree) Mesh() *go3mf.Mesh {
+	return t.mesh
+}
+
+// Box returns the bounding box of the indexed triangles.
+func (t *Tree) Box() go3mf.Box {
+	if len(t.h.nodes) == 0 {
+		return go3mf.Box{}
+	}
+	return t.h.nodes[0].box
+}
+
+// Intersect returns the closest triangle hit by the ray.
+func (t *Tree) Intersect(r Ray) (Hit, bool) {
+	return t.intersect(r, math.MaxFloat32)
+}
+
+// Inside returns true if p is inside the mesh, which should be closed.
+// The result is the majority vote of the parity of the triangles crossed by three rays.
+func (t *Tree) Inside(p go3mf.Point3D) bool {
+	var votes int
+	for _, d := range insideRays {
+		if t.crossings(Ray{Origin: p, Direction: d})%2 == 1 {
+			votes++
+		}
+	}
+	return votes >= 2
+}
+
+// ClosestPoint returns the point of the mesh surface closest to p.
+func (t *Tree) ClosestPoint(p go3mf.Point3D) (Hit, bool) {
+	hit, ok := t.closest(p, math.MaxFloat32, 1, go3mf.Identity())
+	if ok {
+		hit.Distance = float32(math.Sqrt(float64(hit.Distance)))
+	}
+	return hit, ok
+}
+
+func (t *Tree) triangle(i int32) (v1, v2, v3 go3mf.Point3D) {
+	vs := t.mesh.Vertices.Vertex
+	tr := t.mesh.Triangles.Triangle[i]
+	return vs[tr.V1], vs[tr.V2], vs[tr.V3]
+}
+
+func (t *Tree) intersect(r Ray, maxT float32) (Hit, bool) {
+	hit := Hit{Triangle: -1}
+	t.h.raycast(r, maxT, func(prim int32, maxT float32) float32 {
+		v1, v2, v3 := t.triangle(prim)
+		if d, ok := intersectTriangle(r, v1, v2, v3); ok && d < maxT {
+			hit.Distance, hit.Triangle = d, int(prim)
+			hit.Normal = v2.Sub(v1).Cross(v3.Sub(v1)).Normalize()
+			return d
+		}
+		return maxT
+	})
+	if hit.Triangle < 0 {
+		return Hit{}, false
+	}
+	hit.Point = r.At(hit.Distance)
+	return hit, true
+}
+
+// crossings returns the number of triangles crossed by the ray.
+func (t *Tree) crossings(r Ray) int {
+	var n int
+	t.h.raycast(r, math.MaxFloat32, func(prim int32, maxT float32) float32 {
+		v1, v2, v3 := t.triangle(prim)
+		if d, ok := intersectTriangle(r, v1, v2, v3); ok && d > 0 {
+			n++
+		}
+		return maxT
+	})
+	return n
+}
+
+// closest returns the closest point to p, which is in tree coordinates,
+// measuring the squared distances in the space defined by m.
+// The distance in that space is at least scale times the tree distance.
+func (t *Tree) closest(p go3mf.Point3D, maxSq, scale float32, m go3mf.Matrix) (Hit, bool) {
+	hit := Hit{Triangle: -1}
+	pm := m.Mul3D(p)
+	t.h.nearest(p, maxSq, scale*scale, func(prim int32, bestSq float32) float32 {
+		v1, v2, v3 := t.triangle(prim)
+		v1, v2, v3 = m.Mul3D(v1), m.Mul3D(v2), m.Mul3D(v3)
+		c := closestPointTriangle(pm, v1, v2, v3)
+		if d := sqDistance(c, pm); d < bestSq {
+			hit.Distance, hit.Point, hit.Triangle = d, c, int(prim)
+			hit.Normal = v2.Sub(v1).Cross(v3.Sub(v1)).Normalize()
+			return d
+		}
+		return bestSq
+	})
+	return hit, hit.Triangle >= 0
+}
+
+// node is a node of a hierarchy.
+// Leaves have count > 0 and hold the primitives prims[start:start+count].
+// The left child of inner nodes is the next node and the right child is nodes[start].
+type node struct {
+	box          go3mf.Box
+	start, count int32
+}
+
+// hierarchy is a bounding volume hierarchy over generic primitives.
+type hierarchy struct {
+	nodes []node
+	prims []int32
+}
+
+func buildHierarchy(boxes []go3mf.Box, ids []int32) hierarchy {
+	h := hierarchy{prims: make([]int32, len(boxes))}
+	if len(boxes) == 0 {
+		return h
+	}
+	b := &builder{h: &h, boxes: boxes, centers: make([]go3mf.Point3D, len(boxes))}
+	for i, box := range boxes {
+		h.prims[i] = int32(i)
+		b.centers[i] = box.Min.Add(box.Max).Mul(0.5)
+	}
+	h.nodes = make([]node, 0, 2*len(boxes)/leafSize+1)
+	b.build(0, len(boxes))
+	// The primitives are built as positions in boxes, translate them to ids.
+	for i, p := range h.prims {
+		h.prims[i] = ids[p]
+	}
+	return h
+}
+
+type builder struct {
+	h       *hierarchy
+	boxes   []go3mf.Box
+	centers []go3mf.Point3D
+}
+
+// build creates the node with the primitives prims[start:end],
+// splitting them by the median along the longest axis of their centers.
+func (b *builder) build(start, end int) {
+	idx := len(b.h.nodes)
+	b.h.nodes = append(b.h.nodes, node{})
+	box, cbox := emptyBox(), emptyBox()
+	prims := b.h.prims[start:end]
+	for _, p := range prims {
+		box = unionBox(box, b.boxes[p])
+		cbox = extendBox(cbox, b.centers[p])
+	}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if cbox.Max[i]-cbox.Min[i] > cbox.Max[axis]-cbox.Min[axis] {
+			axis = i
+		}
+	}
+	if end-start <= leafSize || cbox.Max[axis] == cbox.Min[axis] {
+		b.h.nodes[idx] = node{box: box, start: int32(start), count: int32(end - start)}
+		return
+	}
+	sort.Slice(prims, func(i, j int) bool {
+		return b.centers[prims[i]][axis] < b.centers[prims[j]][axis]
+	})
+	mid := (start + end) / 2
+	b.build(start, mid)
+	right := len(b.h.nodes)
+	b.build(mid, end)
+	b.h.nodes[idx] = node{box: box, start: int32(right)}
+}
+
+// raycast calls hit for the primitives whose boxes are crossed by the ray before maxT,
+// visiting first the closest nodes. hit returns the new maximum distance.
+func (h *hierarchy) raycast(r Ray, maxT float32, hit func(prim int32, maxT float32) float32) {
+	if len(h.nodes) == 0 {
+		return
+	}
+	inv := r.invDirection()
+	stack := make([]int32, 1, 64)
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &h.nodes[i]
+		if _, ok := intersectBox(n.box, r.Origin, inv, maxT); !ok {
+			continue
+		}
+		if n.count > 0 {
+			for _, p := range h.prims[n.start : n.start+n.count] {
+				maxT = hit(p, maxT)
+			}
+			continue
+		}
+		left, right := i+1, n.start
+		tl, okl := intersectBox(h.nodes[left].box, r.Origin, inv, maxT)
+		tr, okr := intersectBox(h.nodes[right].box, r.Origin, inv, maxT)
+		switch {
+		case okl && okr && tr < tl:
+			stack = append(stack, left, right)
+		case okl && okr:
+			stack = append(stack, right, left)
+		case okl:
+			stack = append(stack, left)
+		case okr:
+			stack = append(stack, right)
+		}
+	}
+}
+
+// nearest calls dist for the primitives whose boxes are closer to p than the current best
+// squared distance, starting with maxSq. Box distances are multiplied by sqScale,
+// to support primitives measured in other spaces. dist returns the new best distance.
+func (h *hierarchy) nearest(p go3mf.Point3D, maxSq, sqScale float32, dist func(prim int32, bestSq float32) float32) {
+	if len(h.nodes) == 0 {
+		return
+	}
+	best := maxSq
+	stack := make([]int32, 1, 64)
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := &h.nodes[i]
+		if sqDistanceBox(n.box, p)*sqScale >= best {
+			continue
+		}
+		if n.count > 0 {
+			for _, prim := range h.prims[n.start : n.start+n.count] {
+				best = dist(prim, best)
+			}
+			continue
+		}
+		left, right := i+1, n.start
+		if sqDistanceBox(h.nodes[left].box, p) < sqDistanceBox(h.nodes[right].box, p) {
+			stack = append(stack, right, left)
+		} else {
+			stack = append(stack, left, right)
+		}
+	}
+}
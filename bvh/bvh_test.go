@@ -0,0 +1,191 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/internal/meshtest"
+)
+
+// createSoup returns a mesh with n random triangles inside the unit cube.
+func createSoup(rnd *rand.Rand, n int) *go3mf.Mesh {
+	m := new(go3mf.Mesh)
+	for i := 0; i < n; i++ {
+		c := go3mf.Point3D{rnd.Float32(), rnd.Float32(), rnd.Float32()}
+		for j := 0; j < 3; j++ {
+			m.Vertices.Vertex = append(m.Vertices.Vertex, c.Add(go3mf.Point3D{rnd.Float32() * 0.1, rnd.Float32() * 0.1, rnd.Float32() * 0.1}))
+		}
+		m.Triangles.Triangle = append(m.Triangles.Triangle, go3mf.Triangle{V1: uint32(3 * i), V2: uint32(3*i + 1), V3: uint32(3*i + 2)})
+	}
+	return m
+}
+
+func randomPoint(rnd *rand.Rand) go3mf.Point3D {
+	return go3mf.Point3D{rnd.Float32()*2 - 0.5, rnd.Float32()*2 - 0.5, rnd.Float32()*2 - 0.5}
+}
+
+func TestNew(t *testing.T) {
+	mesh := meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 2, 3})
+	mesh.Triangles.Triangle = append(mesh.Triangles.Triangle, go3mf.Triangle{V1: 0, V2: 1, V3: 100})
+	tree := New(mesh)
+	if tree.Mesh() != mesh {
+		t.Error("New() mesh is not kept")
+	}
+	if want := (go3mf.Box{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{1, 2, 3}}); tree.Box() != want {
+		t.Errorf("Tree.Box() = %v, want %v", tree.Box(), want)
+	}
+	if len(tree.h.prims) != 12 {
+		t.Errorf("New() primitives = %d, want 12", len(tree.h.prims))
+	}
+	empty := New(new(go3mf.Mesh))
+	if empty.Box() != (go3mf.Box{}) {
+		t.Errorf("Tree.Box() = %v", empty.Box())
+	}
+	if _, ok := empty.Intersect(Ray{Direction: go3mf.Point3D{1, 0, 0}}); ok {
+		t.Error("Tree.Intersect() hit an empty tree")
+	}
+	if _, ok := empty.ClosestPoint(go3mf.Point3D{}); ok {
+		t.Error("Tree.ClosestPoint() found a point in an empty tree")
+	}
+}
+
+func TestTree_Intersect(t *testing.T) {
+	tree := New(meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 1, 1}))
+	tests := []struct {
+		name   string
+		r      Ray
+		want   Hit
+		wantOk bool
+	}{
+		{"front", Ray{go3mf.Point3D{-1, 0.5, 0.25}, go3mf.Point3D{1, 0, 0}}, Hit{Distance: 1, Point: go3mf.Point3D{0, 0.5, 0.25}, Normal: go3mf.Point3D{-1, 0, 0}}, true},
+		{"inside", Ray{go3mf.Point3D{0.5, 0.25, 0.5}, go3mf.Point3D{0, 0, 2}}, Hit{Distance: 0.25, Point: go3mf.Point3D{0.5, 0.25, 1}, Normal: go3mf.Point3D{0, 0, 1}}, true},
+		{"miss", Ray{go3mf.Point3D{-1, 0.5, 0.5}, go3mf.Point3D{-1, 0, 0}}, Hit{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tree.Intersect(tt.r)
+			if ok != tt.wantOk {
+				t.Fatalf("Tree.Intersect() ok = %v, want %v", ok, tt.wantOk)
+			}
+			got.Triangle = 0
+			if got != tt.want {
+				t.Errorf("Tree.Intersect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTree_Inside(t *testing.T) {
+	tree := New(meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 1, 1}))
+	tests := []struct {
+		p    go3mf.Point3D
+		want bool
+	}{
+		{go3mf.Point3D{0.5, 0.5, 0.5}, true},
+		{go3mf.Point3D{0.01, 0.99, 0.5}, true},
+		{go3mf.Point3D{1.5, 0.5, 0.5}, false},
+		{go3mf.Point3D{-0.01, 0.5, 0.5}, false},
+		{go3mf.Point3D{5, 5, 5}, false},
+	}
+	for _, tt := range tests {
+		if got := tree.Inside(tt.p); got != tt.want {
+			t.Errorf("Tree.Inside(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestTree_ClosestPoint(t *testing.T) {
+	tree := New(meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 1, 1}))
+	tests := []struct {
+		name string
+		p    go3mf.Point3D
+		want go3mf.Point3D
+		dist float32
+	}{
+		{"outside", go3mf.Point3D{0.5, 0.5, 3}, go3mf.Point3D{0.5, 0.5, 1}, 2},
+		{"inside", go3mf.Point3D{0.5, 0.5, 0.1}, go3mf.Point3D{0.5, 0.5, 0}, 0.1},
+		{"corner", go3mf.Point3D{2, 2, 2}, go3mf.Point3D{1, 1, 1}, float32(math.Sqrt(3))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tree.ClosestPoint(tt.p)
+			if !ok {
+				t.Fatal("Tree.ClosestPoint() not found")
+			}
+			if sqDistance(got.Point, tt.want) > 1e-10 || math.Abs(float64(got.Distance-tt.dist)) > 1e-6 {
+				t.Errorf("Tree.ClosestPoint() = %v, want %v at %v", got, tt.want, tt.dist)
+			}
+		})
+	}
+}
+
+// TestTree_bruteForce compares the tree queries with a loop over all the triangles.
+func TestTree_bruteForce(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	mesh := createSoup(rnd, 500)
+	tree := New(mesh)
+	vs := mesh.Vertices.Vertex
+	for i := 0; i < 200; i++ {
+		r := Ray{Origin: randomPoint(rnd), Direction: randomPoint(rnd).Sub(go3mf.Point3D{0.5, 0.5, 0.5})}
+		want, wantOk := float32(math.MaxFloat32), false
+		for _, tr := range mesh.Triangles.Triangle {
+			if d, ok := intersectTriangle(r, vs[tr.V1], vs[tr.V2], vs[tr.V3]); ok && d < want {
+				want, wantOk = d, true
+			}
+		}
+		got, ok := tree.Intersect(r)
+		if ok != wantOk || (ok && got.Distance != want) {
+			t.Fatalf("Tree.Intersect(%v) = %v, %v, want %v, %v", r, got.Distance, ok, want, wantOk)
+		}
+
+		p := randomPoint(rnd)
+		wantSq := float32(math.MaxFloat32)
+		for _, tr := range mesh.Triangles.Triangle {
+			if d := sqDistance(p, closestPointTriangle(p, vs[tr.V1], vs[tr.V2], vs[tr.V3])); d < wantSq {
+				wantSq = d
+			}
+		}
+		hit, _ := tree.ClosestPoint(p)
+		if math.Abs(float64(hit.Distance*hit.Distance-wantSq)) > 1e-5 {
+			t.Fatalf("Tree.ClosestPoint(%v) = %v, want %v", p, hit.Distance*hit.Distance, wantSq)
+		}
+	}
+}
+
+func Test_buildHierarchy(t *testing.T) {
+	rnd := rand.New(rand.NewSource(2))
+	mesh := createSoup(rnd, 100)
+	tree := New(mesh)
+	// Every primitive is in exactly one leaf, and the leaves are inside their parents.
+	seen := make(map[int32]bool)
+	var walk func(i int32, parent go3mf.Box)
+	walk = func(i int32, parent go3mf.Box) {
+		n := tree.h.nodes[i]
+		if unionBox(parent, n.box) != parent {
+			t.Errorf("node %d box %v is not inside %v", i, n.box, parent)
+		}
+		if n.count > 0 {
+			if n.count > leafSize {
+				t.Errorf("leaf %d has %d primitives", i, n.count)
+			}
+			for _, p := range tree.h.prims[n.start : n.start+n.count] {
+				if seen[p] {
+					t.Errorf("primitive %d is duplicated", p)
+				}
+				seen[p] = true
+			}
+			return
+		}
+		walk(i+1, n.box)
+		walk(n.start, n.box)
+	}
+	walk(0, tree.Box())
+	if len(seen) != 100 {
+		t.Errorf("hierarchy has %d primitives, want 100", len(seen))
+	}
+}
@@ -0,0 +1,187 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+// A Ray is a half-line starting at Origin.
+// Distances along the ray are measured in units of Direction,
+// so they are euclidean distances when Direction is normalized.
+type Ray struct {
+	Origin    go3mf.Point3D
+	Direction go3mf.Point3D
+}
+
+// At returns the point of the ray at distance t.
+func (r Ray) At(t float32) go3mf.Point3D {
+	return r.Origin.Add(r.Direction.Mul(t))
+}
+
+// transform returns the ray in the space defined by m.
+// Affine transforms keep the distances along the ray.
+func (r Ray) transform(m go3mf.Matrix) Ray {
+	return Ray{
+		Origin:    m.Mul3D(r.Origin),
+		Direction: m.Mul3D(r.Direction).Sub(m.Mul3D(go3mf.Point3D{})),
+	}
+}
+
+// invDirection precomputes the inverse of the ray direction for the slab test.
+func (r Ray) invDirection() go3mf.Point3D {
+	return go3mf.Point3D{1 / r.Direction[0], 1 / r.Direction[1], 1 / r.Direction[2]}
+}
+
+// intersectBox returns the distance at which the ray enters the box,
+// and false if it misses the box or enters it after maxT.
+func intersectBox(b go3mf.Box, origin, inv go3mf.Point3D, maxT float32) (float32, bool) {
+	tmin, tmax := float32(0), maxT
+	for i := 0; i < 3; i++ {
+		t1 := (b.Min[i] - origin[i]) * inv[i]
+		t2 := (b.Max[i] - origin[i]) * inv[i]
+		// NaN appears when the origin is on a slab boundary and the ray is parallel to it.
+		if t1 != t1 || t2 != t2 {
+			continue
+		}
+		if t1 > t2 {
+			t1, t2 = t2, t1
+		}
+		if t1 > tmin {
+			tmin = t1
+		}
+		if t2 < tmax {
+			tmax = t2
+		}
+		if tmin > tmax {
+			return 0, false
+		}
+	}
+	return tmin, true
+}
+
+// intersectTriangle returns the distance at which the ray hits the triangle.
+func intersectTriangle(r Ray, v1, v2, v3 go3mf.Point3D) (float32, bool) {
+	e1, e2 := v2.Sub(v1), v3.Sub(v1)
+	h := r.Direction.Cross(e2)
+	a := e1.Dot(h)
+	if a == 0 {
+		return 0, false
+	}
+	f := 1 / a
+	s := r.Origin.Sub(v1)
+	u := f * s.Dot(h)
+	if u < 0 || u > 1 {
+		return 0, false
+	}
+	q := s.Cross(e1)
+	v := f * r.Direction.Dot(q)
+	if v < 0 || u+v > 1 {
+		return 0, false
+	}
+	t := f * e2.Dot(q)
+	return t, t >= 0
+}
+
+// closestPointTriangle returns the point of the triangle closest to p.
+func closestPointTriangle(p, a, b, c go3mf.Point3D) go3mf.Point3D {
+	ab, ac, ap := b.Sub(a), c.Sub(a), p.Sub(a)
+	d1, d2 := ab.Dot(ap), ac.Dot(ap)
+	if d1 <= 0 && d2 <= 0 {
+		return a
+	}
+	bp := p.Sub(b)
+	d3, d4 := ab.Dot(bp), ac.Dot(bp)
+	if d3 >= 0 && d4 <= d3 {
+		return b
+	}
+	vc := d1*d4 - d3*d2
+	if vc <= 0 && d1 >= 0 && d3 <= 0 {
+		return a.Add(ab.Mul(d1 / (d1 - d3)))
+	}
+	cp := p.Sub(c)
+	d5, d6 := ab.Dot(cp), ac.Dot(cp)
+	if d6 >= 0 && d5 <= d6 {
+		return c
+	}
+	vb := d5*d2 - d1*d6
+	if vb <= 0 && d2 >= 0 && d6 <= 0 {
+		return a.Add(ac.Mul(d2 / (d2 - d6)))
+	}
+	va := d3*d6 - d5*d4
+	if va <= 0 && d4-d3 >= 0 && d5-d6 >= 0 {
+		return b.Add(c.Sub(b).Mul((d4 - d3) / ((d4 - d3) + (d5 - d6))))
+	}
+	denom := 1 / (va + vb + vc)
+	return a.Add(ab.Mul(vb * denom)).Add(ac.Mul(vc * denom))
+}
+
+// sqDistanceBox returns the squared distance from p to the box,
+// zero if p is inside.
+func sqDistanceBox(b go3mf.Box, p go3mf.Point3D) float32 {
+	var d float32
+	for i := 0; i < 3; i++ {
+		if p[i] < b.Min[i] {
+			d += (b.Min[i] - p[i]) * (b.Min[i] - p[i])
+		} else if p[i] > b.Max[i] {
+			d += (p[i] - b.Max[i]) * (p[i] - b.Max[i])
+		}
+	}
+	return d
+}
+
+func sqDistance(a, b go3mf.Point3D) float32 {
+	d := a.Sub(b)
+	return d.Dot(d)
+}
+
+// transformBox returns the box containing the transformed corners of b.
+func transformBox(m go3mf.Matrix, b go3mf.Box) go3mf.Box {
+	out := emptyBox()
+	for i := 0; i < 8; i++ {
+		corner := b.Min
+		for j := 0; j < 3; j++ {
+			if i&(1<<uint(j)) != 0 {
+				corner[j] = b.Max[j]
+			}
+		}
+		out = extendBox(out, m.Mul3D(corner))
+	}
+	return out
+}
+
+func emptyBox() go3mf.Box {
+	return go3mf.Box{
+		Min: go3mf.Point3D{math.MaxFloat32, math.MaxFloat32, math.MaxFloat32},
+		Max: go3mf.Point3D{-math.MaxFloat32, -math.MaxFloat32, -math.MaxFloat32},
+	}
+}
+
+func extendBox(b go3mf.Box, p go3mf.Point3D) go3mf.Box {
+	for i := 0; i < 3; i++ {
+		if p[i] < b.Min[i] {
+			b.Min[i] = p[i]
+		}
+		if p[i] > b.Max[i] {
+			b.Max[i] = p[i]
+		}
+	}
+	return b
+}
+
+func unionBox(a, b go3mf.Box) go3mf.Box {
+	return extendBox(extendBox(a, b.Min), b.Max)
+}
+
+// linearNorm returns the Frobenius norm of the linear part of m,
+// which bounds how much m can stretch a distance.
+func linearNorm(m go3mf.Matrix) float32 {
+	var s float64
+	for _, i := range [9]int{0, 1, 2, 4, 5, 6, 8, 9, 10} {
+		s += float64(m[i]) * float64(m[i])
+	}
+	return float32(math.Sqrt(s))
+}
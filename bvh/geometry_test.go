@@ -0,0 +1,117 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func TestRay_At(t *testing.T) {
+	r := Ray{Origin: go3mf.Point3D{1, 2, 3}, Direction: go3mf.Point3D{0, 0, 2}}
+	if got := r.At(1.5); got != (go3mf.Point3D{1, 2, 6}) {
+		t.Errorf("Ray.At() = %v", got)
+	}
+}
+
+func TestRay_transform(t *testing.T) {
+	m := go3mf.Matrix{0, 2, 0, 0, -2, 0, 0, 0, 0, 0, 2, 0, 1, 2, 3, 1}
+	r := Ray{Origin: go3mf.Point3D{1, 1, 1}, Direction: go3mf.Point3D{1, 0, 0}}
+	got := r.transform(m)
+	// Distances along the ray are kept.
+	if p := got.At(3); p != m.Mul3D(r.At(3)) {
+		t.Errorf("Ray.transform() = %v, want %v", p, m.Mul3D(r.At(3)))
+	}
+}
+
+func Test_intersectBox(t *testing.T) {
+	box := go3mf.Box{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{1, 1, 1}}
+	tests := []struct {
+		name   string
+		r      Ray
+		maxT   float32
+		want   float32
+		wantOk bool
+	}{
+		{"front", Ray{go3mf.Point3D{-1, 0.5, 0.5}, go3mf.Point3D{1, 0, 0}}, 10, 1, true},
+		{"back", Ray{go3mf.Point3D{2, 0.5, 0.5}, go3mf.Point3D{1, 0, 0}}, 10, 0, false},
+		{"inside", Ray{go3mf.Point3D{0.5, 0.5, 0.5}, go3mf.Point3D{0, 1, 0}}, 10, 0, true},
+		{"far", Ray{go3mf.Point3D{-1, 0.5, 0.5}, go3mf.Point3D{1, 0, 0}}, 0.5, 0, false},
+		{"miss", Ray{go3mf.Point3D{-1, 2, 0.5}, go3mf.Point3D{1, 0, 0}}, 10, 0, false},
+		{"boundary", Ray{go3mf.Point3D{-1, 0, 0.5}, go3mf.Point3D{1, 0, 0}}, 10, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := intersectBox(box, tt.r.Origin, tt.r.invDirection(), tt.maxT)
+			if ok != tt.wantOk || (ok && got != tt.want) {
+				t.Errorf("intersectBox() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_intersectTriangle(t *testing.T) {
+	v1, v2, v3 := go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 0, 0}, go3mf.Point3D{0, 1, 0}
+	tests := []struct {
+		name   string
+		r      Ray
+		want   float32
+		wantOk bool
+	}{
+		{"hit", Ray{go3mf.Point3D{0.25, 0.25, 1}, go3mf.Point3D{0, 0, -1}}, 1, true},
+		{"back", Ray{go3mf.Point3D{0.25, 0.25, -1}, go3mf.Point3D{0, 0, -1}}, 0, false},
+		{"outside", Ray{go3mf.Point3D{1, 1, 1}, go3mf.Point3D{0, 0, -1}}, 0, false},
+		{"parallel", Ray{go3mf.Point3D{0.25, 0.25, 1}, go3mf.Point3D{1, 0, 0}}, 0, false},
+		{"scaled", Ray{go3mf.Point3D{0.25, 0.25, 1}, go3mf.Point3D{0, 0, -2}}, 0.5, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := intersectTriangle(tt.r, v1, v2, v3)
+			if ok != tt.wantOk || (ok && got != tt.want) {
+				t.Errorf("intersectTriangle() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_closestPointTriangle(t *testing.T) {
+	a, b, c := go3mf.Point3D{0, 0, 0}, go3mf.Point3D{2, 0, 0}, go3mf.Point3D{0, 2, 0}
+	tests := []struct {
+		name string
+		p    go3mf.Point3D
+		want go3mf.Point3D
+	}{
+		{"face", go3mf.Point3D{0.5, 0.5, 3}, go3mf.Point3D{0.5, 0.5, 0}},
+		{"a", go3mf.Point3D{-1, -1, 1}, a},
+		{"b", go3mf.Point3D{3, -1, 0}, b},
+		{"c", go3mf.Point3D{-1, 3, 0}, c},
+		{"ab", go3mf.Point3D{1, -1, 0}, go3mf.Point3D{1, 0, 0}},
+		{"ac", go3mf.Point3D{-1, 1, 0}, go3mf.Point3D{0, 1, 0}},
+		{"bc", go3mf.Point3D{2, 2, 0}, go3mf.Point3D{1, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := closestPointTriangle(tt.p, a, b, c); sqDistance(got, tt.want) > 1e-10 {
+				t.Errorf("closestPointTriangle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_transformBox(t *testing.T) {
+	// A rotation of 90 degrees around z.
+	m := go3mf.Matrix{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+	got := transformBox(m, go3mf.Box{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{2, 1, 1}})
+	if want := (go3mf.Box{Min: go3mf.Point3D{-1, 0, 0}, Max: go3mf.Point3D{0, 2, 1}}); got != want {
+		t.Errorf("transformBox() = %v, want %v", got, want)
+	}
+}
+
+func Test_linearNorm(t *testing.T) {
+	if got := linearNorm(go3mf.Identity().Translate(5, 5, 5)); math.Abs(float64(got)-math.Sqrt(3)) > 1e-6 {
+		t.Errorf("linearNorm() = %v, want %v", got, math.Sqrt(3))
+	}
+}
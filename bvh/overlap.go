@@ -0,0 +1,192 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+// Overlap returns true if the instances i and j intersect,
+// either because their surfaces cross or touch, or because
+// one of them is inside the other.
+//
+// The containment test checks a single vertex of each instance,
+// so it assumes that the meshes are closed and connected.
+func (s *Scene) Overlap(i, j int) bool {
+	a, b := &s.instances[i], &s.instances[j]
+	if len(a.Tree.h.nodes) == 0 || len(b.Tree.h.nodes) == 0 || !boxesOverlap(a.box, b.box) {
+		return false
+	}
+	// Transform from the b tree space to the a tree space, and vice versa.
+	ab, ba := a.inverse.Mul(b.Transform), b.inverse.Mul(a.Transform)
+	if treesIntersect(a.Tree, b.Tree, ab) {
+		return true
+	}
+	return a.Tree.Inside(ab.Mul3D(b.Tree.anyVertex())) || b.Tree.Inside(ba.Mul3D(a.Tree.anyVertex()))
+}
+
+// anyVertex returns the first vertex of an indexed triangle.
+func (t *Tree) anyVertex() go3mf.Point3D {
+	v, _, _ := t.triangle(t.h.prims[0])
+	return v
+}
+
+// treesIntersect returns true if any triangle of a intersects any triangle of b,
+// being m the transform from the b space to the a space.
+func treesIntersect(a, b *Tree, m go3mf.Matrix) bool {
+	type pair struct{ a, b int32 }
+	stack := []pair{{0, 0}}
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		na, nb := &a.h.nodes[p.a], &b.h.nodes[p.b]
+		bbox := transformBox(m, nb.box)
+		if !boxesOverlap(na.box, bbox) {
+			continue
+		}
+		switch {
+		case na.count > 0 && nb.count > 0:
+			for _, ta := range a.h.prims[na.start : na.start+na.count] {
+				a1, a2, a3 := a.triangle(ta)
+				for _, tb := range b.h.prims[nb.start : nb.start+nb.count] {
+					b1, b2, b3 := b.triangle(tb)
+					if trianglesIntersect([3]go3mf.Point3D{a1, a2, a3}, [3]go3mf.Point3D{m.Mul3D(b1), m.Mul3D(b2), m.Mul3D(b3)}) {
+						return true
+					}
+				}
+			}
+		case nb.count > 0 || (na.count == 0 && boxVolume(na.box) > boxVolume(bbox)):
+			stack = append(stack, pair{p.a + 1, p.b}, pair{na.start, p.b})
+		default:
+			stack = append(stack, pair{p.a, p.b + 1}, pair{p.a, nb.start})
+		}
+	}
+	return false
+}
+
+func boxesOverlap(a, b go3mf.Box) bool {
+	for i := 0; i < 3; i++ {
+		if a.Max[i] < b.Min[i] || b.Max[i] < a.Min[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func boxVolume(b go3mf.Box) float32 {
+	d := b.Max.Sub(b.Min)
+	return d[0] * d[1] * d[2]
+}
+
+// trianglesIntersect returns true if the triangles cross or touch.
+func trianglesIntersect(a, b [3]go3mf.Point3D) bool {
+	n := b[1].Sub(b[0]).Cross(b[2].Sub(b[0]))
+	if isCoplanar(a, b[0], n) {
+		return coplanarIntersect(a, b, n)
+	}
+	for i := 0; i < 3; i++ {
+		if segmentTriangle(a[i], a[(i+1)%3], b) || segmentTriangle(b[i], b[(i+1)%3], a) {
+			return true
+		}
+	}
+	return false
+}
+
+// isCoplanar returns true if the triangle t is on the plane with normal n that contains p.
+func isCoplanar(t [3]go3mf.Point3D, p, n go3mf.Point3D) bool {
+	l := n.Len()
+	if l == 0 {
+		return false
+	}
+	var size float32
+	for _, v := range t {
+		size = float32(math.Max(float64(size), float64(v.Sub(p).Len())))
+	}
+	tol := 1e-6 * l * size
+	for _, v := range t {
+		if math.Abs(float64(n.Dot(v.Sub(p)))) > float64(tol) {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentTriangle returns true if the segment from p to q crosses or touches the triangle.
+// Segments parallel to the triangle plane never cross it.
+func segmentTriangle(p, q go3mf.Point3D, t [3]go3mf.Point3D) bool {
+	d := q.Sub(p)
+	e1, e2 := t[1].Sub(t[0]), t[2].Sub(t[0])
+	h := d.Cross(e2)
+	a := e1.Dot(h)
+	if a == 0 {
+		return false
+	}
+	f := 1 / a
+	s := p.Sub(t[0])
+	u := f * s.Dot(h)
+	if u < 0 || u > 1 {
+		return false
+	}
+	r := s.Cross(e1)
+	v := f * d.Dot(r)
+	if v < 0 || u+v > 1 {
+		return false
+	}
+	w := f * e2.Dot(r)
+	return w >= 0 && w <= 1
+}
+
+// coplanarIntersect returns true if the coplanar triangles overlap,
+// projecting them to the plane of the axes where n is longer.
+func coplanarIntersect(a, b [3]go3mf.Point3D, n go3mf.Point3D) bool {
+	x, y := 1, 2
+	if math.Abs(float64(n[1])) > math.Abs(float64(n[0])) && math.Abs(float64(n[1])) >= math.Abs(float64(n[2])) {
+		x, y = 0, 2
+	} else if math.Abs(float64(n[2])) > math.Abs(float64(n[0])) {
+		x, y = 0, 1
+	}
+	var pa, pb [3][2]float32
+	for i := 0; i < 3; i++ {
+		pa[i] = [2]float32{a[i][x], a[i][y]}
+		pb[i] = [2]float32{b[i][x], b[i][y]}
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if segmentsIntersect(pa[i], pa[(i+1)%3], pb[j], pb[(j+1)%3]) {
+				return true
+			}
+		}
+	}
+	return pointInTriangle(pa[0], pb) || pointInTriangle(pb[0], pa)
+}
+
+func orient(a, b, c [2]float32) float32 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func onSegment(a, b, p [2]float32) bool {
+	return math.Min(float64(a[0]), float64(b[0])) <= float64(p[0]) && float64(p[0]) <= math.Max(float64(a[0]), float64(b[0])) &&
+		math.Min(float64(a[1]), float64(b[1])) <= float64(p[1]) && float64(p[1]) <= math.Max(float64(a[1]), float64(b[1]))
+}
+
+// segmentsIntersect returns true if the segments p1-p2 and q1-q2 cross or touch.
+func segmentsIntersect(p1, p2, q1, q2 [2]float32) bool {
+	d1, d2 := orient(q1, q2, p1), orient(q1, q2, p2)
+	d3, d4 := orient(p1, p2, q1), orient(p1, p2, q2)
+	if ((d1 > 0 && d2 < 0) || (d1 < 0 && d2 > 0)) && ((d3 > 0 && d4 < 0) || (d3 < 0 && d4 > 0)) {
+		return true
+	}
+	return (d1 == 0 && onSegment(q1, q2, p1)) || (d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) || (d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// pointInTriangle returns true if p is inside or on the border of the triangle t.
+func pointInTriangle(p [2]float32, t [3][2]float32) bool {
+	d1, d2, d3 := orient(t[0], t[1], p), orient(t[1], t[2], p), orient(t[2], t[0], p)
+	neg := d1 < 0 || d2 < 0 || d3 < 0
+	pos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(neg && pos)
+}
@@ -0,0 +1,77 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/internal/meshtest"
+)
+
+func TestScene_Overlap(t *testing.T) {
+	cube := New(meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 1, 1}))
+	small := New(meshtest.Box(go3mf.Point3D{0.4, 0.4, 0.4}, go3mf.Point3D{0.6, 0.6, 0.6}))
+	// A rotation of 45 degrees around z.
+	c, s := float32(0.70710678), float32(0.70710678)
+	rot := go3mf.Matrix{c, s, 0, 0, -s, c, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+	tests := []struct {
+		name string
+		a, b *Tree
+		ta   go3mf.Matrix
+		tb   go3mf.Matrix
+		want bool
+	}{
+		{"apart", cube, cube, go3mf.Identity(), go3mf.Identity().Translate(2, 0, 0), false},
+		{"crossing", cube, cube, go3mf.Identity(), go3mf.Identity().Translate(0.5, 0.5, 0.5), true},
+		{"touching", cube, cube, go3mf.Identity(), go3mf.Identity().Translate(1, 0, 0), true},
+		{"inside", cube, small, go3mf.Identity(), go3mf.Identity(), true},
+		{"outside", small, cube, go3mf.Identity(), go3mf.Identity(), true},
+		// The boxes overlap but the rotated cube corner does not reach the other cube.
+		{"rotated", cube, cube, go3mf.Identity(), rot.Translate(1.6, 0.5, 0), false},
+		{"rotatedCrossing", cube, cube, go3mf.Identity(), rot.Translate(1.2, 0.5, 0), true},
+		{"empty", cube, New(new(go3mf.Mesh)), go3mf.Identity(), go3mf.Identity(), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := new(Scene)
+			i, _ := sc.Add(tt.a, tt.ta)
+			j, _ := sc.Add(tt.b, tt.tb)
+			if got := sc.Overlap(i, j); got != tt.want {
+				t.Errorf("Scene.Overlap() = %v, want %v", got, tt.want)
+			}
+			if got := sc.Overlap(j, i); got != tt.want {
+				t.Errorf("Scene.Overlap() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_trianglesIntersect(t *testing.T) {
+	base := [3]go3mf.Point3D{{0, 0, 0}, {2, 0, 0}, {0, 2, 0}}
+	tests := []struct {
+		name string
+		b    [3]go3mf.Point3D
+		want bool
+	}{
+		{"crossing", [3]go3mf.Point3D{{0.5, 0.5, -1}, {0.5, 0.5, 1}, {1, 0.2, 1}}, true},
+		{"above", [3]go3mf.Point3D{{0.5, 0.5, 1}, {1, 0.5, 1}, {0.5, 1, 2}}, false},
+		{"piercing", [3]go3mf.Point3D{{-1, 0.5, -1}, {3, 0.5, -1}, {1, 0.5, 1}}, true},
+		{"vertex", [3]go3mf.Point3D{{2, 0, 0}, {3, 0, 1}, {3, 1, 1}}, true},
+		{"coplanarOverlap", [3]go3mf.Point3D{{0.5, 0.5, 0}, {3, 0.5, 0}, {0.5, 3, 0}}, true},
+		{"coplanarInside", [3]go3mf.Point3D{{0.1, 0.1, 0}, {0.5, 0.1, 0}, {0.1, 0.5, 0}}, true},
+		{"coplanarContains", [3]go3mf.Point3D{{-1, -1, 0}, {5, -1, 0}, {-1, 5, 0}}, true},
+		{"coplanarApart", [3]go3mf.Point3D{{2, 2, 0}, {3, 2, 0}, {2, 3, 0}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trianglesIntersect(base, tt.b); got != tt.want {
+				t.Errorf("trianglesIntersect() = %v, want %v", got, tt.want)
+			}
+			if got := trianglesIntersect(tt.b, base); got != tt.want {
+				t.Errorf("trianglesIntersect() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
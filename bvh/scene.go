@@ -0,0 +1,189 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"errors"
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+// ErrSingularTransform is returned when an instance transform is not invertible.
+var ErrSingularTransform = errors.New("bvh: transform is not invertible")
+
+// An Instance places a tree in the scene.
+type Instance struct {
+	Tree      *Tree
+	Transform go3mf.Matrix
+	// Object and Path identify the mesh object, when the instance
+	// comes from a model. Item is the build item that references it.
+	Object *go3mf.Object
+	Path   string
+	Item   *go3mf.Item
+
+	inverse go3mf.Matrix
+	box     go3mf.Box
+	// scale is the minimum stretch factor of the transform.
+	scale float32
+}
+
+// Box returns the bounding box of the instance in scene coordinates.
+func (in *Instance) Box() go3mf.Box {
+	return in.box
+}
+
+// A Scene is a set of transformed trees with a hierarchy over their bounding boxes.
+// Trees are shared by all the instances of the same mesh, so instancing
+// does not copy the mesh vertices.
+type Scene struct {
+	instances []Instance
+	trees     map[*go3mf.Mesh]*Tree
+	h         hierarchy
+	dirty     bool
+}
+
+// NewScene returns the scene of the build items of m,
+// following components recursively and applying the item and component transforms.
+func NewScene(m *go3mf.Model) (*Scene, error) {
+	s := new(Scene)
+	for _, item := range m.Build.Items {
+		if err := m.WalkItemMeshes(item, s.addMesh(item)); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// Add adds an instance of tree and returns its index.
+func (s *Scene) Add(tree *Tree, t go3mf.Matrix) (int, error) {
+	return s.add(Instance{Tree: tree, Transform: t.OrIdentity()})
+}
+
+// AddObject adds the meshes of the object o, defined in the model part path,
+// following its components recursively.
+func (s *Scene) AddObject(m *go3mf.Model, path string, o *go3mf.Object, t go3mf.Matrix) error {
+	return m.WalkMeshes(path, o, t.OrIdentity(), s.addMesh(nil))
+}
+
+// Instances returns the scene instances.
+func (s *Scene) Instances() []Instance {
+	return s.instances
+}
+
+// Box returns the bounding box of the scene.
+func (s *Scene) Box() go3mf.Box {
+	s.build()
+	if len(s.h.nodes) == 0 {
+		return go3mf.Box{}
+	}
+	return s.h.nodes[0].box
+}
+
+// Intersect returns the closest triangle hit by the ray.
+func (s *Scene) Intersect(r Ray) (Hit, bool) {
+	s.build()
+	best := Hit{Instance: -1}
+	s.h.raycast(r, math.MaxFloat32, func(prim int32, maxT float32) float32 {
+		in := &s.instances[prim]
+		if hit, ok := in.Tree.intersect(r.transform(in.inverse), maxT); ok {
+			best = hit
+			best.Instance = int(prim)
+			return hit.Distance
+		}
+		return maxT
+	})
+	if best.Instance < 0 {
+		return Hit{}, false
+	}
+	in := &s.instances[best.Instance]
+	v1, v2, v3 := in.Tree.triangle(int32(best.Triangle))
+	v1, v2, v3 = in.Transform.Mul3D(v1), in.Transform.Mul3D(v2), in.Transform.Mul3D(v3)
+	best.Point = r.At(best.Distance)
+	best.Normal = v2.Sub(v1).Cross(v3.Sub(v1)).Normalize()
+	return best, true
+}
+
+// Inside returns true if p is inside any instance.
+func (s *Scene) Inside(p go3mf.Point3D) bool {
+	s.build()
+	var inside bool
+	// Only the instances whose box contains p are visited.
+	s.h.nearest(p, math.SmallestNonzeroFloat32, 1, func(prim int32, bestSq float32) float32 {
+		in := &s.instances[prim]
+		if !inside && in.Tree.Inside(in.inverse.Mul3D(p)) {
+			inside = true
+		}
+		return bestSq
+	})
+	return inside
+}
+
+// ClosestPoint returns the point of the instances surface closest to p.
+func (s *Scene) ClosestPoint(p go3mf.Point3D) (Hit, bool) {
+	s.build()
+	best := Hit{Instance: -1, Distance: math.MaxFloat32}
+	s.h.nearest(p, math.MaxFloat32, 1, func(prim int32, bestSq float32) float32 {
+		in := &s.instances[prim]
+		if hit, ok := in.Tree.closest(in.inverse.Mul3D(p), bestSq, in.scale, in.Transform); ok {
+			best = hit
+			best.Instance = int(prim)
+			return hit.Distance
+		}
+		return bestSq
+	})
+	if best.Instance < 0 {
+		return Hit{}, false
+	}
+	best.Distance = float32(math.Sqrt(float64(best.Distance)))
+	return best, true
+}
+
+// addMesh returns a mesh walker that adds an instance of each mesh object,
+// sharing the tree of the meshes already in the scene.
+func (s *Scene) addMesh(item *go3mf.Item) func(string, *go3mf.Object, go3mf.Matrix) error {
+	return func(path string, o *go3mf.Object, t go3mf.Matrix) error {
+		if s.trees == nil {
+			s.trees = make(map[*go3mf.Mesh]*Tree)
+		}
+		tree, ok := s.trees[o.Mesh]
+		if !ok {
+			tree = New(o.Mesh)
+			s.trees[o.Mesh] = tree
+		}
+		_, err := s.add(Instance{Tree: tree, Transform: t, Object: o, Path: path, Item: item})
+		return err
+	}
+}
+
+func (s *Scene) add(in Instance) (int, error) {
+	inv, ok := in.Transform.Inverse()
+	if !ok {
+		return -1, ErrSingularTransform
+	}
+	in.inverse = inv
+	in.box = transformBox(in.Transform, in.Tree.Box())
+	in.scale = 1 / linearNorm(inv)
+	s.instances = append(s.instances, in)
+	s.dirty = true
+	return len(s.instances) - 1, nil
+}
+
+// build updates the hierarchy of the instances when they have changed.
+func (s *Scene) build() {
+	if !s.dirty {
+		return
+	}
+	boxes := make([]go3mf.Box, 0, len(s.instances))
+	ids := make([]int32, 0, len(s.instances))
+	for i := range s.instances {
+		if len(s.instances[i].Tree.h.nodes) == 0 {
+			continue
+		}
+		boxes = append(boxes, s.instances[i].box)
+		ids = append(ids, int32(i))
+	}
+	s.h = buildHierarchy(boxes, ids)
+	s.dirty = false
+}
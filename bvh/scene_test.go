@@ -0,0 +1,173 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package bvh
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/internal/meshtest"
+)
+
+// createModel returns a model with a unit cube instanced by two build items,
+// one of them through a component.
+func createModel() *go3mf.Model {
+	m := new(go3mf.Model)
+	m.Resources.Objects = append(m.Resources.Objects,
+		&go3mf.Object{ID: 1, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{1, 1, 1})},
+		&go3mf.Object{ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{
+			{ObjectID: 1, Transform: go3mf.Identity().Translate(0, 0, 10)},
+			{ObjectID: 5},
+		}}},
+	)
+	m.Build.Items = append(m.Build.Items,
+		&go3mf.Item{ObjectID: 1},
+		&go3mf.Item{ObjectID: 2, Transform: go3mf.Identity().Translate(5, 0, 0)},
+		&go3mf.Item{ObjectID: 3},
+	)
+	return m
+}
+
+func TestNewScene(t *testing.T) {
+	m := createModel()
+	s, err := NewScene(m)
+	if err != nil {
+		t.Fatalf("NewScene() error = %v", err)
+	}
+	ins := s.Instances()
+	if len(ins) != 2 {
+		t.Fatalf("NewScene() instances = %d, want 2", len(ins))
+	}
+	if ins[0].Tree != ins[1].Tree {
+		t.Error("NewScene() instances do not share the tree")
+	}
+	if ins[1].Item != m.Build.Items[1] || ins[1].Object != m.Resources.Objects[0] {
+		t.Errorf("NewScene() instance = %v", ins[1])
+	}
+	if want := (go3mf.Box{Min: go3mf.Point3D{5, 0, 10}, Max: go3mf.Point3D{6, 1, 11}}); ins[1].Box() != want {
+		t.Errorf("Instance.Box() = %v, want %v", ins[1].Box(), want)
+	}
+	if want := (go3mf.Box{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{6, 1, 11}}); s.Box() != want {
+		t.Errorf("Scene.Box() = %v, want %v", s.Box(), want)
+	}
+
+	m.Build.Items[0].Transform = go3mf.Matrix{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+	if _, err := NewScene(m); err != ErrSingularTransform {
+		t.Errorf("NewScene() error = %v, want %v", err, ErrSingularTransform)
+	}
+}
+
+func TestScene_Intersect(t *testing.T) {
+	s, _ := NewScene(createModel())
+	tests := []struct {
+		name     string
+		r        Ray
+		instance int
+		point    go3mf.Point3D
+		normal   go3mf.Point3D
+		wantOk   bool
+	}{
+		{"first", Ray{go3mf.Point3D{-1, 0.5, 0.5}, go3mf.Point3D{1, 0, 0}}, 0, go3mf.Point3D{0, 0.5, 0.5}, go3mf.Point3D{-1, 0, 0}, true},
+		{"second", Ray{go3mf.Point3D{5.5, 0.5, 20}, go3mf.Point3D{0, 0, -1}}, 1, go3mf.Point3D{5.5, 0.5, 11}, go3mf.Point3D{0, 0, 1}, true},
+		{"closest", Ray{go3mf.Point3D{10, 0.5, 0.5}, go3mf.Point3D{-1, 0, 0}}, 0, go3mf.Point3D{1, 0.5, 0.5}, go3mf.Point3D{1, 0, 0}, true},
+		{"miss", Ray{go3mf.Point3D{10, 0.5, 10.5}, go3mf.Point3D{1, 0, 0}}, 0, go3mf.Point3D{}, go3mf.Point3D{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := s.Intersect(tt.r)
+			if ok != tt.wantOk {
+				t.Fatalf("Scene.Intersect() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			if got.Instance != tt.instance || got.Point != tt.point || got.Normal != tt.normal {
+				t.Errorf("Scene.Intersect() = %v", got)
+			}
+		})
+	}
+}
+
+func TestScene_Inside(t *testing.T) {
+	s, _ := NewScene(createModel())
+	tests := []struct {
+		p    go3mf.Point3D
+		want bool
+	}{
+		{go3mf.Point3D{0.5, 0.5, 0.5}, true},
+		{go3mf.Point3D{5.5, 0.5, 10.5}, true},
+		{go3mf.Point3D{5.5, 0.5, 0.5}, false},
+		{go3mf.Point3D{3, 3, 3}, false},
+	}
+	for _, tt := range tests {
+		if got := s.Inside(tt.p); got != tt.want {
+			t.Errorf("Scene.Inside(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestScene_ClosestPoint(t *testing.T) {
+	s := new(Scene)
+	if _, ok := s.ClosestPoint(go3mf.Point3D{}); ok {
+		t.Error("Scene.ClosestPoint() found a point in an empty scene")
+	}
+	rnd := rand.New(rand.NewSource(3))
+	mesh := createSoup(rnd, 200)
+	tree := New(mesh)
+	// Rotations, non uniform scales and shears.
+	transforms := []go3mf.Matrix{
+		go3mf.Identity(),
+		{0, 1, 0, 0, -1, 0, 0, 0, 0, 0, 1, 0, 2, 0, 0, 1},
+		{3, 0, 0, 0, 0, 0.5, 0, 0, 0, 0, 1, 0, 0, 2, 0, 1},
+		{1, 0.5, 0, 0, 0, 1, 0, 0, 0.3, 0, 2, 0, 1, 1, 1, 1},
+	}
+	for _, tr := range transforms {
+		if _, err := s.Add(tree, tr); err != nil {
+			t.Fatalf("Scene.Add() error = %v", err)
+		}
+	}
+	vs := mesh.Vertices.Vertex
+	for i := 0; i < 100; i++ {
+		p := randomPoint(rnd).Mul(4)
+		wantSq, wantInstance := float32(math.MaxFloat32), -1
+		for j, m := range transforms {
+			for _, tr := range mesh.Triangles.Triangle {
+				v1, v2, v3 := m.Mul3D(vs[tr.V1]), m.Mul3D(vs[tr.V2]), m.Mul3D(vs[tr.V3])
+				if d := sqDistance(p, closestPointTriangle(p, v1, v2, v3)); d < wantSq {
+					wantSq, wantInstance = d, j
+				}
+			}
+		}
+		got, ok := s.ClosestPoint(p)
+		if !ok {
+			t.Fatalf("Scene.ClosestPoint(%v) not found", p)
+		}
+		if math.Abs(float64(got.Distance*got.Distance-wantSq)) > 1e-4 {
+			t.Fatalf("Scene.ClosestPoint(%v) = %v (instance %d), want %v (instance %d)", p, got.Distance*got.Distance, got.Instance, wantSq, wantInstance)
+		}
+		if d := sqDistance(got.Point, p); math.Abs(float64(d-wantSq)) > 1e-4 {
+			t.Errorf("Scene.ClosestPoint(%v) point = %v", p, got.Point)
+		}
+	}
+}
+
+func TestScene_AddObject(t *testing.T) {
+	m := createModel()
+	s := new(Scene)
+	if err := s.AddObject(m, "", m.Resources.Objects[1], go3mf.Matrix{}); err != nil {
+		t.Fatalf("Scene.AddObject() error = %v", err)
+	}
+	if len(s.Instances()) != 1 || s.Instances()[0].Transform != go3mf.Identity().Translate(0, 0, 10) {
+		t.Errorf("Scene.AddObject() instances = %v", s.Instances())
+	}
+	if i, err := s.Add(New(new(go3mf.Mesh)), go3mf.Identity()); err != nil || i != 1 {
+		t.Errorf("Scene.Add() = %d, %v", i, err)
+	}
+	// Empty trees are not part of the hierarchy.
+	if want := (go3mf.Box{Min: go3mf.Point3D{0, 0, 10}, Max: go3mf.Point3D{1, 1, 11}}); s.Box() != want {
+		t.Errorf("Scene.Box() = %v, want %v", s.Box(), want)
+	}
+}
@@ -0,0 +1,169 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	stlexporter "github.com/hpinc/go3mf/exporter/stl"
+	"github.com/hpinc/go3mf/importer/obj"
+	"github.com/hpinc/go3mf/importer/ply"
+	stlimporter "github.com/hpinc/go3mf/importer/stl"
+	"github.com/hpinc/go3mf/thumbnail"
+)
+
+func runConvert(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	ascii := fs.Bool("ascii", false, "write ASCII STL instead of binary")
+	colors := fs.String("colors", "none", "binary STL color mode: none, viscam or magics")
+	units := fs.String("units", "", "convert the model to these units before writing, e.g. millimeter or inch")
+	thumb := fs.Bool("thumbnail", false, "render the package thumbnail when writing 3MF")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: go3mf convert [flags] input output")
+		fmt.Fprintln(stderr, "Input formats: .3mf, .stl, .obj, .ply. Output formats: .3mf, .stl.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	colorMode, ok := map[string]stlexporter.ColorMode{
+		"none":   stlexporter.ColorNone,
+		"viscam": stlexporter.ColorVisCAM,
+		"magics": stlexporter.ColorMagics,
+	}[*colors]
+	if !ok {
+		fmt.Fprintf(stderr, "go3mf: invalid color mode %q\n", *colors)
+		return 2
+	}
+	var model go3mf.Model
+	in, out := fs.Arg(0), fs.Arg(1)
+	if err := decodeFile(in, &model); err != nil {
+		fmt.Fprintf(stderr, "go3mf: %s: %v\n", in, err)
+		return 1
+	}
+	if *units != "" {
+		u, ok := parseUnits(*units)
+		if !ok {
+			fmt.Fprintf(stderr, "go3mf: invalid units %q\n", *units)
+			return 2
+		}
+		if err := model.ConvertUnits(u); err != nil {
+			fmt.Fprintf(stderr, "go3mf: %v\n", err)
+			return 1
+		}
+	}
+	var err error
+	switch ext := strings.ToLower(filepath.Ext(out)); ext {
+	case ".3mf":
+		if *thumb {
+			err = new(thumbnail.Renderer).SetThumbnail(&model)
+		}
+		if err == nil {
+			err = write3MF(out, &model)
+		}
+	case ".stl":
+		format := stlexporter.FormatBinary
+		if *ascii {
+			format = stlexporter.FormatASCII
+		}
+		err = writeSTL(out, &model, format, colorMode)
+	default:
+		err = fmt.Errorf("unsupported output format %q", ext)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "go3mf: %s: %v\n", out, err)
+		return 1
+	}
+	fmt.Fprintf(stdout, "%s -> %s\n", in, out)
+	return 0
+}
+
+// decodeFile reads a 3MF, STL, OBJ or PLY file into m
+// depending on the file extension.
+func decodeFile(name string, m *go3mf.Model) error {
+	ext := strings.ToLower(filepath.Ext(name))
+	switch ext {
+	case ".3mf":
+		r, err := go3mf.OpenReader(name)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		return r.Decode(m)
+	case ".stl", ".obj", ".ply":
+	default:
+		return fmt.Errorf("unsupported input format %q", ext)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	switch ext {
+	case ".stl":
+		return stlimporter.NewDecoder(f).Decode(m)
+	case ".obj":
+		d := obj.NewDecoder(f)
+		dir := filepath.Dir(name)
+		d.OpenFile = func(name string) (io.ReadCloser, error) {
+			return os.Open(filepath.Join(dir, name))
+		}
+		return d.Decode(m)
+	}
+	return ply.NewDecoder(f).Decode(m)
+}
+
+func write3MF(name string, m *go3mf.Model) error {
+	w, err := go3mf.CreateWriter(name)
+	if err != nil {
+		return err
+	}
+	if err = w.Encode(m); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func writeSTL(name string, m *go3mf.Model, format stlexporter.Format, colors stlexporter.ColorMode) error {
+	if len(m.Build.Items) == 0 {
+		return errors.New("model does not have build items")
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	e := stlexporter.NewEncoder(f)
+	e.Format = format
+	e.ColorMode = colors
+	if err = e.Encode(m); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func parseUnits(s string) (go3mf.Units, bool) {
+	for _, u := range []go3mf.Units{
+		go3mf.UnitMillimeter, go3mf.UnitMicrometer, go3mf.UnitCentimeter,
+		go3mf.UnitInch, go3mf.UnitFoot, go3mf.UnitMeter,
+	} {
+		if u.String() == s {
+			return u, true
+		}
+	}
+	return 0, false
+}
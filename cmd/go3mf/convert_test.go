@@ -0,0 +1,82 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/thumbnail"
+)
+
+func Test_runConvert(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	stlFile := filepath.Join(dir, "cube.stl")
+	asciiFile := filepath.Join(dir, "ascii.stl")
+	backFile := filepath.Join(dir, "back.3mf")
+	inchFile := filepath.Join(dir, "inch.3mf")
+	objFile := filepath.Join(dir, "tri.obj")
+	plyFile := filepath.Join(dir, "tri.ply")
+	ioutil.WriteFile(objFile, []byte("v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n"), 0644)
+	ioutil.WriteFile(plyFile, []byte("ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\nelement face 1\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n3 0 1 2\n"), 0644)
+	tests := []struct {
+		name    string
+		args    []string
+		want    int
+		wantErr string
+	}{
+		{"noArgs", nil, 2, "Usage"},
+		{"badColors", []string{"-colors", "foo", cubeFile, stlFile}, 2, `invalid color mode "foo"`},
+		{"badUnits", []string{"-units", "foo", cubeFile, stlFile}, 2, `invalid units "foo"`},
+		{"badInput", []string{"a.txt", stlFile}, 1, `unsupported input format ".txt"`},
+		{"missingInput", []string{"missing.stl", stlFile}, 1, "missing.stl"},
+		{"badOutput", []string{cubeFile, filepath.Join(dir, "a.txt")}, 1, `unsupported output format ".txt"`},
+		{"toSTL", []string{"-colors", "viscam", cubeFile, stlFile}, 0, ""},
+		{"toASCII", []string{"-ascii", cubeFile, asciiFile}, 0, ""},
+		{"to3MF", []string{"-thumbnail", stlFile, backFile}, 0, ""},
+		{"toInch", []string{"-units", "inch", asciiFile, inchFile}, 0, ""},
+		{"fromOBJ", []string{objFile, filepath.Join(dir, "obj.3mf")}, 0, ""},
+		{"fromPLY", []string{plyFile, filepath.Join(dir, "ply.stl")}, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := runConvert(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("runConvert() = %v, want %v, stderr = %v", got, tt.want, stderr.String())
+			}
+			if !strings.Contains(stderr.String(), tt.wantErr) {
+				t.Errorf("runConvert() stderr = %v, want %v", stderr.String(), tt.wantErr)
+			}
+		})
+	}
+
+	var back, inch go3mf.Model
+	if err := decodeFile(backFile, &back); err != nil {
+		t.Fatalf("decodeFile() error = %v", err)
+	}
+	if got := back.BoundingBox(); got != (go3mf.Box{Min: go3mf.Point3D{30, 30, 50}, Max: go3mf.Point3D{130, 130, 150}}) {
+		t.Errorf("runConvert() bounding box = %v", got)
+	}
+	if back.Thumbnail != thumbnail.DefaultPath || len(back.Attachments) != 1 {
+		t.Errorf("runConvert() thumbnail = %s, attachments = %d", back.Thumbnail, len(back.Attachments))
+	}
+	if err := decodeFile(inchFile, &inch); err != nil {
+		t.Fatalf("decodeFile() error = %v", err)
+	}
+	if inch.Units != go3mf.UnitInch {
+		t.Errorf("runConvert() units = %v", inch.Units)
+	}
+}
+
+func Test_writeSTL_noItems(t *testing.T) {
+	if err := writeSTL("a.stl", new(go3mf.Model), 0, 0); err == nil {
+		t.Error("writeSTL() expected error")
+	}
+}
@@ -0,0 +1,86 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hpinc/go3mf"
+)
+
+func runInfo(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("info", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: go3mf info file.3mf...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	code := 0
+	for _, name := range fs.Args() {
+		var model go3mf.Model
+		if err := decodeFile(name, &model); err != nil {
+			fmt.Fprintf(stderr, "go3mf: %s: %v\n", name, err)
+			code = 1
+			continue
+		}
+		printInfo(stdout, name, &model)
+	}
+	return code
+}
+
+func printInfo(w io.Writer, name string, m *go3mf.Model) {
+	fmt.Fprintf(w, "file: %s\n", name)
+	fmt.Fprintf(w, "units: %s\n", m.Units)
+	if len(m.Extensions) != 0 {
+		fmt.Fprintln(w, "extensions:")
+		for _, ext := range m.Extensions {
+			var req string
+			if ext.IsRequired {
+				req = " (required)"
+			}
+			fmt.Fprintf(w, "  %s: %s%s\n", ext.LocalName, ext.Namespace, req)
+		}
+	}
+	var objects, triangles, vertices int
+	fmt.Fprintln(w, "objects:")
+	m.WalkObjects(func(path string, o *go3mf.Object) error {
+		objects++
+		fmt.Fprintf(w, "  - id: %d", o.ID)
+		if path != "" {
+			fmt.Fprintf(w, " path: %s", path)
+		}
+		fmt.Fprintf(w, " type: %s", o.Type)
+		if o.Name != "" {
+			fmt.Fprintf(w, " name: %q", o.Name)
+		}
+		if o.Mesh != nil {
+			fmt.Fprintf(w, " vertices: %d triangles: %d", len(o.Mesh.Vertices.Vertex), len(o.Mesh.Triangles.Triangle))
+			vertices += len(o.Mesh.Vertices.Vertex)
+			triangles += len(o.Mesh.Triangles.Triangle)
+		} else if o.Components != nil {
+			fmt.Fprintf(w, " components: %d", len(o.Components.Component))
+		}
+		fmt.Fprintln(w)
+		return nil
+	})
+	fmt.Fprintf(w, "total objects: %d\n", objects)
+	fmt.Fprintf(w, "total vertices: %d\n", vertices)
+	fmt.Fprintf(w, "total triangles: %d\n", triangles)
+	fmt.Fprintf(w, "build items: %d\n", len(m.Build.Items))
+	box := m.BoundingBox()
+	fmt.Fprintf(w, "bounding box: min %s max %s\n", formatPoint(box.Min), formatPoint(box.Max))
+}
+
+func formatPoint(p go3mf.Point3D) string {
+	return fmt.Sprintf("(%g, %g, %g)", p.X(), p.Y(), p.Z())
+}
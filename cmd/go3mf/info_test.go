@@ -0,0 +1,64 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func Test_runInfo(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if got := runInfo([]string{cubeFile, "missing.3mf"}, &stdout, &stderr); got != 1 {
+		t.Errorf("runInfo() = %v, want 1", got)
+	}
+	for _, want := range []string{
+		"units: millimeter",
+		`- id: 1 type: model name: "Cube" vertices: 8 triangles: 12`,
+		"total triangles: 12",
+		"build items: 1",
+		"bounding box: min (30, 30, 50) max (130, 130, 150)",
+	} {
+		if !strings.Contains(stdout.String(), want) {
+			t.Errorf("runInfo() stdout = %v, want %v", stdout.String(), want)
+		}
+	}
+	if !strings.Contains(stderr.String(), "missing.3mf") {
+		t.Errorf("runInfo() stderr = %v", stderr.String())
+	}
+}
+
+func Test_printInfo(t *testing.T) {
+	m := &go3mf.Model{
+		Units:      go3mf.UnitInch,
+		Extensions: []go3mf.Extension{{Namespace: "http://foo", LocalName: "f", IsRequired: true}},
+		Resources: go3mf.Resources{Objects: []*go3mf.Object{
+			{ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 1}}}},
+		}},
+		Childs: map[string]*go3mf.ChildModel{
+			"/other.model": {Resources: go3mf.Resources{Objects: []*go3mf.Object{{ID: 1, Type: go3mf.ObjectTypeSupport, Mesh: new(go3mf.Mesh)}}}},
+		},
+	}
+	var b bytes.Buffer
+	printInfo(&b, "a.3mf", m)
+	want := `file: a.3mf
+units: inch
+extensions:
+  f: http://foo (required)
+objects:
+  - id: 1 path: /other.model type: support vertices: 0 triangles: 0
+  - id: 2 type: model components: 1
+total objects: 2
+total vertices: 0
+total triangles: 0
+build items: 0
+bounding box: min (0, 0, 0) max (0, 0, 0)
+`
+	if got := b.String(); got != want {
+		t.Errorf("printInfo() = %v, want %v", got, want)
+	}
+}
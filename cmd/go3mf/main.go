@@ -0,0 +1,74 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Command go3mf validates, inspects and converts 3MF files.
+//
+// Usage:
+//
+//	go3mf <command> [arguments]
+//
+// The commands are:
+//
+//	validate    check that 3MF files conform to the specification
+//	info        print a summary of 3MF files
+//	convert     convert between 3MF, STL, OBJ and PLY
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	_ "github.com/hpinc/go3mf/beamlattice"
+	_ "github.com/hpinc/go3mf/booleanoperations"
+	_ "github.com/hpinc/go3mf/displacement"
+	_ "github.com/hpinc/go3mf/materials"
+	_ "github.com/hpinc/go3mf/production"
+	_ "github.com/hpinc/go3mf/securecontent"
+	_ "github.com/hpinc/go3mf/slices"
+	_ "github.com/hpinc/go3mf/trianglesets"
+	_ "github.com/hpinc/go3mf/volumetric"
+)
+
+const usage = `Usage: go3mf <command> [arguments]
+
+The commands are:
+
+	validate    check that 3MF files conform to the specification
+	info        print a summary of 3MF files
+	convert     convert between 3MF, STL, OBJ and PLY
+
+Use "go3mf <command> -h" for more information about a command.
+`
+
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"validate": runValidate,
+	"info":     runInfo,
+	"convert":  runConvert,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command defined in args and returns the exit code.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "go3mf: unknown command %q\n", args[0])
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	return cmd(args[1:], stdout, stderr)
+}
@@ -0,0 +1,67 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+const cubeFile = "../../testdata/cube.3mf"
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "go3mf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+// writeModel encodes m into a 3MF file in dir.
+func writeModel(t *testing.T, dir, name string, m *go3mf.Model) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := write3MF(path, m); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func Test_run(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       int
+		wantStdout string
+		wantStderr string
+	}{
+		{"empty", nil, 2, "", "Usage: go3mf"},
+		{"help", []string{"help"}, 0, "Usage: go3mf", ""},
+		{"unknown", []string{"foo"}, 2, "", `unknown command "foo"`},
+		{"validateNoArgs", []string{"validate"}, 2, "", "Usage: go3mf validate"},
+		{"infoNoArgs", []string{"info"}, 2, "", "Usage: go3mf info"},
+		{"convertNoArgs", []string{"convert"}, 2, "", "Usage: go3mf convert"},
+		{"badFlag", []string{"info", "-foo"}, 2, "", "flag provided but not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("run() = %v, want %v", got, tt.want)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("run() stdout = %v, want %v", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("run() stderr = %v, want %v", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
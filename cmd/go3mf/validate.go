@@ -0,0 +1,116 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/reporting"
+)
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	lenient := fs.Bool("lenient", false, "continue decoding after recoverable errors")
+	noCoherency := fs.Bool("no-coherency", false, "skip the mesh coherency checks")
+	format := fs.String("format", "text", "output format: text, json or sarif")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: go3mf validate [flags] file.3mf...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+	switch *format {
+	case "text", "json", "sarif":
+	default:
+		fmt.Fprintf(stderr, "go3mf validate: unknown format %q\n", *format)
+		return 2
+	}
+	code := 0
+	reports := make([]*reporting.Report, 0, fs.NArg())
+	for _, name := range fs.Args() {
+		errs := validateFile(name, !*lenient, !*noCoherency)
+		if len(errs) != 0 {
+			code = 1
+		}
+		if *format != "text" {
+			reports = append(reports, reporting.New(name, &specerr.List{Errors: errs}))
+			continue
+		}
+		for _, err := range errs {
+			fmt.Fprintf(stdout, "%s: %s\n", name, formatError(err))
+		}
+		if len(errs) == 0 {
+			fmt.Fprintf(stdout, "%s: ok\n", name)
+		}
+	}
+	var err error
+	switch *format {
+	case "json":
+		err = reporting.WriteJSON(stdout, reports...)
+	case "sarif":
+		err = reporting.WriteSARIF(stdout, reports...)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "go3mf validate: %v\n", err)
+		return 1
+	}
+	return code
+}
+
+// validateFile decodes and validates a 3MF file,
+// returning all the errors found.
+func validateFile(name string, strict, coherency bool) []error {
+	r, err := go3mf.OpenReader(name)
+	if err != nil {
+		return []error{err}
+	}
+	defer r.Close()
+	r.Strict = strict
+	var model go3mf.Model
+	if err := r.Decode(&model); err != nil {
+		return flatten(err)
+	}
+	errs := flatten(model.Validate())
+	if coherency {
+		errs = append(errs, flatten(model.ValidateCoherency())...)
+	}
+	return errs
+}
+
+// flatten returns the errors contained in err.
+func flatten(err error) []error {
+	if err == nil {
+		return nil
+	}
+	if list, ok := err.(*specerr.List); ok {
+		var errs []error
+		for _, e := range list.Errors {
+			errs = append(errs, flatten(e)...)
+		}
+		return errs
+	}
+	return []error{err}
+}
+
+// formatError prints the part path and the XPath of the element
+// that caused the error, if available.
+func formatError(err error) string {
+	if e, ok := err.(*specerr.Error); ok {
+		if e.Path != "" {
+			return fmt.Sprintf("%s %s: %v", e.Path, e.XPath(), e.Err)
+		}
+		return fmt.Sprintf("%s: %v", e.XPath(), e.Err)
+	}
+	return err.Error()
+}
@@ -0,0 +1,93 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+func Test_runValidate(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	invalid := writeModel(t, dir, "invalid.3mf", &go3mf.Model{
+		Resources: go3mf.Resources{Objects: []*go3mf.Object{{ID: 1}}},
+		Build:     go3mf.Build{Items: []*go3mf.Item{{ObjectID: 1}}},
+	})
+	incoherent := writeModel(t, dir, "incoherent.3mf", &go3mf.Model{
+		Resources: go3mf.Resources{Objects: []*go3mf.Object{{ID: 1, Mesh: &go3mf.Mesh{
+			Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+			Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+				{V1: 0, V2: 1, V3: 2}, {V1: 0, V2: 1, V3: 3}, {V1: 1, V2: 2, V3: 3}, {V1: 0, V2: 3, V3: 2},
+			}},
+		}}}},
+		Build: go3mf.Build{Items: []*go3mf.Item{{ObjectID: 1}}},
+	})
+	tests := []struct {
+		name string
+		args []string
+		want int
+		out  []string
+	}{
+		{"valid", []string{cubeFile}, 0, []string{"cube.3mf: ok"}},
+		{"missing", []string{"missing.3mf"}, 1, []string{"missing.3mf: open missing.3mf"}},
+		{"invalid", []string{invalid}, 1, []string{"/model/resources/object[0]: " + specerr.ErrInvalidObject.Error()}},
+		{"incoherent", []string{incoherent}, 1, []string{"/model/resources/object[0]/mesh: " + specerr.ErrMeshConsistency.Error()}},
+		{"noCoherency", []string{"-no-coherency", incoherent}, 0, []string{"incoherent.3mf: ok"}},
+		{"several", []string{cubeFile, invalid}, 1, []string{"cube.3mf: ok", "invalid.3mf: /model"}},
+		{"json", []string{"-format", "json", cubeFile, invalid}, 1, []string{`"valid": true`, `"ruleId": "CORE023"`}},
+		{"sarif", []string{"-format", "sarif", invalid}, 1, []string{`"version": "2.1.0"`, `"ruleId": "CORE023"`, `"fullyQualifiedName": "/model/resources/object[0]"`}},
+		{"badFormat", []string{"-format", "xml", cubeFile}, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := runValidate(tt.args, &stdout, &stderr); got != tt.want {
+				t.Errorf("runValidate() = %v, want %v", got, tt.want)
+			}
+			for _, out := range tt.out {
+				if !strings.Contains(stdout.String(), out) {
+					t.Errorf("runValidate() stdout = %v, want %v", stdout.String(), out)
+				}
+			}
+		})
+	}
+}
+
+func Test_formatError(t *testing.T) {
+	err := errors.New("foo")
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain", err, "foo"},
+		{"xpath", specerr.WrapIndex(err, "object", 1), "/object[1]: foo"},
+		{"path", specerr.WrapPath(err, "resources", "/other.model"), "/other.model /resources: foo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatError(tt.err); got != tt.want {
+				t.Errorf("formatError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_flatten(t *testing.T) {
+	e1, e2, e3 := errors.New("1"), errors.New("2"), errors.New("3")
+	got := flatten(&specerr.List{Errors: []error{e1, &specerr.List{Errors: []error{e2, e3}}}})
+	if len(got) != 3 || got[0] != e1 || got[1] != e2 || got[2] != e3 {
+		t.Errorf("flatten() = %v", got)
+	}
+	if got := flatten(nil); got != nil {
+		t.Errorf("flatten() = %v, want nil", got)
+	}
+}
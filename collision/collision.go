@@ -0,0 +1,169 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package collision finds the build items of a model that overlap each other
+// once their transforms are applied, and the items that do not fit in a printable volume.
+//
+// Items are first paired by their world bounding boxes and then
+// checked with exact triangle-triangle tests.
+package collision
+
+import (
+	"math"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/bvh"
+)
+
+// A Pair identifies two colliding items by their index in go3mf.Build.Items, being A < B.
+type Pair struct {
+	A, B int
+}
+
+// Result contains the outcome of a check.
+type Result struct {
+	// Collisions lists the colliding items, sorted by A and then by B.
+	Collisions []Pair
+	// Bounds is the world bounding box of each item, computed from the
+	// transformed vertices. It is the zero Box for items without meshes.
+	Bounds []go3mf.Box
+	// Fits reports whether each item is inside the printable volume.
+	// Items without meshes always fit. It is nil when the Checker has no volume.
+	Fits []bool
+}
+
+// Colliding returns true if any pair of items collide.
+func (r *Result) Colliding() bool {
+	return len(r.Collisions) > 0
+}
+
+// A Checker detects collisions between build items.
+//
+// Surfaces that touch are reported as collisions, and so are items
+// completely inside other items. The containment test assumes closed meshes.
+type Checker struct {
+	// Volume is the printable volume, in model units.
+	// A nil Volume disables the fit check.
+	Volume *go3mf.Box
+}
+
+// Check returns the collisions between the build items of m,
+// following components recursively.
+// Collisions between the components of the same item are not reported.
+func (c *Checker) Check(m *go3mf.Model) (*Result, error) {
+	s, err := bvh.NewScene(m)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[*go3mf.Item]int, len(m.Build.Items))
+	for i, item := range m.Build.Items {
+		index[item] = i
+	}
+	items := make([][]int, len(m.Build.Items))
+	for i, in := range s.Instances() {
+		j := index[in.Item]
+		items[j] = append(items[j], i)
+	}
+	r := &Result{Bounds: make([]go3mf.Box, len(items))}
+	empty := make([]bool, len(items))
+	for i, ins := range items {
+		r.Bounds[i], empty[i] = bounds(s.Instances(), ins)
+	}
+	r.Collisions = collisions(s, items, r.Bounds, empty)
+	if c.Volume != nil {
+		r.Fits = make([]bool, len(items))
+		for i, b := range r.Bounds {
+			r.Fits[i] = empty[i] || inside(b, *c.Volume)
+		}
+	}
+	return r, nil
+}
+
+// collisions runs a sweep and prune over the x axis of the item bounds
+// and checks the instances of the candidate pairs.
+func collisions(s *bvh.Scene, items [][]int, bounds []go3mf.Box, empty []bool) []Pair {
+	order := make([]int, 0, len(items))
+	for i := range items {
+		if !empty[i] {
+			order = append(order, i)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bounds[order[i]].Min[0] < bounds[order[j]].Min[0]
+	})
+	var pairs []Pair
+	for i, a := range order {
+		for _, b := range order[i+1:] {
+			if bounds[b].Min[0] > bounds[a].Max[0] {
+				break
+			}
+			if overlap(bounds[a], bounds[b]) && instancesOverlap(s, items[a], items[b]) {
+				p := Pair{A: a, B: b}
+				if a > b {
+					p = Pair{A: b, B: a}
+				}
+				pairs = append(pairs, p)
+			}
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].A != pairs[j].A {
+			return pairs[i].A < pairs[j].A
+		}
+		return pairs[i].B < pairs[j].B
+	})
+	return pairs
+}
+
+func instancesOverlap(s *bvh.Scene, a, b []int) bool {
+	ins := s.Instances()
+	for _, i := range a {
+		for _, j := range b {
+			if overlap(ins[i].Box(), ins[j].Box()) && s.Overlap(i, j) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// bounds returns the box of the transformed vertices of the instances,
+// and true if there are no vertices.
+func bounds(ins []bvh.Instance, ids []int) (go3mf.Box, bool) {
+	inf := float32(math.Inf(1))
+	box := go3mf.Box{Min: go3mf.Point3D{inf, inf, inf}, Max: go3mf.Point3D{-inf, -inf, -inf}}
+	for _, i := range ids {
+		mesh := ins[i].Tree.Mesh()
+		for _, v := range mesh.Vertices.Vertex {
+			v = ins[i].Transform.Mul3D(v)
+			for k := 0; k < 3; k++ {
+				box.Min[k] = float32(math.Min(float64(box.Min[k]), float64(v[k])))
+				box.Max[k] = float32(math.Max(float64(box.Max[k]), float64(v[k])))
+			}
+		}
+	}
+	if box.Min[0] > box.Max[0] {
+		return go3mf.Box{}, true
+	}
+	return box, false
+}
+
+func overlap(a, b go3mf.Box) bool {
+	for i := 0; i < 3; i++ {
+		if a.Max[i] < b.Min[i] || b.Max[i] < a.Min[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// inside returns true if a is inside b, borders included.
+func inside(a, b go3mf.Box) bool {
+	for i := 0; i < 3; i++ {
+		if a.Min[i] < b.Min[i] || a.Max[i] > b.Max[i] {
+			return false
+		}
+	}
+	return true
+}
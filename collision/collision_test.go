@@ -0,0 +1,109 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package collision
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/internal/meshtest"
+)
+
+// createModel returns a model with a 10mm cube and a small 2mm cube,
+// and an item of the cube for each transform.
+func createModel(transforms ...go3mf.Matrix) *go3mf.Model {
+	m := new(go3mf.Model)
+	m.Resources.Objects = append(m.Resources.Objects,
+		&go3mf.Object{ID: 1, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 10})},
+		&go3mf.Object{ID: 2, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{2, 2, 2})},
+		&go3mf.Object{ID: 3, Components: &go3mf.Components{Component: []*go3mf.Component{
+			{ObjectID: 2},
+			{ObjectID: 2, Transform: go3mf.Identity().Translate(20, 0, 0)},
+		}}},
+	)
+	for _, t := range transforms {
+		m.Build.Items = append(m.Build.Items, &go3mf.Item{ObjectID: 1, Transform: t})
+	}
+	return m
+}
+
+func TestChecker_Check(t *testing.T) {
+	// A rotation of 45 degrees around z.
+	c := float32(0.70710678)
+	rot := go3mf.Matrix{c, c, 0, 0, -c, c, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+	tests := []struct {
+		name  string
+		m     *go3mf.Model
+		want  []Pair
+		wantF []bool
+	}{
+		{"empty", createModel(), nil, []bool{}},
+		{"apart", createModel(go3mf.Matrix{}, go3mf.Identity().Translate(15, 0, 0), go3mf.Identity().Translate(0, 15, 0)), nil, []bool{true, true, true}},
+		{"crossing", createModel(go3mf.Matrix{}, go3mf.Identity().Translate(5, 5, 0), go3mf.Identity().Translate(30, 0, 0)), []Pair{{0, 1}}, []bool{true, true, false}},
+		{"touching", createModel(go3mf.Identity().Translate(10, 0, 0), go3mf.Matrix{}), []Pair{{0, 1}}, []bool{true, true}},
+		{"chain", createModel(go3mf.Identity().Translate(16, 0, 0), go3mf.Identity().Translate(8, 0, 0), go3mf.Matrix{}), []Pair{{0, 1}, {1, 2}}, []bool{false, true, true}},
+		// The boxes overlap but the rotated cube does not reach the other one.
+		{"rotated", createModel(go3mf.Matrix{}, rot.Translate(16, 5, 0)), nil, []bool{true, true}},
+		{"rotatedCrossing", createModel(go3mf.Matrix{}, rot.Translate(12, 2, 0)), []Pair{{0, 1}}, []bool{true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volume := go3mf.Box{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{25, 25, 25}}
+			got, err := (&Checker{Volume: &volume}).Check(tt.m)
+			if err != nil {
+				t.Fatalf("Checker.Check() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Collisions, tt.want) {
+				t.Errorf("Checker.Check() collisions = %v, want %v", got.Collisions, tt.want)
+			}
+			if got.Colliding() != (len(tt.want) > 0) {
+				t.Errorf("Result.Colliding() = %v", got.Colliding())
+			}
+			if !reflect.DeepEqual(got.Fits, tt.wantF) {
+				t.Errorf("Checker.Check() fits = %v, want %v", got.Fits, tt.wantF)
+			}
+		})
+	}
+}
+
+func TestChecker_Check_components(t *testing.T) {
+	m := createModel(go3mf.Matrix{})
+	// The components of item 1 do not collide with each other,
+	// but the second one crosses item 2.
+	m.Build.Items = append(m.Build.Items,
+		&go3mf.Item{ObjectID: 3, Transform: go3mf.Identity().Translate(20, 0, 0)},
+		&go3mf.Item{ObjectID: 1, Transform: go3mf.Identity().Translate(39, 0, 0)},
+		&go3mf.Item{ObjectID: 100},
+		// Inside item 0, without touching it.
+		&go3mf.Item{ObjectID: 2, Transform: go3mf.Identity().Translate(4, 4, 4)},
+	)
+	got, err := new(Checker).Check(m)
+	if err != nil {
+		t.Fatalf("Checker.Check() error = %v", err)
+	}
+	if want := []Pair{{0, 4}, {1, 2}}; !reflect.DeepEqual(got.Collisions, want) {
+		t.Errorf("Checker.Check() collisions = %v, want %v", got.Collisions, want)
+	}
+	if got.Fits != nil {
+		t.Errorf("Checker.Check() fits = %v, want nil", got.Fits)
+	}
+	want := []go3mf.Box{
+		{Min: go3mf.Point3D{0, 0, 0}, Max: go3mf.Point3D{10, 10, 10}},
+		{Min: go3mf.Point3D{20, 0, 0}, Max: go3mf.Point3D{42, 2, 2}},
+		{Min: go3mf.Point3D{39, 0, 0}, Max: go3mf.Point3D{49, 10, 10}},
+		{},
+		{Min: go3mf.Point3D{4, 4, 4}, Max: go3mf.Point3D{6, 6, 6}},
+	}
+	if !reflect.DeepEqual(got.Bounds, want) {
+		t.Errorf("Checker.Check() bounds = %v, want %v", got.Bounds, want)
+	}
+}
+
+func TestChecker_Check_error(t *testing.T) {
+	m := createModel(go3mf.Matrix{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1})
+	if _, err := new(Checker).Check(m); err == nil {
+		t.Error("Checker.Check() expected an error for a singular transform")
+	}
+}
@@ -25,6 +25,10 @@ const (
 	RelTypePrintTicket = "http://schemas.microsoft.com/3dmanufacturing/2013/01/printticket"
 	// RelTypeMustPreserve is the canonical must preserve relationship type.
 	RelTypeMustPreserve = "http://schemas.openxmlformats.org/package/2006/relationships/mustpreserve"
+	// RelTypeDigitalSignatureOrigin is the canonical digital signature origin relationship type.
+	RelTypeDigitalSignatureOrigin = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/origin"
+	// RelTypeDigitalSignature is the canonical digital signature relationship type.
+	RelTypeDigitalSignature = "http://schemas.openxmlformats.org/package/2006/relationships/digital-signature/signature"
 
 	// DefaultModelPath is the recommended root model part name.
 	DefaultModelPath = "/3D/3dmodel.model"
@@ -41,6 +45,10 @@ const (
 	ContentType3DModel = "application/vnd.ms-package.3dmanufacturing-3dmodel+xml"
 	// ContentTypePrintTicket is the print ticket content type.
 	ContentTypePrintTicket = "application/vnd.ms-printing.printticket+xml"
+	// ContentTypeSignatureOrigin is the digital signature origin content type.
+	ContentTypeSignatureOrigin = "application/vnd.openxmlformats-package.digital-signature-origin"
+	// ContentTypeSignature is the XML digital signature content type.
+	ContentTypeSignature = "application/vnd.openxmlformats-package.digital-signature-xmlsignature+xml"
 )
 
 // Units define the allowed model units.
@@ -333,6 +341,47 @@ func (m *Model) WalkObjects(fn func(string, *Object) error) error {
 	return nil
 }
 
+// MaxComponentDepth is the maximum depth of the components followed by WalkMeshes,
+// which avoids infinite loops when components are recursive.
+const MaxComponentDepth = 64
+
+// WalkMeshes walks the mesh objects of o, defined in the model path, following the components
+// recursively, calling fn for each mesh object and stopping if fn returns an error.
+//
+// fn receives the path where the mesh object is defined and its transform,
+// which is t composed with the transforms of the components that lead to it.
+func (m *Model) WalkMeshes(path string, o *Object, t Matrix, fn func(string, *Object, Matrix) error) error {
+	return m.walkMeshes(path, o, t, 0, fn)
+}
+
+// WalkItemMeshes calls WalkMeshes for the object of the build item,
+// starting with the item transform. Items with missing objects are ignored.
+func (m *Model) WalkItemMeshes(item *Item, fn func(string, *Object, Matrix) error) error {
+	path := item.ObjectPath()
+	if o, ok := m.FindObject(path, item.ObjectID); ok {
+		return m.walkMeshes(path, o, item.Transform.OrIdentity(), 0, fn)
+	}
+	return nil
+}
+
+func (m *Model) walkMeshes(path string, o *Object, t Matrix, depth int, fn func(string, *Object, Matrix) error) error {
+	if o.Mesh != nil {
+		return fn(path, o, t)
+	}
+	if o.Components == nil || depth >= MaxComponentDepth {
+		return nil
+	}
+	for _, c := range o.Components.Component {
+		cpath := c.ObjectPath(path)
+		if obj, ok := m.FindObject(cpath, c.ObjectID); ok {
+			if err := m.walkMeshes(cpath, obj, t.Mul(c.Transform.OrIdentity()), depth+1, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Base defines the Model Base Material Resource.
 // A model material resource is an in memory representation of the 3MF
 // material resource object.
@@ -410,6 +459,7 @@ type Object struct {
 	Metadata   MetadataGroup
 	Mesh       *Mesh
 	Components *Components
+	Any        spec.Any
 	AnyAttr    spec.AnyAttr
 }
 
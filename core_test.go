@@ -4,6 +4,7 @@
 package go3mf
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -437,6 +438,89 @@ func TestModel_WalkObjects(t *testing.T) {
 	}
 }
 
+func TestModel_WalkMeshes(t *testing.T) {
+	mesh, other := new(Mesh), new(Mesh)
+	m := &Model{Childs: map[string]*ChildModel{
+		"/other.model": {Resources: Resources{Objects: []*Object{{ID: 5, Mesh: other}}}},
+	}, Resources: Resources{Objects: []*Object{
+		{ID: 1, Mesh: mesh},
+		{ID: 2, Components: &Components{Component: []*Component{
+			{ObjectID: 1, Transform: Identity().Translate(1, 0, 0)},
+			{ObjectID: 5, AnyAttr: spec.AnyAttr{&fakeAttr{Value: "/other.model"}}},
+			{ObjectID: 100},
+		}}},
+		{ID: 3, Components: &Components{Component: []*Component{{ObjectID: 3}}}},
+	}}}
+	type walked struct {
+		path string
+		mesh *Mesh
+		t    Matrix
+	}
+	walk := func(o *Object, t Matrix) []walked {
+		var got []walked
+		m.WalkMeshes("", o, t, func(path string, o *Object, t Matrix) error {
+			got = append(got, walked{path, o.Mesh, t})
+			return nil
+		})
+		return got
+	}
+	tests := []struct {
+		name string
+		o    *Object
+		t    Matrix
+		want []walked
+	}{
+		{"mesh", m.Resources.Objects[0], Identity(), []walked{{"", mesh, Identity()}}},
+		{"components", m.Resources.Objects[1], Identity().Translate(0, 2, 0), []walked{
+			{"", mesh, Identity().Translate(1, 2, 0)},
+			{"/other.model", other, Identity().Translate(0, 2, 0)},
+		}},
+		{"recursive", m.Resources.Objects[2], Identity(), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := walk(tt.o, tt.t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Model.WalkMeshes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	errWalk := errors.New("walk")
+	var n int
+	err := m.WalkMeshes("", m.Resources.Objects[1], Identity(), func(string, *Object, Matrix) error {
+		n++
+		return errWalk
+	})
+	if err != errWalk || n != 1 {
+		t.Errorf("Model.WalkMeshes() error = %v after %d meshes, want %v after 1", err, n, errWalk)
+	}
+}
+
+func TestModel_WalkItemMeshes(t *testing.T) {
+	m := &Model{Resources: Resources{Objects: []*Object{{ID: 1, Mesh: new(Mesh)}}}}
+	tests := []struct {
+		name string
+		item *Item
+		want []Matrix
+	}{
+		{"identity", &Item{ObjectID: 1}, []Matrix{Identity()}},
+		{"transform", &Item{ObjectID: 1, Transform: Identity().Translate(1, 2, 3)}, []Matrix{Identity().Translate(1, 2, 3)}},
+		{"missing", &Item{ObjectID: 2}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Matrix
+			m.WalkItemMeshes(tt.item, func(_ string, _ *Object, t Matrix) error {
+				got = append(got, t)
+				return nil
+			})
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Model.WalkItemMeshes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMesh_BoundingBox(t *testing.T) {
 	tests := []struct {
 		name string
@@ -16,9 +16,10 @@ import (
 
 type modelDecoder struct {
 	baseDecoder
-	model  *Model
-	isRoot bool
-	path   string
+	model   *Model
+	isRoot  bool
+	path    string
+	handler MeshHandler
 }
 
 func (d *modelDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
@@ -26,7 +27,7 @@ func (d *modelDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 		switch name.Local {
 		case attrResources:
 			resources, _ := d.model.FindResources(d.path)
-			child = &resourceDecoder{resources: resources, model: d.model}
+			child = &resourceDecoder{resources: resources, model: d.model, path: d.path, handler: d.handler}
 			i = -1
 		case attrBuild:
 			if d.isRoot {
@@ -272,6 +273,8 @@ type resourceDecoder struct {
 	baseDecoder
 	model     *Model
 	resources *Resources
+	path      string
+	handler   MeshHandler
 }
 
 func (d *resourceDecoder) Start(attrs []spec.XMLAttr) error {
@@ -291,7 +294,7 @@ func (d *resourceDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder
 	if name.Space == Namespace {
 		switch name.Local {
 		case attrObject:
-			child = &objectDecoder{resources: d.resources, model: d.model}
+			child = &objectDecoder{resources: d.resources, model: d.model, path: d.path, handler: d.handler}
 			i = len(d.resources.Objects)
 		case attrBaseMaterials:
 			child = &baseMaterialsDecoder{resources: d.resources}
@@ -392,6 +395,8 @@ func (d *baseMaterialDecoder) Start(attrs []spec.XMLAttr) error {
 type meshDecoder struct {
 	baseDecoder
 	resource *Object
+	path     string
+	handler  MeshHandler
 }
 
 func (d *meshDecoder) Start(attrs []spec.XMLAttr) error {
@@ -411,10 +416,10 @@ func (d *meshDecoder) Start(attrs []spec.XMLAttr) error {
 func (d *meshDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 	if name.Space == Namespace {
 		if name.Local == attrVertices {
-			child = &verticesDecoder{mesh: d.resource.Mesh}
+			child = &verticesDecoder{resource: d.resource, path: d.path, handler: d.handler}
 			i = -1
 		} else if name.Local == attrTriangles {
-			child = &trianglesDecoder{resource: d.resource}
+			child = &trianglesDecoder{resource: d.resource, path: d.path, handler: d.handler}
 			i = -1
 		}
 	} else {
@@ -430,18 +435,24 @@ func (d *meshDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 
 type verticesDecoder struct {
 	baseDecoder
-	mesh          *Mesh
+	resource      *Object
+	path          string
+	handler       MeshHandler
 	vertexDecoder vertexDecoder
+	count         int
 }
 
 func (d *verticesDecoder) Start(attrs []spec.XMLAttr) error {
-	d.vertexDecoder.mesh = d.mesh
+	d.vertexDecoder.resource = d.resource
+	d.vertexDecoder.path = d.path
+	d.vertexDecoder.handler = d.handler
+	mesh := d.resource.Mesh
 	var errs error
 	for _, a := range attrs {
 		var attr spec.AttrGroup
-		if attr = d.mesh.Vertices.AnyAttr.Get(a.Name.Space); attr == nil {
+		if attr = mesh.Vertices.AnyAttr.Get(a.Name.Space); attr == nil {
 			attr = spec.NewAttrGroup(a.Name.Space, xml.Name{Space: Namespace, Local: attrVertices})
-			d.mesh.Vertices.AnyAttr = append(d.mesh.Vertices.AnyAttr, attr)
+			mesh.Vertices.AnyAttr = append(mesh.Vertices.AnyAttr, attr)
 		}
 		errs = specerr.Append(errs, attr.Unmarshal3MFAttr(a))
 	}
@@ -451,14 +462,17 @@ func (d *verticesDecoder) Start(attrs []spec.XMLAttr) error {
 func (d *verticesDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 	if name.Space == Namespace && name.Local == attrVertex {
 		child = &d.vertexDecoder
-		i = len(d.mesh.Vertices.Vertex)
+		i = d.count
+		d.count++
 	}
 	return
 }
 
 type vertexDecoder struct {
 	baseDecoder
-	mesh *Mesh
+	resource *Object
+	path     string
+	handler  MeshHandler
 }
 
 func (d *vertexDecoder) Start(attrs []spec.XMLAttr) error {
@@ -483,22 +497,31 @@ func (d *vertexDecoder) Start(attrs []spec.XMLAttr) error {
 			z = float32(val)
 		}
 	}
-	d.mesh.Vertices.Vertex = append(d.mesh.Vertices.Vertex, Point3D{x, y, z})
+	if d.handler != nil {
+		d.handler.Vertex(d.path, d.resource, Point3D{x, y, z})
+	} else {
+		d.resource.Mesh.Vertices.Vertex = append(d.resource.Mesh.Vertices.Vertex, Point3D{x, y, z})
+	}
 	return errs
 }
 
 type trianglesDecoder struct {
 	baseDecoder
 	resource        *Object
+	path            string
+	handler         MeshHandler
 	triangleDecoder triangleDecoder
+	count           int
 }
 
 func (d *trianglesDecoder) Start(attrs []spec.XMLAttr) error {
-	d.triangleDecoder.mesh = d.resource.Mesh
+	d.triangleDecoder.resource = d.resource
+	d.triangleDecoder.path = d.path
+	d.triangleDecoder.handler = d.handler
 	d.triangleDecoder.defaultPropertyID = d.resource.PID
 	d.triangleDecoder.defaultPropertyIndex = d.resource.PIndex
 
-	if len(d.resource.Mesh.Triangles.Triangle) == 0 && len(d.resource.Mesh.Vertices.Vertex) > 0 {
+	if d.handler == nil && len(d.resource.Mesh.Triangles.Triangle) == 0 && len(d.resource.Mesh.Vertices.Vertex) > 0 {
 		d.resource.Mesh.Triangles.Triangle = make([]Triangle, 0, len(d.resource.Mesh.Vertices.Vertex)*2)
 	}
 	var errs error
@@ -516,14 +539,17 @@ func (d *trianglesDecoder) Start(attrs []spec.XMLAttr) error {
 func (d *trianglesDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 	if name.Space == Namespace && name.Local == attrTriangle {
 		child = &d.triangleDecoder
-		i = len(d.resource.Mesh.Triangles.Triangle)
+		i = d.count
+		d.count++
 	}
 	return
 }
 
 type triangleDecoder struct {
 	baseDecoder
-	mesh                                    *Mesh
+	resource                                *Object
+	path                                    string
+	handler                                 MeshHandler
 	defaultPropertyIndex, defaultPropertyID uint32
 }
 
@@ -582,7 +608,11 @@ func (d *triangleDecoder) Start(attrs []spec.XMLAttr) error {
 	pid = applyDefault(pid, d.defaultPropertyID, hasPID)
 	t.PID = pid
 	t.P1, t.P2, t.P3 = p1, p2, p3
-	d.mesh.Triangles.Triangle = append(d.mesh.Triangles.Triangle, t)
+	if d.handler != nil {
+		d.handler.Triangle(d.path, d.resource, t)
+	} else {
+		d.resource.Mesh.Triangles.Triangle = append(d.resource.Mesh.Triangles.Triangle, t)
+	}
 	return errs
 }
 
@@ -598,6 +628,8 @@ type objectDecoder struct {
 	model     *Model
 	resources *Resources
 	resource  Object
+	path      string
+	handler   MeshHandler
 }
 
 func (d *objectDecoder) End() {
@@ -624,7 +656,7 @@ func (d *objectDecoder) Start(attrs []spec.XMLAttr) error {
 func (d *objectDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 	if name.Space == Namespace {
 		if name.Local == attrMesh {
-			child = &meshDecoder{resource: &d.resource}
+			child = &meshDecoder{resource: &d.resource, path: d.path, handler: d.handler}
 			i = -1
 		} else if name.Local == attrComponents {
 			child = &componentsDecoder{resource: &d.resource}
@@ -633,6 +665,13 @@ func (d *objectDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder)
 			child = &metadataGroupDecoder{metadatas: &d.resource.Metadata, model: d.model}
 			i = -1
 		}
+	} else {
+		dec := spec.NewElementDecoder(name)
+		child = dec
+		if dec != nil {
+			d.resource.Any = append(d.resource.Any, dec.Element().(spec.Marshaler))
+		}
+		i = -1
 	}
 	return
 }
@@ -750,15 +789,16 @@ func (d *baseDecoder) End()                       {}
 
 type topLevelDecoder struct {
 	baseDecoder
-	model  *Model
-	isRoot bool
-	path   string
+	model   *Model
+	isRoot  bool
+	path    string
+	handler MeshHandler
 }
 
 func (d *topLevelDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
 	modelName := xml.Name{Space: Namespace, Local: attrModel}
 	if name == modelName {
-		child = &modelDecoder{model: d.model, isRoot: d.isRoot, path: d.path}
+		child = &modelDecoder{model: d.model, isRoot: d.isRoot, path: d.path, handler: d.handler}
 		i = -1
 	}
 	return
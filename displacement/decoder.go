@@ -0,0 +1,389 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func (Spec) NewAttrGroup(xml.Name) spec.AttrGroup {
+	return nil
+}
+
+func (Spec) NewElementDecoder(name xml.Name) (child spec.GetterElementDecoder) {
+	if name.Space != Namespace {
+		return
+	}
+	switch name.Local {
+	case attrDisplacement2D:
+		child = new(displacement2DDecoder)
+	case attrNormVectorGroup:
+		child = new(normVectorGroupDecoder)
+	case attrDisp2DGroup:
+		child = new(disp2DGroupDecoder)
+	case attrDisplacementMesh:
+		child = new(displacementMeshDecoder)
+	}
+	return
+}
+
+type displacement2DDecoder struct {
+	baseDecoder
+	resource Displacement2D
+}
+
+func (d *displacement2DDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *displacement2DDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		var ok bool
+		switch a.Name.Local {
+		case attrID:
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+		case attrPath:
+			d.resource.Path = string(a.Value)
+		case attrChannel:
+			if d.resource.Channel, ok = newChannel(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrTileStyleU:
+			if d.resource.TileStyleU, ok = newTileStyle(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrTileStyleV:
+			if d.resource.TileStyleV, ok = newTileStyle(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrFilter:
+			if d.resource.Filter, ok = newTextureFilter(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		}
+	}
+	return errs
+}
+
+type normVectorGroupDecoder struct {
+	baseDecoder
+	resource      NormVectorGroup
+	vectorDecoder normVectorDecoder
+}
+
+func (d *normVectorGroupDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *normVectorGroupDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	d.vectorDecoder.resource = &d.resource
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrID {
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+			break
+		}
+	}
+	return
+}
+
+func (d *normVectorGroupDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrNormVector {
+		child = &d.vectorDecoder
+		i = len(d.resource.Vectors)
+	}
+	return
+}
+
+type normVectorDecoder struct {
+	baseDecoder
+	resource *NormVectorGroup
+}
+
+func (d *normVectorDecoder) Start(attrs []spec.XMLAttr) error {
+	p, errs := parsePoint(attrs)
+	d.resource.Vectors = append(d.resource.Vectors, p)
+	return errs
+}
+
+type disp2DGroupDecoder struct {
+	baseDecoder
+	resource     Disp2DGroup
+	coordDecoder disp2DCoordDecoder
+}
+
+func (d *disp2DGroupDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *disp2DGroupDecoder) Start(attrs []spec.XMLAttr) error {
+	d.coordDecoder.resource = &d.resource
+	var errs error
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID, attrDispID, attrNID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			switch a.Name.Local {
+			case attrID:
+				d.resource.ID = uint32(val)
+			case attrDispID:
+				d.resource.DisplacementID = uint32(val)
+			case attrNID:
+				d.resource.NormVectorGroupID = uint32(val)
+			}
+		case attrHeight:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.Height = float32(val)
+		case attrOffset:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.Offset = float32(val)
+		}
+	}
+	return errs
+}
+
+func (d *disp2DGroupDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrDisp2DCoord {
+		child = &d.coordDecoder
+		i = len(d.resource.Coords)
+	}
+	return
+}
+
+type disp2DCoordDecoder struct {
+	baseDecoder
+	resource *Disp2DGroup
+}
+
+func (d *disp2DCoordDecoder) Start(attrs []spec.XMLAttr) error {
+	var (
+		c    = Disp2DCoord{F: 1}
+		errs error
+	)
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrU, attrV, attrF:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, a.Name.Local != attrF))
+			}
+			switch a.Name.Local {
+			case attrU:
+				c.U = float32(val)
+			case attrV:
+				c.V = float32(val)
+			case attrF:
+				c.F = float32(val)
+			}
+		case attrN:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			c.N = uint32(val)
+		}
+	}
+	d.resource.Coords = append(d.resource.Coords, c)
+	return errs
+}
+
+type displacementMeshDecoder struct {
+	baseDecoder
+	resource DisplacementMesh
+}
+
+func (d *displacementMeshDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *displacementMeshDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace {
+		if name.Local == attrVertices {
+			child = &verticesDecoder{vertexDecoder: vertexDecoder{resource: &d.resource}}
+			i = -1
+		} else if name.Local == attrTriangles {
+			child = &trianglesDecoder{triangleDecoder: triangleDecoder{resource: &d.resource}}
+			i = -1
+		}
+	}
+	return
+}
+
+type verticesDecoder struct {
+	baseDecoder
+	vertexDecoder vertexDecoder
+}
+
+func (d *verticesDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrVertex {
+		child = &d.vertexDecoder
+		i = len(d.vertexDecoder.resource.Vertices)
+	}
+	return
+}
+
+type vertexDecoder struct {
+	baseDecoder
+	resource *DisplacementMesh
+}
+
+func (d *vertexDecoder) Start(attrs []spec.XMLAttr) error {
+	p, errs := parsePoint(attrs)
+	d.resource.Vertices = append(d.resource.Vertices, p)
+	return errs
+}
+
+type trianglesDecoder struct {
+	baseDecoder
+	triangleDecoder triangleDecoder
+}
+
+func (d *trianglesDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrDID {
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.triangleDecoder.resource.Triangles.DID = uint32(val)
+			break
+		}
+	}
+	return
+}
+
+func (d *trianglesDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrTriangle {
+		child = &d.triangleDecoder
+		i = len(d.triangleDecoder.resource.Triangles.Triangle)
+	}
+	return
+}
+
+type triangleDecoder struct {
+	baseDecoder
+	resource *DisplacementMesh
+}
+
+func (d *triangleDecoder) Start(attrs []spec.XMLAttr) error {
+	var (
+		t                   Triangle
+		hasD2, hasD3        bool
+		hasP2, hasP3        bool
+		errs                error
+		required, isTriAttr bool
+	)
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		val, err := strconv.ParseUint(string(a.Value), 10, 32)
+		required, isTriAttr = false, true
+		switch a.Name.Local {
+		case attrV1:
+			t.V1, required = uint32(val), true
+		case attrV2:
+			t.V2, required = uint32(val), true
+		case attrV3:
+			t.V3, required = uint32(val), true
+		case attrD1:
+			t.D1 = uint32(val)
+		case attrD2:
+			t.D2, hasD2 = uint32(val), true
+		case attrD3:
+			t.D3, hasD3 = uint32(val), true
+		case attrDID:
+			t.DID = uint32(val)
+		case attrPID:
+			t.PID = uint32(val)
+		case attrP1:
+			t.P1 = uint32(val)
+		case attrP2:
+			t.P2, hasP2 = uint32(val), true
+		case attrP3:
+			t.P3, hasP3 = uint32(val), true
+		default:
+			isTriAttr = false
+		}
+		if err != nil && isTriAttr {
+			errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, required))
+		}
+	}
+	if !hasD2 {
+		t.D2 = t.D1
+	}
+	if !hasD3 {
+		t.D3 = t.D1
+	}
+	if !hasP2 {
+		t.P2 = t.P1
+	}
+	if !hasP3 {
+		t.P3 = t.P1
+	}
+	d.resource.Triangles.Triangle = append(d.resource.Triangles.Triangle, t)
+	return errs
+}
+
+func parsePoint(attrs []spec.XMLAttr) (p go3mf.Point3D, errs error) {
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		var i int
+		switch a.Name.Local {
+		case attrX:
+			i = 0
+		case attrY:
+			i = 1
+		case attrZ:
+			i = 2
+		default:
+			continue
+		}
+		val, err := strconv.ParseFloat(string(a.Value), 32)
+		if err != nil {
+			errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+		}
+		p[i] = float32(val)
+	}
+	return
+}
+
+type baseDecoder struct {
+}
+
+func (d *baseDecoder) Start([]spec.XMLAttr) error { return nil }
+func (d *baseDecoder) End()                       {}
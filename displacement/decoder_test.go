@@ -0,0 +1,138 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestDecode(t *testing.T) {
+	tex := &Displacement2D{ID: 1, Path: "/3D/Textures/disp.png", Channel: ChannelR, TileStyleU: TileMirror, TileStyleV: TileClamp, Filter: TextureFilterNearest}
+	normals := &NormVectorGroup{ID: 2, Vectors: []go3mf.Point3D{{0, 0, 1}, {0.5, 0.5, 0.7}}}
+	group := &Disp2DGroup{ID: 3, DisplacementID: 1, NormVectorGroupID: 2, Height: 0.5, Offset: -0.1, Coords: []Disp2DCoord{
+		{U: 0, V: 0, N: 0, F: 1}, {U: 1, V: 0.5, N: 1, F: 0.5},
+	}}
+	obj := &go3mf.Object{ID: 4, PID: 7, Any: spec.Any{&DisplacementMesh{
+		Vertices: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}},
+		Triangles: Triangles{DID: 3, Triangle: []Triangle{
+			{V1: 0, V2: 1, V3: 2, D1: 1, D2: 1, D3: 1},
+			{V1: 0, V2: 2, V3: 1, D1: 0, D2: 1, D3: 0, DID: 3, PID: 7, P1: 1, P2: 1, P3: 1},
+		}},
+	}}}
+	want := &go3mf.Model{Path: "/3D/3dmodel.model", Resources: go3mf.Resources{
+		Assets: []go3mf.Asset{tex, normals, group}, Objects: []*go3mf.Object{obj},
+	}}
+	want.Extensions = []go3mf.Extension{DefaultExtension}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:d="http://schemas.microsoft.com/3dmanufacturing/displacement/2022/07"
+		requiredextensions="d">
+		<resources>
+			<d:displacement2d id="1" path="/3D/Textures/disp.png" channel="R" tilestyleu="mirror" tilestylev="clamp" filter="nearest"/>
+			<d:normvectorgroup id="2">
+				<d:normvector x="0" y="0" z="1"/>
+				<d:normvector x="0.5" y="0.5" z="0.7"/>
+			</d:normvectorgroup>
+			<d:disp2dgroup id="3" dispid="1" nid="2" height="0.5" offset="-0.1">
+				<d:disp2dcoord u="0" v="0" n="0"/>
+				<d:disp2dcoord u="1" v="0.5" n="1" f="0.5"/>
+			</d:disp2dgroup>
+			<object id="4" pid="7">
+				<d:displacementmesh>
+					<d:vertices>
+						<d:vertex x="0" y="0" z="0"/>
+						<d:vertex x="10" y="0" z="0"/>
+						<d:vertex x="0" y="10" z="0"/>
+					</d:vertices>
+					<d:triangles did="3">
+						<d:triangle v1="0" v2="1" v3="2" d1="1"/>
+						<d:triangle v1="0" v2="2" v3="1" d1="0" d2="1" d3="0" did="3" pid="7" p1="1"/>
+					</d:triangles>
+				</d:displacementmesh>
+			</object>
+		</resources>
+		<build/>
+		</model>
+		`
+	t.Run("base", func(t *testing.T) {
+		if err := go3mf.UnmarshalModel([]byte(rootFile), got); err != nil {
+			t.Errorf("UnmarshalModel() unexpected error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("UnmarshalModel() = %v", diff)
+			return
+		}
+	})
+}
+
+func TestDecode_warns(t *testing.T) {
+	want := []string{
+		fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.ParseAttrError{Required: true, Name: "id"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.ParseAttrError{Required: false, Name: "channel"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.ParseAttrError{Required: false, Name: "tilestyleu"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.ParseAttrError{Required: false, Name: "tilestylev"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.ParseAttrError{Required: false, Name: "filter"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/normvectorgroup[1]: %v", &errors.ParseAttrError{Required: true, Name: "id"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/normvectorgroup[1]/normvector[0]: %v", &errors.ParseAttrError{Required: true, Name: "x"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.ParseAttrError{Required: true, Name: "dispid"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.ParseAttrError{Required: true, Name: "height"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.ParseAttrError{Required: false, Name: "offset"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]/disp2dcoord[0]: %v", &errors.ParseAttrError{Required: true, Name: "u"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]/disp2dcoord[0]: %v", &errors.ParseAttrError{Required: true, Name: "n"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]/disp2dcoord[0]: %v", &errors.ParseAttrError{Required: false, Name: "f"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/vertices/vertex[0]: %v", &errors.ParseAttrError{Required: true, Name: "z"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/triangles: %v", &errors.ParseAttrError{Required: false, Name: "did"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/triangles/triangle[0]: %v", &errors.ParseAttrError{Required: true, Name: "v1"}),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/triangles/triangle[0]: %v", &errors.ParseAttrError{Required: false, Name: "d1"}),
+	}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:d="http://schemas.microsoft.com/3dmanufacturing/displacement/2022/07"
+		requiredextensions="d">
+		<resources>
+			<d:displacement2d id="a" path="/3D/Textures/disp.png" channel="C" tilestyleu="a" tilestylev="b" filter="c"/>
+			<d:normvectorgroup id="b">
+				<d:normvector x="a" y="0" z="1"/>
+			</d:normvectorgroup>
+			<d:disp2dgroup id="3" dispid="a" nid="2" height="b" offset="c">
+				<d:disp2dcoord u="a" v="0" n="-1" f="b"/>
+			</d:disp2dgroup>
+			<object id="4">
+				<d:displacementmesh>
+					<d:vertices>
+						<d:vertex x="0" y="0" z="a"/>
+					</d:vertices>
+					<d:triangles did="a">
+						<d:triangle v1="a" v2="1" v3="2" d1="b"/>
+					</d:triangles>
+				</d:displacementmesh>
+			</object>
+		</resources>
+		<build/>
+		</model>`
+
+	t.Run("base", func(t *testing.T) {
+		err := go3mf.UnmarshalModel([]byte(rootFile), got)
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		var errs []string
+		for _, err := range err.(*errors.List).Errors {
+			errs = append(errs, err.Error())
+		}
+		if diff := deep.Equal(errs, want); diff != nil {
+			t.Errorf("UnmarshalModel_warn() = %v", diff)
+			return
+		}
+	})
+}
@@ -0,0 +1,308 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package displacement implements the 3MF Displacement extension,
+// which adds fine surface detail to meshes by moving their vertices
+// along normal vectors by the amount read from a texture.
+package displacement
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
+	"github.com/hpinc/go3mf/spec"
+)
+
+const (
+	// Namespace is the canonical name of this extension.
+	Namespace = "http://schemas.microsoft.com/3dmanufacturing/displacement/2022/07"
+	// RelTypeTexture3D is the canonical 3D texture relationship type.
+	RelTypeTexture3D = "http://schemas.microsoft.com/3dmanufacturing/2013/01/3dtexture"
+	// ContentTypePNG is the content type of the displacement textures.
+	ContentTypePNG = "image/png"
+)
+
+var DefaultExtension = go3mf.Extension{
+	Namespace:  Namespace,
+	LocalName:  "d",
+	IsRequired: true,
+}
+
+func init() {
+	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrMissingDisplacementPart:   {ID: "DISP001", Name: "MissingDisplacementPart", Severity: reporting.SeverityError},
+		ErrDisplacementContentType:   {ID: "DISP002", Name: "DisplacementContentType", Severity: reporting.SeverityError},
+		ErrDisplacementReference:     {ID: "DISP003", Name: "DisplacementReference", Severity: reporting.SeverityError},
+		ErrNormVectorReference:       {ID: "DISP004", Name: "NormVectorReference", Severity: reporting.SeverityError},
+		ErrDisp2DGroupReference:      {ID: "DISP005", Name: "Disp2DGroupReference", Severity: reporting.SeverityError},
+		ErrZeroNormVector:            {ID: "DISP006", Name: "ZeroNormVector", Severity: reporting.SeverityError},
+		ErrDisplacementObjectContent: {ID: "DISP007", Name: "DisplacementObjectContent", Severity: reporting.SeverityError},
+	})
+}
+
+type Spec struct{}
+
+var (
+	ErrMissingDisplacementPart   = errors.New("displacement texture part MUST be added as an attachment")
+	ErrDisplacementContentType   = errors.New("displacement texture part MUST be a PNG image")
+	ErrDisplacementReference     = errors.New("MUST reference to a displacement2d resource")
+	ErrNormVectorReference       = errors.New("MUST reference to a normvectorgroup resource")
+	ErrDisp2DGroupReference      = errors.New("MUST reference to a disp2dgroup resource")
+	ErrZeroNormVector            = errors.New("a normvector MUST NOT have zero length")
+	ErrDisplacementObjectContent = errors.New("an object containing a displacementmesh MUST NOT contain a mesh or components")
+)
+
+// Channel defines the texture channel used to read the displacement.
+type Channel uint8
+
+// Supported channels, G is the default one.
+const (
+	ChannelG Channel = iota
+	ChannelR
+	ChannelB
+	ChannelA
+)
+
+func newChannel(s string) (c Channel, ok bool) {
+	c, ok = map[string]Channel{
+		"R": ChannelR,
+		"G": ChannelG,
+		"B": ChannelB,
+		"A": ChannelA,
+	}[s]
+	return
+}
+
+func (c Channel) String() string {
+	return map[Channel]string{
+		ChannelR: "R",
+		ChannelG: "G",
+		ChannelB: "B",
+		ChannelA: "A",
+	}[c]
+}
+
+// TileStyle defines the allowed tile styles.
+type TileStyle uint8
+
+// Supported tile style.
+const (
+	TileWrap TileStyle = iota
+	TileMirror
+	TileClamp
+	TileNone
+)
+
+func newTileStyle(s string) (t TileStyle, ok bool) {
+	t, ok = map[string]TileStyle{
+		"wrap":   TileWrap,
+		"mirror": TileMirror,
+		"clamp":  TileClamp,
+		"none":   TileNone,
+	}[s]
+	return
+}
+
+func (t TileStyle) String() string {
+	return map[TileStyle]string{
+		TileWrap:   "wrap",
+		TileMirror: "mirror",
+		TileClamp:  "clamp",
+		TileNone:   "none",
+	}[t]
+}
+
+// TextureFilter defines the allowed texture filters.
+type TextureFilter uint8
+
+// Supported texture filters.
+const (
+	TextureFilterAuto TextureFilter = iota
+	TextureFilterLinear
+	TextureFilterNearest
+)
+
+func newTextureFilter(s string) (t TextureFilter, ok bool) {
+	t, ok = map[string]TextureFilter{
+		"auto":    TextureFilterAuto,
+		"linear":  TextureFilterLinear,
+		"nearest": TextureFilterNearest,
+	}[s]
+	return
+}
+
+func (t TextureFilter) String() string {
+	return map[TextureFilter]string{
+		TextureFilterAuto:    "auto",
+		TextureFilterLinear:  "linear",
+		TextureFilterNearest: "nearest",
+	}[t]
+}
+
+// Displacement2D defines a PNG texture containing the displacement values.
+type Displacement2D struct {
+	ID         uint32
+	Path       string
+	Channel    Channel
+	TileStyleU TileStyle
+	TileStyleV TileStyle
+	Filter     TextureFilter
+}
+
+// Identify returns the unique ID of the resource.
+func (r *Displacement2D) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (Displacement2D) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrDisplacement2D}
+}
+
+// NormVectorGroup defines the normal vectors used to displace the vertices.
+type NormVectorGroup struct {
+	ID      uint32
+	Vectors []go3mf.Point3D
+}
+
+// Identify returns the unique ID of the resource.
+func (r *NormVectorGroup) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (NormVectorGroup) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrNormVectorGroup}
+}
+
+// Disp2DCoord maps a triangle vertex to a position of the displacement texture
+// and to a normal vector. F is a factor applied to the displacement, default to 1.
+type Disp2DCoord struct {
+	U, V float32
+	N    uint32
+	F    float32
+}
+
+// Disp2DGroup defines how a displacement texture is applied.
+// The displacement of a point is F * (Height * value + Offset),
+// being value the normalized texture channel value.
+type Disp2DGroup struct {
+	ID                uint32
+	DisplacementID    uint32
+	NormVectorGroupID uint32
+	Height            float32
+	Offset            float32
+	Coords            []Disp2DCoord
+}
+
+// Identify returns the unique ID of the resource.
+func (r *Disp2DGroup) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (Disp2DGroup) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrDisp2DGroup}
+}
+
+// Scale multiplies the height and the offset by factor.
+func (r *Disp2DGroup) Scale(factor float32) {
+	r.Height *= factor
+	r.Offset *= factor
+}
+
+// Triangle defines a triangle of a displacement mesh.
+// D1, D2 and D3 are indices into the Disp2DGroup identified by DID,
+// or by the Triangles DID if zero. A triangle without group is not displaced.
+type Triangle struct {
+	V1, V2, V3 uint32
+	D1, D2, D3 uint32
+	DID        uint32
+	PID        uint32
+	P1, P2, P3 uint32
+}
+
+// Triangles defines the triangles of a displacement mesh.
+type Triangles struct {
+	DID      uint32
+	Triangle []Triangle
+}
+
+// DisplacementMesh defines a mesh that has to be displaced.
+type DisplacementMesh struct {
+	Vertices  []go3mf.Point3D
+	Triangles Triangles
+}
+
+// Scale multiplies the vertices by factor.
+func (d *DisplacementMesh) Scale(factor float32) {
+	for i := range d.Vertices {
+		d.Vertices[i] = d.Vertices[i].Mul(factor)
+	}
+}
+
+// ObjectContent marks the displacement mesh as the content of its object.
+func (d *DisplacementMesh) ObjectContent() {}
+
+// DisplacementGroup returns the Disp2DGroup ID of the i'th triangle,
+// zero if the triangle is not displaced.
+func (d *DisplacementMesh) DisplacementGroup(i int) uint32 {
+	if did := d.Triangles.Triangle[i].DID; did != 0 {
+		return did
+	}
+	return d.Triangles.DID
+}
+
+// GetDisplacementMesh returns the displacement mesh of the object, if any.
+func GetDisplacementMesh(obj *go3mf.Object) *DisplacementMesh {
+	for _, a := range obj.Any {
+		if a, ok := a.(*DisplacementMesh); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+const (
+	attrDisplacement2D   = "displacement2d"
+	attrNormVectorGroup  = "normvectorgroup"
+	attrNormVector       = "normvector"
+	attrDisp2DGroup      = "disp2dgroup"
+	attrDisp2DCoord      = "disp2dcoord"
+	attrDisplacementMesh = "displacementmesh"
+	attrVertices         = "vertices"
+	attrVertex           = "vertex"
+	attrTriangles        = "triangles"
+	attrTriangle         = "triangle"
+	attrID               = "id"
+	attrPath             = "path"
+	attrChannel          = "channel"
+	attrTileStyleU       = "tilestyleu"
+	attrTileStyleV       = "tilestylev"
+	attrFilter           = "filter"
+	attrX                = "x"
+	attrY                = "y"
+	attrZ                = "z"
+	attrDispID           = "dispid"
+	attrNID              = "nid"
+	attrHeight           = "height"
+	attrOffset           = "offset"
+	attrU                = "u"
+	attrV                = "v"
+	attrN                = "n"
+	attrF                = "f"
+	attrDID              = "did"
+	attrV1               = "v1"
+	attrV2               = "v2"
+	attrV3               = "v3"
+	attrD1               = "d1"
+	attrD2               = "d2"
+	attrD3               = "d3"
+	attrPID              = "pid"
+	attrP1               = "p1"
+	attrP2               = "p2"
+	attrP3               = "p3"
+)
@@ -0,0 +1,112 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+var _ go3mf.Asset = new(Displacement2D)
+var _ go3mf.Asset = new(NormVectorGroup)
+var _ go3mf.Asset = new(Disp2DGroup)
+var _ spec.Marshaler = new(Displacement2D)
+var _ spec.Marshaler = new(NormVectorGroup)
+var _ spec.Marshaler = new(Disp2DGroup)
+var _ spec.Marshaler = new(DisplacementMesh)
+var _ spec.Scaler = new(Disp2DGroup)
+var _ spec.Scaler = new(DisplacementMesh)
+var _ spec.ChildElementDecoder = new(normVectorGroupDecoder)
+var _ spec.ChildElementDecoder = new(disp2DGroupDecoder)
+var _ spec.ChildElementDecoder = new(displacementMeshDecoder)
+var _ spec.ChildElementDecoder = new(verticesDecoder)
+var _ spec.ChildElementDecoder = new(trianglesDecoder)
+
+func TestChannel_String(t *testing.T) {
+	for _, c := range []Channel{ChannelR, ChannelG, ChannelB, ChannelA} {
+		got, ok := newChannel(c.String())
+		if !ok || got != c {
+			t.Errorf("newChannel() = %v, %v, want %v", got, ok, c)
+		}
+	}
+	if _, ok := newChannel("C"); ok {
+		t.Error("newChannel() expected false")
+	}
+}
+
+func TestTileStyle_String(t *testing.T) {
+	for _, s := range []TileStyle{TileWrap, TileMirror, TileClamp, TileNone} {
+		got, ok := newTileStyle(s.String())
+		if !ok || got != s {
+			t.Errorf("newTileStyle() = %v, %v, want %v", got, ok, s)
+		}
+	}
+	if _, ok := newTileStyle("repeat"); ok {
+		t.Error("newTileStyle() expected false")
+	}
+}
+
+func TestTextureFilter_String(t *testing.T) {
+	for _, f := range []TextureFilter{TextureFilterAuto, TextureFilterLinear, TextureFilterNearest} {
+		got, ok := newTextureFilter(f.String())
+		if !ok || got != f {
+			t.Errorf("newTextureFilter() = %v, %v, want %v", got, ok, f)
+		}
+	}
+	if _, ok := newTextureFilter("cubic"); ok {
+		t.Error("newTextureFilter() expected false")
+	}
+}
+
+func TestXMLName(t *testing.T) {
+	tests := []struct {
+		a    go3mf.Asset
+		want string
+	}{
+		{new(Displacement2D), "displacement2d"},
+		{new(NormVectorGroup), "normvectorgroup"},
+		{new(Disp2DGroup), "disp2dgroup"},
+	}
+	for _, tt := range tests {
+		if got := tt.a.XMLName(); got.Space != Namespace || got.Local != tt.want {
+			t.Errorf("XMLName() = %v, want %v", got, tt.want)
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	g := &Disp2DGroup{Height: 1, Offset: -0.5}
+	g.Scale(2)
+	if want := (&Disp2DGroup{Height: 2, Offset: -1}); !reflect.DeepEqual(g, want) {
+		t.Errorf("Disp2DGroup.Scale() = %v, want %v", g, want)
+	}
+	d := &DisplacementMesh{Vertices: []go3mf.Point3D{{1, 2, 3}}}
+	d.Scale(2)
+	if want := []go3mf.Point3D{{2, 4, 6}}; !reflect.DeepEqual(d.Vertices, want) {
+		t.Errorf("DisplacementMesh.Scale() = %v, want %v", d.Vertices, want)
+	}
+}
+
+func TestDisplacementMesh_DisplacementGroup(t *testing.T) {
+	d := &DisplacementMesh{Triangles: Triangles{DID: 5, Triangle: []Triangle{{}, {DID: 6}}}}
+	if got := d.DisplacementGroup(0); got != 5 {
+		t.Errorf("DisplacementMesh.DisplacementGroup() = %v, want 5", got)
+	}
+	if got := d.DisplacementGroup(1); got != 6 {
+		t.Errorf("DisplacementMesh.DisplacementGroup() = %v, want 6", got)
+	}
+}
+
+func TestGetDisplacementMesh(t *testing.T) {
+	d := new(DisplacementMesh)
+	if got := GetDisplacementMesh(&go3mf.Object{Any: spec.Any{&spec.UnknownTokens{}, d}}); got != d {
+		t.Errorf("GetDisplacementMesh() = %v, want %v", got, d)
+	}
+	if got := GetDisplacementMesh(new(go3mf.Object)); got != nil {
+		t.Errorf("GetDisplacementMesh() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,154 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+// Marshal3MF encodes the resource.
+func (r *Displacement2D) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	x.AddRelationship(spec.Relationship{Path: r.Path, Type: RelTypeTexture3D})
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrDisplacement2D}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+		{Name: xml.Name{Local: attrPath}, Value: r.Path},
+	}}
+	if r.Channel != ChannelG {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrChannel}, Value: r.Channel.String()})
+	}
+	if r.TileStyleU != TileWrap {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrTileStyleU}, Value: r.TileStyleU.String()})
+	}
+	if r.TileStyleV != TileWrap {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrTileStyleV}, Value: r.TileStyleV.String()})
+	}
+	if r.Filter != TextureFilterAuto {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrFilter}, Value: r.Filter.String()})
+	}
+	x.SetAutoClose(true)
+	x.EncodeToken(xs)
+	x.SetAutoClose(false)
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *NormVectorGroup) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrNormVectorGroup}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	x.EncodeToken(xs)
+	x.SetAutoClose(true)
+	for _, v := range r.Vectors {
+		x.EncodeToken(pointToken(x, attrNormVector, v))
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *Disp2DGroup) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	prec := x.FloatPresicion()
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrDisp2DGroup}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+		{Name: xml.Name{Local: attrDispID}, Value: strconv.FormatUint(uint64(r.DisplacementID), 10)},
+		{Name: xml.Name{Local: attrNID}, Value: strconv.FormatUint(uint64(r.NormVectorGroupID), 10)},
+		{Name: xml.Name{Local: attrHeight}, Value: strconv.FormatFloat(float64(r.Height), 'f', prec, 32)},
+	}}
+	if r.Offset != 0 {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrOffset}, Value: strconv.FormatFloat(float64(r.Offset), 'f', prec, 32)})
+	}
+	x.EncodeToken(xs)
+	x.SetAutoClose(true)
+	for _, c := range r.Coords {
+		xc := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrDisp2DCoord}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrU}, Value: strconv.FormatFloat(float64(c.U), 'f', prec, 32)},
+			{Name: xml.Name{Local: attrV}, Value: strconv.FormatFloat(float64(c.V), 'f', prec, 32)},
+			{Name: xml.Name{Local: attrN}, Value: strconv.FormatUint(uint64(c.N), 10)},
+		}}
+		if c.F != 1 {
+			xc.Attr = append(xc.Attr, xml.Attr{Name: xml.Name{Local: attrF}, Value: strconv.FormatFloat(float64(c.F), 'f', prec, 32)})
+		}
+		x.EncodeToken(xc)
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (d *DisplacementMesh) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xm := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrDisplacementMesh}}
+	x.EncodeToken(xm)
+
+	xvs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrVertices}}
+	x.EncodeToken(xvs)
+	x.SetAutoClose(true)
+	for _, v := range d.Vertices {
+		x.EncodeToken(pointToken(x, attrVertex, v))
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xvs.End())
+
+	xts := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTriangles}}
+	if d.Triangles.DID != 0 {
+		xts.Attr = append(xts.Attr, xml.Attr{Name: xml.Name{Local: attrDID}, Value: strconv.FormatUint(uint64(d.Triangles.DID), 10)})
+	}
+	x.EncodeToken(xts)
+	x.SetAutoClose(true)
+	for _, t := range d.Triangles.Triangle {
+		x.EncodeToken(triangleToken(t))
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xts.End())
+
+	x.EncodeToken(xm.End())
+	return nil
+}
+
+func triangleToken(t Triangle) xml.StartElement {
+	xt := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTriangle}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrV1}, Value: strconv.FormatUint(uint64(t.V1), 10)},
+		{Name: xml.Name{Local: attrV2}, Value: strconv.FormatUint(uint64(t.V2), 10)},
+		{Name: xml.Name{Local: attrV3}, Value: strconv.FormatUint(uint64(t.V3), 10)},
+	}}
+	xt.Attr = appendIndices(xt.Attr, [3]string{attrD1, attrD2, attrD3}, t.D1, t.D2, t.D3)
+	if t.DID != 0 {
+		xt.Attr = append(xt.Attr, xml.Attr{Name: xml.Name{Local: attrDID}, Value: strconv.FormatUint(uint64(t.DID), 10)})
+	}
+	if t.PID != 0 {
+		xt.Attr = append(xt.Attr, xml.Attr{Name: xml.Name{Local: attrPID}, Value: strconv.FormatUint(uint64(t.PID), 10)})
+		xt.Attr = appendIndices(xt.Attr, [3]string{attrP1, attrP2, attrP3}, t.P1, t.P2, t.P3)
+	}
+	return xt
+}
+
+// appendIndices appends the indices omitting the trailing ones
+// when all of them are equal to the first one.
+func appendIndices(attrs []xml.Attr, names [3]string, i1, i2, i3 uint32) []xml.Attr {
+	if i1 == 0 && i2 == 0 && i3 == 0 {
+		return attrs
+	}
+	attrs = append(attrs, xml.Attr{Name: xml.Name{Local: names[0]}, Value: strconv.FormatUint(uint64(i1), 10)})
+	if i1 != i2 || i1 != i3 {
+		attrs = append(attrs,
+			xml.Attr{Name: xml.Name{Local: names[1]}, Value: strconv.FormatUint(uint64(i2), 10)},
+			xml.Attr{Name: xml.Name{Local: names[2]}, Value: strconv.FormatUint(uint64(i3), 10)},
+		)
+	}
+	return attrs
+}
+
+func pointToken(x spec.Encoder, name string, p go3mf.Point3D) xml.StartElement {
+	prec := x.FloatPresicion()
+	return xml.StartElement{Name: xml.Name{Space: Namespace, Local: name}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrX}, Value: strconv.FormatFloat(float64(p.X()), 'f', prec, 32)},
+		{Name: xml.Name{Local: attrY}, Value: strconv.FormatFloat(float64(p.Y()), 'f', prec, 32)},
+		{Name: xml.Name{Local: attrZ}, Value: strconv.FormatFloat(float64(p.Z()), 'f', prec, 32)},
+	}}
+}
@@ -0,0 +1,58 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestMarshalModel(t *testing.T) {
+	m := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources: go3mf.Resources{
+			Assets: []go3mf.Asset{
+				&Displacement2D{ID: 1, Path: "/3D/Textures/disp.png"},
+				&Displacement2D{ID: 2, Path: "/3D/Textures/disp2.png", Channel: ChannelA, TileStyleU: TileNone, TileStyleV: TileMirror, Filter: TextureFilterLinear},
+				&NormVectorGroup{ID: 3, Vectors: []go3mf.Point3D{{0, 0, 1}, {1, 0, 0}}},
+				&Disp2DGroup{ID: 4, DisplacementID: 1, NormVectorGroupID: 3, Height: 2, Coords: []Disp2DCoord{
+					{U: 0.25, V: 0.75, N: 1, F: 1}, {U: 1, V: 1, N: 0, F: 2},
+				}},
+				&Disp2DGroup{ID: 5, DisplacementID: 2, NormVectorGroupID: 3, Height: 1, Offset: 0.5, Coords: []Disp2DCoord{{F: 1}}},
+			},
+			Objects: []*go3mf.Object{
+				{ID: 6, PID: 4, PIndex: 1, Any: spec.Any{&DisplacementMesh{
+					Vertices: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}},
+					Triangles: Triangles{DID: 4, Triangle: []Triangle{
+						{V1: 0, V2: 2, V3: 1},
+						{V1: 0, V2: 1, V3: 3, D1: 1, D2: 1, D3: 1},
+						{V1: 0, V2: 3, V3: 2, D1: 0, D2: 1, D3: 0, DID: 5},
+						{V1: 1, V2: 2, V3: 3, PID: 4, P1: 1, P2: 0, P3: 1},
+					}},
+				}}},
+			},
+		},
+	}
+
+	t.Run("base", func(t *testing.T) {
+		b, err := go3mf.MarshalModel(m)
+		if err != nil {
+			t.Errorf("displacement.MarshalModel() error = %v", err)
+			return
+		}
+		newModel := new(go3mf.Model)
+		newModel.Path = m.Path
+		if err := go3mf.UnmarshalModel(b, newModel); err != nil {
+			t.Errorf("displacement.MarshalModel() error decoding = %v, s = %s", err, string(b))
+			return
+		}
+		if diff := deep.Equal(m, newModel); diff != nil {
+			t.Errorf("displacement.MarshalModel() = %v, s = %s", diff, string(b))
+		}
+	})
+}
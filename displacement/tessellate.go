@@ -0,0 +1,335 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"math"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+// Tessellate returns a plain mesh resulting of splitting each triangle
+// of the displacement mesh in subdivisions^2 triangles and moving the
+// new vertices along the interpolated normal vector by the displacement
+// sampled from the texture.
+//
+// Vertices shared by adjacent triangles are only displaced once,
+// using the parameters of the first triangle that contains them.
+// Triangles without a Disp2DGroup are split but not displaced,
+// and the triangle properties are not kept.
+func (d *DisplacementMesh) Tessellate(m *go3mf.Model, path string, subdivisions int) (*go3mf.Mesh, error) {
+	if subdivisions < 1 {
+		subdivisions = 1
+	}
+	t := &tessellator{
+		m: m, path: path, n: subdivisions, src: d,
+		mesh:     new(go3mf.Mesh),
+		vertices: make(map[vertexKey]uint32),
+		samplers: make(map[uint32]*sampler),
+	}
+	for i := range d.Triangles.Triangle {
+		if err := t.triangle(i); err != nil {
+			return nil, err
+		}
+	}
+	return t.mesh, nil
+}
+
+// vertexKey identifies the vertices that lay on the original edges.
+// Original vertices are identified by a == b and step 0.
+type vertexKey struct {
+	a, b uint32
+	step int
+}
+
+type tessellator struct {
+	m        *go3mf.Model
+	path     string
+	n        int
+	src      *DisplacementMesh
+	mesh     *go3mf.Mesh
+	vertices map[vertexKey]uint32
+	samplers map[uint32]*sampler
+}
+
+// corner holds the displacement parameters of a triangle vertex.
+type corner struct {
+	uv     [2]float32
+	normal go3mf.Point3D
+	f      float32
+}
+
+func (t *tessellator) triangle(i int) error {
+	tri := t.src.Triangles.Triangle[i]
+	idx := [3]uint32{tri.V1, tri.V2, tri.V3}
+	var pos [3]go3mf.Point3D
+	for j, v := range idx {
+		if int(v) >= len(t.src.Vertices) {
+			return errors.ErrIndexOutOfBounds
+		}
+		pos[j] = t.src.Vertices[v]
+	}
+	var (
+		group   *Disp2DGroup
+		smp     *sampler
+		corners [3]corner
+	)
+	if did := t.src.DisplacementGroup(i); did != 0 {
+		var err error
+		group, smp, err = t.group(did)
+		if err != nil {
+			return err
+		}
+		normals, err := t.normals(group)
+		if err != nil {
+			return err
+		}
+		for j, di := range [3]uint32{tri.D1, tri.D2, tri.D3} {
+			if int(di) >= len(group.Coords) {
+				return errors.ErrIndexOutOfBounds
+			}
+			c := group.Coords[di]
+			if int(c.N) >= len(normals.Vectors) {
+				return errors.ErrIndexOutOfBounds
+			}
+			corners[j] = corner{uv: [2]float32{c.U, c.V}, normal: normals.Vectors[c.N], f: c.F}
+		}
+	}
+
+	n := t.n
+	grid := make([][]uint32, n+1)
+	for a := 0; a <= n; a++ {
+		grid[a] = make([]uint32, n+1-a)
+		for b := 0; b <= n-a; b++ {
+			w := [3]float32{float32(n-a-b) / float32(n), float32(a) / float32(n), float32(b) / float32(n)}
+			key, shared := t.key(idx, n-a-b, a, b)
+			if shared {
+				if v, ok := t.vertices[key]; ok {
+					grid[a][b] = v
+					continue
+				}
+			}
+			p := pos[0].Mul(w[0]).Add(pos[1].Mul(w[1])).Add(pos[2].Mul(w[2]))
+			if group != nil {
+				p = p.Add(displace(group, smp, corners, w))
+			}
+			v := uint32(len(t.mesh.Vertices.Vertex))
+			t.mesh.Vertices.Vertex = append(t.mesh.Vertices.Vertex, p)
+			if shared {
+				t.vertices[key] = v
+			}
+			grid[a][b] = v
+		}
+	}
+	for a := 0; a < n; a++ {
+		for b := 0; b < n-a; b++ {
+			t.mesh.Triangles.Triangle = append(t.mesh.Triangles.Triangle,
+				go3mf.Triangle{V1: grid[a][b], V2: grid[a+1][b], V3: grid[a][b+1]})
+			if a+b < n-1 {
+				t.mesh.Triangles.Triangle = append(t.mesh.Triangles.Triangle,
+					go3mf.Triangle{V1: grid[a+1][b], V2: grid[a+1][b+1], V3: grid[a][b+1]})
+			}
+		}
+	}
+	return nil
+}
+
+// key returns the identifier of the grid point with barycentric steps (s0, s1, s2),
+// and false if the point is inside the triangle.
+func (t *tessellator) key(idx [3]uint32, s0, s1, s2 int) (vertexKey, bool) {
+	steps := [3]int{s0, s1, s2}
+	for j := range idx {
+		if steps[j] == t.n {
+			return vertexKey{a: idx[j], b: idx[j]}, true
+		}
+	}
+	for j := range idx {
+		if steps[j] != 0 {
+			continue
+		}
+		a, b := (j+1)%3, (j+2)%3
+		if idx[a] > idx[b] {
+			a, b = b, a
+		}
+		return vertexKey{a: idx[a], b: idx[b], step: steps[b]}, true
+	}
+	return vertexKey{}, false
+}
+
+func displace(g *Disp2DGroup, smp *sampler, c [3]corner, w [3]float32) go3mf.Point3D {
+	var (
+		normal go3mf.Point3D
+		u, v   float32
+		f      float32
+	)
+	for j := range c {
+		normal = normal.Add(c[j].normal.Mul(w[j]))
+		u += c[j].uv[0] * w[j]
+		v += c[j].uv[1] * w[j]
+		f += c[j].f * w[j]
+	}
+	if normal.Len() == 0 {
+		return go3mf.Point3D{}
+	}
+	h := f * (g.Height*smp.sample(u, v) + g.Offset)
+	return normal.Normalize().Mul(h)
+}
+
+func (t *tessellator) group(id uint32) (*Disp2DGroup, *sampler, error) {
+	a, ok := t.m.FindAsset(t.path, id)
+	if !ok {
+		return nil, nil, ErrDisp2DGroupReference
+	}
+	g, ok := a.(*Disp2DGroup)
+	if !ok {
+		return nil, nil, ErrDisp2DGroupReference
+	}
+	if s, ok := t.samplers[g.DisplacementID]; ok {
+		return g, s, nil
+	}
+	a, ok = t.m.FindAsset(t.path, g.DisplacementID)
+	if !ok {
+		return nil, nil, ErrDisplacementReference
+	}
+	tex, ok := a.(*Displacement2D)
+	if !ok {
+		return nil, nil, ErrDisplacementReference
+	}
+	img, err := tex.Image(t.m)
+	if err != nil {
+		return nil, nil, err
+	}
+	s := newSampler(tex, img)
+	t.samplers[g.DisplacementID] = s
+	return g, s, nil
+}
+
+func (t *tessellator) normals(g *Disp2DGroup) (*NormVectorGroup, error) {
+	a, ok := t.m.FindAsset(t.path, g.NormVectorGroupID)
+	if !ok {
+		return nil, ErrNormVectorReference
+	}
+	normals, ok := a.(*NormVectorGroup)
+	if !ok {
+		return nil, ErrNormVectorReference
+	}
+	return normals, nil
+}
+
+// Image decodes the PNG texture, which is read from the model attachments.
+//
+// The attachment stream is not consumed, so the model can still be encoded.
+func (r *Displacement2D) Image(m *go3mf.Model) (image.Image, error) {
+	a, ok := findAttachment(m, r.Path)
+	if !ok {
+		return nil, ErrMissingDisplacementPart
+	}
+	var b []byte
+	if buf, ok := a.Stream.(*bytes.Buffer); ok {
+		b = buf.Bytes()
+	} else {
+		var err error
+		if b, err = ioutil.ReadAll(a.Stream); err != nil {
+			return nil, err
+		}
+		a.Stream = bytes.NewBuffer(b)
+	}
+	return png.Decode(bytes.NewReader(b))
+}
+
+// sampler reads the normalized channel values of a displacement texture.
+type sampler struct {
+	img          image.Image
+	channel      Channel
+	tileU, tileV TileStyle
+	nearest      bool
+}
+
+func newSampler(r *Displacement2D, img image.Image) *sampler {
+	return &sampler{
+		img: img, channel: r.Channel,
+		tileU: r.TileStyleU, tileV: r.TileStyleV,
+		nearest: r.Filter == TextureFilterNearest,
+	}
+}
+
+// sample returns the channel value at (u, v), in the range [0, 1].
+// The v axis points up, so v=0 is the bottom row of the image.
+func (s *sampler) sample(u, v float32) float32 {
+	b := s.img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return 0
+	}
+	x := float64(u)*float64(w) - 0.5
+	y := (1-float64(v))*float64(h) - 0.5
+	if s.nearest {
+		return s.texel(int(math.Round(x)), int(math.Round(y)))
+	}
+	x0, y0 := math.Floor(x), math.Floor(y)
+	fx, fy := float32(x-x0), float32(y-y0)
+	ix, iy := int(x0), int(y0)
+	top := s.texel(ix, iy)*(1-fx) + s.texel(ix+1, iy)*fx
+	bottom := s.texel(ix, iy+1)*(1-fx) + s.texel(ix+1, iy+1)*fx
+	return top*(1-fy) + bottom*fy
+}
+
+func (s *sampler) texel(x, y int) float32 {
+	b := s.img.Bounds()
+	var ok bool
+	if x, ok = tile(x, b.Dx(), s.tileU); !ok {
+		return 0
+	}
+	if y, ok = tile(y, b.Dy(), s.tileV); !ok {
+		return 0
+	}
+	c := color.NRGBA64Model.Convert(s.img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA64)
+	var val uint16
+	switch s.channel {
+	case ChannelR:
+		val = c.R
+	case ChannelG:
+		val = c.G
+	case ChannelB:
+		val = c.B
+	case ChannelA:
+		val = c.A
+	}
+	return float32(val) / math.MaxUint16
+}
+
+// tile maps i into [0, n) following the tile style,
+// returning false if the texel is outside the texture.
+func tile(i, n int, style TileStyle) (int, bool) {
+	switch style {
+	case TileMirror:
+		i = mod(i, 2*n)
+		if i >= n {
+			i = 2*n - 1 - i
+		}
+	case TileClamp:
+		if i < 0 {
+			i = 0
+		} else if i >= n {
+			i = n - 1
+		}
+	case TileNone:
+		if i < 0 || i >= n {
+			return 0, false
+		}
+	default:
+		i = mod(i, n)
+	}
+	return i, true
+}
+
+func mod(a, b int) int {
+	return ((a % b) + b) % b
+}
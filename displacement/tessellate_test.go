@@ -0,0 +1,179 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func newTextureModel(t *testing.T, img image.Image) *go3mf.Model {
+	t.Helper()
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return &go3mf.Model{
+		Path:        "/3D/3dmodel.model",
+		Attachments: []go3mf.Attachment{{Path: "/3D/Textures/disp.png", ContentType: ContentTypePNG, Stream: buf}},
+		Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			&Displacement2D{ID: 1, Path: "/3D/Textures/disp.png"},
+			&NormVectorGroup{ID: 2, Vectors: []go3mf.Point3D{{0, 0, 1}, {0, 0, 0}}},
+			&Disp2DGroup{ID: 3, DisplacementID: 1, NormVectorGroupID: 2, Height: 2, Offset: 0.5, Coords: []Disp2DCoord{
+				{U: 0, V: 0, F: 1}, {U: 1, V: 0, F: 1}, {U: 0, V: 1, F: 1}, {U: 1, V: 1, F: 1}, {N: 1, F: 1},
+			}},
+		}},
+	}
+}
+
+func uniformImage(v uint8) image.Image {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = v
+	}
+	return img
+}
+
+func TestDisplacementMesh_Tessellate(t *testing.T) {
+	quad := []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {10, 10, 0}}
+	tests := []struct {
+		name          string
+		mesh          *DisplacementMesh
+		subdivisions  int
+		wantVertices  int
+		wantTriangles int
+		wantZ         float32
+	}{
+		{"noGroup", &DisplacementMesh{Vertices: quad[:3], Triangles: Triangles{Triangle: []Triangle{
+			{V1: 0, V2: 1, V3: 2},
+		}}}, 2, 6, 4, 0},
+		{"zeroSubdivisions", &DisplacementMesh{Vertices: quad[:3], Triangles: Triangles{DID: 3, Triangle: []Triangle{
+			{V1: 0, V2: 1, V3: 2, D1: 0, D2: 1, D3: 2},
+		}}}, 0, 3, 1, 2.5},
+		{"shared", &DisplacementMesh{Vertices: quad, Triangles: Triangles{DID: 3, Triangle: []Triangle{
+			{V1: 0, V2: 1, V3: 2, D1: 0, D2: 1, D3: 2},
+			{V1: 1, V2: 3, V3: 2, D1: 1, D2: 3, D3: 2},
+		}}}, 3, 16, 18, 2.5},
+		{"zeroNormal", &DisplacementMesh{Vertices: quad[:3], Triangles: Triangles{DID: 3, Triangle: []Triangle{
+			{V1: 0, V2: 1, V3: 2, D1: 4, D2: 4, D3: 4},
+		}}}, 1, 3, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTextureModel(t, uniformImage(255))
+			got, err := tt.mesh.Tessellate(m, "", tt.subdivisions)
+			if err != nil {
+				t.Fatalf("DisplacementMesh.Tessellate() error = %v", err)
+			}
+			if n := len(got.Vertices.Vertex); n != tt.wantVertices {
+				t.Errorf("DisplacementMesh.Tessellate() vertices = %d, want %d", n, tt.wantVertices)
+			}
+			if n := len(got.Triangles.Triangle); n != tt.wantTriangles {
+				t.Errorf("DisplacementMesh.Tessellate() triangles = %d, want %d", n, tt.wantTriangles)
+			}
+			for i, v := range got.Vertices.Vertex {
+				if v.Z() != tt.wantZ {
+					t.Errorf("DisplacementMesh.Tessellate() vertex %d = %v, want z %v", i, v, tt.wantZ)
+				}
+			}
+			if _, ok := m.Attachments[0].Stream.(*bytes.Buffer); !ok || m.Attachments[0].Stream.(*bytes.Buffer).Len() == 0 {
+				t.Error("DisplacementMesh.Tessellate() consumed the texture attachment")
+			}
+		})
+	}
+}
+
+func TestDisplacementMesh_Tessellate_error(t *testing.T) {
+	tests := []struct {
+		name string
+		mesh *DisplacementMesh
+		want error
+	}{
+		{"vertex", &DisplacementMesh{Triangles: Triangles{Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}}}, nil},
+		{"group", &DisplacementMesh{Vertices: make([]go3mf.Point3D, 3), Triangles: Triangles{DID: 2, Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}}}, ErrDisp2DGroupReference},
+		{"coord", &DisplacementMesh{Vertices: make([]go3mf.Point3D, 3), Triangles: Triangles{DID: 3, Triangle: []Triangle{{V1: 0, V2: 1, V3: 2, D1: 10}}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTextureModel(t, uniformImage(0))
+			_, err := tt.mesh.Tessellate(m, "", 1)
+			if err == nil {
+				t.Fatal("DisplacementMesh.Tessellate() error expected")
+			}
+			if tt.want != nil && err != tt.want {
+				t.Errorf("DisplacementMesh.Tessellate() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	t.Run("texture", func(t *testing.T) {
+		m := newTextureModel(t, uniformImage(0))
+		m.Attachments = nil
+		mesh := &DisplacementMesh{Vertices: make([]go3mf.Point3D, 3), Triangles: Triangles{DID: 3, Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}}}
+		if _, err := mesh.Tessellate(m, "", 1); err != ErrMissingDisplacementPart {
+			t.Errorf("DisplacementMesh.Tessellate() error = %v, want %v", err, ErrMissingDisplacementPart)
+		}
+	})
+}
+
+func TestSampler_sample(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	img.Set(1, 0, color.NRGBA{G: 255, A: 255})
+	img.Set(0, 1, color.NRGBA{B: 255, A: 255})
+	img.Set(1, 1, color.NRGBA{A: 0})
+	tests := []struct {
+		name string
+		tex  *Displacement2D
+		u, v float32
+		want float32
+	}{
+		{"topLeftR", &Displacement2D{Channel: ChannelR, Filter: TextureFilterNearest}, 0.25, 0.75, 1},
+		{"topRightG", &Displacement2D{Channel: ChannelG, Filter: TextureFilterNearest}, 0.75, 0.75, 1},
+		{"bottomLeftB", &Displacement2D{Channel: ChannelB, Filter: TextureFilterNearest}, 0.25, 0.25, 1},
+		{"bottomRightA", &Displacement2D{Channel: ChannelA, Filter: TextureFilterNearest}, 0.75, 0.25, 0},
+		{"linear", &Displacement2D{Channel: ChannelR, TileStyleU: TileClamp, TileStyleV: TileClamp}, 0.5, 0.75, 0.5},
+		{"wrap", &Displacement2D{Channel: ChannelR, Filter: TextureFilterNearest}, 1.25, 1.75, 1},
+		{"none", &Displacement2D{Channel: ChannelR, Filter: TextureFilterNearest, TileStyleU: TileNone}, 1.25, 0.75, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newSampler(tt.tex, img).sample(tt.u, tt.v); got != tt.want {
+				t.Errorf("sampler.sample() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_tile(t *testing.T) {
+	tests := []struct {
+		name   string
+		i, n   int
+		style  TileStyle
+		want   int
+		wantOk bool
+	}{
+		{"wrapInside", 2, 4, TileWrap, 2, true},
+		{"wrapOver", 5, 4, TileWrap, 1, true},
+		{"wrapUnder", -1, 4, TileWrap, 3, true},
+		{"mirrorOver", 5, 4, TileMirror, 2, true},
+		{"mirrorUnder", -1, 4, TileMirror, 0, true},
+		{"clampOver", 5, 4, TileClamp, 3, true},
+		{"clampUnder", -1, 4, TileClamp, 0, true},
+		{"noneInside", 3, 4, TileNone, 3, true},
+		{"noneOver", 4, 4, TileNone, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tile(tt.i, tt.n, tt.style)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("tile() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+func (Spec) Validate(m interface{}, path string, e interface{}) error {
+	switch e := e.(type) {
+	case *go3mf.Object:
+		return validateObject(m.(*go3mf.Model), path, e)
+	case go3mf.Asset:
+		return validateAsset(m.(*go3mf.Model), path, e)
+	}
+	return nil
+}
+
+func validateAsset(m *go3mf.Model, path string, r go3mf.Asset) (errs error) {
+	switch r := r.(type) {
+	case *Displacement2D:
+		errs = validateDisplacement2D(m, r)
+	case *NormVectorGroup:
+		errs = validateNormVectorGroup(r)
+	case *Disp2DGroup:
+		errs = validateDisp2DGroup(m, path, r)
+	}
+	return
+}
+
+func validateDisplacement2D(m *go3mf.Model, r *Displacement2D) (errs error) {
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if r.Path == "" {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrPath))
+	} else if a, ok := findAttachment(m, r.Path); !ok {
+		errs = errors.Append(errs, ErrMissingDisplacementPart)
+	} else if a.ContentType != ContentTypePNG {
+		errs = errors.Append(errs, ErrDisplacementContentType)
+	}
+	return
+}
+
+func validateNormVectorGroup(r *NormVectorGroup) (errs error) {
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if len(r.Vectors) == 0 {
+		errs = errors.Append(errs, errors.ErrEmptyResourceProps)
+	}
+	for i, v := range r.Vectors {
+		if v.Len() == 0 {
+			errs = errors.Append(errs, errors.WrapIndex(ErrZeroNormVector, attrNormVector, i))
+		}
+	}
+	return
+}
+
+func validateDisp2DGroup(m *go3mf.Model, path string, r *Disp2DGroup) (errs error) {
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if r.DisplacementID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrDispID))
+	} else if a, ok := m.FindAsset(path, r.DisplacementID); !ok {
+		errs = errors.Append(errs, ErrDisplacementReference)
+	} else if _, ok := a.(*Displacement2D); !ok {
+		errs = errors.Append(errs, ErrDisplacementReference)
+	}
+	var normals *NormVectorGroup
+	if r.NormVectorGroupID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrNID))
+	} else if a, ok := m.FindAsset(path, r.NormVectorGroupID); !ok {
+		errs = errors.Append(errs, ErrNormVectorReference)
+	} else if normals, ok = a.(*NormVectorGroup); !ok {
+		errs = errors.Append(errs, ErrNormVectorReference)
+	}
+	if r.Height == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrHeight))
+	}
+	if len(r.Coords) == 0 {
+		errs = errors.Append(errs, errors.ErrEmptyResourceProps)
+	}
+	if normals != nil {
+		for i, c := range r.Coords {
+			if int(c.N) >= len(normals.Vectors) {
+				errs = errors.Append(errs, errors.WrapIndex(errors.ErrIndexOutOfBounds, attrDisp2DCoord, i))
+			}
+		}
+	}
+	return
+}
+
+func validateObject(m *go3mf.Model, path string, obj *go3mf.Object) error {
+	mesh := GetDisplacementMesh(obj)
+	if mesh == nil {
+		return nil
+	}
+	var errs error
+	if obj.Mesh != nil || obj.Components != nil {
+		errs = errors.Append(errs, ErrDisplacementObjectContent)
+	}
+	switch obj.Type {
+	case go3mf.ObjectTypeModel, go3mf.ObjectTypeSolidSupport:
+		if len(mesh.Vertices) < 3 {
+			errs = errors.Append(errs, errors.ErrInsufficientVertices)
+		}
+		if len(mesh.Triangles.Triangle) <= 3 {
+			errs = errors.Append(errs, errors.ErrInsufficientTriangles)
+		}
+	}
+	groups := make(map[uint32]*Disp2DGroup)
+	findGroup := func(id uint32) (*Disp2DGroup, bool) {
+		if g, ok := groups[id]; ok {
+			return g, g != nil
+		}
+		var g *Disp2DGroup
+		if a, ok := m.FindAsset(path, id); ok {
+			g, _ = a.(*Disp2DGroup)
+		}
+		groups[id] = g
+		return g, g != nil
+	}
+	if mesh.Triangles.DID != 0 {
+		if _, ok := findGroup(mesh.Triangles.DID); !ok {
+			errs = errors.Append(errs, errors.Wrap(ErrDisp2DGroupReference, attrTriangles))
+		}
+	}
+	nodeCount := uint32(len(mesh.Vertices))
+	for i, t := range mesh.Triangles.Triangle {
+		if t.V1 == t.V2 || t.V1 == t.V3 || t.V2 == t.V3 {
+			errs = errors.Append(errs, errors.WrapIndex(errors.ErrDuplicatedIndices, attrTriangle, i))
+		}
+		if t.V1 >= nodeCount || t.V2 >= nodeCount || t.V3 >= nodeCount {
+			errs = errors.Append(errs, errors.WrapIndex(errors.ErrIndexOutOfBounds, attrTriangle, i))
+		}
+		did := mesh.DisplacementGroup(i)
+		if did == 0 {
+			continue
+		}
+		if g, ok := findGroup(did); !ok {
+			if t.DID != 0 {
+				errs = errors.Append(errs, errors.WrapIndex(ErrDisp2DGroupReference, attrTriangle, i))
+			}
+		} else {
+			l := uint32(len(g.Coords))
+			if t.D1 >= l || t.D2 >= l || t.D3 >= l {
+				errs = errors.Append(errs, errors.WrapIndex(errors.ErrIndexOutOfBounds, attrTriangle, i))
+			}
+		}
+	}
+	if errs != nil {
+		errs = errors.Wrap(errs, attrDisplacementMesh)
+	}
+	return errs
+}
+
+func findAttachment(m *go3mf.Model, path string) (*go3mf.Attachment, bool) {
+	for i, a := range m.Attachments {
+		if strings.EqualFold(a.Path, path) {
+			return &m.Attachments[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,113 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package displacement
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestValidate(t *testing.T) {
+	tetra := []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}
+	tests := []struct {
+		name  string
+		model *go3mf.Model
+		want  []string
+	}{
+		{"displacement2d", &go3mf.Model{Attachments: []go3mf.Attachment{
+			{Path: "/a.png", ContentType: ContentTypePNG, Stream: new(bytes.Buffer)},
+			{Path: "/b.jpg", ContentType: "image/jpeg", Stream: new(bytes.Buffer)},
+		}, Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			&Displacement2D{},
+			&Displacement2D{ID: 1, Path: "/c.png"},
+			&Displacement2D{ID: 2, Path: "/b.jpg"},
+			&Displacement2D{ID: 3, Path: "/A.png"},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[0]: %v", &errors.MissingFieldError{Name: attrPath}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[1]: %v", ErrMissingDisplacementPart),
+			fmt.Sprintf("go3mf: XPath: /model/resources/displacement2d[2]: %v", ErrDisplacementContentType),
+		}},
+		{"normvectorgroup", &go3mf.Model{Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			&NormVectorGroup{},
+			&NormVectorGroup{ID: 1, Vectors: []go3mf.Point3D{{0, 0, 1}, {}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/normvectorgroup[0]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/normvectorgroup[0]: %v", errors.ErrEmptyResourceProps),
+			fmt.Sprintf("go3mf: XPath: /model/resources/normvectorgroup[1]/normvector[1]: %v", ErrZeroNormVector),
+		}},
+		{"disp2dgroup", &go3mf.Model{Attachments: []go3mf.Attachment{
+			{Path: "/a.png", ContentType: ContentTypePNG, Stream: new(bytes.Buffer)},
+		}, Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			&Displacement2D{ID: 1, Path: "/a.png"},
+			&NormVectorGroup{ID: 2, Vectors: []go3mf.Point3D{{0, 0, 1}}},
+			&Disp2DGroup{},
+			&Disp2DGroup{ID: 3, DisplacementID: 2, NormVectorGroupID: 1, Height: 1, Coords: []Disp2DCoord{{N: 5}}},
+			&Disp2DGroup{ID: 4, DisplacementID: 100, NormVectorGroupID: 100, Height: 1, Coords: []Disp2DCoord{{N: 5}}},
+			&Disp2DGroup{ID: 5, DisplacementID: 1, NormVectorGroupID: 2, Height: 1, Coords: []Disp2DCoord{{N: 0}, {N: 1}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.MissingFieldError{Name: attrDispID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.MissingFieldError{Name: attrNID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", &errors.MissingFieldError{Name: attrHeight}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[2]: %v", errors.ErrEmptyResourceProps),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[3]: %v", ErrDisplacementReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[3]: %v", ErrNormVectorReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[4]: %v", ErrDisplacementReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[4]: %v", ErrNormVectorReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/disp2dgroup[5]/disp2dcoord[1]: %v", errors.ErrIndexOutOfBounds),
+		}},
+		{"displacementmesh", &go3mf.Model{Attachments: []go3mf.Attachment{
+			{Path: "/a.png", ContentType: ContentTypePNG, Stream: new(bytes.Buffer)},
+		}, Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			&Displacement2D{ID: 1, Path: "/a.png"},
+			&NormVectorGroup{ID: 2, Vectors: []go3mf.Point3D{{0, 0, 1}}},
+			&Disp2DGroup{ID: 3, DisplacementID: 1, NormVectorGroupID: 2, Height: 1, Coords: []Disp2DCoord{{}, {}}},
+		}, Objects: []*go3mf.Object{
+			{ID: 4, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 5}}}, Any: spec.Any{&DisplacementMesh{
+				Vertices: tetra[:2], Triangles: Triangles{DID: 2, Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}},
+			}}},
+			{ID: 5, Any: spec.Any{&DisplacementMesh{
+				Vertices: tetra, Triangles: Triangles{DID: 3, Triangle: []Triangle{
+					{V1: 0, V2: 2, V3: 1},
+					{V1: 0, V2: 1, V3: 1, D1: 2},
+					{V1: 0, V2: 3, V3: 4, DID: 1},
+					{V1: 1, V2: 2, V3: 3, D1: 1, D2: 1, D3: 1},
+				}},
+			}}},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh: %v", ErrDisplacementObjectContent),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh: %v", errors.ErrInsufficientVertices),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh: %v", errors.ErrInsufficientTriangles),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/triangles: %v", ErrDisp2DGroupReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/displacementmesh/triangle[0]: %v", errors.ErrIndexOutOfBounds),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/displacementmesh/triangle[1]: %v", errors.ErrDuplicatedIndices),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/displacementmesh/triangle[1]: %v", errors.ErrIndexOutOfBounds),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/displacementmesh/triangle[2]: %v", errors.ErrIndexOutOfBounds),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/displacementmesh/triangle[2]: %v", ErrDisp2DGroupReference),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Extensions = []go3mf.Extension{DefaultExtension}
+			err := tt.model.Validate()
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			var errs []string
+			for _, err := range err.(*errors.List).Errors {
+				errs = append(errs, err.Error())
+			}
+			if diff := deep.Equal(errs, tt.want); diff != nil {
+				t.Errorf("Validate() = %v", diff)
+			}
+		})
+	}
+}
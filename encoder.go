@@ -126,12 +126,28 @@ func CreateWriter(name string) (*WriteCloser, error) {
 	}, nil
 }
 
+// A PartWriter transforms the content of the package parts before they are written,
+// which can be used to encrypt them.
+type PartWriter interface {
+	// Writer returns the writer used to encode the named part, which writes into w.
+	// It is closed once the part is complete.
+	Writer(name, contentType string, w io.Writer) (io.WriteCloser, error)
+	// Close is called once all the parts are written, before closing the package.
+	// create adds a new part and addRel adds a package root relationship,
+	// none of them is transformed by the PartWriter.
+	Close(create func(name, contentType string) (io.Writer, error), addRel func(Relationship)) error
+}
+
 // An Encoder writes Model data to an output stream.
 //
 // See the documentation for strconv.FormatFloat for details about the FloatPrecision behaviour.
 type Encoder struct {
 	FloatPrecision int
-	w              packageWriter
+	// PartWriter, if not nil, is applied to every model part and attachment.
+	PartWriter PartWriter
+	// Signer, if not nil, signs the package once all the parts are written.
+	Signer *Signer
+	w      packageWriter
 }
 
 // NewEncoder returns a new encoder that writes to w.
@@ -144,9 +160,27 @@ func NewEncoder(w io.Writer) *Encoder {
 
 // Encode writes the XML encoding of m to the stream.
 func (e *Encoder) Encode(m *Model) error {
-	if err := e.writeAttachements(m.Attachments); err != nil {
+	w, enc, err := e.createRootModel(m)
+	if err != nil {
 		return err
 	}
+	if err = e.writeModel(enc, m); err != nil {
+		return err
+	}
+	return e.closeRootModel(m, w, enc)
+}
+
+// createRootModel writes the attachments and creates the root model part.
+func (e *Encoder) createRootModel(m *Model) (packagePart, *xmlEncoder, error) {
+	if w, ok := e.w.(*opcWriter); ok {
+		w.signer = e.Signer
+	}
+	if _, ok := e.w.(*partWriterFilter); !ok && e.PartWriter != nil {
+		e.w = &partWriterFilter{w: e.w, f: e.PartWriter}
+	}
+	if err := e.writeAttachements(m.Attachments); err != nil {
+		return nil, nil, err
+	}
 	rootName := m.PathOrDefault()
 	for _, r := range m.RootRelationships {
 		e.w.AddRelationship(r)
@@ -155,10 +189,10 @@ func (e *Encoder) Encode(m *Model) error {
 
 	w, err := e.w.Create(rootName, ContentType3DModel)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 	if _, err := w.Write([]byte(xml.Header)); err != nil {
-		return err
+		return nil, nil, err
 	}
 	enc := newXMLEncoder(w, e.FloatPrecision)
 	enc.relationships = make([]Relationship, len(m.Relationships))
@@ -166,16 +200,18 @@ func (e *Encoder) Encode(m *Model) error {
 	for path := range m.Childs {
 		enc.AddRelationship(spec.Relationship{Type: RelType3DModel, Path: path})
 	}
-	if err = e.writeModel(enc, m); err != nil {
-		return err
-	}
+	return w, enc, nil
+}
+
+// closeRootModel adds the root model relationships, writes the child models
+// and closes the package.
+func (e *Encoder) closeRootModel(m *Model, w packagePart, enc *xmlEncoder) error {
 	for _, r := range enc.relationships {
 		w.AddRelationship(r)
 	}
-	if err = e.writeChildModels(m); err != nil {
+	if err := e.writeChildModels(m); err != nil {
 		return err
 	}
-
 	return e.w.Close()
 }
 
@@ -204,6 +240,64 @@ func (e *Encoder) writeChildModels(m *Model) error {
 	return nil
 }
 
+// partWriterFilter applies a PartWriter to the parts created in w.
+type partWriterFilter struct {
+	w       packageWriter
+	f       PartWriter
+	current io.WriteCloser
+}
+
+func (p *partWriterFilter) Create(name, contentType string) (packagePart, error) {
+	if err := p.closeCurrent(); err != nil {
+		return nil, err
+	}
+	part, err := p.w.Create(name, contentType)
+	if err != nil {
+		return nil, err
+	}
+	wc, err := p.f.Writer(name, contentType, part)
+	if err != nil {
+		return nil, err
+	}
+	p.current = wc
+	return &filteredPart{Writer: wc, part: part}, nil
+}
+
+type filteredPart struct {
+	io.Writer
+	part packagePart
+}
+
+func (p *filteredPart) AddRelationship(r Relationship) {
+	p.part.AddRelationship(r)
+}
+
+func (p *partWriterFilter) AddRelationship(r Relationship) {
+	p.w.AddRelationship(r)
+}
+
+func (p *partWriterFilter) Close() error {
+	if err := p.closeCurrent(); err != nil {
+		return err
+	}
+	err := p.f.Close(func(name, contentType string) (io.Writer, error) {
+		return p.w.Create(name, contentType)
+	}, p.w.AddRelationship)
+	if err != nil {
+		return err
+	}
+	return p.w.Close()
+}
+
+func (p *partWriterFilter) closeCurrent() error {
+	if p.current == nil {
+		return nil
+	}
+	err := p.current.Close()
+	p.current = nil
+	return err
+}
+
 func (e *Encoder) writeAttachements(att []Attachment) error {
 	for _, a := range att {
 		w, err := e.w.Create(a.Path, a.ContentType)
@@ -373,6 +467,23 @@ func (e *Encoder) writeMetadata(x spec.Encoder, metadata []Metadata) {
 }
 
 func (e *Encoder) writeObject(x spec.Encoder, r *Object) {
+	xo := e.objectToken(x, r, r.Components == nil)
+	x.EncodeToken(xo)
+
+	if len(r.Metadata.Metadata) != 0 {
+		e.writeMetadataGroup(x, r.Metadata)
+	}
+
+	if r.Mesh != nil {
+		e.writeMesh(x, r, r.Mesh)
+	} else if r.Components != nil {
+		e.writeComponents(x, r.Components)
+	}
+	r.Any.Marshal3MF(x, &xo)
+	x.EncodeToken(xo.End())
+}
+
+func (e *Encoder) objectToken(x spec.Encoder, r *Object, hasPID bool) xml.StartElement {
 	xo := xml.StartElement{Name: xml.Name{Local: attrObject}, Attr: []xml.Attr{
 		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
 	}}
@@ -389,7 +500,7 @@ func (e *Encoder) writeObject(x spec.Encoder, r *Object) {
 	if r.Name != "" {
 		xo.Attr = append(xo.Attr, xml.Attr{Name: xml.Name{Local: attrName}, Value: r.Name})
 	}
-	if r.Mesh != nil {
+	if hasPID {
 		if r.PID != 0 {
 			xo.Attr = append(xo.Attr, xml.Attr{
 				Name: xml.Name{Local: attrPID}, Value: strconv.FormatUint(uint64(r.PID), 10),
@@ -402,18 +513,7 @@ func (e *Encoder) writeObject(x spec.Encoder, r *Object) {
 		}
 	}
 	r.AnyAttr.Marshal3MF(x, &xo)
-	x.EncodeToken(xo)
-
-	if len(r.Metadata.Metadata) != 0 {
-		e.writeMetadataGroup(x, r.Metadata)
-	}
-
-	if r.Mesh != nil {
-		e.writeMesh(x, r, r.Mesh)
-	} else if r.Components != nil {
-		e.writeComponents(x, r.Components)
-	}
-	x.EncodeToken(xo.End())
+	return xo
 }
 
 func (e *Encoder) writeComponents(x spec.Encoder, comps *Components) {
@@ -441,6 +541,11 @@ func (e *Encoder) writeVertices(x spec.Encoder, m *Mesh) {
 	xvs := xml.StartElement{Name: xml.Name{Local: attrVertices}}
 	m.Vertices.AnyAttr.Marshal3MF(x, &xvs)
 	x.EncodeToken(xvs)
+	e.writeVertexChunk(x, m.Vertices.Vertex)
+	x.EncodeToken(xvs.End())
+}
+
+func (e *Encoder) writeVertexChunk(x spec.Encoder, vs []Point3D) {
 	prec := x.FloatPresicion()
 	start := xml.StartElement{
 		Name: xml.Name{Local: attrVertex},
@@ -452,7 +557,7 @@ func (e *Encoder) writeVertices(x spec.Encoder, m *Mesh) {
 	}
 	x.SetAutoClose(true)
 	x.SetSkipAttrEscape(true)
-	for _, v := range m.Vertices.Vertex {
+	for _, v := range vs {
 		start.Attr[0].Value = strconv.FormatFloat(float64(v.X()), 'f', prec, 32)
 		start.Attr[1].Value = strconv.FormatFloat(float64(v.Y()), 'f', prec, 32)
 		start.Attr[2].Value = strconv.FormatFloat(float64(v.Z()), 'f', prec, 32)
@@ -460,13 +565,17 @@ func (e *Encoder) writeVertices(x spec.Encoder, m *Mesh) {
 	}
 	x.SetSkipAttrEscape(false)
 	x.SetAutoClose(false)
-	x.EncodeToken(xvs.End())
 }
 
 func (e *Encoder) writeTriangles(x spec.Encoder, r *Object, m *Mesh) {
 	xvt := xml.StartElement{Name: xml.Name{Local: attrTriangles}}
 	m.Triangles.AnyAttr.Marshal3MF(x, &xvt)
 	x.EncodeToken(xvt)
+	e.writeTriangleChunk(x, r, m.Triangles.Triangle)
+	x.EncodeToken(xvt.End())
+}
+
+func (e *Encoder) writeTriangleChunk(x spec.Encoder, r *Object, ts []Triangle) {
 	start := xml.StartElement{
 		Name: xml.Name{Local: attrTriangle},
 	}
@@ -481,7 +590,7 @@ func (e *Encoder) writeTriangles(x spec.Encoder, r *Object, m *Mesh) {
 	}
 	x.SetAutoClose(true)
 	x.SetSkipAttrEscape(true)
-	for _, t := range m.Triangles.Triangle {
+	for _, t := range ts {
 		attrs[0].Value = strconv.FormatUint(uint64(t.V1), 10)
 		attrs[1].Value = strconv.FormatUint(uint64(t.V2), 10)
 		attrs[2].Value = strconv.FormatUint(uint64(t.V3), 10)
@@ -504,7 +613,6 @@ func (e *Encoder) writeTriangles(x spec.Encoder, r *Object, m *Mesh) {
 	}
 	x.SetSkipAttrEscape(false)
 	x.SetAutoClose(false)
-	x.EncodeToken(xvt.End())
 }
 
 func (e *Encoder) writeMesh(x spec.Encoder, r *Object, m *Mesh) {
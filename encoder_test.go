@@ -101,6 +101,16 @@ func TestMarshalModel(t *testing.T) {
 					}},
 					Components: &Components{Component: []*Component{{ObjectID: 8, Transform: Matrix{3, 0, 0, 0, 0, 1, 0, 0, 0, 0, 2, 0, -66.4, -87.1, 8.8, 1},
 						AnyAttr: spec.AnyAttr{&fakeAttr{Value: "component_fake"}, &spec.UnknownAttrs{Space: fooSpace, Attr: []xml.Attr{{Name: fooName, Value: "foo8"}}}}}}},
+					Any: spec.Any{
+						&spec.UnknownTokens{Token: []xml.Token{
+							xml.StartElement{Name: fooName},
+							xml.EndElement{Name: fooName},
+						}},
+						&spec.UnknownTokens{Token: []xml.Token{
+							xml.StartElement{Name: xml.Name{Space: fooName.Space, Local: "other"}},
+							xml.EndElement{Name: xml.Name{Space: fooName.Space, Local: "other"}},
+						}},
+					},
 				},
 			},
 		},
@@ -0,0 +1,63 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/hpinc/go3mf"
+)
+
+// asciiEncoder can write an ASCII STL to a Write stream.
+type asciiEncoder struct {
+	w io.Writer
+}
+
+func (e *asciiEncoder) encode(ctx context.Context, _ uint32, walk func(func(*facet) error) error) error {
+	w := bufio.NewWriter(e.w)
+	w.WriteString("solid\n")
+	var (
+		buf           []byte
+		nFace         int
+		nextFaceCheck = checkEveryFaces
+	)
+	err := walk(func(f *facet) error {
+		buf = appendPoint(append(buf[:0], "facet normal "...), f.Normal())
+		buf = append(buf, "\n  outer loop\n"...)
+		for _, v := range f.Vertices {
+			buf = appendPoint(append(buf, "    vertex "...), v)
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, "  endloop\nendfacet\n"...)
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+		nFace++
+		if nFace > nextFaceCheck {
+			nextFaceCheck += checkEveryFaces
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default: // Default is must to avoid blocking
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	w.WriteString("endsolid\n")
+	return w.Flush()
+}
+
+func appendPoint(b []byte, p go3mf.Point3D) []byte {
+	b = strconv.AppendFloat(b, float64(p.X()), 'e', -1, 32)
+	b = append(b, ' ')
+	b = strconv.AppendFloat(b, float64(p.Y()), 'e', -1, 32)
+	b = append(b, ' ')
+	return strconv.AppendFloat(b, float64(p.Z()), 'e', -1, 32)
+}
@@ -0,0 +1,38 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func Test_asciiEncoder_encode(t *testing.T) {
+	want := `solid
+facet normal 0e+00 0e+00 1e+00
+  outer loop
+    vertex 0e+00 0e+00 0e+00
+    vertex 1e+00 0e+00 0e+00
+    vertex 0e+00 1.5e+00 0e+00
+  endloop
+endfacet
+endsolid
+`
+	f := facet{Vertices: [3]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1.5, 0}}}
+	var b bytes.Buffer
+	e := &asciiEncoder{w: &b}
+	err := e.encode(context.Background(), 1, func(fn func(*facet) error) error {
+		return fn(&f)
+	})
+	if err != nil {
+		t.Errorf("asciiEncoder.encode() error = %v", err)
+		return
+	}
+	if got := b.String(); got != want {
+		t.Errorf("asciiEncoder.encode() = %v, want %v", got, want)
+	}
+}
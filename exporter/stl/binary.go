@@ -0,0 +1,100 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"io"
+	"math"
+)
+
+const (
+	sizeOfHeader = 80
+	sizeOfFace   = 50
+)
+
+var defaultMagicsColor = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+// binaryEncoder can write a binary STL to a Write stream.
+type binaryEncoder struct {
+	w         io.Writer
+	colorMode ColorMode
+}
+
+func (e *binaryEncoder) encode(ctx context.Context, count uint32, walk func(func(*facet) error) error) error {
+	w := bufio.NewWriter(e.w)
+	if _, err := w.Write(e.header(count)); err != nil {
+		return err
+	}
+	var (
+		buf           [sizeOfFace]byte
+		nFace         int
+		nextFaceCheck = checkEveryFaces
+	)
+	err := walk(func(f *facet) error {
+		n := f.Normal()
+		putPoint(buf[0:], [3]float32(n))
+		putPoint(buf[12:], [3]float32(f.Vertices[0]))
+		putPoint(buf[24:], [3]float32(f.Vertices[1]))
+		putPoint(buf[36:], [3]float32(f.Vertices[2]))
+		binary.LittleEndian.PutUint16(buf[48:], e.attribute(f))
+		if _, err := w.Write(buf[:]); err != nil {
+			return err
+		}
+		nFace++
+		if nFace > nextFaceCheck {
+			nextFaceCheck += checkEveryFaces
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default: // Default is must to avoid blocking
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (e *binaryEncoder) header(count uint32) []byte {
+	header := make([]byte, sizeOfHeader+4)
+	n := copy(header, "go3mf binary STL")
+	if e.colorMode == ColorMagics {
+		n += copy(header[n:], " COLOR=")
+		c := defaultMagicsColor
+		copy(header[n:], []byte{c.R, c.G, c.B, c.A})
+	}
+	binary.LittleEndian.PutUint32(header[sizeOfHeader:], count)
+	return header
+}
+
+func (e *binaryEncoder) attribute(f *facet) uint16 {
+	switch e.colorMode {
+	case ColorVisCAM:
+		if f.HasColor {
+			return 1<<15 | to5Bits(f.Color.R)<<10 | to5Bits(f.Color.G)<<5 | to5Bits(f.Color.B)
+		}
+	case ColorMagics:
+		if f.HasColor {
+			return to5Bits(f.Color.B)<<10 | to5Bits(f.Color.G)<<5 | to5Bits(f.Color.R)
+		}
+		return 1 << 15
+	}
+	return 0
+}
+
+func to5Bits(c uint8) uint16 {
+	return uint16(c) >> 3
+}
+
+func putPoint(b []byte, p [3]float32) {
+	binary.LittleEndian.PutUint32(b[0:], math.Float32bits(p[0]))
+	binary.LittleEndian.PutUint32(b[4:], math.Float32bits(p[1]))
+	binary.LittleEndian.PutUint32(b[8:], math.Float32bits(p[2]))
+}
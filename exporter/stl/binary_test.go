@@ -0,0 +1,82 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func Test_binaryEncoder_encode(t *testing.T) {
+	f := facet{Vertices: [3]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, Color: color.RGBA{R: 255, A: 255}, HasColor: true}
+	tests := []struct {
+		name      string
+		colorMode ColorMode
+		wantAttr  uint16
+	}{
+		{"nocolor", ColorNone, 0},
+		{"viscam", ColorVisCAM, 0xfc00},
+		{"magics", ColorMagics, 0x001f},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			e := &binaryEncoder{w: &b, colorMode: tt.colorMode}
+			err := e.encode(context.Background(), 1, func(fn func(*facet) error) error {
+				return fn(&f)
+			})
+			if err != nil {
+				t.Errorf("binaryEncoder.encode() error = %v", err)
+				return
+			}
+			got := b.Bytes()
+			if len(got) != sizeOfHeader+4+sizeOfFace {
+				t.Errorf("binaryEncoder.encode() len = %d, want %d", len(got), sizeOfHeader+4+sizeOfFace)
+				return
+			}
+			if n := binary.LittleEndian.Uint32(got[sizeOfHeader:]); n != 1 {
+				t.Errorf("binaryEncoder.encode() count = %d, want 1", n)
+			}
+			if normal := binary.LittleEndian.Uint32(got[sizeOfHeader+12:]); normal != 0x3f800000 {
+				t.Errorf("binaryEncoder.encode() normal z = %x, want 1.0", normal)
+			}
+			if attr := binary.LittleEndian.Uint16(got[len(got)-2:]); attr != tt.wantAttr {
+				t.Errorf("binaryEncoder.encode() attribute = %x, want %x", attr, tt.wantAttr)
+			}
+			if bytes.HasPrefix(got, []byte("solid")) {
+				t.Error("binaryEncoder.encode() header MUST NOT start with solid")
+			}
+			if hasColor := bytes.Contains(got[:sizeOfHeader], []byte("COLOR=")); hasColor != (tt.colorMode == ColorMagics) {
+				t.Errorf("binaryEncoder.encode() header COLOR= = %v", hasColor)
+			}
+		})
+	}
+}
+
+func Test_binaryEncoder_attribute(t *testing.T) {
+	tests := []struct {
+		name      string
+		colorMode ColorMode
+		f         facet
+		want      uint16
+	}{
+		{"viscam-nocolor", ColorVisCAM, facet{}, 0},
+		{"viscam", ColorVisCAM, facet{Color: color.RGBA{R: 8, G: 16, B: 24}, HasColor: true}, 1<<15 | 1<<10 | 2<<5 | 3},
+		{"magics-nocolor", ColorMagics, facet{}, 1 << 15},
+		{"magics", ColorMagics, facet{Color: color.RGBA{R: 8, G: 16, B: 24}, HasColor: true}, 3<<10 | 2<<5 | 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &binaryEncoder{colorMode: tt.colorMode}
+			if got := e.attribute(&tt.f); got != tt.want {
+				t.Errorf("binaryEncoder.attribute() = %x, want %x", got, tt.want)
+			}
+		})
+	}
+}
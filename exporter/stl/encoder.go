@@ -0,0 +1,208 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"context"
+	"image/color"
+	"io"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+var checkEveryFaces = 1000
+
+// Format defines the STL encoding.
+type Format uint8
+
+// Supported STL encodings.
+const (
+	FormatBinary Format = iota
+	FormatASCII
+)
+
+// ColorMode defines how the triangle colors are stored
+// in the attribute byte count of a binary STL.
+// ASCII STL does not support colors.
+type ColorMode uint8
+
+// Supported color modes.
+const (
+	// ColorNone does not store colors.
+	ColorNone ColorMode = iota
+	// ColorVisCAM follows the VisCAM and SolidView convention:
+	// bits 0 to 4 are blue, 5 to 9 are green, 10 to 14 are red
+	// and bit 15 is set when the color is valid.
+	ColorVisCAM
+	// ColorMagics follows the Materialise Magics convention:
+	// bits 0 to 4 are red, 5 to 9 are green, 10 to 14 are blue
+	// and bit 15 is cleared when the facet has its own color.
+	// Facets without color use the COLOR= value stored in the header.
+	ColorMagics
+)
+
+// facet is a triangle in build coordinates.
+type facet struct {
+	Vertices [3]go3mf.Point3D
+	Color    color.RGBA
+	HasColor bool
+}
+
+// Normal returns the unit normal of the facet following the right-hand rule.
+func (f *facet) Normal() go3mf.Point3D {
+	return f.Vertices[1].Sub(f.Vertices[0]).Cross(f.Vertices[2].Sub(f.Vertices[0])).Normalize()
+}
+
+type facetEncoder interface {
+	encode(ctx context.Context, count uint32, walk func(func(*facet) error) error) error
+}
+
+// Encoder can encode a Model as a STL.
+// All the meshes referenced by the build items are flattened into a single solid,
+// following components recursively and applying the item and component transforms.
+type Encoder struct {
+	Format    Format
+	ColorMode ColorMode
+	w         io.Writer
+}
+
+// NewEncoder creates a new encoder that writes a binary STL without colors to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w: w,
+	}
+}
+
+// Encode writes the STL encoding of all the build items of m.
+func (e *Encoder) Encode(m *go3mf.Model) error {
+	return e.EncodeContext(context.Background(), m)
+}
+
+// EncodeContext writes the STL encoding of all the build items of m.
+func (e *Encoder) EncodeContext(ctx context.Context, m *go3mf.Model) error {
+	return e.encode(ctx, m, m.Build.Items)
+}
+
+// EncodeItem writes the STL encoding of a single build item of m.
+// It is useful to create one STL file per build item.
+func (e *Encoder) EncodeItem(m *go3mf.Model, item *go3mf.Item) error {
+	return e.EncodeItemContext(context.Background(), m, item)
+}
+
+// EncodeItemContext writes the STL encoding of a single build item of m.
+func (e *Encoder) EncodeItemContext(ctx context.Context, m *go3mf.Model, item *go3mf.Item) error {
+	return e.encode(ctx, m, []*go3mf.Item{item})
+}
+
+func (e *Encoder) encode(ctx context.Context, m *go3mf.Model, items []*go3mf.Item) error {
+	var enc facetEncoder
+	if e.Format == FormatASCII {
+		enc = &asciiEncoder{w: e.w}
+	} else {
+		enc = &binaryEncoder{w: e.w, colorMode: e.ColorMode}
+	}
+	var count uint32
+	for _, item := range items {
+		m.WalkItemMeshes(item, func(_ string, o *go3mf.Object, _ go3mf.Matrix) error {
+			count += countFacets(o.Mesh)
+			return nil
+		})
+	}
+	w := &walker{model: m, colors: e.Format == FormatBinary && e.ColorMode != ColorNone}
+	return enc.encode(ctx, count, func(fn func(*facet) error) error {
+		for _, item := range items {
+			if err := w.walkItem(item, fn); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// countFacets returns the number of valid triangles of the mesh.
+func countFacets(mesh *go3mf.Mesh) uint32 {
+	var count uint32
+	n := len(mesh.Vertices.Vertex)
+	for i := range mesh.Triangles.Triangle {
+		if validTriangle(&mesh.Triangles.Triangle[i], n) {
+			count++
+		}
+	}
+	return count
+}
+
+type walker struct {
+	model  *go3mf.Model
+	colors bool
+}
+
+func (w *walker) walkItem(item *go3mf.Item, fn func(*facet) error) error {
+	return w.model.WalkItemMeshes(item, func(path string, o *go3mf.Object, t go3mf.Matrix) error {
+		return w.walkMesh(o, path, t, fn)
+	})
+}
+
+func (w *walker) walkMesh(o *go3mf.Object, path string, t go3mf.Matrix, fn func(*facet) error) error {
+	var f facet
+	vertices := o.Mesh.Vertices.Vertex
+	for _, tr := range o.Mesh.Triangles.Triangle {
+		if !validTriangle(&tr, len(vertices)) {
+			continue
+		}
+		f.Vertices[0] = t.Mul3D(vertices[tr.V1])
+		f.Vertices[1] = t.Mul3D(vertices[tr.V2])
+		f.Vertices[2] = t.Mul3D(vertices[tr.V3])
+		if w.colors {
+			f.Color, f.HasColor = w.triangleColor(o, path, &tr)
+		}
+		if err := fn(&f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validTriangle returns true if the triangle indices are inside the n mesh vertices.
+// Triangles with invalid indices are not written nor counted.
+func validTriangle(t *go3mf.Triangle, n int) bool {
+	return int(t.V1) < n && int(t.V2) < n && int(t.V3) < n
+}
+
+// triangleColor returns the average color of the triangle vertices,
+// falling back to the object default property.
+func (w *walker) triangleColor(o *go3mf.Object, path string, t *go3mf.Triangle) (color.RGBA, bool) {
+	pid, indices := t.PID, [3]uint32{t.P1, t.P2, t.P3}
+	if pid == 0 {
+		pid, indices = o.PID, [3]uint32{o.PIndex, o.PIndex, o.PIndex}
+	}
+	if pid == 0 {
+		return color.RGBA{}, false
+	}
+	a, ok := w.model.FindAsset(path, pid)
+	if !ok {
+		return color.RGBA{}, false
+	}
+	var colors []color.RGBA
+	switch a := a.(type) {
+	case *go3mf.BaseMaterials:
+		colors = make([]color.RGBA, len(a.Materials))
+		for i, b := range a.Materials {
+			colors[i] = b.Color
+		}
+	case *materials.ColorGroup:
+		colors = a.Colors
+	default:
+		return color.RGBA{}, false
+	}
+	var r, g, b, alpha uint32
+	for _, i := range indices {
+		if int(i) >= len(colors) {
+			return color.RGBA{}, false
+		}
+		c := colors[i]
+		r, g, b, alpha = r+uint32(c.R), g+uint32(c.G), b+uint32(c.B), alpha+uint32(c.A)
+	}
+	return color.RGBA{R: uint8(r / 3), G: uint8(g / 3), B: uint8(b / 3), A: uint8(alpha / 3)}, true
+}
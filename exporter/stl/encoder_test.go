@@ -0,0 +1,210 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package stl
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	importer "github.com/hpinc/go3mf/importer/stl"
+	"github.com/hpinc/go3mf/materials"
+)
+
+func createTetrahedron(id uint32) *go3mf.Object {
+	return &go3mf.Object{ID: id, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+		}},
+	}}
+}
+
+func createModel() *go3mf.Model {
+	m := new(go3mf.Model)
+	m.Resources.Objects = append(m.Resources.Objects, createTetrahedron(1), &go3mf.Object{
+		ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{
+			{ObjectID: 1, Transform: go3mf.Identity().Translate(0, 0, 20)},
+			{ObjectID: 1},
+		}},
+	})
+	m.Build.Items = append(m.Build.Items,
+		&go3mf.Item{ObjectID: 1},
+		&go3mf.Item{ObjectID: 2, Transform: go3mf.Identity().Translate(100, 0, 0)},
+	)
+	return m
+}
+
+func TestNewEncoder(t *testing.T) {
+	w := new(bytes.Buffer)
+	if got := NewEncoder(w); !reflect.DeepEqual(got, &Encoder{w: w}) {
+		t.Errorf("NewEncoder() = %v", got)
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	want := []go3mf.Point3D{
+		{0, 0, 0}, {0, 10, 0}, {10, 0, 0}, {0, 0, 10},
+		{100, 0, 20}, {100, 10, 20}, {110, 0, 20}, {100, 0, 30},
+		{100, 0, 0}, {100, 10, 0}, {110, 0, 0}, {100, 0, 10},
+	}
+	tests := []struct {
+		name   string
+		format Format
+	}{
+		{"binary", FormatBinary},
+		{"ascii", FormatASCII},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			e := NewEncoder(&b)
+			e.Format = tt.format
+			if err := e.Encode(createModel()); err != nil {
+				t.Errorf("Encoder.Encode() error = %v", err)
+				return
+			}
+			got := new(go3mf.Model)
+			if err := importer.NewDecoder(&b).Decode(got); err != nil {
+				t.Errorf("Encoder.Encode() error decoding = %v", err)
+				return
+			}
+			mesh := got.Resources.Objects[0].Mesh
+			if diff := deep.Equal(mesh.Vertices.Vertex, want); diff != nil {
+				t.Errorf("Encoder.Encode() = %v", diff)
+			}
+			if len(mesh.Triangles.Triangle) != 12 {
+				t.Errorf("Encoder.Encode() triangles = %d, want %d", len(mesh.Triangles.Triangle), 12)
+			}
+			if err := mesh.ValidateCoherency(); err != nil {
+				t.Errorf("Encoder.Encode() coherency error = %v", err)
+			}
+		})
+	}
+}
+
+func TestEncoder_EncodeItem(t *testing.T) {
+	m := createModel()
+	var b bytes.Buffer
+	if err := NewEncoder(&b).EncodeItem(m, m.Build.Items[1]); err != nil {
+		t.Errorf("Encoder.EncodeItem() error = %v", err)
+		return
+	}
+	got := new(go3mf.Model)
+	if err := importer.NewDecoder(&b).Decode(got); err != nil {
+		t.Errorf("Encoder.EncodeItem() error decoding = %v", err)
+		return
+	}
+	box := got.Resources.Objects[0].Mesh.BoundingBox()
+	if diff := deep.Equal(box, go3mf.Box{Min: go3mf.Point3D{100, 0, 0}, Max: go3mf.Point3D{110, 10, 30}}); diff != nil {
+		t.Errorf("Encoder.EncodeItem() = %v", diff)
+	}
+	if len(got.Resources.Objects[0].Mesh.Triangles.Triangle) != 8 {
+		t.Errorf("Encoder.EncodeItem() triangles = %d, want %d", len(got.Resources.Objects[0].Mesh.Triangles.Triangle), 8)
+	}
+}
+
+func TestEncoder_EncodeContext_Cancel(t *testing.T) {
+	checkEveryFaces = 1
+	defer func() { checkEveryFaces = 1000 }()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, format := range []Format{FormatBinary, FormatASCII} {
+		e := NewEncoder(new(bytes.Buffer))
+		e.Format = format
+		if err := e.EncodeContext(ctx, createModel()); err != context.Canceled {
+			t.Errorf("Encoder.EncodeContext() error = %v, want %v", err, context.Canceled)
+		}
+	}
+}
+
+func TestEncoder_Encode_invalidIndices(t *testing.T) {
+	m := createModel()
+	mesh := m.Resources.Objects[0].Mesh
+	mesh.Triangles.Triangle = append(mesh.Triangles.Triangle, go3mf.Triangle{V1: 0, V2: 1, V3: 4})
+	var b bytes.Buffer
+	if err := NewEncoder(&b).EncodeItem(m, m.Build.Items[1]); err != nil {
+		t.Fatalf("Encoder.EncodeItem() error = %v", err)
+	}
+	// 80 bytes of header, the facet count and 50 bytes per facet.
+	if want := 84 + 8*50; b.Len() != want {
+		t.Errorf("Encoder.EncodeItem() size = %d, want %d", b.Len(), want)
+	}
+	got := new(go3mf.Model)
+	if err := importer.NewDecoder(&b).Decode(got); err != nil {
+		t.Fatalf("Encoder.EncodeItem() error decoding = %v", err)
+	}
+	if n := len(got.Resources.Objects[0].Mesh.Triangles.Triangle); n != 8 {
+		t.Errorf("Encoder.EncodeItem() triangles = %d, want %d", n, 8)
+	}
+}
+
+func Test_countFacets(t *testing.T) {
+	m := createModel()
+	invalid := createTetrahedron(4)
+	invalid.Mesh.Triangles.Triangle = append(invalid.Mesh.Triangles.Triangle, go3mf.Triangle{V1: 0, V2: 1, V3: 4})
+	m.Resources.Objects = append(m.Resources.Objects, &go3mf.Object{
+		ID: 3, Components: &go3mf.Components{Component: []*go3mf.Component{{ObjectID: 3}}},
+	}, invalid)
+	tests := []struct {
+		name string
+		id   uint32
+		want uint32
+	}{
+		{"mesh", 1, 4},
+		{"components", 2, 8},
+		{"recursive", 3, 0},
+		{"invalidIndices", 4, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _ := m.FindObject("", tt.id)
+			var got uint32
+			m.WalkMeshes("", o, go3mf.Identity(), func(_ string, o *go3mf.Object, _ go3mf.Matrix) error {
+				got += countFacets(o.Mesh)
+				return nil
+			})
+			if got != tt.want {
+				t.Errorf("countFacets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_walker_triangleColor(t *testing.T) {
+	m := new(go3mf.Model)
+	m.Resources.Assets = append(m.Resources.Assets,
+		&go3mf.BaseMaterials{ID: 10, Materials: []go3mf.Base{{Name: "a", Color: color.RGBA{R: 255, A: 255}}}},
+		&materials.ColorGroup{ID: 11, Colors: []color.RGBA{{R: 30, A: 255}, {G: 60, A: 255}, {B: 90, A: 255}}},
+		&materials.Texture2D{ID: 12},
+	)
+	tests := []struct {
+		name  string
+		o     *go3mf.Object
+		t     *go3mf.Triangle
+		want  color.RGBA
+		want1 bool
+	}{
+		{"none", &go3mf.Object{}, &go3mf.Triangle{}, color.RGBA{}, false},
+		{"object", &go3mf.Object{PID: 10}, &go3mf.Triangle{}, color.RGBA{R: 255, A: 255}, true},
+		{"base", &go3mf.Object{}, &go3mf.Triangle{PID: 10}, color.RGBA{R: 255, A: 255}, true},
+		{"colorgroup", &go3mf.Object{}, &go3mf.Triangle{PID: 11, P1: 0, P2: 1, P3: 2}, color.RGBA{R: 10, G: 20, B: 30, A: 255}, true},
+		{"outOfBounds", &go3mf.Object{}, &go3mf.Triangle{PID: 11, P1: 3}, color.RGBA{}, false},
+		{"texture", &go3mf.Object{}, &go3mf.Triangle{PID: 12}, color.RGBA{}, false},
+		{"missing", &go3mf.Object{}, &go3mf.Triangle{PID: 100}, color.RGBA{}, false},
+	}
+	w := &walker{model: m, colors: true}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, got1 := w.triangleColor(tt.o, "", tt.t)
+			if got != tt.want || got1 != tt.want1 {
+				t.Errorf("walker.triangleColor() = %v, %v, want %v, %v", got, got1, tt.want, tt.want1)
+			}
+		})
+	}
+}
@@ -0,0 +1,417 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package obj
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+var checkEveryFaces = 1000
+
+var errInvalidIndex = errors.New("obj: invalid face index")
+
+// Decoder can decode a Wavefront OBJ.
+//
+// Each o and g statement starts a new object. Polygons are triangulated as fans.
+// Materials diffuse colors are added to a BaseMaterials resource
+// and diffuse texture maps are added as Texture2D and Texture2DGroup resources.
+type Decoder struct {
+	// OpenFile opens the material libraries and texture images referenced
+	// by the OBJ stream, using the names as they appear in the file.
+	// If nil, mtllib statements are ignored.
+	OpenFile func(name string) (io.ReadCloser, error)
+	r        io.Reader
+}
+
+// NewDecoder creates a new decoder.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decode creates the objects from a read stream.
+func (d *Decoder) Decode(m *go3mf.Model) error {
+	return d.DecodeContext(context.Background(), m)
+}
+
+// DecodeContext creates the objects from a read stream.
+func (d *Decoder) DecodeContext(ctx context.Context, m *go3mf.Model) error {
+	s := &decodeState{
+		d:         d,
+		model:     m,
+		materials: make(map[string]*material),
+		textures:  make(map[string]*textureGroup),
+	}
+	if err := s.decode(ctx); err != nil {
+		return err
+	}
+	s.flush()
+	return nil
+}
+
+type vertexRef struct {
+	v, vt int
+}
+
+type group struct {
+	object   *go3mf.Object
+	vertices map[int]uint32
+}
+
+type textureGroup struct {
+	group  *materials.Texture2DGroup
+	coords map[materials.TextureCoord]uint32
+}
+
+type decodeState struct {
+	d          *Decoder
+	model      *go3mf.Model
+	positions  []go3mf.Point3D
+	texCoords  []materials.TextureCoord
+	materials  map[string]*material
+	textures   map[string]*textureGroup
+	base       *go3mf.BaseMaterials
+	baseIndex  map[string]uint32
+	current    *group
+	objects    []*go3mf.Object
+	usemtl     *material
+	faceCount  int
+	refs       []vertexRef
+	nextCheck  int
+	hasTexture bool
+}
+
+func (s *decodeState) decode(ctx context.Context) error {
+	s.nextCheck = checkEveryFaces
+	scanner := bufio.NewScanner(s.d.r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		var err error
+		switch fields[0] {
+		case "v":
+			err = s.parseVertex(fields)
+		case "vt":
+			err = s.parseTexCoord(fields)
+		case "f":
+			err = s.parseFace(fields)
+			if s.faceCount > s.nextCheck {
+				select {
+				case <-ctx.Done():
+					err = ctx.Err()
+				default: // Default is must to avoid blocking
+				}
+				s.nextCheck += checkEveryFaces
+			}
+		case "o", "g":
+			s.startGroup(strings.Join(fields[1:], " "))
+		case "usemtl":
+			if len(fields) > 1 {
+				s.usemtl = s.materials[fields[1]]
+			} else {
+				s.usemtl = nil
+			}
+		case "mtllib":
+			err = s.loadMaterials(fields[1:])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func (s *decodeState) parseVertex(fields []string) error {
+	if len(fields) < 4 {
+		return errors.New("obj: vertex MUST have 3 coordinates")
+	}
+	var p go3mf.Point3D
+	for i := 0; i < 3; i++ {
+		val, err := strconv.ParseFloat(fields[i+1], 32)
+		if err != nil {
+			return err
+		}
+		p[i] = float32(val)
+	}
+	s.positions = append(s.positions, p)
+	return nil
+}
+
+func (s *decodeState) parseTexCoord(fields []string) error {
+	var uv materials.TextureCoord
+	for i := 0; i < 2 && i+1 < len(fields); i++ {
+		val, err := strconv.ParseFloat(fields[i+1], 32)
+		if err != nil {
+			return err
+		}
+		uv[i] = float32(val)
+	}
+	s.texCoords = append(s.texCoords, uv)
+	return nil
+}
+
+func (s *decodeState) parseFace(fields []string) error {
+	s.refs = s.refs[:0]
+	for _, f := range fields[1:] {
+		ref, err := s.parseVertexRef(f)
+		if err != nil {
+			return err
+		}
+		s.refs = append(s.refs, ref)
+	}
+	if len(s.refs) < 3 {
+		return nil
+	}
+	if s.current == nil {
+		s.startGroup("")
+	}
+	var indices [3]uint32
+	for i := 1; i < len(s.refs)-1; i++ {
+		refs := [3]vertexRef{s.refs[0], s.refs[i], s.refs[i+1]}
+		for j, ref := range refs {
+			indices[j] = s.addVertex(ref.v)
+		}
+		if indices[0] == indices[1] || indices[0] == indices[2] || indices[1] == indices[2] {
+			continue
+		}
+		t := go3mf.Triangle{V1: indices[0], V2: indices[1], V3: indices[2]}
+		s.applyMaterial(&t, refs)
+		mesh := s.current.object.Mesh
+		mesh.Triangles.Triangle = append(mesh.Triangles.Triangle, t)
+		s.faceCount++
+	}
+	return nil
+}
+
+// parseVertexRef parses a v, v/vt, v//vn or v/vt/vn face element.
+func (s *decodeState) parseVertexRef(f string) (vertexRef, error) {
+	ref := vertexRef{vt: -1}
+	parts := strings.SplitN(f, "/", 3)
+	var err error
+	if ref.v, err = resolveIndex(parts[0], len(s.positions)); err != nil {
+		return ref, err
+	}
+	if len(parts) > 1 && parts[1] != "" {
+		if ref.vt, err = resolveIndex(parts[1], len(s.texCoords)); err != nil {
+			return ref, err
+		}
+	}
+	return ref, nil
+}
+
+// resolveIndex converts 1-based and negative relative indices into 0-based indices.
+func resolveIndex(s string, count int) (int, error) {
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errInvalidIndex
+	}
+	if val < 0 {
+		val += count
+	} else {
+		val--
+	}
+	if val < 0 || val >= count {
+		return 0, errInvalidIndex
+	}
+	return val, nil
+}
+
+func (s *decodeState) addVertex(v int) uint32 {
+	if index, ok := s.current.vertices[v]; ok {
+		return index
+	}
+	mesh := s.current.object.Mesh
+	mesh.Vertices.Vertex = append(mesh.Vertices.Vertex, s.positions[v])
+	index := uint32(len(mesh.Vertices.Vertex)) - 1
+	s.current.vertices[v] = index
+	return index
+}
+
+func (s *decodeState) startGroup(name string) {
+	if s.current != nil && len(s.current.object.Mesh.Triangles.Triangle) == 0 {
+		s.current.object.Name = name
+		return
+	}
+	s.current = &group{
+		object:   &go3mf.Object{Name: name, Mesh: new(go3mf.Mesh)},
+		vertices: make(map[int]uint32),
+	}
+	s.objects = append(s.objects, s.current.object)
+}
+
+func (s *decodeState) loadMaterials(names []string) error {
+	if s.d.OpenFile == nil {
+		return nil
+	}
+	for _, name := range names {
+		f, err := s.d.OpenFile(name)
+		if err != nil {
+			return err
+		}
+		err = decodeMTL(f, s.materials)
+		f.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *decodeState) applyMaterial(t *go3mf.Triangle, refs [3]vertexRef) {
+	if s.usemtl == nil {
+		return
+	}
+	if s.usemtl.DiffuseMap != "" && refs[0].vt >= 0 && refs[1].vt >= 0 && refs[2].vt >= 0 {
+		if tex := s.textureGroup(s.usemtl.DiffuseMap); tex != nil {
+			t.PID = tex.group.ID
+			t.P1 = tex.coordIndex(s.texCoords[refs[0].vt])
+			t.P2 = tex.coordIndex(s.texCoords[refs[1].vt])
+			t.P3 = tex.coordIndex(s.texCoords[refs[2].vt])
+			return
+		}
+	}
+	t.PID = s.baseMaterials().ID
+	index, ok := s.baseIndex[s.usemtl.Name]
+	if !ok {
+		s.base.Materials = append(s.base.Materials, go3mf.Base{Name: s.usemtl.Name, Color: s.usemtl.Diffuse})
+		index = uint32(len(s.base.Materials)) - 1
+		s.baseIndex[s.usemtl.Name] = index
+	}
+	t.P1, t.P2, t.P3 = index, index, index
+}
+
+func (s *decodeState) baseMaterials() *go3mf.BaseMaterials {
+	if s.base == nil {
+		s.base = &go3mf.BaseMaterials{ID: s.model.Resources.UnusedID()}
+		s.baseIndex = make(map[string]uint32)
+		s.model.Resources.Assets = append(s.model.Resources.Assets, s.base)
+	}
+	return s.base
+}
+
+// textureGroup returns the texture group associated to the texture image,
+// creating the resources and the attachment when needed.
+// Returns nil if the image is not supported or cannot be read.
+func (s *decodeState) textureGroup(name string) *textureGroup {
+	if tex, ok := s.textures[name]; ok {
+		return tex
+	}
+	s.textures[name] = nil
+	if s.d.OpenFile == nil {
+		return nil
+	}
+	var contentType materials.Texture2DType
+	switch strings.ToLower(path.Ext(name)) {
+	case ".png":
+		contentType = materials.TextureTypePNG
+	case ".jpg", ".jpeg":
+		contentType = materials.TextureTypeJPEG
+	default:
+		return nil
+	}
+	f, err := s.d.OpenFile(name)
+	if err != nil {
+		return nil
+	}
+	buff := new(bytes.Buffer)
+	_, err = io.Copy(buff, f)
+	f.Close()
+	if err != nil {
+		return nil
+	}
+	texturePath := s.texturePath(name)
+	s.model.Attachments = append(s.model.Attachments, go3mf.Attachment{
+		Path:        texturePath,
+		ContentType: contentType.String(),
+		Stream:      buff,
+	})
+	texture := &materials.Texture2D{ID: s.model.Resources.UnusedID(), Path: texturePath, ContentType: contentType}
+	s.model.Resources.Assets = append(s.model.Resources.Assets, texture)
+	tex := &textureGroup{
+		group:  &materials.Texture2DGroup{ID: s.model.Resources.UnusedID(), TextureID: texture.ID},
+		coords: make(map[materials.TextureCoord]uint32),
+	}
+	s.model.Resources.Assets = append(s.model.Resources.Assets, tex.group)
+	s.textures[name] = tex
+	s.hasTexture = true
+	return tex
+}
+
+// texturePath returns the attachment path of the texture file name,
+// keeping its relative directory so maps with the same file name do not collide.
+// Parent directories are dropped, and a suffix is added if the path is already used.
+func (s *decodeState) texturePath(name string) string {
+	name = path.Clean(strings.Replace(name, "\\", "/", -1))
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	for strings.HasPrefix(name, "/") || strings.HasPrefix(name, "../") {
+		name = strings.TrimPrefix(strings.TrimPrefix(name, "/"), "../")
+	}
+	texturePath := go3mf.Default3DTexturesDir + name
+	ext := path.Ext(texturePath)
+	for i := 1; s.hasAttachment(texturePath); i++ {
+		texturePath = fmt.Sprintf("%s_%d%s", strings.TrimSuffix(go3mf.Default3DTexturesDir+name, ext), i, ext)
+	}
+	return texturePath
+}
+
+func (s *decodeState) hasAttachment(p string) bool {
+	for _, a := range s.model.Attachments {
+		if strings.EqualFold(a.Path, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *textureGroup) coordIndex(uv materials.TextureCoord) uint32 {
+	if index, ok := t.coords[uv]; ok {
+		return index
+	}
+	t.group.Coords = append(t.group.Coords, uv)
+	index := uint32(len(t.group.Coords)) - 1
+	t.coords[uv] = index
+	return index
+}
+
+// flush adds the decoded objects and their build items to the model.
+func (s *decodeState) flush() {
+	for _, o := range s.objects {
+		if len(o.Mesh.Triangles.Triangle) == 0 {
+			continue
+		}
+		for _, t := range o.Mesh.Triangles.Triangle {
+			if t.PID != 0 {
+				o.PID, o.PIndex = t.PID, t.P1
+				break
+			}
+		}
+		o.ID = s.model.Resources.UnusedID()
+		s.model.Resources.Objects = append(s.model.Resources.Objects, o)
+		s.model.Build.Items = append(s.model.Build.Items, &go3mf.Item{ObjectID: o.ID})
+	}
+	if s.hasTexture {
+		for _, ext := range s.model.Extensions {
+			if ext.Namespace == materials.Namespace {
+				return
+			}
+		}
+		s.model.Extensions = append(s.model.Extensions, materials.DefaultExtension)
+	}
+}
@@ -0,0 +1,194 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package obj
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+func openFiles(files map[string]string) func(string) (io.ReadCloser, error) {
+	return func(name string) (io.ReadCloser, error) {
+		if content, ok := files[name]; ok {
+			return ioutil.NopCloser(bytes.NewBufferString(content)), nil
+		}
+		return nil, errors.New("not found")
+	}
+}
+
+func TestNewDecoder(t *testing.T) {
+	r := new(bytes.Buffer)
+	if got := NewDecoder(r); !reflect.DeepEqual(got, &Decoder{r: r}) {
+		t.Errorf("NewDecoder() = %v", got)
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	cube := `
+	# two groups sharing the global vertex list
+	mtllib cube.mtl
+	v 0 0 0
+	v 1 0 0
+	v 1 1 0
+	v 0 1 0
+	v 0 0 1
+	vt 0 0
+	vt 1 0
+	vt 1 1
+	o bottom
+	usemtl red
+	f 1 4 3 2
+	g top
+	usemtl wood
+	f -4/1 -3/2 -2/3
+	usemtl unknown
+	f 5//1 1//1 2//1
+	`
+	mtl := `
+	newmtl red
+	Kd 1 0 0
+	newmtl wood
+	Kd 0 1 0
+	map_Kd textures\wood.png
+	`
+	files := map[string]string{"cube.mtl": mtl, "textures\\wood.png": "png"}
+	base := &go3mf.BaseMaterials{ID: 1, Materials: []go3mf.Base{{Name: "red", Color: color.RGBA{R: 255, A: 255}}}}
+	texture := &materials.Texture2D{ID: 2, Path: "/3D/Textures/textures/wood.png", ContentType: materials.TextureTypePNG}
+	texGroup := &materials.Texture2DGroup{ID: 3, TextureID: 2, Coords: []materials.TextureCoord{{0, 0}, {1, 0}, {1, 1}}}
+	bottom := &go3mf.Object{ID: 4, Name: "bottom", PID: 1, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {0, 1, 0}, {1, 1, 0}, {1, 0, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2, PID: 1}, {V1: 0, V2: 2, V3: 3, PID: 1},
+		}},
+	}}
+	top := &go3mf.Object{ID: 5, Name: "top", PID: 3, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{1, 0, 0}, {1, 1, 0}, {0, 1, 0}, {0, 0, 1}, {0, 0, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2, PID: 3, P1: 0, P2: 1, P3: 2}, {V1: 3, V2: 4, V3: 0},
+		}},
+	}}
+	want := &go3mf.Model{
+		Resources:   go3mf.Resources{Assets: []go3mf.Asset{base, texture, texGroup}, Objects: []*go3mf.Object{bottom, top}},
+		Build:       go3mf.Build{Items: []*go3mf.Item{{ObjectID: 4}, {ObjectID: 5}}},
+		Extensions:  []go3mf.Extension{materials.DefaultExtension},
+		Attachments: []go3mf.Attachment{{Path: "/3D/Textures/textures/wood.png", ContentType: "image/png", Stream: bytes.NewBufferString("png")}},
+	}
+	t.Run("base", func(t *testing.T) {
+		d := NewDecoder(bytes.NewBufferString(cube))
+		d.OpenFile = openFiles(files)
+		got := new(go3mf.Model)
+		if err := d.Decode(got); err != nil {
+			t.Errorf("Decoder.Decode() error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("Decoder.Decode() = %v", diff)
+		}
+	})
+	t.Run("nomaterials", func(t *testing.T) {
+		got := new(go3mf.Model)
+		if err := NewDecoder(bytes.NewBufferString(cube)).Decode(got); err != nil {
+			t.Errorf("Decoder.Decode() error = %v", err)
+			return
+		}
+		if len(got.Resources.Assets) != 0 || len(got.Resources.Objects) != 2 {
+			t.Errorf("Decoder.Decode() = %v", got.Resources)
+		}
+	})
+}
+
+func TestDecoder_Decode_Error(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		obj  string
+	}{
+		{"vertex", context.Background(), "v 1 2"},
+		{"vertexValue", context.Background(), "v 1 2 a"},
+		{"texcoord", context.Background(), "vt a"},
+		{"index", context.Background(), "v 0 0 0\nf 1 2 3"},
+		{"indexValue", context.Background(), "v 0 0 0\nf 1 a 1"},
+		{"texIndex", context.Background(), "v 0 0 0\nf 1/1 1/1 1/1"},
+		{"mtllib", context.Background(), "mtllib missing.mtl"},
+		{"cancel", ctx, "v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\nf 1 2 3"},
+	}
+	checkEveryFaces = 1
+	defer func() { checkEveryFaces = 1000 }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDecoder(bytes.NewBufferString(tt.obj))
+			d.OpenFile = openFiles(nil)
+			if err := d.DecodeContext(tt.ctx, new(go3mf.Model)); err == nil {
+				t.Error("Decoder.DecodeContext() expected error")
+			}
+		})
+	}
+}
+
+func Test_decodeState_texturePath(t *testing.T) {
+	s := &decodeState{model: new(go3mf.Model)}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"diffuse.png", "/3D/Textures/diffuse.png"},
+		{"a/diffuse.png", "/3D/Textures/a/diffuse.png"},
+		{"b\\diffuse.png", "/3D/Textures/b/diffuse.png"},
+		{"./a/../b/diffuse.png", "/3D/Textures/b/diffuse_1.png"},
+		{"../diffuse.png", "/3D/Textures/diffuse_1.png"},
+		{"C:\\maps\\diffuse.png", "/3D/Textures/maps/diffuse.png"},
+		{"/maps/Diffuse.png", "/3D/Textures/maps/Diffuse_1.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.texturePath(tt.name)
+			if got != tt.want {
+				t.Errorf("decodeState.texturePath() = %v, want %v", got, tt.want)
+			}
+			s.model.Attachments = append(s.model.Attachments, go3mf.Attachment{Path: got})
+		})
+	}
+}
+
+func Test_resolveIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		count   int
+		want    int
+		wantErr bool
+	}{
+		{"first", "1", 3, 0, false},
+		{"last", "3", 3, 2, false},
+		{"relative", "-1", 3, 2, false},
+		{"zero", "0", 3, 0, true},
+		{"outOfBounds", "4", 3, 0, true},
+		{"relativeOutOfBounds", "-4", 3, 0, true},
+		{"nan", "a", 3, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveIndex(tt.s, tt.count)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolveIndex() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("resolveIndex() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package obj
+
+import (
+	"bufio"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// material is the subset of a MTL material that can be mapped to 3MF.
+type material struct {
+	Name       string
+	Diffuse    color.RGBA
+	DiffuseMap string
+}
+
+// decodeMTL reads the materials defined in a MTL library.
+func decodeMTL(r io.Reader, materials map[string]*material) error {
+	var current *material
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "newmtl":
+			if len(fields) < 2 {
+				current = nil
+				continue
+			}
+			current = &material{
+				Name:    fields[1],
+				Diffuse: color.RGBA{R: 255, G: 255, B: 255, A: 255},
+			}
+			materials[current.Name] = current
+		case "Kd":
+			if current != nil && len(fields) >= 4 {
+				current.Diffuse.R = parseChannel(fields[1])
+				current.Diffuse.G = parseChannel(fields[2])
+				current.Diffuse.B = parseChannel(fields[3])
+			}
+		case "d":
+			if current != nil && len(fields) >= 2 {
+				current.Diffuse.A = parseChannel(fields[len(fields)-1])
+			}
+		case "Tr":
+			if current != nil && len(fields) >= 2 {
+				current.Diffuse.A = 255 - parseChannel(fields[len(fields)-1])
+			}
+		case "map_Kd":
+			// Options, such as -s or -clamp, precede the file name.
+			if current != nil && len(fields) >= 2 {
+				current.DiffuseMap = fields[len(fields)-1]
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// parseChannel converts a [0, 1] float into a color channel.
+func parseChannel(s string) uint8 {
+	val, err := strconv.ParseFloat(s, 32)
+	if err != nil {
+		return 0
+	}
+	if val <= 0 {
+		return 0
+	}
+	if val >= 1 {
+		return 255
+	}
+	return uint8(val*255 + 0.5)
+}
@@ -0,0 +1,56 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package obj
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func Test_decodeMTL(t *testing.T) {
+	mtl := `
+	# comment
+	newmtl red
+	Ka 1.000 1.000 1.000
+	Kd 1.000 0.000 0.000
+	d 0.5
+	newmtl textured
+	Kd 0.2 0.4 0.6
+	Tr 0
+	map_Kd -s 1 1 1 wood.png
+	newmtl
+	Kd 1 1 1
+	`
+	want := map[string]*material{
+		"red":      {Name: "red", Diffuse: color.RGBA{R: 255, A: 128}},
+		"textured": {Name: "textured", Diffuse: color.RGBA{R: 51, G: 102, B: 153, A: 255}, DiffuseMap: "wood.png"},
+	}
+	got := make(map[string]*material)
+	if err := decodeMTL(bytes.NewBufferString(mtl), got); err != nil {
+		t.Errorf("decodeMTL() error = %v", err)
+		return
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("decodeMTL() = %v", diff)
+	}
+}
+
+func Test_parseChannel(t *testing.T) {
+	tests := []struct {
+		s    string
+		want uint8
+	}{
+		{"0", 0}, {"-1", 0}, {"1", 255}, {"2", 255}, {"0.5", 128}, {"a", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := parseChannel(tt.s); got != tt.want {
+				t.Errorf("parseChannel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
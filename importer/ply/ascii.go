@@ -0,0 +1,35 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+)
+
+// asciiReader reads the values of an ASCII PLY body.
+type asciiReader struct {
+	scanner *bufio.Scanner
+}
+
+func newASCIIReader(r io.Reader) *asciiReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Split(bufio.ScanWords)
+	return &asciiReader{scanner: scanner}
+}
+
+func (r *asciiReader) read(t dataType) (float64, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return 0, err
+		}
+		return 0, io.ErrUnexpectedEOF
+	}
+	if t.isFloat() {
+		return strconv.ParseFloat(r.scanner.Text(), 64)
+	}
+	val, err := strconv.ParseInt(r.scanner.Text(), 10, 64)
+	return float64(val), err
+}
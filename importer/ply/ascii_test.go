@@ -0,0 +1,37 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_asciiReader_read(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		t       dataType
+		want    float64
+		wantErr bool
+	}{
+		{"eof", "", typeFloat32, 0, true},
+		{"badfloat", "a", typeFloat32, 0, true},
+		{"badint", "1.5", typeInt32, 0, true},
+		{"float", " 1.5e1\n", typeFloat64, 15, false},
+		{"int", "\t-3 ", typeInt16, -3, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newASCIIReader(strings.NewReader(tt.data)).read(tt.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("asciiReader.read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("asciiReader.read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+)
+
+// binaryReader reads the values of a binary PLY body.
+type binaryReader struct {
+	r            io.Reader
+	littleEndian bool
+	buf          [8]byte
+}
+
+func (r *binaryReader) read(t dataType) (float64, error) {
+	b := r.buf[:t.size()]
+	if _, err := io.ReadFull(r.r, b); err != nil {
+		return 0, err
+	}
+	var order binary.ByteOrder = binary.BigEndian
+	if r.littleEndian {
+		order = binary.LittleEndian
+	}
+	switch t {
+	case typeInt8:
+		return float64(int8(b[0])), nil
+	case typeUint8:
+		return float64(b[0]), nil
+	case typeInt16:
+		return float64(int16(order.Uint16(b))), nil
+	case typeUint16:
+		return float64(order.Uint16(b)), nil
+	case typeInt32:
+		return float64(int32(order.Uint32(b))), nil
+	case typeUint32:
+		return float64(order.Uint32(b)), nil
+	case typeFloat32:
+		return float64(math.Float32frombits(order.Uint32(b))), nil
+	}
+	return math.Float64frombits(order.Uint64(b)), nil
+}
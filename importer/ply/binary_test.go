@@ -0,0 +1,68 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+func createBinarySquare(littleEndian bool) []byte {
+	var order binary.ByteOrder = binary.BigEndian
+	format := "binary_big_endian"
+	if littleEndian {
+		order = binary.LittleEndian
+		format = "binary_little_endian"
+	}
+	b := new(bytes.Buffer)
+	b.WriteString("ply\nformat " + format + " 1.0\nelement vertex 4\nproperty float x\nproperty float y\nproperty double z\n")
+	b.WriteString("element face 1\nproperty list uchar ushort vertex_indices\nend_header\n")
+	for _, v := range [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+		binary.Write(b, order, v)
+		binary.Write(b, order, float64(0))
+	}
+	b.WriteByte(4)
+	binary.Write(b, order, []uint16{0, 1, 2, 3})
+	return b.Bytes()
+}
+
+func Test_binaryReader_read(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		littleEndian bool
+		t            dataType
+		want         float64
+		wantErr      bool
+	}{
+		{"eof", nil, true, typeUint8, 0, true},
+		{"short", []byte{1}, true, typeUint16, 0, true},
+		{"int8", []byte{0xff}, true, typeInt8, -1, false},
+		{"uint8", []byte{0xff}, true, typeUint8, 255, false},
+		{"int16", []byte{0xfe, 0xff}, true, typeInt16, -2, false},
+		{"uint16", []byte{0x01, 0x02}, false, typeUint16, 258, false},
+		{"int32", []byte{0xff, 0xff, 0xff, 0xfd}, false, typeInt32, -3, false},
+		{"uint32", []byte{0x01, 0, 0, 0}, true, typeUint32, 1, false},
+		{"float32", []byte{0x3f, 0xc0, 0, 0}, false, typeFloat32, 1.5, false},
+		{"float64", []byte{0, 0, 0, 0, 0, 0, 0xf8, 0x3f}, true, typeFloat64, 1.5, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &binaryReader{r: bytes.NewReader(tt.data), littleEndian: tt.littleEndian}
+			got, err := r.read(tt.t)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("binaryReader.read() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("binaryReader.read() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := (&binaryReader{r: bytes.NewReader([]byte{1})}).read(typeUint16); err != io.ErrUnexpectedEOF {
+		t.Errorf("binaryReader.read() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
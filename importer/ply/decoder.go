@@ -0,0 +1,437 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+var checkEveryFaces = 1000
+
+var (
+	errInvalidHeader = errors.New("ply: invalid header")
+	errInvalidIndex  = errors.New("ply: face vertex index out of range")
+)
+
+type format uint8
+
+const (
+	formatASCII format = iota
+	formatBinaryLittleEndian
+	formatBinaryBigEndian
+)
+
+type dataType uint8
+
+const (
+	typeInt8 dataType = iota + 1
+	typeUint8
+	typeInt16
+	typeUint16
+	typeInt32
+	typeUint32
+	typeFloat32
+	typeFloat64
+)
+
+func newDataType(s string) (t dataType, ok bool) {
+	t, ok = map[string]dataType{
+		"char": typeInt8, "int8": typeInt8,
+		"uchar": typeUint8, "uint8": typeUint8,
+		"short": typeInt16, "int16": typeInt16,
+		"ushort": typeUint16, "uint16": typeUint16,
+		"int": typeInt32, "int32": typeInt32,
+		"uint": typeUint32, "uint32": typeUint32,
+		"float": typeFloat32, "float32": typeFloat32,
+		"double": typeFloat64, "float64": typeFloat64,
+	}[s]
+	return
+}
+
+func (t dataType) size() int {
+	switch t {
+	case typeInt8, typeUint8:
+		return 1
+	case typeInt16, typeUint16:
+		return 2
+	case typeInt32, typeUint32, typeFloat32:
+		return 4
+	}
+	return 8
+}
+
+func (t dataType) isFloat() bool {
+	return t == typeFloat32 || t == typeFloat64
+}
+
+type property struct {
+	Name      string
+	Type      dataType
+	CountType dataType // Only defined for lists.
+}
+
+func (p *property) isList() bool {
+	return p.CountType != 0
+}
+
+type element struct {
+	Name       string
+	Count      int
+	Properties []property
+}
+
+type header struct {
+	Format   format
+	Elements []element
+}
+
+// valueReader reads the values of the elements defined in the header.
+type valueReader interface {
+	read(t dataType) (float64, error)
+}
+
+// Decoder can decode a PLY.
+// It supports ASCII, binary little endian and binary big endian encodings.
+//
+// Per-vertex and per-face colors are added to a ColorGroup resource.
+type Decoder struct {
+	r io.Reader
+}
+
+// NewDecoder creates a new decoder.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r: r,
+	}
+}
+
+// Decode creates a mesh from a read stream.
+func (d *Decoder) Decode(m *go3mf.Model) error {
+	return d.DecodeContext(context.Background(), m)
+}
+
+// DecodeContext creates a mesh from a read stream.
+func (d *Decoder) DecodeContext(ctx context.Context, m *go3mf.Model) error {
+	b := bufio.NewReader(d.r)
+	h, err := decodeHeader(b)
+	if err != nil {
+		return err
+	}
+	var r valueReader
+	switch h.Format {
+	case formatASCII:
+		r = newASCIIReader(b)
+	case formatBinaryLittleEndian:
+		r = &binaryReader{r: b, littleEndian: true}
+	default:
+		r = &binaryReader{r: b}
+	}
+	s := &decodeState{
+		model:  m,
+		obj:    &go3mf.Object{Mesh: new(go3mf.Mesh)},
+		colors: make(map[color.RGBA]uint32),
+	}
+	s.builder = go3mf.NewMeshBuilder(s.obj.Mesh)
+	for i := range h.Elements {
+		if err = s.decodeElement(ctx, r, &h.Elements[i]); err != nil {
+			return err
+		}
+	}
+	s.flush()
+	return nil
+}
+
+func decodeHeader(r *bufio.Reader) (*header, error) {
+	line, err := r.ReadString('\n')
+	if err != nil || strings.TrimSpace(line) != "ply" {
+		return nil, errInvalidHeader
+	}
+	h := new(header)
+	var hasFormat bool
+	for {
+		line, err = r.ReadString('\n')
+		if err != nil {
+			return nil, errInvalidHeader
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch fields[0] {
+		case "format":
+			if len(fields) < 2 {
+				return nil, errInvalidHeader
+			}
+			switch fields[1] {
+			case "ascii":
+				h.Format = formatASCII
+			case "binary_little_endian":
+				h.Format = formatBinaryLittleEndian
+			case "binary_big_endian":
+				h.Format = formatBinaryBigEndian
+			default:
+				return nil, errInvalidHeader
+			}
+			hasFormat = true
+		case "element":
+			if len(fields) != 3 {
+				return nil, errInvalidHeader
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, errInvalidHeader
+			}
+			h.Elements = append(h.Elements, element{Name: fields[1], Count: count})
+		case "property":
+			if len(h.Elements) == 0 {
+				return nil, errInvalidHeader
+			}
+			p, err := decodeProperty(fields)
+			if err != nil {
+				return nil, err
+			}
+			e := &h.Elements[len(h.Elements)-1]
+			e.Properties = append(e.Properties, p)
+		case "end_header":
+			if !hasFormat {
+				return nil, errInvalidHeader
+			}
+			return h, nil
+		}
+	}
+}
+
+func decodeProperty(fields []string) (p property, err error) {
+	var ok bool
+	if len(fields) == 5 && fields[1] == "list" {
+		p.Name = fields[4]
+		if p.CountType, ok = newDataType(fields[2]); !ok || p.CountType.isFloat() {
+			return p, errInvalidHeader
+		}
+		if p.Type, ok = newDataType(fields[3]); !ok {
+			return p, errInvalidHeader
+		}
+		return p, nil
+	}
+	if len(fields) != 3 {
+		return p, errInvalidHeader
+	}
+	p.Name = fields[2]
+	if p.Type, ok = newDataType(fields[1]); !ok {
+		return p, errInvalidHeader
+	}
+	return p, nil
+}
+
+type decodeState struct {
+	model       *go3mf.Model
+	obj         *go3mf.Object
+	builder     *go3mf.MeshBuilder
+	vertices    []uint32 // ply index -> mesh index
+	vertexColor []uint32 // ply index -> color index
+	group       *materials.ColorGroup
+	colors      map[color.RGBA]uint32
+	indices     []uint32
+}
+
+func (s *decodeState) decodeElement(ctx context.Context, r valueReader, e *element) error {
+	var (
+		values        = make([]float64, len(e.Properties))
+		nextFaceCheck = checkEveryFaces
+	)
+	for i := 0; i < e.Count; i++ {
+		s.indices = s.indices[:0]
+		for j := range e.Properties {
+			p := &e.Properties[j]
+			if !p.isList() {
+				val, err := r.read(p.Type)
+				if err != nil {
+					return err
+				}
+				values[j] = val
+				continue
+			}
+			count, err := r.read(p.CountType)
+			if err != nil {
+				return err
+			}
+			if count < 0 {
+				return fmt.Errorf("ply: invalid list size %v", count)
+			}
+			// Only the vertex indices are used, other lists such as texcoord are skipped.
+			isIndex := p.Name == "vertex_indices" || p.Name == "vertex_index"
+			for k := 0; k < int(count); k++ {
+				val, err := r.read(p.Type)
+				if err != nil {
+					return err
+				}
+				if isIndex {
+					s.indices = append(s.indices, uint32(val))
+				}
+			}
+		}
+		switch e.Name {
+		case "vertex":
+			s.addVertex(e, values)
+		case "face":
+			if err := s.addFace(e, values); err != nil {
+				return err
+			}
+			if i > nextFaceCheck {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				default: // Default is must to avoid blocking
+				}
+				nextFaceCheck += checkEveryFaces
+			}
+		}
+	}
+	return nil
+}
+
+func (s *decodeState) addVertex(e *element, values []float64) {
+	var p go3mf.Point3D
+	c, hasColor := readColor(e, values)
+	for j, prop := range e.Properties {
+		switch prop.Name {
+		case "x":
+			p[0] = float32(values[j])
+		case "y":
+			p[1] = float32(values[j])
+		case "z":
+			p[2] = float32(values[j])
+		}
+	}
+	s.vertices = append(s.vertices, s.builder.AddVertex(p))
+	if hasColor {
+		for len(s.vertexColor) < len(s.vertices)-1 {
+			s.vertexColor = append(s.vertexColor, s.colorIndex(color.RGBA{R: 255, G: 255, B: 255, A: 255}))
+		}
+		s.vertexColor = append(s.vertexColor, s.colorIndex(c))
+	}
+}
+
+func (s *decodeState) addFace(e *element, values []float64) error {
+	if len(s.indices) < 3 {
+		return nil
+	}
+	for _, index := range s.indices {
+		if int(index) >= len(s.vertices) {
+			return errInvalidIndex
+		}
+	}
+	faceColor, hasFaceColor := readColor(e, values)
+	var faceColorIndex uint32
+	if hasFaceColor {
+		faceColorIndex = s.colorIndex(faceColor)
+	}
+	mesh := s.obj.Mesh
+	for i := 1; i < len(s.indices)-1; i++ {
+		refs := [3]uint32{s.indices[0], s.indices[i], s.indices[i+1]}
+		t := go3mf.Triangle{V1: s.vertices[refs[0]], V2: s.vertices[refs[1]], V3: s.vertices[refs[2]]}
+		if t.V1 == t.V2 || t.V1 == t.V3 || t.V2 == t.V3 {
+			continue
+		}
+		if hasFaceColor {
+			t.PID = s.group.ID
+			t.P1, t.P2, t.P3 = faceColorIndex, faceColorIndex, faceColorIndex
+		} else if s.group != nil {
+			t.PID = s.group.ID
+			t.P1, t.P2, t.P3 = s.vertexColorIndex(refs[0]), s.vertexColorIndex(refs[1]), s.vertexColorIndex(refs[2])
+		}
+		mesh.Triangles.Triangle = append(mesh.Triangles.Triangle, t)
+	}
+	return nil
+}
+
+func (s *decodeState) vertexColorIndex(i uint32) uint32 {
+	if int(i) < len(s.vertexColor) {
+		return s.vertexColor[i]
+	}
+	return s.colorIndex(color.RGBA{R: 255, G: 255, B: 255, A: 255})
+}
+
+func (s *decodeState) colorIndex(c color.RGBA) uint32 {
+	if s.group == nil {
+		s.group = &materials.ColorGroup{ID: s.model.Resources.UnusedID()}
+		s.model.Resources.Assets = append(s.model.Resources.Assets, s.group)
+	}
+	if index, ok := s.colors[c]; ok {
+		return index
+	}
+	s.group.Colors = append(s.group.Colors, c)
+	index := uint32(len(s.group.Colors)) - 1
+	s.colors[c] = index
+	return index
+}
+
+// flush adds the decoded object and its build item to the model.
+func (s *decodeState) flush() {
+	tris := s.obj.Mesh.Triangles.Triangle
+	if s.group != nil && len(tris) > 0 {
+		s.obj.PID, s.obj.PIndex = tris[0].PID, tris[0].P1
+		hasExt := false
+		for _, ext := range s.model.Extensions {
+			if ext.Namespace == materials.Namespace {
+				hasExt = true
+				break
+			}
+		}
+		if !hasExt {
+			s.model.Extensions = append(s.model.Extensions, materials.DefaultExtension)
+		}
+	}
+	s.obj.ID = s.model.Resources.UnusedID()
+	s.model.Resources.Objects = append(s.model.Resources.Objects, s.obj)
+	s.model.Build.Items = append(s.model.Build.Items, &go3mf.Item{ObjectID: s.obj.ID})
+}
+
+// readColor extracts the red, green, blue and alpha properties.
+// Integer channels are expected in the [0, 255] range and
+// float channels in the [0, 1] range.
+func readColor(e *element, values []float64) (color.RGBA, bool) {
+	c := color.RGBA{A: 255}
+	var hasColor bool
+	for j, prop := range e.Properties {
+		if prop.isList() {
+			continue
+		}
+		var channel *uint8
+		switch prop.Name {
+		case "red", "diffuse_red", "r":
+			channel = &c.R
+		case "green", "diffuse_green", "g":
+			channel = &c.G
+		case "blue", "diffuse_blue", "b":
+			channel = &c.B
+		case "alpha", "a":
+			channel = &c.A
+		default:
+			continue
+		}
+		hasColor = true
+		val := values[j]
+		if prop.Type.isFloat() {
+			val *= 255
+		}
+		if val < 0 {
+			val = 0
+		} else if val > 255 {
+			val = 255
+		}
+		*channel = uint8(val + 0.5)
+	}
+	return c, hasColor
+}
@@ -0,0 +1,277 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package ply
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"image/color"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+const squareASCII = `ply
+format ascii 1.0
+comment a unit square
+element vertex 4
+property float x
+property float y
+property float z
+element face 1
+property list uchar int vertex_indices
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+4 0 1 2 3
+`
+
+func TestNewDecoder(t *testing.T) {
+	type args struct {
+		r io.Reader
+	}
+	tests := []struct {
+		name string
+		args args
+		want *Decoder
+	}{
+		{"base", args{new(bytes.Buffer)}, &Decoder{r: new(bytes.Buffer)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewDecoder(tt.args.r); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewDecoder() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode(t *testing.T) {
+	square := &go3mf.Object{ID: 1, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2}, {V1: 0, V2: 2, V3: 3},
+		}},
+	}}
+	tests := []struct {
+		name    string
+		d       *Decoder
+		want    *go3mf.Object
+		wantErr bool
+	}{
+		{"empty", NewDecoder(new(bytes.Buffer)), nil, true},
+		{"noply", NewDecoder(bytes.NewBufferString("solid\n")), nil, true},
+		{"noformat", NewDecoder(bytes.NewBufferString("ply\nend_header\n")), nil, true},
+		{"badformat", NewDecoder(bytes.NewBufferString("ply\nformat other 1.0\nend_header\n")), nil, true},
+		{"badelement", NewDecoder(bytes.NewBufferString("ply\nformat ascii 1.0\nelement vertex a\nend_header\n")), nil, true},
+		{"orphanproperty", NewDecoder(bytes.NewBufferString("ply\nformat ascii 1.0\nproperty float x\nend_header\n")), nil, true},
+		{"badtype", NewDecoder(bytes.NewBufferString("ply\nformat ascii 1.0\nelement vertex 1\nproperty float128 x\nend_header\n")), nil, true},
+		{"badlisttype", NewDecoder(bytes.NewBufferString("ply\nformat ascii 1.0\nelement face 1\nproperty list float int vertex_indices\nend_header\n")), nil, true},
+		{"truncated", NewDecoder(bytes.NewBufferString(strings.TrimSuffix(squareASCII, "4 0 1 2 3\n"))), nil, true},
+		{"outofrange", NewDecoder(bytes.NewBufferString(strings.Replace(squareASCII, "4 0 1 2 3", "3 0 1 7", 1))), nil, true},
+		{"ascii", NewDecoder(bytes.NewBufferString(squareASCII)), square, false},
+		{"crlf", NewDecoder(bytes.NewBufferString(strings.ReplaceAll(squareASCII, "\n", "\r\n"))), square, false},
+		{"little", NewDecoder(bytes.NewReader(createBinarySquare(true))), square, false},
+		{"big", NewDecoder(bytes.NewReader(createBinarySquare(false))), square, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := new(go3mf.Model)
+			err := tt.d.Decode(got)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Decoder.Decode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := deep.Equal(got.Resources.Objects[0], tt.want); diff != nil {
+				t.Errorf("Decoder.Decode() = %v", diff)
+			}
+			if len(got.Build.Items) != 1 || got.Build.Items[0].ObjectID != 1 {
+				t.Errorf("Decoder.Decode() items = %v", got.Build.Items)
+			}
+		})
+	}
+}
+
+func TestDecoder_Decode_vertexColors(t *testing.T) {
+	r := strings.NewReader(`ply
+format ascii 1.0
+element vertex 3
+property float x
+property float y
+property float z
+property uchar red
+property uchar green
+property uchar blue
+element face 1
+property list uchar uint vertex_index
+end_header
+0 0 0 255 0 0
+1 0 0 0 255 0
+0 1 0 255 0 0
+3 0 1 2
+`)
+	got := new(go3mf.Model)
+	if err := NewDecoder(r).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want := &materials.ColorGroup{ID: 1, Colors: []color.RGBA{{R: 255, A: 255}, {G: 255, A: 255}}}
+	if diff := deep.Equal(got.Resources.Assets, []go3mf.Asset{want}); diff != nil {
+		t.Errorf("Decoder.Decode() assets = %v", diff)
+	}
+	obj := got.Resources.Objects[0]
+	if obj.ID != 2 || obj.PID != 1 || obj.PIndex != 0 {
+		t.Errorf("Decoder.Decode() object = %v", obj)
+	}
+	wantTri := go3mf.Triangle{V1: 0, V2: 1, V3: 2, PID: 1, P1: 0, P2: 1, P3: 0}
+	if diff := deep.Equal(obj.Mesh.Triangles.Triangle, []go3mf.Triangle{wantTri}); diff != nil {
+		t.Errorf("Decoder.Decode() triangles = %v", diff)
+	}
+	if len(got.Extensions) != 1 || got.Extensions[0].Namespace != materials.Namespace {
+		t.Errorf("Decoder.Decode() extensions = %v", got.Extensions)
+	}
+}
+
+func TestDecoder_Decode_texcoord(t *testing.T) {
+	r := strings.NewReader(`ply
+format ascii 1.0
+element vertex 4
+property float x
+property float y
+property float z
+element face 2
+property list uchar int vertex_indices
+property list uchar float texcoord
+end_header
+0 0 0
+1 0 0
+0 1 0
+0 0 1
+3 0 1 2 6 0 0 1 0 0 1
+4 0 1 3 2 8 0 0 1 0 1 1 0 1
+`)
+	got := new(go3mf.Model)
+	if err := NewDecoder(r).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want := []go3mf.Triangle{{V1: 0, V2: 1, V3: 2}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}}
+	if diff := deep.Equal(got.Resources.Objects[0].Mesh.Triangles.Triangle, want); diff != nil {
+		t.Errorf("Decoder.Decode() triangles = %v", diff)
+	}
+}
+
+func TestDecoder_Decode_faceColors(t *testing.T) {
+	r := strings.NewReader(`ply
+format ascii 1.0
+element vertex 4
+property double x
+property double y
+property double z
+element face 2
+property list uchar int vertex_indices
+property float red
+property float green
+property float blue
+property float alpha
+end_header
+0 0 0
+1 0 0
+1 1 0
+0 1 0
+3 0 1 2 1 0 0 1
+3 0 2 3 0 0 1 0.5
+`)
+	got := new(go3mf.Model)
+	if err := NewDecoder(r).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	want := &materials.ColorGroup{ID: 1, Colors: []color.RGBA{{R: 255, A: 255}, {B: 255, A: 128}}}
+	if diff := deep.Equal(got.Resources.Assets, []go3mf.Asset{want}); diff != nil {
+		t.Errorf("Decoder.Decode() assets = %v", diff)
+	}
+	want1 := []go3mf.Triangle{
+		{V1: 0, V2: 1, V3: 2, PID: 1},
+		{V1: 0, V2: 2, V3: 3, PID: 1, P1: 1, P2: 1, P3: 1},
+	}
+	if diff := deep.Equal(got.Resources.Objects[0].Mesh.Triangles.Triangle, want1); diff != nil {
+		t.Errorf("Decoder.Decode() triangles = %v", diff)
+	}
+}
+
+func TestDecoder_Decode_skipElements(t *testing.T) {
+	r := strings.NewReader(`ply
+format ascii 1.0
+element vertex 3
+property float x
+property float y
+property float z
+property float nx
+element edge 1
+property int vertex1
+property int vertex2
+element face 2
+property list uchar int vertex_indices
+end_header
+0 0 0 1
+1 0 0 1
+0 1 0 1
+0 1
+3 0 1 2
+3 0 0 1
+`)
+	got := new(go3mf.Model)
+	if err := NewDecoder(r).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if n := len(got.Resources.Objects[0].Mesh.Triangles.Triangle); n != 1 {
+		t.Errorf("Decoder.Decode() triangles = %d, want 1", n)
+	}
+	if len(got.Resources.Assets) != 0 {
+		t.Errorf("Decoder.Decode() assets = %v", got.Resources.Assets)
+	}
+}
+
+func TestDecoder_DecodeContext_cancel(t *testing.T) {
+	checkEveryFaces = 1
+	defer func() { checkEveryFaces = 1000 }()
+	var b strings.Builder
+	b.WriteString("ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\n")
+	b.WriteString("element face 10\nproperty list uchar int vertex_indices\nend_header\n0 0 0\n1 0 0\n0 1 0\n")
+	for i := 0; i < 10; i++ {
+		b.WriteString("3 0 1 2\n")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := NewDecoder(strings.NewReader(b.String())).DecodeContext(ctx, new(go3mf.Model)); err != context.Canceled {
+		t.Errorf("Decoder.DecodeContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func Test_decodeHeader(t *testing.T) {
+	h, err := decodeHeader(bufio.NewReader(strings.NewReader(squareASCII)))
+	if err != nil {
+		t.Fatalf("decodeHeader() error = %v", err)
+	}
+	want := &header{Format: formatASCII, Elements: []element{
+		{Name: "vertex", Count: 4, Properties: []property{
+			{Name: "x", Type: typeFloat32}, {Name: "y", Type: typeFloat32}, {Name: "z", Type: typeFloat32},
+		}},
+		{Name: "face", Count: 1, Properties: []property{
+			{Name: "vertex_indices", Type: typeInt32, CountType: typeUint8},
+		}},
+	}}
+	if diff := deep.Equal(h, want); diff != nil {
+		t.Errorf("decodeHeader() = %v", diff)
+	}
+}
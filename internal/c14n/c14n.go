@@ -0,0 +1,252 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package c14n implements the Canonical XML 1.0 algorithm, without comments,
+// as used by the XML digital signatures.
+package c14n
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+)
+
+const nsXML = "http://www.w3.org/XML/1998/namespace"
+
+// ErrNotFound is returned when no element matches the selection.
+var ErrNotFound = errors.New("c14n: element not found")
+
+// Element returns the canonical form of the first element for which match returns true.
+// The StartElement passed to match has its name space resolved.
+//
+// Namespace declarations and xml: attributes inherited from the ancestors
+// are rendered in the selected element, as the specification requires.
+func Element(doc []byte, match func(xml.StartElement) bool) ([]byte, error) {
+	c := canonicalizer{d: xml.NewDecoder(bytes.NewReader(doc))}
+	return c.run(match)
+}
+
+// Document returns the canonical form of doc.
+func Document(doc []byte) ([]byte, error) {
+	c := canonicalizer{d: xml.NewDecoder(bytes.NewReader(doc)), whole: true}
+	return c.run(func(xml.StartElement) bool { return true })
+}
+
+type scope struct {
+	ns       map[string]string // prefix -> name space declared in the input
+	rendered map[string]string // prefix -> name space rendered in the output
+	xmlAttrs []xml.Attr        // xml: attributes in scope
+}
+
+type canonicalizer struct {
+	d      *xml.Decoder
+	whole  bool
+	b      bytes.Buffer
+	stack  []scope
+	depth  int // depth inside the selected element, 0 if outside.
+	done   bool
+	inRoot bool
+}
+
+func (c *canonicalizer) run(match func(xml.StartElement) bool) ([]byte, error) {
+	c.stack = []scope{{ns: map[string]string{"xml": nsXML}, rendered: map[string]string{}}}
+	for !c.done {
+		t, err := c.d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			c.start(t, match)
+		case xml.EndElement:
+			c.end(t)
+		case xml.CharData:
+			if c.depth > 0 {
+				escapeText(&c.b, t)
+			}
+		case xml.ProcInst:
+			if t.Target == "xml" {
+				continue
+			}
+			if c.depth > 0 || c.whole {
+				if c.whole && c.depth == 0 && c.inRoot {
+					c.b.WriteByte('\n')
+				}
+				c.b.WriteString("<?" + t.Target)
+				if len(t.Inst) > 0 {
+					c.b.WriteByte(' ')
+					c.b.Write(t.Inst)
+				}
+				c.b.WriteString("?>")
+				if c.whole && c.depth == 0 && !c.inRoot {
+					c.b.WriteByte('\n')
+				}
+			}
+		}
+	}
+	if c.b.Len() == 0 {
+		return nil, ErrNotFound
+	}
+	return c.b.Bytes(), nil
+}
+
+func (c *canonicalizer) start(t xml.StartElement, match func(xml.StartElement) bool) {
+	parent := c.stack[len(c.stack)-1]
+	s := scope{ns: parent.ns, rendered: parent.rendered, xmlAttrs: parent.xmlAttrs}
+	var (
+		decls []xml.Attr
+		attrs []xml.Attr
+	)
+	for _, a := range t.Attr {
+		switch {
+		case a.Name.Space == "xmlns":
+			decls = append(decls, a)
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			decls = append(decls, a)
+		default:
+			attrs = append(attrs, a)
+		}
+	}
+	if len(decls) > 0 {
+		s.ns = make(map[string]string, len(parent.ns)+len(decls))
+		for k, v := range parent.ns {
+			s.ns[k] = v
+		}
+		for _, a := range decls {
+			if a.Name.Space == "" {
+				s.ns[""] = a.Value
+			} else {
+				s.ns[a.Name.Local] = a.Value
+			}
+		}
+	}
+	var ownXML []xml.Attr
+	for _, a := range attrs {
+		if a.Name.Space == "xml" {
+			ownXML = append(ownXML, a)
+		}
+	}
+	if len(ownXML) > 0 {
+		s.xmlAttrs = mergeXMLAttrs(parent.xmlAttrs, ownXML)
+	}
+	c.stack = append(c.stack, s)
+
+	if c.depth == 0 {
+		if c.done || !match(c.resolve(t, s.ns)) {
+			return
+		}
+		// Apex element: render everything in scope.
+		parent.rendered = map[string]string{}
+		attrs = mergeXMLAttrs(s.xmlAttrs, attrs)
+		c.inRoot = true
+	}
+	c.depth++
+	rendered := make(map[string]string, len(s.ns))
+	var out []xml.Attr
+	for prefix, uri := range s.ns {
+		if prefix == "xml" {
+			continue
+		}
+		rendered[prefix] = uri
+		prev, ok := parent.rendered[prefix]
+		if ok && prev == uri {
+			continue
+		}
+		if prefix == "" {
+			if uri == "" && prev == "" {
+				continue
+			}
+			out = append(out, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: uri})
+		} else {
+			out = append(out, xml.Attr{Name: xml.Name{Space: "xmlns", Local: prefix}, Value: uri})
+		}
+	}
+	c.stack[len(c.stack)-1].rendered = rendered
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name.Space < out[j].Name.Space || (out[i].Name.Space == out[j].Name.Space && out[i].Name.Local < out[j].Name.Local)
+	})
+	sort.SliceStable(attrs, func(i, j int) bool {
+		si, sj := c.attrSpace(attrs[i], s.ns), c.attrSpace(attrs[j], s.ns)
+		if si != sj {
+			return si < sj
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+	c.b.WriteByte('<')
+	c.b.WriteString(qname(t.Name))
+	for _, a := range append(out, attrs...) {
+		c.b.WriteByte(' ')
+		c.b.WriteString(qname(a.Name))
+		c.b.WriteString(`="`)
+		escapeAttr(&c.b, a.Value)
+		c.b.WriteByte('"')
+	}
+	c.b.WriteByte('>')
+}
+
+func (c *canonicalizer) end(t xml.EndElement) {
+	c.stack = c.stack[:len(c.stack)-1]
+	if c.depth == 0 {
+		return
+	}
+	c.b.WriteString("</" + qname(t.Name) + ">")
+	c.depth--
+	if c.depth == 0 && !c.whole {
+		c.done = true
+	}
+}
+
+func (c *canonicalizer) resolve(t xml.StartElement, ns map[string]string) xml.StartElement {
+	r := xml.StartElement{Name: xml.Name{Space: ns[t.Name.Space], Local: t.Name.Local}, Attr: make([]xml.Attr, len(t.Attr))}
+	for i, a := range t.Attr {
+		r.Attr[i] = xml.Attr{Name: xml.Name{Space: c.attrSpace(a, ns), Local: a.Name.Local}, Value: a.Value}
+	}
+	return r
+}
+
+func (c *canonicalizer) attrSpace(a xml.Attr, ns map[string]string) string {
+	if a.Name.Space == "" {
+		return ""
+	}
+	return ns[a.Name.Space]
+}
+
+func mergeXMLAttrs(inherited, own []xml.Attr) []xml.Attr {
+	merged := append([]xml.Attr(nil), own...)
+	for _, a := range inherited {
+		found := false
+		for _, o := range own {
+			if o.Name == a.Name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, a)
+		}
+	}
+	return merged
+}
+
+func qname(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func escapeText(b *bytes.Buffer, s []byte) {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	r.WriteString(b, string(s))
+}
+
+func escapeAttr(b *bytes.Buffer, s string) {
+	r := strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+	r.WriteString(b, s)
+}
@@ -0,0 +1,90 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package c14n
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestDocument(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"tags", `<doc>
+   <e1   />
+   <e2   ></e2>
+   <e3   name = "elem3"   id="elem3"   />
+   <e4   name="elem4"   id="elem4"   ></e4>
+   <e5 a:attr="out" b:attr="sorted" attr2="all" attr="I'm"
+      xmlns:b="http://www.ietf.org"
+      xmlns:a="http://www.w3.org"
+      xmlns="http://example.org"/>
+   <e6 xmlns="" xmlns:a="http://www.w3.org">
+      <e7 xmlns="http://www.ietf.org">
+         <e8 xmlns="" xmlns:a="http://www.w3.org">
+            <e9 xmlns="" xmlns:a="http://www.ietf.org"/>
+         </e8>
+      </e7>
+   </e6>
+</doc>`, `<doc>
+   <e1></e1>
+   <e2></e2>
+   <e3 id="elem3" name="elem3"></e3>
+   <e4 id="elem4" name="elem4"></e4>
+   <e5 xmlns="http://example.org" xmlns:a="http://www.w3.org" xmlns:b="http://www.ietf.org" attr="I'm" attr2="all" b:attr="sorted" a:attr="out"></e5>
+   <e6 xmlns:a="http://www.w3.org">
+      <e7 xmlns="http://www.ietf.org">
+         <e8 xmlns="">
+            <e9 xmlns:a="http://www.ietf.org"></e9>
+         </e8>
+      </e7>
+   </e6>
+</doc>`},
+		{"pi", "<?xml version=\"1.0\"?>\n<?pi data?>\n<!-- c --><r/>\n<?post?>", "<?pi data?>\n<r></r>\n<?post?>"},
+		{"escape", `<r a="&quot;&#9;&lt;&#13;" b='&apos;'>x &amp; &lt; &gt; &#13; "'<!--c--></r>`, `<r a="&quot;&#x9;&lt;&#xD;" b="'">x &amp; &lt; &gt; &#xD; "'</r>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Document([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("Document() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Document() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestElement(t *testing.T) {
+	doc := []byte(`<a xmlns="u" xmlns:p="v" xml:lang="en"><b p:x="1"><c xmlns:p="v"/></b><b id="2"/></a>`)
+	tests := []struct {
+		name    string
+		match   func(xml.StartElement) bool
+		want    string
+		wantErr bool
+	}{
+		{"inherited", func(s xml.StartElement) bool { return s.Name.Local == "b" },
+			`<b xmlns="u" xmlns:p="v" xml:lang="en" p:x="1"><c></c></b>`, false},
+		{"attr", func(s xml.StartElement) bool { return len(s.Attr) > 0 && s.Attr[0].Value == "2" },
+			`<b xmlns="u" xmlns:p="v" id="2" xml:lang="en"></b>`, false},
+		{"space", func(s xml.StartElement) bool { return s.Name.Space == "u" && s.Name.Local == "c" },
+			`<c xmlns="u" xmlns:p="v" xml:lang="en"></c>`, false},
+		{"notfound", func(s xml.StartElement) bool { return false }, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Element(doc, tt.match)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Element() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Element() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package meshtest provides mesh fixtures shared by the package tests.
+package meshtest
+
+import "github.com/hpinc/go3mf"
+
+// Box returns a closed box mesh with outward normals.
+func Box(min, max go3mf.Point3D) *go3mf.Mesh {
+	m := new(go3mf.Mesh)
+	for _, v := range []go3mf.Point3D{
+		{min[0], min[1], min[2]}, {max[0], min[1], min[2]}, {max[0], max[1], min[2]}, {min[0], max[1], min[2]},
+		{min[0], min[1], max[2]}, {max[0], min[1], max[2]}, {max[0], max[1], max[2]}, {min[0], max[1], max[2]},
+	} {
+		m.Vertices.Vertex = append(m.Vertices.Vertex, v)
+	}
+	for _, t := range [][3]uint32{
+		{0, 2, 1}, {0, 3, 2}, {4, 5, 6}, {4, 6, 7}, {0, 1, 5}, {0, 5, 4},
+		{1, 2, 6}, {1, 6, 5}, {2, 3, 7}, {2, 7, 6}, {3, 0, 4}, {3, 4, 7},
+	} {
+		m.Triangles.Triangle = append(m.Triangles.Triangle, go3mf.Triangle{V1: t[0], V2: t[1], V3: t[2]})
+	}
+	return m
+}
@@ -8,11 +8,26 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hpinc/go3mf"
 	specerr "github.com/hpinc/go3mf/errors"
 	"github.com/hpinc/go3mf/spec"
 )
 
-func (Spec) NewAttrGroup(xml.Name) spec.AttrGroup {
+func (Spec) NewAttrGroup(parent xml.Name) spec.AttrGroup {
+	if parent.Space == go3mf.Namespace && parent.Local == "basematerials" {
+		return new(BaseMaterialsAttr)
+	}
+	return nil
+}
+
+func (u *BaseMaterialsAttr) Unmarshal3MFAttr(a spec.XMLAttr) error {
+	if a.Name.Local == attrDisplayPropertiesID {
+		val, err := strconv.ParseUint(string(a.Value), 10, 32)
+		if err != nil {
+			return specerr.NewParseAttrError(a.Name.Local, false)
+		}
+		u.DisplayPropertiesID = uint32(val)
+	}
 	return nil
 }
 
@@ -31,6 +46,16 @@ func (Spec) NewElementDecoder(name xml.Name) (child spec.GetterElementDecoder) {
 		child = new(compositeMaterialsDecoder)
 	case attrMultiProps:
 		child = new(multiPropertiesDecoder)
+	case attrPBSpecularDisplayProps:
+		child = new(pbSpecularDisplayPropsDecoder)
+	case attrPBMetallicDisplayProps:
+		child = new(pbMetallicDisplayPropsDecoder)
+	case attrPBSpecularTextureDisplayProps:
+		child = new(pbSpecularTextureDisplayPropsDecoder)
+	case attrPBMetallicTextureDisplayProps:
+		child = new(pbMetallicTextureDisplayPropsDecoder)
+	case attrTranslucentDisplayProps:
+		child = new(translucentDisplayPropsDecoder)
 	}
 	return
 }
@@ -56,13 +81,22 @@ func (d *colorGroupDecoder) Child(name xml.Name) (i int, child spec.ElementDecod
 func (d *colorGroupDecoder) Start(attrs []spec.XMLAttr) (errs error) {
 	d.colorDecoder.resource = &d.resource
 	for _, a := range attrs {
-		if a.Name.Space == "" && a.Name.Local == attrID {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID:
 			id, err := strconv.ParseUint(string(a.Value), 10, 32)
 			if err != nil {
 				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
 			}
 			d.resource.ID = uint32(id)
-			break
+		case attrDisplayPropertiesID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.DisplayPropertiesID = uint32(val)
 		}
 	}
 	return
@@ -157,6 +191,12 @@ func (d *tex2DGroupDecoder) Start(attrs []spec.XMLAttr) error {
 				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
 			}
 			d.resource.TextureID = uint32(val)
+		case attrDisplayPropertiesID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.DisplayPropertiesID = uint32(val)
 		}
 	}
 	return errs
@@ -237,6 +277,12 @@ func (d *compositeMaterialsDecoder) Start(attrs []spec.XMLAttr) error {
 				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
 			}
 			d.resource.MaterialID = uint32(val)
+		case attrDisplayPropertiesID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.DisplayPropertiesID = uint32(val)
 		case attrMatIndices:
 			for _, f := range strings.Fields(string(a.Value)) {
 				val, err := strconv.ParseUint(f, 10, 32)
@@ -350,6 +396,338 @@ func (d *multiDecoder) Start(attrs []spec.XMLAttr) error {
 	return errs
 }
 
+type pbSpecularDisplayPropsDecoder struct {
+	baseDecoder
+	resource          PBSpecularDisplayProperties
+	pbSpecularDecoder pbSpecularDecoder
+}
+
+func (d *pbSpecularDisplayPropsDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *pbSpecularDisplayPropsDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrPBSpecular {
+		child = &d.pbSpecularDecoder
+		i = len(d.resource.Speculars)
+	}
+	return
+}
+
+func (d *pbSpecularDisplayPropsDecoder) Start(attrs []spec.XMLAttr) error {
+	d.pbSpecularDecoder.resource = &d.resource
+	id, err := parseID(attrs)
+	d.resource.ID = id
+	return err
+}
+
+type pbSpecularDecoder struct {
+	baseDecoder
+	resource *PBSpecularDisplayProperties
+}
+
+func (d *pbSpecularDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	s := PBSpecular{SpecularColor: defaultSpecularColor}
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrName:
+			s.Name = string(a.Value)
+		case attrSpecularColor:
+			c, err := spec.ParseRGBA(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			s.SpecularColor = c
+		case attrGlossiness:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			s.Glossiness = float32(val)
+		}
+	}
+	d.resource.Speculars = append(d.resource.Speculars, s)
+	return errs
+}
+
+type pbMetallicDisplayPropsDecoder struct {
+	baseDecoder
+	resource          PBMetallicDisplayProperties
+	pbMetallicDecoder pbMetallicDecoder
+}
+
+func (d *pbMetallicDisplayPropsDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *pbMetallicDisplayPropsDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrPBMetallic {
+		child = &d.pbMetallicDecoder
+		i = len(d.resource.Metallics)
+	}
+	return
+}
+
+func (d *pbMetallicDisplayPropsDecoder) Start(attrs []spec.XMLAttr) error {
+	d.pbMetallicDecoder.resource = &d.resource
+	id, err := parseID(attrs)
+	d.resource.ID = id
+	return err
+}
+
+type pbMetallicDecoder struct {
+	baseDecoder
+	resource *PBMetallicDisplayProperties
+}
+
+func (d *pbMetallicDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	m := PBMetallic{Roughness: 1}
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrName:
+			m.Name = string(a.Value)
+		case attrMetallicness:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			m.Metallicness = float32(val)
+		case attrRoughness:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			m.Roughness = float32(val)
+		}
+	}
+	d.resource.Metallics = append(d.resource.Metallics, m)
+	return errs
+}
+
+type pbSpecularTextureDisplayPropsDecoder struct {
+	baseDecoder
+	resource PBSpecularTextureDisplayProperties
+}
+
+func (d *pbSpecularTextureDisplayPropsDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *pbSpecularTextureDisplayPropsDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	d.resource.DiffuseFactor = defaultFactorColor
+	d.resource.SpecularFactor = defaultFactorColor
+	d.resource.GlossinessFactor = 1
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID:
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+		case attrName:
+			d.resource.Name = string(a.Value)
+		case attrSpecularTextureID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.SpecularTextureID = uint32(val)
+		case attrGlossinessTextureID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.GlossinessTextureID = uint32(val)
+		case attrDiffuseFactor:
+			c, err := spec.ParseRGBA(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.DiffuseFactor = c
+		case attrSpecularFactor:
+			c, err := spec.ParseRGBA(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.SpecularFactor = c
+		case attrGlossinessFactor:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.GlossinessFactor = float32(val)
+		}
+	}
+	return errs
+}
+
+type pbMetallicTextureDisplayPropsDecoder struct {
+	baseDecoder
+	resource PBMetallicTextureDisplayProperties
+}
+
+func (d *pbMetallicTextureDisplayPropsDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *pbMetallicTextureDisplayPropsDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	d.resource.BaseColorFactor = defaultFactorColor
+	d.resource.MetallicFactor = 1
+	d.resource.RoughnessFactor = 1
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID:
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+		case attrName:
+			d.resource.Name = string(a.Value)
+		case attrMetallicTextureID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.MetallicTextureID = uint32(val)
+		case attrRoughnessTextureID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.RoughnessTextureID = uint32(val)
+		case attrBaseColorFactor:
+			c, err := spec.ParseRGBA(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.BaseColorFactor = c
+		case attrMetallicFactor:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.MetallicFactor = float32(val)
+		case attrRoughnessFactor:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.RoughnessFactor = float32(val)
+		}
+	}
+	return errs
+}
+
+type translucentDisplayPropsDecoder struct {
+	baseDecoder
+	resource           TranslucentDisplayProperties
+	translucentDecoder translucentDecoder
+}
+
+func (d *translucentDisplayPropsDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *translucentDisplayPropsDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrTranslucent {
+		child = &d.translucentDecoder
+		i = len(d.resource.Translucents)
+	}
+	return
+}
+
+func (d *translucentDisplayPropsDecoder) Start(attrs []spec.XMLAttr) error {
+	d.translucentDecoder.resource = &d.resource
+	id, err := parseID(attrs)
+	d.resource.ID = id
+	return err
+}
+
+type translucentDecoder struct {
+	baseDecoder
+	resource *TranslucentDisplayProperties
+}
+
+func (d *translucentDecoder) Start(attrs []spec.XMLAttr) error {
+	var errs error
+	t := Translucent{RefractiveIndex: [3]float32{1, 1, 1}}
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrName:
+			t.Name = string(a.Value)
+		case attrAttenuation:
+			var ok bool
+			if t.Attenuation, ok = parseRGB(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+		case attrRefractiveIndex:
+			var ok bool
+			if t.RefractiveIndex, ok = parseRGB(string(a.Value)); !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrRoughness:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			t.Roughness = float32(val)
+		}
+	}
+	d.resource.Translucents = append(d.resource.Translucents, t)
+	return errs
+}
+
+// parseID returns the value of the id attribute.
+func parseID(attrs []spec.XMLAttr) (uint32, error) {
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrID {
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				return 0, specerr.NewParseAttrError(a.Name.Local, true)
+			}
+			return uint32(id), nil
+		}
+	}
+	return 0, nil
+}
+
+// parseRGB parses a list of three space separated numbers.
+func parseRGB(s string) (v [3]float32, ok bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 3 {
+		return v, false
+	}
+	for i, f := range fields {
+		val, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			return v, false
+		}
+		v[i] = float32(val)
+	}
+	return v, true
+}
+
 type baseDecoder struct {
 }
 
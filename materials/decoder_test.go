@@ -11,6 +11,7 @@ import (
 	"github.com/go-test/deep"
 	"github.com/hpinc/go3mf"
 	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
 )
 
 func TestDecode(t *testing.T) {
@@ -61,6 +62,76 @@ func TestDecode(t *testing.T) {
 	})
 }
 
+func TestDecode_displayProperties(t *testing.T) {
+	want := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+	}
+	want.Resources.Assets = append(want.Resources.Assets,
+		&go3mf.BaseMaterials{ID: 1, Materials: []go3mf.Base{{Name: "Red", Color: color.RGBA{R: 255, A: 255}}}, AnyAttr: spec.AnyAttr{&BaseMaterialsAttr{DisplayPropertiesID: 3}}},
+		&ColorGroup{ID: 2, DisplayPropertiesID: 4, Colors: []color.RGBA{{R: 255, G: 255, B: 255, A: 255}, {A: 255}}},
+		&PBSpecularDisplayProperties{ID: 3, Speculars: []PBSpecular{{Name: "Glossy", SpecularColor: defaultSpecularColor, Glossiness: 0.8}}},
+		&PBMetallicDisplayProperties{ID: 4, Metallics: []PBMetallic{
+			{Name: "Metal", Metallicness: 1, Roughness: 0.2},
+			{Name: "Plastic", Roughness: 1},
+		}},
+		&TranslucentDisplayProperties{ID: 5, Translucents: []Translucent{
+			{Name: "Glass", Attenuation: [3]float32{0.1, 0.2, 0.3}, RefractiveIndex: [3]float32{1.5, 1.5, 1.5}, Roughness: 0.1},
+			{Name: "Water", Attenuation: [3]float32{1, 1, 1}, RefractiveIndex: [3]float32{1, 1, 1}},
+		}},
+		&PBSpecularTextureDisplayProperties{ID: 6, Name: "SpecTex", SpecularTextureID: 8, GlossinessTextureID: 8,
+			DiffuseFactor: defaultFactorColor, SpecularFactor: color.RGBA{R: 128, G: 128, B: 128, A: 255}, GlossinessFactor: 1},
+		&PBMetallicTextureDisplayProperties{ID: 7, Name: "MetalTex", MetallicTextureID: 8, RoughnessTextureID: 8,
+			BaseColorFactor: defaultFactorColor, MetallicFactor: 0.5, RoughnessFactor: 1},
+		&Texture2DGroup{ID: 9, TextureID: 8, DisplayPropertiesID: 7, Coords: []TextureCoord{{0, 0}}},
+		&CompositeMaterials{ID: 10, MaterialID: 1, DisplayPropertiesID: 3, Indices: []uint32{0}, Composites: []Composite{{Values: []float32{1}}}},
+	)
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+	<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:m="http://schemas.microsoft.com/3dmanufacturing/material/2015/02">
+		<resources>
+			<basematerials id="1" m:displaypropertiesid="3">
+				<base name="Red" displaycolor="#FF0000" />
+			</basematerials>
+			<m:colorgroup id="2" displaypropertiesid="4">
+				<m:color color="#FFFFFF" /> <m:color color="#000000" />
+			</m:colorgroup>
+			<m:pbspeculardisplayproperties id="3">
+				<m:pbspecular name="Glossy" glossiness="0.8" />
+			</m:pbspeculardisplayproperties>
+			<m:pbmetallicdisplayproperties id="4">
+				<m:pbmetallic name="Metal" metallicness="1" roughness="0.2" />
+				<m:pbmetallic name="Plastic" />
+			</m:pbmetallicdisplayproperties>
+			<m:translucentdisplayproperties id="5">
+				<m:translucent name="Glass" attenuation="0.1 0.2 0.3" refractiveindex="1.5 1.5 1.5" roughness="0.1" />
+				<m:translucent name="Water" attenuation="1 1 1" />
+			</m:translucentdisplayproperties>
+			<m:pbspeculartexturedisplayproperties id="6" name="SpecTex" speculartextureid="8" glossinesstextureid="8" specularfactor="#808080" />
+			<m:pbmetallictexturedisplayproperties id="7" name="MetalTex" metallictextureid="8" roughnesstextureid="8" metallicfactor="0.5" />
+			<m:texture2dgroup id="9" texid="8" displaypropertiesid="7">
+				<m:tex2coord u="0" v="0" />
+			</m:texture2dgroup>
+			<m:compositematerials id="10" matid="1" matindices="0" displaypropertiesid="3">
+				<m:composite values="1"/>
+			</m:compositematerials>
+		</resources>
+		<build>
+		</build>
+	</model>`
+	t.Run("base", func(t *testing.T) {
+		if err := go3mf.UnmarshalModel([]byte(rootFile), got); err != nil {
+			t.Errorf("DecodeRawModel() unexpected error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("DecodeRawModell() = %v", diff)
+			return
+		}
+	})
+}
+
 func TestDecode_warns(t *testing.T) {
 	want := []string{
 		fmt.Sprintf("go3mf: XPath: /model/resources/texture2d[1]: %v", errors.NewParseAttrError("id", true)),
@@ -71,6 +142,22 @@ func TestDecode_warns(t *testing.T) {
 		fmt.Sprintf("go3mf: XPath: /model/resources/compositematerials[4]: %v", errors.NewParseAttrError("matid", true)),
 		fmt.Sprintf("go3mf: XPath: /model/resources/compositematerials[4]/composite[1]: %v", errors.NewParseAttrError("values", true)),
 		fmt.Sprintf("go3mf: XPath: /model/resources/multiproperties[5]: %v", errors.NewParseAttrError("pids", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/basematerials[7]: %v", errors.NewParseAttrError("displaypropertiesid", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/colorgroup[8]: %v", errors.NewParseAttrError("displaypropertiesid", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculardisplayproperties[9]: %v", errors.NewParseAttrError("id", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculardisplayproperties[9]/pbspecular[0]: %v", errors.NewParseAttrError("specularcolor", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculardisplayproperties[9]/pbspecular[0]: %v", errors.NewParseAttrError("glossiness", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallicdisplayproperties[10]/pbmetallic[0]: %v", errors.NewParseAttrError("metallicness", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallicdisplayproperties[10]/pbmetallic[0]: %v", errors.NewParseAttrError("roughness", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/translucentdisplayproperties[11]/translucent[0]: %v", errors.NewParseAttrError("attenuation", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/translucentdisplayproperties[11]/translucent[0]: %v", errors.NewParseAttrError("refractiveindex", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/translucentdisplayproperties[11]/translucent[0]: %v", errors.NewParseAttrError("roughness", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[12]: %v", errors.NewParseAttrError("speculartextureid", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[12]: %v", errors.NewParseAttrError("diffusefactor", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[12]: %v", errors.NewParseAttrError("glossinessfactor", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallictexturedisplayproperties[13]: %v", errors.NewParseAttrError("roughnesstextureid", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallictexturedisplayproperties[13]: %v", errors.NewParseAttrError("basecolorfactor", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallictexturedisplayproperties[13]: %v", errors.NewParseAttrError("roughnessfactor", false)),
 	}
 	got := new(go3mf.Model)
 	got.Path = "/3D/3dmodel.model"
@@ -93,6 +180,23 @@ func TestDecode_warns(t *testing.T) {
 				<m:multi />
 			</m:multiproperties>
 			<m:multiproperties id="19" />
+			<basematerials id="20" m:displaypropertiesid="a">
+				<base name="Red" displaycolor="#FF0000" />
+			</basematerials>
+			<m:colorgroup id="21" displaypropertiesid="b">
+				<m:color color="#FFFFFF" />
+			</m:colorgroup>
+			<m:pbspeculardisplayproperties id="c">
+				<m:pbspecular name="Glossy" specularcolor="#38" glossiness="d" />
+			</m:pbspeculardisplayproperties>
+			<m:pbmetallicdisplayproperties id="23">
+				<m:pbmetallic name="Metal" metallicness="e" roughness="f" />
+			</m:pbmetallicdisplayproperties>
+			<m:translucentdisplayproperties id="24">
+				<m:translucent name="Glass" attenuation="0.1 0.2" refractiveindex="1 1 a" roughness="g" />
+			</m:translucentdisplayproperties>
+			<m:pbspeculartexturedisplayproperties id="25" name="SpecTex" speculartextureid="h" glossinesstextureid="6" diffusefactor="#1" glossinessfactor="i" />
+			<m:pbmetallictexturedisplayproperties id="26" name="MetalTex" metallictextureid="6" roughnesstextureid="j" basecolorfactor="#2" roughnessfactor="k" />
 			<object id="8" name="Box 1" pid="5" pindex="0" type="model">
 				<mesh>
 					<vertices>
@@ -16,6 +16,7 @@ func (r *ColorGroup) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
 	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrColorGroup}, Attr: []xml.Attr{
 		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
 	}}
+	xs.Attr = appendDisplayPropertiesID(xs.Attr, r.DisplayPropertiesID)
 	x.EncodeToken(xs)
 	x.SetAutoClose(true)
 	x.SetSkipAttrEscape(true)
@@ -38,6 +39,7 @@ func (r *Texture2DGroup) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
 		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
 		{Name: xml.Name{Local: attrTexID}, Value: strconv.FormatUint(uint64(r.TextureID), 10)},
 	}}
+	xs.Attr = appendDisplayPropertiesID(xs.Attr, r.DisplayPropertiesID)
 	x.EncodeToken(xs)
 	x.SetAutoClose(true)
 	x.SetSkipAttrEscape(true)
@@ -68,6 +70,7 @@ func (r *CompositeMaterials) Marshal3MF(x spec.Encoder, _ *xml.StartElement) err
 		{Name: xml.Name{Local: attrMatID}, Value: strconv.FormatUint(uint64(r.MaterialID), 10)},
 		{Name: xml.Name{Local: attrMatIndices}, Value: strings.Join(indices, " ")},
 	}}
+	xs.Attr = appendDisplayPropertiesID(xs.Attr, r.DisplayPropertiesID)
 	x.EncodeToken(xs)
 	x.SetAutoClose(true)
 	x.SetSkipAttrEscape(true)
@@ -141,3 +144,170 @@ func (r *Texture2D) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
 	x.SetAutoClose(false)
 	return nil
 }
+
+// Marshal3MF encodes the resource attributes.
+func (u *BaseMaterialsAttr) Marshal3MF(_ spec.Encoder, start *xml.StartElement) error {
+	if u.DisplayPropertiesID != 0 {
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Space: Namespace, Local: attrDisplayPropertiesID},
+			Value: strconv.FormatUint(uint64(u.DisplayPropertiesID), 10),
+		})
+	}
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *PBSpecularDisplayProperties) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBSpecularDisplayProps}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	x.EncodeToken(xs)
+	x.SetAutoClose(true)
+	prec := x.FloatPresicion()
+	for _, s := range r.Speculars {
+		start := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBSpecular}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrName}, Value: s.Name},
+		}}
+		if s.SpecularColor != defaultSpecularColor {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attrSpecularColor}, Value: spec.FormatRGBA(s.SpecularColor)})
+		}
+		if s.Glossiness != 0 {
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: attrGlossiness},
+				Value: strconv.FormatFloat(float64(s.Glossiness), 'f', prec, 32),
+			})
+		}
+		x.EncodeToken(start)
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *PBMetallicDisplayProperties) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBMetallicDisplayProps}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	x.EncodeToken(xs)
+	x.SetAutoClose(true)
+	prec := x.FloatPresicion()
+	for _, m := range r.Metallics {
+		start := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBMetallic}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrName}, Value: m.Name},
+		}}
+		if m.Metallicness != 0 {
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: attrMetallicness},
+				Value: strconv.FormatFloat(float64(m.Metallicness), 'f', prec, 32),
+			})
+		}
+		if m.Roughness != 1 {
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: attrRoughness},
+				Value: strconv.FormatFloat(float64(m.Roughness), 'f', prec, 32),
+			})
+		}
+		x.EncodeToken(start)
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *PBSpecularTextureDisplayProperties) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBSpecularTextureDisplayProps}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+		{Name: xml.Name{Local: attrName}, Value: r.Name},
+		{Name: xml.Name{Local: attrSpecularTextureID}, Value: strconv.FormatUint(uint64(r.SpecularTextureID), 10)},
+		{Name: xml.Name{Local: attrGlossinessTextureID}, Value: strconv.FormatUint(uint64(r.GlossinessTextureID), 10)},
+	}}
+	if r.DiffuseFactor != defaultFactorColor {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrDiffuseFactor}, Value: spec.FormatRGBA(r.DiffuseFactor)})
+	}
+	if r.SpecularFactor != defaultFactorColor {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrSpecularFactor}, Value: spec.FormatRGBA(r.SpecularFactor)})
+	}
+	if r.GlossinessFactor != 1 {
+		xs.Attr = append(xs.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrGlossinessFactor},
+			Value: strconv.FormatFloat(float64(r.GlossinessFactor), 'f', x.FloatPresicion(), 32),
+		})
+	}
+	x.SetAutoClose(true)
+	x.EncodeToken(xs)
+	x.SetAutoClose(false)
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *PBMetallicTextureDisplayProperties) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrPBMetallicTextureDisplayProps}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+		{Name: xml.Name{Local: attrName}, Value: r.Name},
+		{Name: xml.Name{Local: attrMetallicTextureID}, Value: strconv.FormatUint(uint64(r.MetallicTextureID), 10)},
+		{Name: xml.Name{Local: attrRoughnessTextureID}, Value: strconv.FormatUint(uint64(r.RoughnessTextureID), 10)},
+	}}
+	if r.BaseColorFactor != defaultFactorColor {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrBaseColorFactor}, Value: spec.FormatRGBA(r.BaseColorFactor)})
+	}
+	if r.MetallicFactor != 1 {
+		xs.Attr = append(xs.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrMetallicFactor},
+			Value: strconv.FormatFloat(float64(r.MetallicFactor), 'f', x.FloatPresicion(), 32),
+		})
+	}
+	if r.RoughnessFactor != 1 {
+		xs.Attr = append(xs.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrRoughnessFactor},
+			Value: strconv.FormatFloat(float64(r.RoughnessFactor), 'f', x.FloatPresicion(), 32),
+		})
+	}
+	x.SetAutoClose(true)
+	x.EncodeToken(xs)
+	x.SetAutoClose(false)
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *TranslucentDisplayProperties) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTranslucentDisplayProps}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	x.EncodeToken(xs)
+	x.SetAutoClose(true)
+	prec := x.FloatPresicion()
+	for _, t := range r.Translucents {
+		start := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTranslucent}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrName}, Value: t.Name},
+			{Name: xml.Name{Local: attrAttenuation}, Value: formatRGB(t.Attenuation, prec)},
+		}}
+		if t.RefractiveIndex != [3]float32{1, 1, 1} {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: attrRefractiveIndex}, Value: formatRGB(t.RefractiveIndex, prec)})
+		}
+		if t.Roughness != 0 {
+			start.Attr = append(start.Attr, xml.Attr{
+				Name:  xml.Name{Local: attrRoughness},
+				Value: strconv.FormatFloat(float64(t.Roughness), 'f', prec, 32),
+			})
+		}
+		x.EncodeToken(start)
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+func appendDisplayPropertiesID(attrs []xml.Attr, id uint32) []xml.Attr {
+	if id == 0 {
+		return attrs
+	}
+	return append(attrs, xml.Attr{Name: xml.Name{Local: attrDisplayPropertiesID}, Value: strconv.FormatUint(uint64(id), 10)})
+}
+
+func formatRGB(v [3]float32, prec int) string {
+	return strconv.FormatFloat(float64(v[0]), 'f', prec, 32) + " " +
+		strconv.FormatFloat(float64(v[1]), 'f', prec, 32) + " " +
+		strconv.FormatFloat(float64(v[2]), 'f', prec, 32)
+}
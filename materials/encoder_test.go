@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-test/deep"
 	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
 )
 
 func TestMarshalModel(t *testing.T) {
@@ -17,8 +18,26 @@ func TestMarshalModel(t *testing.T) {
 	texGroup := &Texture2DGroup{ID: 2, TextureID: 6, Coords: []TextureCoord{{0.3, 0.5}, {0.3, 0.8}, {0.5, 0.8}, {0.5, 0.5}}}
 	compositeGroup := &CompositeMaterials{ID: 4, MaterialID: 5, Indices: []uint32{1, 2}, Composites: []Composite{{Values: []float32{0.5, 0.5}}, {Values: []float32{0.2, 0.8}}}}
 	multiGroup := &MultiProperties{ID: 9, BlendMethods: []BlendMethod{BlendMultiply}, PIDs: []uint32{5, 2}, Multis: []Multi{{PIndices: []uint32{0, 0}}, {PIndices: []uint32{1, 0}}, {PIndices: []uint32{2, 3}}}}
+	baseMaterials := &go3mf.BaseMaterials{ID: 10, Materials: []go3mf.Base{{Name: "Red", Color: color.RGBA{R: 255, A: 255}}}, AnyAttr: spec.AnyAttr{&BaseMaterialsAttr{DisplayPropertiesID: 11}}}
+	specular := &PBSpecularDisplayProperties{ID: 11, Speculars: []PBSpecular{
+		{Name: "Glossy", SpecularColor: defaultSpecularColor, Glossiness: 0.8},
+		{Name: "Matte", SpecularColor: color.RGBA{R: 10, G: 20, B: 30, A: 255}},
+	}}
+	metallic := &PBMetallicDisplayProperties{ID: 12, Metallics: []PBMetallic{{Name: "Metal", Metallicness: 1, Roughness: 0.2}, {Name: "Plastic", Roughness: 1}}}
+	translucent := &TranslucentDisplayProperties{ID: 13, Translucents: []Translucent{
+		{Name: "Glass", Attenuation: [3]float32{0.1, 0.2, 0.3}, RefractiveIndex: [3]float32{1.5, 1.5, 1.5}, Roughness: 0.1},
+		{Name: "Water", RefractiveIndex: [3]float32{1, 1, 1}},
+	}}
+	specularTexture := &PBSpecularTextureDisplayProperties{ID: 14, Name: "SpecTex", SpecularTextureID: 6, GlossinessTextureID: 6,
+		DiffuseFactor: color.RGBA{R: 1, A: 255}, SpecularFactor: color.RGBA{G: 1, A: 255}, GlossinessFactor: 0.5}
+	metallicTexture := &PBMetallicTextureDisplayProperties{ID: 15, Name: "MetalTex", MetallicTextureID: 6, RoughnessTextureID: 6,
+		BaseColorFactor: defaultFactorColor, MetallicFactor: 0.5, RoughnessFactor: 0.25}
+	colorGroup.DisplayPropertiesID = 12
+	texGroup.DisplayPropertiesID = 15
+	compositeGroup.DisplayPropertiesID = 13
 	m := &go3mf.Model{Path: "/3D/3dmodel.model"}
-	m.Resources.Assets = append(m.Resources.Assets, baseTexture, colorGroup, texGroup, compositeGroup, multiGroup)
+	m.Resources.Assets = append(m.Resources.Assets, baseTexture, colorGroup, texGroup, compositeGroup, multiGroup,
+		baseMaterials, specular, metallic, translucent, specularTexture, metallicTexture)
 	m.Extensions = []go3mf.Extension{DefaultExtension}
 	t.Run("base", func(t *testing.T) {
 		b, err := go3mf.MarshalModel(m)
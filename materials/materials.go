@@ -9,6 +9,7 @@ import (
 	"image/color"
 
 	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
 	"github.com/hpinc/go3mf/spec"
 )
 
@@ -27,6 +28,17 @@ var DefaultExtension = go3mf.Extension{
 
 func init() {
 	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrMultiBlend:         {ID: "MAT001", Name: "MultiBlend", Severity: reporting.SeverityError},
+		ErrMaterialMulti:      {ID: "MAT002", Name: "MaterialMulti", Severity: reporting.SeverityError},
+		ErrMultiRefMulti:      {ID: "MAT003", Name: "MultiRefMulti", Severity: reporting.SeverityError},
+		ErrMultiColors:        {ID: "MAT004", Name: "MultiColors", Severity: reporting.SeverityError},
+		ErrTextureReference:   {ID: "MAT005", Name: "TextureReference", Severity: reporting.SeverityError},
+		ErrCompositeBase:      {ID: "MAT006", Name: "CompositeBase", Severity: reporting.SeverityError},
+		ErrMissingTexturePart: {ID: "MAT007", Name: "MissingTexturePart", Severity: reporting.SeverityError},
+		ErrDisplayReference:   {ID: "MAT008", Name: "DisplayReference", Severity: reporting.SeverityError},
+		ErrDisplayCount:       {ID: "MAT009", Name: "DisplayCount", Severity: reporting.SeverityError},
+	})
 }
 
 type Spec struct{}
@@ -39,6 +51,8 @@ var (
 	ErrTextureReference   = errors.New("MUST reference to a texture resource")
 	ErrCompositeBase      = errors.New("MUST reference to a basematerials group")
 	ErrMissingTexturePart = errors.New("texture part MUST be added as an attachment")
+	ErrDisplayReference   = errors.New("MUST reference to a display properties resource of a type supported by the referencing group")
+	ErrDisplayCount       = errors.New("the number of display properties MUST match the number of properties of the referencing group")
 )
 
 // Texture2DType defines the allowed texture 2D types.
@@ -146,9 +160,10 @@ func (t TextureCoord) V() float32 {
 
 // Texture2DGroup acts as a container for texture coordinate properties.
 type Texture2DGroup struct {
-	ID        uint32
-	TextureID uint32
-	Coords    []TextureCoord
+	ID                  uint32
+	TextureID           uint32
+	DisplayPropertiesID uint32
+	Coords              []TextureCoord
 }
 
 // Len returns the materials count.
@@ -168,8 +183,9 @@ func (Texture2DGroup) XMLName() xml.Name {
 
 // ColorGroup acts as a container for color properties.
 type ColorGroup struct {
-	ID     uint32
-	Colors []color.RGBA
+	ID                  uint32
+	DisplayPropertiesID uint32
+	Colors              []color.RGBA
 }
 
 // Len returns the materials count.
@@ -194,10 +210,11 @@ type Composite struct {
 
 // CompositeMaterials defines materials derived by mixing 2 or more base materials in defined ratios.
 type CompositeMaterials struct {
-	ID         uint32
-	MaterialID uint32
-	Indices    []uint32
-	Composites []Composite
+	ID                  uint32
+	MaterialID          uint32
+	DisplayPropertiesID uint32
+	Indices             []uint32
+	Composites          []Composite
 }
 
 // Len returns the materials count.
@@ -244,6 +261,145 @@ func (MultiProperties) XMLName() xml.Name {
 	return xml.Name{Space: Namespace, Local: attrMultiProps}
 }
 
+// BaseMaterialsAttr adds a display properties reference
+// to the core basematerials element.
+type BaseMaterialsAttr struct {
+	DisplayPropertiesID uint32
+}
+
+func (BaseMaterialsAttr) Namespace() string { return Namespace }
+
+func GetBaseMaterialsAttr(r *go3mf.BaseMaterials) *BaseMaterialsAttr {
+	for _, a := range r.AnyAttr {
+		if a, ok := a.(*BaseMaterialsAttr); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+// PBSpecular defines the physically based properties
+// of a material following the specular workflow.
+type PBSpecular struct {
+	Name          string
+	SpecularColor color.RGBA
+	Glossiness    float32
+}
+
+// PBSpecularDisplayProperties acts as a container for PBSpecular elements.
+type PBSpecularDisplayProperties struct {
+	ID        uint32
+	Speculars []PBSpecular
+}
+
+// Identify returns the unique ID of the resource.
+func (r *PBSpecularDisplayProperties) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (PBSpecularDisplayProperties) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrPBSpecularDisplayProps}
+}
+
+// PBMetallic defines the physically based properties
+// of a material following the metallic workflow.
+type PBMetallic struct {
+	Name         string
+	Metallicness float32
+	Roughness    float32
+}
+
+// PBMetallicDisplayProperties acts as a container for PBMetallic elements.
+type PBMetallicDisplayProperties struct {
+	ID        uint32
+	Metallics []PBMetallic
+}
+
+// Identify returns the unique ID of the resource.
+func (r *PBMetallicDisplayProperties) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (PBMetallicDisplayProperties) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrPBMetallicDisplayProps}
+}
+
+// PBSpecularTextureDisplayProperties defines the specular workflow
+// properties of a texture2dgroup from a specular and a glossiness texture.
+type PBSpecularTextureDisplayProperties struct {
+	ID                  uint32
+	Name                string
+	SpecularTextureID   uint32
+	GlossinessTextureID uint32
+	DiffuseFactor       color.RGBA
+	SpecularFactor      color.RGBA
+	GlossinessFactor    float32
+}
+
+// Identify returns the unique ID of the resource.
+func (r *PBSpecularTextureDisplayProperties) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (PBSpecularTextureDisplayProperties) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrPBSpecularTextureDisplayProps}
+}
+
+// PBMetallicTextureDisplayProperties defines the metallic workflow
+// properties of a texture2dgroup from a metallic and a roughness texture.
+type PBMetallicTextureDisplayProperties struct {
+	ID                 uint32
+	Name               string
+	MetallicTextureID  uint32
+	RoughnessTextureID uint32
+	BaseColorFactor    color.RGBA
+	MetallicFactor     float32
+	RoughnessFactor    float32
+}
+
+// Identify returns the unique ID of the resource.
+func (r *PBMetallicTextureDisplayProperties) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (PBMetallicTextureDisplayProperties) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrPBMetallicTextureDisplayProps}
+}
+
+// Translucent defines the optical properties of a translucent material.
+// Attenuation and RefractiveIndex hold the red, green and blue components.
+type Translucent struct {
+	Name            string
+	Attenuation     [3]float32
+	RefractiveIndex [3]float32
+	Roughness       float32
+}
+
+// TranslucentDisplayProperties acts as a container for Translucent elements.
+type TranslucentDisplayProperties struct {
+	ID           uint32
+	Translucents []Translucent
+}
+
+// Identify returns the unique ID of the resource.
+func (r *TranslucentDisplayProperties) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (TranslucentDisplayProperties) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrTranslucentDisplayProps}
+}
+
+var (
+	defaultSpecularColor = color.RGBA{R: 0x38, G: 0x38, B: 0x38, A: 0xff}
+	defaultFactorColor   = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+)
+
 func newTexture2DType(s string) (t Texture2DType, ok bool) {
 	t, ok = map[string]Texture2DType{
 		"image/png":  TextureTypePNG,
@@ -304,4 +460,31 @@ const (
 	attrPIndices           = "pindices"
 	attrPIDs               = "pids"
 	attrBlendMethods       = "blendmethods"
+
+	attrDisplayPropertiesID           = "displaypropertiesid"
+	attrName                          = "name"
+	attrPBSpecularDisplayProps        = "pbspeculardisplayproperties"
+	attrPBSpecular                    = "pbspecular"
+	attrSpecularColor                 = "specularcolor"
+	attrGlossiness                    = "glossiness"
+	attrPBMetallicDisplayProps        = "pbmetallicdisplayproperties"
+	attrPBMetallic                    = "pbmetallic"
+	attrMetallicness                  = "metallicness"
+	attrRoughness                     = "roughness"
+	attrPBSpecularTextureDisplayProps = "pbspeculartexturedisplayproperties"
+	attrSpecularTextureID             = "speculartextureid"
+	attrGlossinessTextureID           = "glossinesstextureid"
+	attrDiffuseFactor                 = "diffusefactor"
+	attrSpecularFactor                = "specularfactor"
+	attrGlossinessFactor              = "glossinessfactor"
+	attrPBMetallicTextureDisplayProps = "pbmetallictexturedisplayproperties"
+	attrMetallicTextureID             = "metallictextureid"
+	attrRoughnessTextureID            = "roughnesstextureid"
+	attrBaseColorFactor               = "basecolorfactor"
+	attrMetallicFactor                = "metallicfactor"
+	attrRoughnessFactor               = "roughnessfactor"
+	attrTranslucentDisplayProps       = "translucentdisplayproperties"
+	attrTranslucent                   = "translucent"
+	attrAttenuation                   = "attenuation"
+	attrRefractiveIndex               = "refractiveindex"
 )
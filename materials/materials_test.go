@@ -26,6 +26,27 @@ var _ spec.PropertyGroup = new(ColorGroup)
 var _ spec.PropertyGroup = new(Texture2DGroup)
 var _ spec.PropertyGroup = new(CompositeMaterials)
 var _ spec.PropertyGroup = new(MultiProperties)
+var _ go3mf.Asset = new(PBSpecularDisplayProperties)
+var _ go3mf.Asset = new(PBMetallicDisplayProperties)
+var _ go3mf.Asset = new(PBSpecularTextureDisplayProperties)
+var _ go3mf.Asset = new(PBMetallicTextureDisplayProperties)
+var _ go3mf.Asset = new(TranslucentDisplayProperties)
+var _ spec.Marshaler = new(PBSpecularDisplayProperties)
+var _ spec.Marshaler = new(PBMetallicDisplayProperties)
+var _ spec.Marshaler = new(PBSpecularTextureDisplayProperties)
+var _ spec.Marshaler = new(PBMetallicTextureDisplayProperties)
+var _ spec.Marshaler = new(TranslucentDisplayProperties)
+var _ spec.AttrGroup = new(BaseMaterialsAttr)
+
+func TestGetBaseMaterialsAttr(t *testing.T) {
+	attr := &BaseMaterialsAttr{DisplayPropertiesID: 1}
+	if got := GetBaseMaterialsAttr(&go3mf.BaseMaterials{}); got != nil {
+		t.Errorf("GetBaseMaterialsAttr() = %v, want nil", got)
+	}
+	if got := GetBaseMaterialsAttr(&go3mf.BaseMaterials{AnyAttr: spec.AnyAttr{attr}}); got != attr {
+		t.Errorf("GetBaseMaterialsAttr() = %v, want %v", got, attr)
+	}
+}
 
 func TestTexture2D_Identify(t *testing.T) {
 	tests := []struct {
@@ -21,7 +21,7 @@ func (Spec) Validate(model interface{}, path string, asset interface{}) error {
 func validateAsset(m *go3mf.Model, path string, r go3mf.Asset) (errs error) {
 	switch r := r.(type) {
 	case *ColorGroup:
-		errs = validateColorGroup(path, r)
+		errs = validateColorGroup(m, path, r)
 	case *Texture2DGroup:
 		errs = validateTexture2DGroup(m, path, r)
 	case *Texture2D:
@@ -30,11 +30,29 @@ func validateAsset(m *go3mf.Model, path string, r go3mf.Asset) (errs error) {
 		errs = validateMultiProps(m, path, r)
 	case *CompositeMaterials:
 		errs = validateCompositeMat(m, path, r)
+	case *go3mf.BaseMaterials:
+		if attr := GetBaseMaterialsAttr(r); attr != nil {
+			errs = validateDisplayRef(m, path, attr.DisplayPropertiesID, len(r.Materials))
+		}
+	case *PBSpecularDisplayProperties:
+		errs = validateDisplayProps(r.ID, len(r.Speculars), attrPBSpecular, func(i int) string { return r.Speculars[i].Name })
+	case *PBMetallicDisplayProperties:
+		errs = validateDisplayProps(r.ID, len(r.Metallics), attrPBMetallic, func(i int) string { return r.Metallics[i].Name })
+	case *TranslucentDisplayProperties:
+		errs = validateDisplayProps(r.ID, len(r.Translucents), attrTranslucent, func(i int) string { return r.Translucents[i].Name })
+	case *PBSpecularTextureDisplayProperties:
+		errs = validateTextureDisplayProps(r.ID, r.Name)
+		errs = errors.Append(errs, validateTextureRef(m, path, attrSpecularTextureID, r.SpecularTextureID))
+		errs = errors.Append(errs, validateTextureRef(m, path, attrGlossinessTextureID, r.GlossinessTextureID))
+	case *PBMetallicTextureDisplayProperties:
+		errs = validateTextureDisplayProps(r.ID, r.Name)
+		errs = errors.Append(errs, validateTextureRef(m, path, attrMetallicTextureID, r.MetallicTextureID))
+		errs = errors.Append(errs, validateTextureRef(m, path, attrRoughnessTextureID, r.RoughnessTextureID))
 	}
 	return
 }
 
-func validateColorGroup(path string, r *ColorGroup) (errs error) {
+func validateColorGroup(m *go3mf.Model, path string, r *ColorGroup) (errs error) {
 	if r.ID == 0 {
 		errs = errors.Append(errs, errors.ErrMissingID)
 	}
@@ -46,6 +64,7 @@ func validateColorGroup(path string, r *ColorGroup) (errs error) {
 			errs = errors.Append(errs, errors.WrapIndex(errors.NewMissingFieldError(attrColor), attrColor, j))
 		}
 	}
+	errs = errors.Append(errs, validateDisplayRef(m, path, r.DisplayPropertiesID, len(r.Colors)))
 	return
 }
 
@@ -65,6 +84,17 @@ func validateTexture2DGroup(m *go3mf.Model, path string, r *Texture2DGroup) (err
 	if len(r.Coords) == 0 {
 		errs = errors.Append(errs, errors.ErrEmptyResourceProps)
 	}
+	if r.DisplayPropertiesID != 0 {
+		if a, ok := m.FindAsset(path, r.DisplayPropertiesID); ok {
+			switch a.(type) {
+			case *PBSpecularTextureDisplayProperties, *PBMetallicTextureDisplayProperties:
+			default:
+				errs = errors.Append(errs, ErrDisplayReference)
+			}
+		} else {
+			errs = errors.Append(errs, ErrDisplayReference)
+		}
+	}
 	return
 }
 
@@ -174,5 +204,70 @@ func validateCompositeMat(m *go3mf.Model, path string, r *CompositeMaterials) (e
 	if len(r.Composites) == 0 {
 		errs = errors.Append(errs, errors.ErrEmptyResourceProps)
 	}
+	errs = errors.Append(errs, validateDisplayRef(m, path, r.DisplayPropertiesID, len(r.Composites)))
+	return
+}
+
+// validateDisplayRef checks that id references a non textured
+// display properties resource with n elements.
+func validateDisplayRef(m *go3mf.Model, path string, id uint32, n int) error {
+	if id == 0 {
+		return nil
+	}
+	a, ok := m.FindAsset(path, id)
+	if !ok {
+		return ErrDisplayReference
+	}
+	var count int
+	switch a := a.(type) {
+	case *PBSpecularDisplayProperties:
+		count = len(a.Speculars)
+	case *PBMetallicDisplayProperties:
+		count = len(a.Metallics)
+	case *TranslucentDisplayProperties:
+		count = len(a.Translucents)
+	default:
+		return ErrDisplayReference
+	}
+	if count != n {
+		return ErrDisplayCount
+	}
+	return nil
+}
+
+func validateDisplayProps(id uint32, n int, local string, name func(int) string) (errs error) {
+	if id == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if n == 0 {
+		errs = errors.Append(errs, errors.ErrEmptyResourceProps)
+	}
+	for i := 0; i < n; i++ {
+		if name(i) == "" {
+			errs = errors.Append(errs, errors.WrapIndex(errors.NewMissingFieldError(attrName), local, i))
+		}
+	}
+	return
+}
+
+func validateTextureDisplayProps(id uint32, name string) (errs error) {
+	if id == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if name == "" {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrName))
+	}
 	return
 }
+
+func validateTextureRef(m *go3mf.Model, path string, attr string, id uint32) error {
+	if id == 0 {
+		return errors.NewMissingFieldError(attr)
+	}
+	if text, ok := m.FindAsset(path, id); ok {
+		if _, ok := text.(*Texture2D); ok {
+			return nil
+		}
+	}
+	return ErrTextureReference
+}
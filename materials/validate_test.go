@@ -11,6 +11,7 @@ import (
 	"github.com/go-test/deep"
 	"github.com/hpinc/go3mf"
 	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
 )
 
 func TestValidate(t *testing.T) {
@@ -112,6 +113,41 @@ func TestValidate(t *testing.T) {
 			fmt.Sprintf("go3mf: XPath: /model/resources/compositematerials[4]: %v", ErrCompositeBase),
 			fmt.Sprintf("go3mf: XPath: /model/resources/compositematerials[5]: %v", errors.ErrMissingResource),
 		}},
+		{"displayProperties", &go3mf.Model{
+			Attachments: []go3mf.Attachment{{Path: "/a.png"}},
+			Resources: go3mf.Resources{Assets: []go3mf.Asset{
+				&Texture2D{ID: 1, ContentType: TextureTypePNG, Path: "/a.png"},
+				&PBSpecularDisplayProperties{},
+				&PBMetallicDisplayProperties{ID: 2, Metallics: []PBMetallic{{Name: "a"}, {}}},
+				&TranslucentDisplayProperties{ID: 3, Translucents: []Translucent{{Name: "a"}}},
+				&PBSpecularTextureDisplayProperties{},
+				&PBMetallicTextureDisplayProperties{ID: 4, Name: "b", MetallicTextureID: 1, RoughnessTextureID: 2},
+				&PBSpecularTextureDisplayProperties{ID: 5, Name: "c", SpecularTextureID: 100, GlossinessTextureID: 1},
+				&go3mf.BaseMaterials{ID: 6, Materials: []go3mf.Base{{Name: "a", Color: color.RGBA{R: 1}}}, AnyAttr: spec.AnyAttr{&BaseMaterialsAttr{DisplayPropertiesID: 3}}},
+				&go3mf.BaseMaterials{ID: 7, Materials: []go3mf.Base{{Name: "a", Color: color.RGBA{R: 1}}}, AnyAttr: spec.AnyAttr{&BaseMaterialsAttr{DisplayPropertiesID: 2}}},
+				&ColorGroup{ID: 8, DisplayPropertiesID: 4, Colors: []color.RGBA{{R: 1}}},
+				&ColorGroup{ID: 9, DisplayPropertiesID: 100, Colors: []color.RGBA{{R: 1}}},
+				&Texture2DGroup{ID: 10, TextureID: 1, DisplayPropertiesID: 4, Coords: []TextureCoord{{}}},
+				&Texture2DGroup{ID: 11, TextureID: 1, DisplayPropertiesID: 3, Coords: []TextureCoord{{}}},
+				&CompositeMaterials{ID: 12, MaterialID: 6, DisplayPropertiesID: 2, Indices: []uint32{0}, Composites: []Composite{{Values: []float32{1}}}},
+				&CompositeMaterials{ID: 13, MaterialID: 6, DisplayPropertiesID: 3, Indices: []uint32{0}, Composites: []Composite{{Values: []float32{1}}}},
+			}},
+		}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculardisplayproperties[1]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculardisplayproperties[1]: %v", errors.ErrEmptyResourceProps),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallicdisplayproperties[2]/pbmetallic[1]: %v", &errors.MissingFieldError{Name: attrName}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[4]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[4]: %v", &errors.MissingFieldError{Name: attrName}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[4]: %v", &errors.MissingFieldError{Name: attrSpecularTextureID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[4]: %v", &errors.MissingFieldError{Name: attrGlossinessTextureID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbmetallictexturedisplayproperties[5]: %v", ErrTextureReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/pbspeculartexturedisplayproperties[6]: %v", ErrTextureReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/basematerials[8]: %v", ErrDisplayCount),
+			fmt.Sprintf("go3mf: XPath: /model/resources/colorgroup[9]: %v", ErrDisplayReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/colorgroup[10]: %v", ErrDisplayReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/texture2dgroup[12]: %v", ErrDisplayReference),
+			fmt.Sprintf("go3mf: XPath: /model/resources/compositematerials[13]: %v", ErrDisplayCount),
+		}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
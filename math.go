@@ -103,6 +103,47 @@ func (v1 Point3D) Z() float32 {
 	return v1[2]
 }
 
+// Add performs element-wise addition between two vectors.
+func (v1 Point3D) Add(v2 Point3D) Point3D {
+	return Point3D{v1[0] + v2[0], v1[1] + v2[1], v1[2] + v2[2]}
+}
+
+// Sub performs element-wise subtraction between two vectors.
+func (v1 Point3D) Sub(v2 Point3D) Point3D {
+	return Point3D{v1[0] - v2[0], v1[1] - v2[1], v1[2] - v2[2]}
+}
+
+// Mul performs a scalar multiplication between the vector and some constant value c.
+func (v1 Point3D) Mul(c float32) Point3D {
+	return Point3D{v1[0] * c, v1[1] * c, v1[2] * c}
+}
+
+// Dot returns the dot product of two vectors.
+func (v1 Point3D) Dot(v2 Point3D) float32 {
+	return v1[0]*v2[0] + v1[1]*v2[1] + v1[2]*v2[2]
+}
+
+// Cross returns the cross product of two vectors.
+func (v1 Point3D) Cross(v2 Point3D) Point3D {
+	return Point3D{v1[1]*v2[2] - v1[2]*v2[1], v1[2]*v2[0] - v1[0]*v2[2], v1[0]*v2[1] - v1[1]*v2[0]}
+}
+
+// Len returns the vector's length.
+func (v1 Point3D) Len() float32 {
+	return float32(math.Sqrt(float64(v1.Dot(v1))))
+}
+
+// Normalize normalizes the vector. Normalization is (1/|v|)*v,
+// making this equivalent to v.Mul(1/v.Len()).
+// A zero vector is returned unchanged.
+func (v1 Point3D) Normalize() Point3D {
+	l := v1.Len()
+	if l == 0 {
+		return v1
+	}
+	return v1.Mul(1 / l)
+}
+
 // Matrix is a 4x4 matrix in row major order.
 //
 // m[4*r + c] is the element in the r'th row and c'th column.
@@ -122,6 +163,15 @@ func Identity() Matrix {
 	return Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
 }
 
+// OrIdentity returns the identity matrix if m1 is the zero matrix,
+// which is how missing item and component transforms are decoded, else m1.
+func (m1 Matrix) OrIdentity() Matrix {
+	if m1 == (Matrix{}) {
+		return Identity()
+	}
+	return m1
+}
+
 // Translate returns a matrix with a relative translation applied.
 func (m1 Matrix) Translate(x, y, z float32) Matrix {
 	m1[12] += x
@@ -172,6 +222,34 @@ func (m1 Matrix) Mul2D(v Point2D) Point2D {
 	}
 }
 
+// Inverse returns the inverse of an affine matrix,
+// and false if the matrix is not invertible.
+func (m1 Matrix) Inverse() (Matrix, bool) {
+	a, b, c := float64(m1[0]), float64(m1[4]), float64(m1[8])
+	d, e, f := float64(m1[1]), float64(m1[5]), float64(m1[9])
+	g, h, i := float64(m1[2]), float64(m1[6]), float64(m1[10])
+	A, B, C := e*i-f*h, f*g-d*i, d*h-e*g
+	det := a*A + b*B + c*C
+	if det == 0 {
+		return Matrix{}, false
+	}
+	inv := [9]float64{
+		A / det, (c*h - b*i) / det, (b*f - c*e) / det,
+		B / det, (a*i - c*g) / det, (c*d - a*f) / det,
+		C / det, (b*g - a*h) / det, (a*e - b*d) / det,
+	}
+	tx, ty, tz := float64(m1[12]), float64(m1[13]), float64(m1[14])
+	return Matrix{
+		float32(inv[0]), float32(inv[3]), float32(inv[6]), 0,
+		float32(inv[1]), float32(inv[4]), float32(inv[7]), 0,
+		float32(inv[2]), float32(inv[5]), float32(inv[8]), 0,
+		float32(-(inv[0]*tx + inv[1]*ty + inv[2]*tz)),
+		float32(-(inv[3]*tx + inv[4]*ty + inv[5]*tz)),
+		float32(-(inv[6]*tx + inv[7]*ty + inv[8]*tz)),
+		1,
+	}, true
+}
+
 // MulBox performs a "matrix product" between this matrix
 // and a box
 func (m1 Matrix) MulBox(b Box) Box {
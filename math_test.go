@@ -42,6 +42,68 @@ func TestPoint2D_Y(t *testing.T) {
 	}
 }
 
+func TestPoint3D_Add(t *testing.T) {
+	if got := (Point3D{1, 2, 3}).Add(Point3D{4, 5, 6}); got != (Point3D{5, 7, 9}) {
+		t.Errorf("Point3D.Add() = %v, want %v", got, Point3D{5, 7, 9})
+	}
+}
+
+func TestPoint3D_Sub(t *testing.T) {
+	if got := (Point3D{1, 2, 3}).Sub(Point3D{4, 6, 8}); got != (Point3D{-3, -4, -5}) {
+		t.Errorf("Point3D.Sub() = %v, want %v", got, Point3D{-3, -4, -5})
+	}
+}
+
+func TestPoint3D_Mul(t *testing.T) {
+	if got := (Point3D{1, 2, 3}).Mul(2); got != (Point3D{2, 4, 6}) {
+		t.Errorf("Point3D.Mul() = %v, want %v", got, Point3D{2, 4, 6})
+	}
+}
+
+func TestPoint3D_Dot(t *testing.T) {
+	if got := (Point3D{1, 2, 3}).Dot(Point3D{4, 5, 6}); got != 32 {
+		t.Errorf("Point3D.Dot() = %v, want %v", got, 32)
+	}
+}
+
+func TestPoint3D_Cross(t *testing.T) {
+	tests := []struct {
+		name   string
+		v1, v2 Point3D
+		want   Point3D
+	}{
+		{"xy", Point3D{1, 0, 0}, Point3D{0, 1, 0}, Point3D{0, 0, 1}},
+		{"yx", Point3D{0, 1, 0}, Point3D{1, 0, 0}, Point3D{0, 0, -1}},
+		{"parallel", Point3D{1, 2, 3}, Point3D{2, 4, 6}, Point3D{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v1.Cross(tt.v2); got != tt.want {
+				t.Errorf("Point3D.Cross() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint3D_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Point3D
+		want Point3D
+	}{
+		{"zero", Point3D{}, Point3D{}},
+		{"x", Point3D{3, 0, 0}, Point3D{1, 0, 0}},
+		{"xy", Point3D{3, 4, 0}, Point3D{0.6, 0.8, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.Normalize(); got != tt.want {
+				t.Errorf("Point3D.Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_newvec3IFromVec3(t *testing.T) {
 	type args struct {
 		vec Point3D
@@ -180,6 +242,25 @@ func TestMatrix_Translate(t *testing.T) {
 	}
 }
 
+func TestMatrix_OrIdentity(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Matrix
+		want Matrix
+	}{
+		{"zero", Matrix{}, Identity()},
+		{"identity", Identity(), Identity()},
+		{"other", Identity().Translate(1, 2, 3), Identity().Translate(1, 2, 3)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.OrIdentity(); got != tt.want {
+				t.Errorf("Matrix.OrIdentity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestMatrix_Mul3D(t *testing.T) {
 	type args struct {
 		v Point3D
@@ -228,6 +309,38 @@ func TestMatrix_Mul2D(t *testing.T) {
 	}
 }
 
+func TestMatrix_Inverse(t *testing.T) {
+	tests := []struct {
+		name string
+		m1   Matrix
+		ok   bool
+	}{
+		{"zero", Matrix{}, false},
+		{"identity", Identity(), true},
+		{"translate", Identity().Translate(1, 2, 3), true},
+		{"affine", Matrix{0, 2, 0, 0, -1, 0, 0, 0, 0, 0, 4, 0, 5, 6, 7, 1}, true},
+		{"singular", Matrix{1, 0, 0, 0, 2, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.m1.Inverse()
+			if ok != tt.ok {
+				t.Fatalf("Matrix.Inverse() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if m := tt.m1.Mul(got); !reflect.DeepEqual(m, Identity()) {
+				t.Errorf("Matrix.Inverse() = %v, M * M^-1 = %v", got, m)
+			}
+			v := Point3D{1, 2, 3}
+			if p := got.Mul3D(tt.m1.Mul3D(v)); !reflect.DeepEqual(p, v) {
+				t.Errorf("Matrix.Inverse() = %v, want %v", p, v)
+			}
+		})
+	}
+}
+
 func Test_pairMatch_AddMatch(t *testing.T) {
 	p := make(pairMatch)
 	type args struct {
@@ -0,0 +1,202 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"math"
+	"sync"
+)
+
+// massProperties accumulates the volume, the surface area
+// and their first moments of a set of triangles.
+type massProperties struct {
+	volume     float64
+	area       float64
+	moment     [3]float64 // volume weighted centroid.
+	areaMoment [3]float64 // area weighted centroid.
+	vertexSum  [3]float64
+	vertices   int
+}
+
+func (p *massProperties) addMesh(m *Mesh, transform Matrix) {
+	hasTransform := transform != Matrix{} && transform != Identity()
+	vs := m.Vertices.Vertex
+	n := uint32(len(vs))
+	for _, v := range vs {
+		if hasTransform {
+			v = transform.Mul3D(v)
+		}
+		p.vertexSum[0] += float64(v[0])
+		p.vertexSum[1] += float64(v[1])
+		p.vertexSum[2] += float64(v[2])
+	}
+	p.vertices += len(vs)
+	for _, t := range m.Triangles.Triangle {
+		if t.V1 >= n || t.V2 >= n || t.V3 >= n {
+			continue
+		}
+		v1, v2, v3 := vs[t.V1], vs[t.V2], vs[t.V3]
+		if hasTransform {
+			v1, v2, v3 = transform.Mul3D(v1), transform.Mul3D(v2), transform.Mul3D(v3)
+		}
+		p.addTriangle(v1, v2, v3)
+	}
+}
+
+func (p *massProperties) addTriangle(p1, p2, p3 Point3D) {
+	a := [3]float64{float64(p1[0]), float64(p1[1]), float64(p1[2])}
+	b := [3]float64{float64(p2[0]), float64(p2[1]), float64(p2[2])}
+	c := [3]float64{float64(p3[0]), float64(p3[1]), float64(p3[2])}
+	// Signed volume of the tetrahedron formed with the origin.
+	cross := [3]float64{
+		b[1]*c[2] - b[2]*c[1],
+		b[2]*c[0] - b[0]*c[2],
+		b[0]*c[1] - b[1]*c[0],
+	}
+	vol := (a[0]*cross[0] + a[1]*cross[1] + a[2]*cross[2]) / 6
+	ab := [3]float64{b[0] - a[0], b[1] - a[1], b[2] - a[2]}
+	ac := [3]float64{c[0] - a[0], c[1] - a[1], c[2] - a[2]}
+	n := [3]float64{
+		ab[1]*ac[2] - ab[2]*ac[1],
+		ab[2]*ac[0] - ab[0]*ac[2],
+		ab[0]*ac[1] - ab[1]*ac[0],
+	}
+	area := math.Sqrt(n[0]*n[0]+n[1]*n[1]+n[2]*n[2]) / 2
+	p.volume += vol
+	p.area += area
+	for i := 0; i < 3; i++ {
+		sum := a[i] + b[i] + c[i]
+		p.moment[i] += vol * sum / 4
+		p.areaMoment[i] += area * sum / 3
+	}
+}
+
+func (p *massProperties) add(o massProperties) {
+	p.volume += o.volume
+	p.area += o.area
+	for i := 0; i < 3; i++ {
+		p.moment[i] += o.moment[i]
+		p.areaMoment[i] += o.areaMoment[i]
+		p.vertexSum[i] += o.vertexSum[i]
+	}
+	p.vertices += o.vertices
+}
+
+// centroid returns the center of mass of the enclosed volume.
+// If there is no volume it fallbacks to the center of mass of the surface
+// and then to the mean of the vertices.
+func (p *massProperties) centroid() Point3D {
+	var (
+		moment [3]float64
+		weight float64
+	)
+	switch {
+	case p.volume != 0:
+		moment, weight = p.moment, p.volume
+	case p.area != 0:
+		moment, weight = p.areaMoment, p.area
+	case p.vertices != 0:
+		moment, weight = p.vertexSum, float64(p.vertices)
+	default:
+		return Point3D{}
+	}
+	return Point3D{float32(moment[0] / weight), float32(moment[1] / weight), float32(moment[2] / weight)}
+}
+
+// Volume returns the volume enclosed by the mesh.
+// The result is only meaningful for closed meshes, and it is
+// negative if the triangles are oriented inwards.
+func (m *Mesh) Volume() float64 {
+	var p massProperties
+	p.addMesh(m, Matrix{})
+	return p.volume
+}
+
+// SurfaceArea returns the sum of the area of the triangles.
+func (m *Mesh) SurfaceArea() float64 {
+	var p massProperties
+	p.addMesh(m, Matrix{})
+	return p.area
+}
+
+// Centroid returns the center of mass of the volume enclosed by the mesh,
+// assuming a uniform density.
+// If the mesh does not enclose any volume the center of mass of the surface is returned.
+func (m *Mesh) Centroid() Point3D {
+	var p massProperties
+	p.addMesh(m, Matrix{})
+	return p.centroid()
+}
+
+// Volume returns the volume of the object, following the components transforms.
+// path is the model where the object is defined, empty for the root model.
+func (o *Object) Volume(m *Model, path string) float64 {
+	p := o.massProperties(m, path)
+	return p.volume
+}
+
+// SurfaceArea returns the surface area of the object, following the components transforms.
+// path is the model where the object is defined, empty for the root model.
+func (o *Object) SurfaceArea(m *Model, path string) float64 {
+	p := o.massProperties(m, path)
+	return p.area
+}
+
+// Centroid returns the center of mass of the object, following the components transforms.
+// path is the model where the object is defined, empty for the root model.
+func (o *Object) Centroid(m *Model, path string) Point3D {
+	p := o.massProperties(m, path)
+	return p.centroid()
+}
+
+func (o *Object) massProperties(m *Model, path string) massProperties {
+	var p massProperties
+	m.WalkMeshes(path, o, Identity(), func(_ string, o *Object, t Matrix) error {
+		p.addMesh(o.Mesh, t)
+		return nil
+	})
+	return p
+}
+
+// Volume returns the volume of the build items.
+func (m *Model) Volume() float64 {
+	p := m.massProperties()
+	return p.volume
+}
+
+// SurfaceArea returns the surface area of the build items.
+func (m *Model) SurfaceArea() float64 {
+	p := m.massProperties()
+	return p.area
+}
+
+// Centroid returns the center of mass of the build items.
+func (m *Model) Centroid() Point3D {
+	p := m.massProperties()
+	return p.centroid()
+}
+
+func (m *Model) massProperties() massProperties {
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+		p  massProperties
+	)
+	wg.Add(len(m.Build.Items))
+	for i := range m.Build.Items {
+		go func(i int) {
+			defer wg.Done()
+			var ip massProperties
+			m.WalkItemMeshes(m.Build.Items[i], func(_ string, o *Object, t Matrix) error {
+				ip.addMesh(o.Mesh, t)
+				return nil
+			})
+			mu.Lock()
+			p.add(ip)
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	return p
+}
@@ -0,0 +1,199 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hpinc/go3mf/spec"
+)
+
+// newUnitCube returns a closed cube with outward facing triangles
+// and its minimum corner at the origin.
+func newUnitCube(size float32) *Mesh {
+	return &Mesh{
+		Vertices: Vertices{Vertex: []Point3D{
+			{0, 0, 0}, {size, 0, 0}, {size, size, 0}, {0, size, 0},
+			{0, 0, size}, {size, 0, size}, {size, size, size}, {0, size, size},
+		}},
+		Triangles: Triangles{Triangle: []Triangle{
+			{V1: 3, V2: 2, V3: 1}, {V1: 1, V2: 0, V3: 3}, {V1: 4, V2: 5, V3: 6}, {V1: 6, V2: 7, V3: 4},
+			{V1: 0, V2: 1, V3: 5}, {V1: 5, V2: 4, V3: 0}, {V1: 1, V2: 2, V3: 6}, {V1: 6, V2: 5, V3: 1},
+			{V1: 2, V2: 3, V3: 7}, {V1: 7, V2: 6, V3: 2}, {V1: 3, V2: 0, V3: 4}, {V1: 4, V2: 7, V3: 3},
+		}},
+	}
+}
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-4
+}
+
+func pointAlmostEqual(a, b Point3D) bool {
+	return almostEqual(float64(a[0]), float64(b[0])) &&
+		almostEqual(float64(a[1]), float64(b[1])) &&
+		almostEqual(float64(a[2]), float64(b[2]))
+}
+
+func TestMesh_Volume(t *testing.T) {
+	inverted := newUnitCube(2)
+	for i := range inverted.Triangles.Triangle {
+		tr := &inverted.Triangles.Triangle[i]
+		tr.V2, tr.V3 = tr.V3, tr.V2
+	}
+	tests := []struct {
+		name string
+		m    *Mesh
+		want float64
+	}{
+		{"empty", new(Mesh), 0},
+		{"cube", newUnitCube(2), 8},
+		{"inverted", inverted, -8},
+		{"outOfBounds", &Mesh{Triangles: Triangles{Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Volume(); !almostEqual(got, tt.want) {
+				t.Errorf("Mesh.Volume() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMesh_SurfaceArea(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Mesh
+		want float64
+	}{
+		{"empty", new(Mesh), 0},
+		{"cube", newUnitCube(2), 24},
+		{"triangle", &Mesh{
+			Vertices:  Vertices{Vertex: []Point3D{{0, 0, 0}, {3, 0, 0}, {0, 4, 0}}},
+			Triangles: Triangles{Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}},
+		}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.SurfaceArea(); !almostEqual(got, tt.want) {
+				t.Errorf("Mesh.SurfaceArea() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMesh_Centroid(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Mesh
+		want Point3D
+	}{
+		{"empty", new(Mesh), Point3D{}},
+		{"cube", newUnitCube(2), Point3D{1, 1, 1}},
+		{"surface", &Mesh{
+			Vertices:  Vertices{Vertex: []Point3D{{0, 0, 0}, {3, 0, 0}, {0, 3, 0}}},
+			Triangles: Triangles{Triangle: []Triangle{{V1: 0, V2: 1, V3: 2}}},
+		}, Point3D{1, 1, 0}},
+		{"points", &Mesh{Vertices: Vertices{Vertex: []Point3D{{0, 0, 0}, {2, 4, 6}}}}, Point3D{1, 2, 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Centroid(); !pointAlmostEqual(got, tt.want) {
+				t.Errorf("Mesh.Centroid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObject_MassProperties(t *testing.T) {
+	m := &Model{
+		Resources: Resources{Objects: []*Object{
+			{ID: 1, Mesh: newUnitCube(1)},
+			{ID: 2, Components: &Components{Component: []*Component{
+				{ObjectID: 1},
+				{ObjectID: 1, Transform: Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 10, 0, 0, 1}},
+				{ObjectID: 10},
+			}}},
+			{ID: 3, Components: &Components{Component: []*Component{
+				{ObjectID: 1, AnyAttr: spec.AnyAttr{&fakeAttr{Value: "/other.model"}}},
+			}}},
+			{ID: 4},
+			{ID: 6, Components: &Components{Component: []*Component{{ObjectID: 1}, {ObjectID: 6}}}},
+		}},
+		Childs: map[string]*ChildModel{
+			"/other.model": {Resources: Resources{Objects: []*Object{
+				{ID: 1, Components: &Components{Component: []*Component{
+					{ObjectID: 5, Transform: Identity().Translate(0, 0, 5)},
+				}}},
+				{ID: 5, Mesh: newUnitCube(3)},
+			}}},
+		},
+	}
+	tests := []struct {
+		name         string
+		path         string
+		id           uint32
+		wantVolume   float64
+		wantArea     float64
+		wantCentroid Point3D
+	}{
+		{"mesh", "", 1, 1, 6, Point3D{0.5, 0.5, 0.5}},
+		{"components", "", 2, 9, 30, Point3D{(0.5 + 8*11) / 9, (0.5 + 8*1) / 9, (0.5 + 8*1) / 9}},
+		{"childModel", "", 3, 27, 54, Point3D{1.5, 1.5, 6.5}},
+		{"empty", "", 4, 0, 0, Point3D{}},
+		{"recursive", "", 6, MaxComponentDepth, 6 * MaxComponentDepth, Point3D{0.5, 0.5, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o, _ := m.FindObject(tt.path, tt.id)
+			if got := o.Volume(m, tt.path); !almostEqual(got, tt.wantVolume) {
+				t.Errorf("Object.Volume() = %v, want %v", got, tt.wantVolume)
+			}
+			if got := o.SurfaceArea(m, tt.path); !almostEqual(got, tt.wantArea) {
+				t.Errorf("Object.SurfaceArea() = %v, want %v", got, tt.wantArea)
+			}
+			if got := o.Centroid(m, tt.path); !pointAlmostEqual(got, tt.wantCentroid) {
+				t.Errorf("Object.Centroid() = %v, want %v", got, tt.wantCentroid)
+			}
+		})
+	}
+}
+
+func TestModel_MassProperties(t *testing.T) {
+	tests := []struct {
+		name         string
+		m            *Model
+		wantVolume   float64
+		wantArea     float64
+		wantCentroid Point3D
+	}{
+		{"empty", new(Model), 0, 0, Point3D{}},
+		{"base", &Model{
+			Build: Build{Items: []*Item{
+				{ObjectID: 1},
+				{ObjectID: 2, Transform: Identity().Translate(0, 10, 0)},
+				{ObjectID: 10},
+			}},
+			Resources: Resources{Objects: []*Object{
+				{ID: 1, Mesh: newUnitCube(2)},
+				{ID: 2, Components: &Components{Component: []*Component{
+					{ObjectID: 1, Transform: Matrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 3, 0, 0, 0, 0, 1}},
+				}}},
+			}},
+		}, 8 + 24, 24 + 56, Point3D{1, (8*1 + 24*11) / 32.0, (8*1 + 24*3) / 32.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Volume(); !almostEqual(got, tt.wantVolume) {
+				t.Errorf("Model.Volume() = %v, want %v", got, tt.wantVolume)
+			}
+			if got := tt.m.SurfaceArea(); !almostEqual(got, tt.wantArea) {
+				t.Errorf("Model.SurfaceArea() = %v, want %v", got, tt.wantArea)
+			}
+			if got := tt.m.Centroid(); !pointAlmostEqual(got, tt.wantCentroid) {
+				t.Errorf("Model.Centroid() = %v, want %v", got, tt.wantCentroid)
+			}
+		})
+	}
+}
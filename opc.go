@@ -4,6 +4,7 @@
 package go3mf
 
 import (
+	"crypto/sha256"
 	"io"
 
 	"github.com/qmuntal/opc"
@@ -28,11 +29,13 @@ func (o *opcPart) AddRelationship(r Relationship) {
 }
 
 type opcWriter struct {
-	w *opc.Writer
+	w      *opc.Writer
+	signer *Signer
+	signed []signedPart
 }
 
 func newOpcWriter(w io.Writer) *opcWriter {
-	return &opcWriter{opc.NewWriter(w)}
+	return &opcWriter{w: opc.NewWriter(w)}
 }
 
 func (o *opcWriter) Create(name, contentType string) (packagePart, error) {
@@ -41,6 +44,11 @@ func (o *opcWriter) Create(name, contentType string) (packagePart, error) {
 	if err != nil {
 		return nil, err
 	}
+	if o.signer != nil {
+		h := sha256.New()
+		o.signed = append(o.signed, signedPart{part: p, digest: h})
+		w = io.MultiWriter(w, h)
+	}
 	return &opcPart{Writer: w, Part: p}, nil
 }
 
@@ -58,6 +66,11 @@ func (o *opcWriter) AddRelationship(r Relationship) {
 }
 
 func (o *opcWriter) Close() error {
+	if o.signer != nil {
+		if err := o.sign(); err != nil {
+			return err
+		}
+	}
 	return o.w.Close()
 }
 
@@ -0,0 +1,277 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package packing arranges the build items of a model inside a printer build volume,
+// computing new item transforms so that items do not overlap.
+//
+// Items are packed by their bounding boxes with an extreme point heuristic:
+// the largest items are placed first, each one at the lowest free corner
+// left by the items already placed.
+package packing
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/bvh"
+	"github.com/hpinc/go3mf/production"
+	"github.com/hpinc/go3mf/uuid"
+)
+
+// Packer errors.
+var (
+	ErrPackingVolume  = errors.New("packing: build volume is empty")
+	ErrPackingSpacing = errors.New("packing: spacing must not be negative")
+	ErrPackingCopies  = errors.New("packing: copies must not be negative")
+	ErrPackingFull    = errors.New("packing: items do not fit in the build volume")
+)
+
+// Mode defines how items are arranged in the build volume.
+type Mode uint8
+
+// Supported modes.
+const (
+	// ModePlate places all the items on the build plate, as required by FDM printers.
+	ModePlate Mode = iota
+	// ModeVolume stacks items on top of each other, as allowed by powder bed printers.
+	ModeVolume
+)
+
+func (m Mode) String() string {
+	return map[Mode]string{
+		ModePlate:  "plate",
+		ModeVolume: "volume",
+	}[m]
+}
+
+// A Packer computes the transforms of the build items.
+//
+// Items keep their current orientation unless Rotations is set,
+// in which case each item is rotated by one of them.
+// Items that reference missing objects or empty meshes are not moved.
+type Packer struct {
+	// Volume is the build volume, in model units.
+	// The build plate is the bottom face.
+	Volume go3mf.Box
+	Mode   Mode
+	// Spacing is the minimum distance between the boxes of two items.
+	Spacing float32
+	// Rotations are the allowed rotations, applied on top of the current item transforms.
+	// ZRotations and AxisRotations return the usual ones.
+	Rotations []go3mf.Matrix
+	// Copies is the number of extra copies of the item with the same index
+	// in go3mf.Build.Items. Copies are appended to the build items.
+	Copies []int
+}
+
+// job is an item or a copy of an item waiting to be placed.
+type job struct {
+	item int
+	// boxes is the bounding box of the item for each rotation.
+	boxes []go3mf.Box
+	// rotation and at are the placement.
+	rotation int
+	at       go3mf.Point3D
+}
+
+// Pack updates the transforms of the build items of m and appends the copies.
+// If the items do not fit, m is not modified and ErrPackingFull is returned.
+func (p *Packer) Pack(m *go3mf.Model) error {
+	size := p.Volume.Max.Sub(p.Volume.Min)
+	if size[0] <= 0 || size[1] <= 0 || size[2] <= 0 {
+		return ErrPackingVolume
+	}
+	if p.Spacing < 0 {
+		return ErrPackingSpacing
+	}
+	for _, c := range p.Copies {
+		if c < 0 {
+			return ErrPackingCopies
+		}
+	}
+	rotations := p.Rotations
+	if len(rotations) == 0 {
+		rotations = []go3mf.Matrix{go3mf.Identity()}
+	}
+	jobs, err := p.jobs(m, rotations)
+	if err != nil {
+		return err
+	}
+	if !p.place(jobs) {
+		return ErrPackingFull
+	}
+	transforms := make([]go3mf.Matrix, len(jobs))
+	for i, j := range jobs {
+		box := j.boxes[j.rotation]
+		t := rotations[j.rotation].Mul(m.Build.Items[j.item].Transform.OrIdentity())
+		transforms[i] = t.Translate(j.at[0]-box.Min[0], j.at[1]-box.Min[1], j.at[2]-box.Min[2])
+	}
+	// The first job of each item moves the item, the rest are copies.
+	moved := make(map[int]bool)
+	for i, j := range jobs {
+		item := m.Build.Items[j.item]
+		if !moved[j.item] {
+			moved[j.item] = true
+			item.Transform = transforms[i]
+			continue
+		}
+		m.Build.Items = append(m.Build.Items, copyItem(item, transforms[i]))
+	}
+	return nil
+}
+
+// jobs returns the items and copies to place, sorted by the order in which they are placed.
+func (p *Packer) jobs(m *go3mf.Model, rotations []go3mf.Matrix) ([]job, error) {
+	s, err := bvh.NewScene(m)
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[*go3mf.Item]int, len(m.Build.Items))
+	for i, item := range m.Build.Items {
+		index[item] = i
+	}
+	points := make([][]go3mf.Point3D, len(m.Build.Items))
+	for _, in := range s.Instances() {
+		i := index[in.Item]
+		for _, v := range in.Tree.Mesh().Vertices.Vertex {
+			points[i] = append(points[i], in.Transform.Mul3D(v))
+		}
+	}
+	var jobs []job
+	for i, pts := range points {
+		if len(pts) == 0 {
+			continue
+		}
+		j := job{item: i, boxes: make([]go3mf.Box, len(rotations))}
+		for k, r := range rotations {
+			j.boxes[k] = bounds(r, pts)
+		}
+		n := 1
+		if i < len(p.Copies) {
+			n += p.Copies[i]
+		}
+		for k := 0; k < n; k++ {
+			jobs = append(jobs, j)
+		}
+	}
+	sort.SliceStable(jobs, func(i, j int) bool {
+		a, b := jobs[i].boxes[0], jobs[j].boxes[0]
+		if p.Mode == ModePlate {
+			return area(a) > area(b)
+		}
+		return volume(a) > volume(b)
+	})
+	return jobs, nil
+}
+
+// place assigns a position to all the jobs, and returns false if any of them does not fit.
+func (p *Packer) place(jobs []job) bool {
+	size := p.Volume.Max.Sub(p.Volume.Min)
+	eps := 1e-5 * float32(math.Max(float64(size[0]), math.Max(float64(size[1]), float64(size[2]))))
+	corners := []go3mf.Point3D{p.Volume.Min}
+	var placed []go3mf.Box
+	for i := range jobs {
+		j := &jobs[i]
+		found := false
+		for _, c := range corners {
+			if found && !below(c, j.at) {
+				continue
+			}
+			for k, box := range j.boxes {
+				d := box.Max.Sub(box.Min)
+				if !fits(c.Add(d), p.Volume.Max, eps) {
+					continue
+				}
+				b := go3mf.Box{Min: c, Max: c.Add(d).Add(go3mf.Point3D{p.Spacing, p.Spacing, p.Spacing})}
+				if collides(b, placed, eps) {
+					continue
+				}
+				j.at, j.rotation, found = c, k, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+		d := j.boxes[j.rotation].Max.Sub(j.boxes[j.rotation].Min).Add(go3mf.Point3D{p.Spacing, p.Spacing, p.Spacing})
+		placed = append(placed, go3mf.Box{Min: j.at, Max: j.at.Add(d)})
+		corners = append(corners,
+			go3mf.Point3D{j.at[0] + d[0], j.at[1], j.at[2]},
+			go3mf.Point3D{j.at[0], j.at[1] + d[1], j.at[2]},
+		)
+		if p.Mode == ModeVolume {
+			corners = append(corners, go3mf.Point3D{j.at[0], j.at[1], j.at[2] + d[2]})
+		}
+	}
+	return true
+}
+
+// below returns true if a is lower than b, comparing z, then y and then x.
+func below(a, b go3mf.Point3D) bool {
+	for i := 2; i >= 0; i-- {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func fits(p, max go3mf.Point3D, eps float32) bool {
+	return p[0] <= max[0]+eps && p[1] <= max[1]+eps && p[2] <= max[2]+eps
+}
+
+// collides returns true if b shares any interior point with the placed boxes.
+func collides(b go3mf.Box, placed []go3mf.Box, eps float32) bool {
+	for _, o := range placed {
+		if b.Min[0] < o.Max[0]-eps && o.Min[0] < b.Max[0]-eps &&
+			b.Min[1] < o.Max[1]-eps && o.Min[1] < b.Max[1]-eps &&
+			b.Min[2] < o.Max[2]-eps && o.Min[2] < b.Max[2]-eps {
+			return true
+		}
+	}
+	return false
+}
+
+func bounds(r go3mf.Matrix, points []go3mf.Point3D) go3mf.Box {
+	box := go3mf.Box{Min: r.Mul3D(points[0]), Max: r.Mul3D(points[0])}
+	for _, v := range points[1:] {
+		v = r.Mul3D(v)
+		for k := 0; k < 3; k++ {
+			box.Min[k] = float32(math.Min(float64(box.Min[k]), float64(v[k])))
+			box.Max[k] = float32(math.Max(float64(box.Max[k]), float64(v[k])))
+		}
+	}
+	return box
+}
+
+func area(b go3mf.Box) float32 {
+	d := b.Max.Sub(b.Min)
+	return d[0] * d[1]
+}
+
+func volume(b go3mf.Box) float32 {
+	d := b.Max.Sub(b.Min)
+	return d[0] * d[1] * d[2]
+}
+
+// copyItem returns a new item of the same object as item.
+// The production UUID, if any, is not shared with the original item.
+func copyItem(item *go3mf.Item, t go3mf.Matrix) *go3mf.Item {
+	c := &go3mf.Item{
+		ObjectID:   item.ObjectID,
+		Transform:  t,
+		PartNumber: item.PartNumber,
+		Metadata:   item.Metadata,
+	}
+	c.Metadata.Metadata = append([]go3mf.Metadata(nil), item.Metadata.Metadata...)
+	if ext := production.GetItemAttr(item); ext != nil {
+		a := &production.ItemAttr{Path: ext.Path}
+		if ext.UUID != "" {
+			a.UUID = uuid.New()
+		}
+		c.AnyAttr = append(c.AnyAttr, a)
+	}
+	return c
+}
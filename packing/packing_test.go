@@ -0,0 +1,202 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package packing
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/collision"
+	"github.com/hpinc/go3mf/internal/meshtest"
+	"github.com/hpinc/go3mf/production"
+)
+
+// createModel returns a model with a 10mm cube, a 30x5x5mm bar and a
+// 20x20x2mm plate, all of them overlapping at the origin.
+func createModel() *go3mf.Model {
+	m := new(go3mf.Model)
+	m.Resources.Objects = append(m.Resources.Objects,
+		&go3mf.Object{ID: 1, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 10})},
+		&go3mf.Object{ID: 2, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{30, 5, 5})},
+		&go3mf.Object{ID: 3, Mesh: meshtest.Box(go3mf.Point3D{-10, -10, -1}, go3mf.Point3D{10, 10, 1})},
+	)
+	m.Build.Items = append(m.Build.Items,
+		&go3mf.Item{ObjectID: 1, PartNumber: "cube"},
+		&go3mf.Item{ObjectID: 2, Transform: go3mf.Identity().Translate(3, 4, 5)},
+		&go3mf.Item{ObjectID: 3},
+	)
+	return m
+}
+
+// checkPacked verifies that the items do not collide and fit in the volume.
+// Items packed without spacing touch each other, which counts as a collision.
+func checkPacked(t *testing.T, m *go3mf.Model, volume go3mf.Box) *collision.Result {
+	t.Helper()
+	r, err := (&collision.Checker{Volume: &volume}).Check(m)
+	if err != nil {
+		t.Fatalf("Checker.Check() error = %v", err)
+	}
+	if r.Colliding() {
+		t.Errorf("Packer.Pack() collisions = %v", r.Collisions)
+	}
+	for i, ok := range r.Fits {
+		if !ok {
+			t.Errorf("Packer.Pack() item %d does not fit: %v", i, r.Bounds[i])
+		}
+	}
+	return r
+}
+
+func TestPacker_Pack(t *testing.T) {
+	volume := go3mf.Box{Min: go3mf.Point3D{-50, -50, 0}, Max: go3mf.Point3D{50, 50, 100}}
+	tests := []struct {
+		name   string
+		p      Packer
+		nitems int
+	}{
+		{"plate", Packer{Volume: volume, Spacing: 0.5}, 3},
+		{"spacing", Packer{Volume: volume, Spacing: 2}, 3},
+		{"copies", Packer{Volume: volume, Spacing: 1, Copies: []int{5, 2}}, 10},
+		{"rotations", Packer{Volume: volume, Spacing: 1, Copies: []int{3, 3, 3}, Rotations: ZRotations(4)}, 12},
+		{"volume", Packer{Volume: volume, Mode: ModeVolume, Spacing: 1, Copies: []int{20, 20, 20}, Rotations: AxisRotations()}, 63},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := createModel()
+			if err := tt.p.Pack(m); err != nil {
+				t.Fatalf("Packer.Pack() error = %v", err)
+			}
+			if len(m.Build.Items) != tt.nitems {
+				t.Fatalf("Packer.Pack() items = %d, want %d", len(m.Build.Items), tt.nitems)
+			}
+			r := checkPacked(t, m, volume)
+			for i, a := range r.Bounds {
+				if tt.p.Mode == ModePlate && a.Min[2] != volume.Min[2] {
+					t.Errorf("Packer.Pack() item %d is not on the plate: %v", i, a)
+				}
+				for j, b := range r.Bounds[i+1:] {
+					if d := distance(a, b); d < tt.p.Spacing-1e-4 {
+						t.Errorf("Packer.Pack() items %d and %d are %v apart", i, i+1+j, d)
+					}
+				}
+			}
+		})
+	}
+}
+
+// distance returns the maximum separation between a and b along any axis.
+func distance(a, b go3mf.Box) float32 {
+	var d float32
+	for i := 0; i < 3; i++ {
+		if s := b.Min[i] - a.Max[i]; s > d {
+			d = s
+		}
+		if s := a.Min[i] - b.Max[i]; s > d {
+			d = s
+		}
+	}
+	return d
+}
+
+func TestPacker_Pack_rotation(t *testing.T) {
+	// The bar only fits turned by 90 degrees.
+	volume := go3mf.Box{Max: go3mf.Point3D{10, 40, 10}}
+	m := createModel()
+	m.Build.Items = m.Build.Items[1:2]
+	if err := (&Packer{Volume: volume}).Pack(m); err != ErrPackingFull {
+		t.Errorf("Packer.Pack() error = %v, want %v", err, ErrPackingFull)
+	}
+	if want := go3mf.Identity().Translate(3, 4, 5); m.Build.Items[0].Transform != want {
+		t.Errorf("Packer.Pack() modified the transform = %v", m.Build.Items[0].Transform)
+	}
+	if err := (&Packer{Volume: volume, Rotations: ZRotations(4)}).Pack(m); err != nil {
+		t.Fatalf("Packer.Pack() error = %v", err)
+	}
+	r := checkPacked(t, m, volume)
+	if want := (go3mf.Box{Max: go3mf.Point3D{5, 30, 5}}); r.Bounds[0] != want {
+		t.Errorf("Packer.Pack() bounds = %v, want %v", r.Bounds[0], want)
+	}
+}
+
+func TestPacker_Pack_copies(t *testing.T) {
+	m := createModel()
+	m.Build.Items[0].AnyAttr = append(m.Build.Items[0].AnyAttr, &production.ItemAttr{UUID: "a2a0f3b4-a6f2-4b9d-b4c4-8dc8a2c4dc9e", Path: "/3D/other.model"})
+	m.Resources.Objects[0].Mesh = nil
+	m.Build.Items = append(m.Build.Items, &go3mf.Item{ObjectID: 100, Transform: go3mf.Identity().Translate(1, 2, 3)})
+	volume := go3mf.Box{Max: go3mf.Point3D{100, 100, 100}}
+	if err := (&Packer{Volume: volume, Copies: []int{1, 0, 0, 1}}).Pack(m); err != nil {
+		t.Fatalf("Packer.Pack() error = %v", err)
+	}
+	// Items without meshes are neither moved nor copied.
+	if len(m.Build.Items) != 4 {
+		t.Fatalf("Packer.Pack() items = %d, want 4", len(m.Build.Items))
+	}
+	if want := go3mf.Identity().Translate(1, 2, 3); m.Build.Items[3].Transform != want {
+		t.Errorf("Packer.Pack() moved an item without mesh = %v", m.Build.Items[3].Transform)
+	}
+
+	m = createModel()
+	m.Build.Items[0].AnyAttr = append(m.Build.Items[0].AnyAttr, &production.ItemAttr{UUID: "a2a0f3b4-a6f2-4b9d-b4c4-8dc8a2c4dc9e"})
+	m.Build.Items[0].Metadata.Metadata = append(m.Build.Items[0].Metadata.Metadata, go3mf.Metadata{Name: xml.Name{Local: "Title"}, Value: "cube"})
+	if err := (&Packer{Volume: volume, Spacing: 1, Copies: []int{1}}).Pack(m); err != nil {
+		t.Fatalf("Packer.Pack() error = %v", err)
+	}
+	c := m.Build.Items[3]
+	if c.ObjectID != 1 || c.PartNumber != "cube" || !reflect.DeepEqual(c.Metadata, m.Build.Items[0].Metadata) {
+		t.Errorf("Packer.Pack() copy = %v", c)
+	}
+	ext := production.GetItemAttr(c)
+	if ext == nil || ext.UUID == "" || ext.UUID == production.GetItemAttr(m.Build.Items[0]).UUID {
+		t.Errorf("Packer.Pack() copy production attribute = %v", ext)
+	}
+	checkPacked(t, m, volume)
+}
+
+func TestPacker_Pack_error(t *testing.T) {
+	volume := go3mf.Box{Max: go3mf.Point3D{100, 100, 100}}
+	tests := []struct {
+		name string
+		p    Packer
+		want error
+	}{
+		{"volume", Packer{Volume: go3mf.Box{Max: go3mf.Point3D{100, 0, 100}}}, ErrPackingVolume},
+		{"spacing", Packer{Volume: volume, Spacing: -1}, ErrPackingSpacing},
+		{"copies", Packer{Volume: volume, Copies: []int{-1}}, ErrPackingCopies},
+		{"full", Packer{Volume: volume, Copies: []int{200}}, ErrPackingFull},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := createModel()
+			if err := tt.p.Pack(m); err != tt.want {
+				t.Errorf("Packer.Pack() error = %v, want %v", err, tt.want)
+			}
+			if len(m.Build.Items) != 3 || m.Build.Items[0].Transform != (go3mf.Matrix{}) {
+				t.Error("Packer.Pack() modified the model")
+			}
+		})
+	}
+}
+
+func TestPacker_Pack_tight(t *testing.T) {
+	// Four cubes fill the plate when there is no spacing.
+	volume := go3mf.Box{Max: go3mf.Point3D{20, 20, 10}}
+	m := createModel()
+	m.Build.Items = m.Build.Items[:1]
+	if err := (&Packer{Volume: volume, Spacing: 1, Copies: []int{3}}).Pack(m); err != ErrPackingFull {
+		t.Errorf("Packer.Pack() error = %v, want %v", err, ErrPackingFull)
+	}
+	if err := (&Packer{Volume: volume, Copies: []int{3}}).Pack(m); err != nil {
+		t.Fatalf("Packer.Pack() error = %v", err)
+	}
+	var got []go3mf.Point3D
+	for _, item := range m.Build.Items {
+		got = append(got, item.Transform.Mul3D(go3mf.Point3D{}))
+	}
+	want := []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {10, 10, 0}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Packer.Pack() positions = %v, want %v", got, want)
+	}
+}
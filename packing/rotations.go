@@ -0,0 +1,66 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package packing
+
+import (
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+// ZRotations returns n rotations around the z axis evenly spaced,
+// the first one being the identity.
+// ZRotations(4) allows turning items by 90 degrees on the build plate.
+func ZRotations(n int) []go3mf.Matrix {
+	rs := make([]go3mf.Matrix, n)
+	for i := range rs {
+		switch a := 2 * math.Pi * float64(i) / float64(n); {
+		case i == 0:
+			rs[i] = go3mf.Identity()
+		case 4*i%n == 0:
+			// Avoid rounding errors in the right angles.
+			c, s := rightAngle(4 * i / n)
+			rs[i] = rotationZ(c, s)
+		default:
+			rs[i] = rotationZ(float32(math.Cos(a)), float32(math.Sin(a)))
+		}
+	}
+	return rs
+}
+
+// AxisRotations returns the 24 rotations that map the axes onto the axes,
+// the first one being the identity.
+// They allow any face of the item box to lie on the build plate.
+func AxisRotations() []go3mf.Matrix {
+	rs := make([]go3mf.Matrix, 0, 24)
+	perms := [6][3]int{{0, 1, 2}, {1, 2, 0}, {2, 0, 1}, {0, 2, 1}, {2, 1, 0}, {1, 0, 2}}
+	for i, perm := range perms {
+		for signs := 0; signs < 8; signs++ {
+			// Odd permutations need an odd number of negative axes.
+			neg := signs&1 + signs>>1&1 + signs>>2&1
+			if (i >= 3) != (neg%2 == 1) {
+				continue
+			}
+			m := go3mf.Matrix{15: 1}
+			for col := 0; col < 3; col++ {
+				v := float32(1)
+				if signs>>col&1 == 1 {
+					v = -1
+				}
+				m[4*col+perm[col]] = v
+			}
+			rs = append(rs, m)
+		}
+	}
+	return rs
+}
+
+func rotationZ(c, s float32) go3mf.Matrix {
+	return go3mf.Matrix{c, s, 0, 0, -s, c, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+}
+
+// rightAngle returns the cosine and sine of q quarter turns.
+func rightAngle(q int) (float32, float32) {
+	return [4]float32{1, 0, -1, 0}[q%4], [4]float32{0, 1, 0, -1}[q%4]
+}
@@ -0,0 +1,47 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package packing
+
+import (
+	"math"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func TestZRotations(t *testing.T) {
+	rs := ZRotations(4)
+	want := []go3mf.Point3D{{1, 0, 0}, {0, 1, 0}, {-1, 0, 0}, {0, -1, 0}}
+	for i, r := range rs {
+		if got := r.Mul3D(go3mf.Point3D{1, 0, 0}); got != want[i] {
+			t.Errorf("ZRotations()[%d] = %v, want %v", i, got, want[i])
+		}
+	}
+	r := ZRotations(8)[1].Mul3D(go3mf.Point3D{1, 0, 0})
+	if math.Abs(float64(r[0]-r[1])) > 1e-6 || math.Abs(float64(r.Len()-1)) > 1e-6 {
+		t.Errorf("ZRotations(8)[1] = %v", r)
+	}
+}
+
+func TestAxisRotations(t *testing.T) {
+	rs := AxisRotations()
+	if len(rs) != 24 {
+		t.Fatalf("AxisRotations() = %d rotations, want 24", len(rs))
+	}
+	if rs[0] != go3mf.Identity() {
+		t.Errorf("AxisRotations()[0] = %v, want the identity", rs[0])
+	}
+	seen := make(map[go3mf.Matrix]bool)
+	for _, r := range rs {
+		if seen[r] {
+			t.Errorf("AxisRotations() duplicated %v", r)
+		}
+		seen[r] = true
+		// Rotations keep the handedness.
+		x, y, z := r.Mul3D(go3mf.Point3D{1, 0, 0}), r.Mul3D(go3mf.Point3D{0, 1, 0}), r.Mul3D(go3mf.Point3D{0, 0, 1})
+		if x.Cross(y) != z {
+			t.Errorf("AxisRotations() %v is not a rotation", r)
+		}
+	}
+}
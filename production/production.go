@@ -7,6 +7,7 @@ import (
 	"errors"
 
 	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
 	"github.com/hpinc/go3mf/spec"
 	"github.com/hpinc/go3mf/uuid"
 )
@@ -34,6 +35,10 @@ type Spec struct{}
 
 func init() {
 	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrUUID:             {ID: "PROD001", Name: "UUID", Severity: reporting.SeverityError},
+		ErrProdRefInNonRoot: {ID: "PROD002", Name: "ProdRefInNonRoot", Severity: reporting.SeverityError},
+	})
 }
 
 // BuildAttr provides a UUID in the root model file build element to ensure
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
@@ -61,7 +62,7 @@ func (r *ReadCloser) Close() error {
 	return r.f.Close()
 }
 
-func decodeModelFile(ctx context.Context, r io.Reader, model *Model, path string, isRoot, strict bool) error {
+func decodeModelFile(ctx context.Context, r io.Reader, model *Model, path string, isRoot, strict bool, handler MeshHandler) error {
 	x := xml3mf.NewDecoder(r)
 	type stackElement struct {
 		decoder spec.ElementDecoder
@@ -75,7 +76,7 @@ func decodeModelFile(ctx context.Context, r io.Reader, model *Model, path string
 		currentName    xml.Name
 		errs           specerr.List
 	)
-	currentDecoder = &topLevelDecoder{isRoot: isRoot, model: model, path: path}
+	currentDecoder = &topLevelDecoder{isRoot: isRoot, model: model, path: path, handler: handler}
 	var err error
 	x.OnStart = func(tp xml3mf.StartElement) {
 		if childDecoder, ok := currentDecoder.(spec.ChildElementDecoder); ok {
@@ -158,9 +159,44 @@ func decodeModelFile(ctx context.Context, r io.Reader, model *Model, path string
 	return err
 }
 
+// MeshHandler receives the vertices and triangles of the meshes as they are decoded.
+//
+// The object passed to the handler has its attributes already decoded,
+// path is the model file where the object is defined.
+// Methods can be called concurrently when the package contains more than one model file.
+type MeshHandler interface {
+	Vertex(path string, obj *Object, v Point3D)
+	Triangle(path string, obj *Object, t Triangle)
+}
+
+// ErrRootModelSkipped is returned when the PartReader skips the root model part,
+// as there is nothing to decode.
+var ErrRootModelSkipped = errors.New("go3mf: root model part skipped by the part reader")
+
+// A PartReader transforms the content of the package parts before they are decoded,
+// which can be used to decrypt them.
+type PartReader interface {
+	// Open is called once the package is opened, before reading any part.
+	// rels are the package root relationships and open returns the raw
+	// content of the named part.
+	Open(rels []Relationship, open func(name string) (io.ReadCloser, error)) error
+	// Reader returns the content of the named part, read from r.
+	// If it returns a nil reader the part is skipped, together with the
+	// relationships targeting it. Skipping the root model part
+	// makes the decoder fail with ErrRootModelSkipped.
+	Reader(name string, r io.Reader) (io.Reader, error)
+}
+
 // Decoder implements a 3mf file decoder.
 type Decoder struct {
-	Strict        bool
+	Strict bool
+	// MeshHandler, if not nil, enables the streaming mode:
+	// vertices and triangles are sent to the handler instead of being
+	// appended to the mesh, so Mesh.Vertices and Mesh.Triangles remain empty.
+	// Cancel the context to stop decoding.
+	MeshHandler MeshHandler
+	// PartReader, if not nil, is applied to every model part and attachment.
+	PartReader    PartReader
 	p             packageReader
 	flate         func(r io.Reader) io.ReadCloser
 	nonRootModels []packageFile
@@ -200,12 +236,15 @@ func UnmarshalModel(data []byte, model *Model) error {
 }
 
 func (d *Decoder) processRootModel(ctx context.Context, rootFile packageFile, model *Model) error {
-	f, err := rootFile.Open()
+	f, err := d.openFile(rootFile)
 	if err != nil {
 		return err
 	}
+	if f == nil {
+		return ErrRootModelSkipped
+	}
 	defer f.Close()
-	err = decodeModelFile(ctx, f, model, rootFile.Name(), true, d.Strict)
+	err = decodeModelFile(ctx, f, model, rootFile.Name(), true, d.Strict, d.MeshHandler)
 	if err != nil {
 		return err
 	}
@@ -241,6 +280,11 @@ func (d *Decoder) processOPC(model *Model) (packageFile, error) {
 	if err := d.p.Open(d.flate); err != nil {
 		return nil, err
 	}
+	if d.PartReader != nil {
+		if err := d.PartReader.Open(d.p.Relationships(), d.openRaw); err != nil {
+			return nil, err
+		}
+	}
 	var rootFile packageFile
 	for _, r := range d.p.Relationships() {
 		if r.Type == RelType3DModel {
@@ -250,13 +294,29 @@ func (d *Decoder) processOPC(model *Model) (packageFile, error) {
 				return nil, errors.New("package root model points to an unexisting file")
 			}
 			model.Path = rootFile.Name()
-			d.extractCoreAttachments(rootFile, model, true)
+			if err := d.extractCoreAttachments(rootFile, model, true); err != nil {
+				return nil, err
+			}
 			for _, file := range d.nonRootModels {
-				d.extractCoreAttachments(file, model, false)
+				if err := d.extractCoreAttachments(file, model, false); err != nil {
+					return nil, err
+				}
 			}
+		} else if r.Type == RelTypeDigitalSignatureOrigin {
+			// Signatures are checked by VerifySignatures, not kept as attachments.
+			continue
 		} else if att, ok := d.p.FindFileFromName(r.Path); ok {
-			model.RootRelationships = append(model.RootRelationships, r)
-			model.Attachments = d.addAttachment(model.Attachments, att)
+			var (
+				added bool
+				err   error
+			)
+			model.Attachments, added, err = d.addAttachment(model.Attachments, att)
+			if err != nil {
+				return nil, err
+			}
+			if added {
+				model.RootRelationships = append(model.RootRelationships, r)
+			}
 		}
 	}
 	if rootFile == nil {
@@ -265,54 +325,133 @@ func (d *Decoder) processOPC(model *Model) (packageFile, error) {
 	return rootFile, nil
 }
 
-func (d *Decoder) extractCoreAttachments(modelFile packageFile, model *Model, isRoot bool) {
+func (d *Decoder) extractCoreAttachments(modelFile packageFile, model *Model, isRoot bool) error {
 	for _, rel := range modelFile.Relationships() {
-		if file, ok := modelFile.FindFileFromName(rel.Path); ok {
-			if isRoot {
-				if rel.Type == RelType3DModel {
-					d.nonRootModels = append(d.nonRootModels, file)
-					if model.Childs == nil {
-						model.Childs = make(map[string]*ChildModel)
-					}
-					model.Childs[file.Name()] = new(ChildModel)
-				} else {
-					model.Attachments = d.addAttachment(model.Attachments, file)
-					model.Relationships = append(model.Relationships, rel)
-				}
-			} else if rel.Type != RelType3DModel {
-				if child, ok := model.Childs[modelFile.Name()]; ok {
-					model.Attachments = d.addAttachment(model.Attachments, file)
-					child.Relationships = append(child.Relationships, rel)
-				}
+		file, ok := modelFile.FindFileFromName(rel.Path)
+		if !ok {
+			continue
+		}
+		if isRoot && rel.Type == RelType3DModel {
+			d.nonRootModels = append(d.nonRootModels, file)
+			if model.Childs == nil {
+				model.Childs = make(map[string]*ChildModel)
+			}
+			model.Childs[file.Name()] = new(ChildModel)
+			continue
+		}
+		if rel.Type == RelType3DModel {
+			continue
+		}
+		var child *ChildModel
+		if !isRoot {
+			if child, ok = model.Childs[modelFile.Name()]; !ok {
+				continue
 			}
 		}
+		var (
+			added bool
+			err   error
+		)
+		model.Attachments, added, err = d.addAttachment(model.Attachments, file)
+		if err != nil {
+			return err
+		}
+		if !added {
+			continue
+		}
+		if isRoot {
+			model.Relationships = append(model.Relationships, rel)
+		} else {
+			child.Relationships = append(child.Relationships, rel)
+		}
 	}
+	return nil
 }
 
-func (d *Decoder) addAttachment(attachments []Attachment, file packageFile) []Attachment {
+// addAttachment appends file to the attachments if it is not already there.
+// It reports false if the PartReader skipped the file.
+func (d *Decoder) addAttachment(attachments []Attachment, file packageFile) ([]Attachment, bool, error) {
 	for _, att := range attachments {
 		if strings.EqualFold(att.Path, file.Name()) {
-			return attachments
+			return attachments, true, nil
+		}
+	}
+	stream, err := d.openFile(file)
+	if err != nil {
+		if _, ok := err.(*partReaderError); ok {
+			return attachments, false, err
 		}
+		return attachments, true, nil
 	}
-	if buff, err := copyFile(file); err == nil {
-		return append(attachments, Attachment{
+	if stream == nil {
+		return attachments, false, nil
+	}
+	defer stream.Close()
+	buff := new(bytes.Buffer)
+	if _, err := io.Copy(buff, stream); err == nil {
+		attachments = append(attachments, Attachment{
 			Path:        file.Name(),
 			Stream:      buff,
 			ContentType: file.ContentType(),
 		})
 	}
-	return attachments
+	return attachments, true, nil
+}
+
+// partReaderError wraps the errors returned by the PartReader,
+// which are not ignored when reading attachments.
+type partReaderError struct {
+	name string
+	err  error
+}
+
+func (e *partReaderError) Error() string {
+	return fmt.Sprintf("go3mf: reading part %s: %v", e.name, e.err)
+}
+
+func (e *partReaderError) Unwrap() error {
+	return e.err
+}
+
+// openFile opens file applying the PartReader, if any.
+// It returns a nil ReadCloser if the part has to be skipped.
+func (d *Decoder) openFile(file packageFile) (io.ReadCloser, error) {
+	rc, err := file.Open()
+	if err != nil || d.PartReader == nil {
+		return rc, err
+	}
+	r, err := d.PartReader.Reader(file.Name(), rc)
+	if err != nil {
+		rc.Close()
+		return nil, &partReaderError{name: file.Name(), err: err}
+	}
+	if r == nil {
+		rc.Close()
+		return nil, nil
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{r, rc}, nil
+}
+
+// openRaw returns the content of the named part, without applying the PartReader.
+func (d *Decoder) openRaw(name string) (io.ReadCloser, error) {
+	file, ok := d.p.FindFileFromName(name)
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return file.Open()
 }
 
 func (d *Decoder) readChildModel(ctx context.Context, i int, model *Model) error {
 	attachment := d.nonRootModels[i]
-	file, err := attachment.Open()
-	if err != nil {
+	file, err := d.openFile(attachment)
+	if err != nil || file == nil {
 		return err
 	}
 	defer file.Close()
-	err = decodeModelFile(ctx, file, model, attachment.Name(), false, d.Strict)
+	err = decodeModelFile(ctx, file, model, attachment.Name(), false, d.Strict, d.MeshHandler)
 	select {
 	case <-ctx.Done():
 		err = ctx.Err()
@@ -321,17 +460,6 @@ func (d *Decoder) readChildModel(ctx context.Context, i int, model *Model) error
 	return err
 }
 
-func copyFile(file packageFile) (io.Reader, error) {
-	stream, err := file.Open()
-	if err != nil {
-		return nil, err
-	}
-	buff := new(bytes.Buffer)
-	_, err = io.Copy(buff, stream)
-	stream.Close()
-	return buff, err
-}
-
 type fakePackageFile struct {
 	data []byte
 }
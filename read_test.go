@@ -15,6 +15,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/go-test/deep"
@@ -587,6 +588,84 @@ func TestDecoder_Decode(t *testing.T) {
 	}
 }
 
+type meshCollector struct {
+	sync.Mutex
+	meshes map[string]*Mesh
+}
+
+func (c *meshCollector) mesh(path string, obj *Object) *Mesh {
+	key := path + "#" + strconv.FormatUint(uint64(obj.ID), 10)
+	m, ok := c.meshes[key]
+	if !ok {
+		m = new(Mesh)
+		c.meshes[key] = m
+	}
+	return m
+}
+
+func (c *meshCollector) Vertex(path string, obj *Object, v Point3D) {
+	c.Lock()
+	m := c.mesh(path, obj)
+	m.Vertices.Vertex = append(m.Vertices.Vertex, v)
+	c.Unlock()
+}
+
+func (c *meshCollector) Triangle(path string, obj *Object, t Triangle) {
+	c.Lock()
+	m := c.mesh(path, obj)
+	m.Triangles.Triangle = append(m.Triangles.Triangle, t)
+	c.Unlock()
+}
+
+func TestDecoder_MeshHandler(t *testing.T) {
+	for _, name := range []string{"cube.3mf", "super_boogoku_tiny.3mf"} {
+		t.Run(name, func(t *testing.T) {
+			want := new(Model)
+			r, err := OpenReader("testdata/" + name)
+			if err != nil {
+				t.Fatalf("OpenReader() error = %v", err)
+			}
+			defer r.Close()
+			if err := r.Decode(want); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			r1, _ := OpenReader("testdata/" + name)
+			defer r1.Close()
+			c := &meshCollector{meshes: make(map[string]*Mesh)}
+			r1.MeshHandler = c
+			got := new(Model)
+			if err := r1.Decode(got); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			var meshes int
+			got.WalkObjects(func(path string, obj *Object) error {
+				if obj.Mesh == nil {
+					return nil
+				}
+				meshes++
+				if len(obj.Mesh.Vertices.Vertex) != 0 || len(obj.Mesh.Triangles.Triangle) != 0 {
+					t.Errorf("Decoder.Decode() streamed mesh %d is not empty", obj.ID)
+				}
+				wantObj, _ := want.FindObject(path, obj.ID)
+				if path == "" {
+					path = got.Path
+				}
+				gotMesh := c.mesh(path, obj)
+				if diff := deep.Equal(gotMesh.Vertices.Vertex, wantObj.Mesh.Vertices.Vertex); diff != nil {
+					t.Errorf("Decoder.Decode() vertices = %v", diff)
+				}
+				if diff := deep.Equal(gotMesh.Triangles.Triangle, wantObj.Mesh.Triangles.Triangle); diff != nil {
+					t.Errorf("Decoder.Decode() triangles = %v", diff)
+				}
+				return nil
+			})
+			if meshes == 0 {
+				t.Error("Decoder.Decode() no meshes found")
+			}
+		})
+	}
+}
+
 func Test_modelFile_Decode(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -616,7 +695,7 @@ func Test_modelFile_Decode(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := decodeModelFile(tt.args.ctx, tt.args.r, new(Model), "", true, false); (err != nil) != tt.wantErr {
+			if err := decodeModelFile(tt.args.ctx, tt.args.r, new(Model), "", true, false, nil); (err != nil) != tt.wantErr {
 				t.Errorf("modelFile.Decode() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -789,3 +868,125 @@ func TestOpenReader(t *testing.T) {
 		return
 	}
 }
+
+// xorPart implements PartReader and PartWriter flipping the bits of every part.
+type xorPart struct {
+	skip     string
+	err      error
+	closeRel bool
+}
+
+func (x *xorPart) Open(_ []Relationship, _ func(string) (io.ReadCloser, error)) error {
+	return nil
+}
+
+func (x *xorPart) Reader(name string, r io.Reader) (io.Reader, error) {
+	if strings.EqualFold(name, x.skip) {
+		return nil, x.err
+	}
+	b, err := ioutil.ReadAll(r)
+	return bytes.NewReader(xorBytes(b)), err
+}
+
+func (x *xorPart) Writer(_, _ string, w io.Writer) (io.WriteCloser, error) {
+	return &xorWriter{w: w}, nil
+}
+
+func (x *xorPart) Close(create func(string, string) (io.Writer, error), addRel func(Relationship)) error {
+	if !x.closeRel {
+		return nil
+	}
+	w, err := create("/xor.txt", "text/plain")
+	if err != nil {
+		return err
+	}
+	addRel(Relationship{Path: "/xor.txt", Type: "xor"})
+	_, err = w.Write(xorBytes([]byte("xor")))
+	return err
+}
+
+type xorWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+func (x *xorWriter) Write(b []byte) (int, error) {
+	return x.buf.Write(b)
+}
+
+func (x *xorWriter) Close() error {
+	_, err := x.w.Write(xorBytes(x.buf.Bytes()))
+	return err
+}
+
+func xorBytes(b []byte) []byte {
+	for i := range b {
+		b[i] ^= 0xff
+	}
+	return b
+}
+
+func TestDecoder_PartReader(t *testing.T) {
+	want := new(Model)
+	r, err := OpenReader("testdata/super_boogoku_tiny.3mf")
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	defer r.Close()
+	if err := r.Decode(want); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	buff := new(bytes.Buffer)
+	enc := NewEncoder(buff)
+	enc.FloatPrecision = -1
+	enc.PartWriter = &xorPart{closeRel: true}
+	if err := enc.Encode(want); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	data := buff.Bytes()
+	decode := func(p PartReader) (*Model, error) {
+		d := NewDecoder(bytes.NewReader(data), int64(len(data)))
+		d.PartReader = p
+		m := new(Model)
+		return m, d.Decode(m)
+	}
+	if got, err := decode(nil); err == nil && len(got.Resources.Objects) != 0 {
+		t.Error("Decoder.Decode() decoded the parts without PartReader")
+	}
+	got, err := decode(new(xorPart))
+	if err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if diff := deep.Equal(got.Resources, want.Resources); diff != nil {
+		t.Errorf("Decoder.Decode() resources = %v", diff)
+	}
+	if len(got.Attachments) != len(want.Attachments)+1 || len(got.RootRelationships) != len(want.RootRelationships)+1 {
+		t.Errorf("Decoder.Decode() attachments = %d, want %d", len(got.Attachments), len(want.Attachments)+1)
+	}
+	for _, a := range got.Attachments {
+		if a.Path == "/xor.txt" {
+			if b, _ := ioutil.ReadAll(a.Stream); string(b) != "xor" {
+				t.Errorf("Decoder.Decode() attachment = %s", b)
+			}
+		}
+	}
+
+	got, err = decode(&xorPart{skip: "/xor.txt"})
+	if err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if len(got.Attachments) != len(want.Attachments) || len(got.RootRelationships) != len(want.RootRelationships) {
+		t.Errorf("Decoder.Decode() skipped attachments = %d, want %d", len(got.Attachments), len(want.Attachments))
+	}
+
+	if _, err = decode(&xorPart{skip: want.Path}); err != ErrRootModelSkipped {
+		t.Errorf("Decoder.Decode() error = %v, want %v", err, ErrRootModelSkipped)
+	}
+
+	errPart := errors.New("part error")
+	for _, name := range []string{"/xor.txt", want.Attachments[0].Path, want.Path} {
+		if _, err = decode(&xorPart{skip: name, err: errPart}); !errors.Is(err, errPart) {
+			t.Errorf("Decoder.Decode() error = %v, want %v", err, errPart)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"github.com/hpinc/go3mf"
+)
+
+// FillHoles closes the boundary loops with up to maxEdges edges
+// by adding a triangle fan oriented consistently with the surrounding triangles.
+// Loops that go through the same vertex more than once are not filled.
+// The triangles must be consistently oriented, see OrientTriangles.
+//
+// It returns the number of filled holes.
+func FillHoles(m *go3mf.Mesh, maxEdges int) int {
+	if maxEdges < 3 {
+		return 0
+	}
+	ts := m.Triangles.Triangle
+	halfEdges := make(map[uint64]struct{}, len(ts)*3)
+	for i := range ts {
+		fv := vertices(&ts[i])
+		for j := 0; j < 3; j++ {
+			halfEdges[edgeKey(fv[j], fv[(j+1)%3])] = struct{}{}
+		}
+	}
+	// A boundary half edge a->b has no twin b->a,
+	// so the hole is traversed from b to a.
+	var (
+		next      = make(map[uint32]uint32)
+		ambiguous = make(map[uint32]bool)
+		starts    []uint32
+	)
+	for i := range ts {
+		fv := vertices(&ts[i])
+		for j := 0; j < 3; j++ {
+			a, b := fv[j], fv[(j+1)%3]
+			if _, ok := halfEdges[edgeKey(b, a)]; ok {
+				continue
+			}
+			if _, ok := next[b]; ok {
+				ambiguous[b] = true
+				continue
+			}
+			next[b] = a
+			starts = append(starts, b)
+		}
+	}
+	var (
+		count   int
+		visited = make(map[uint32]bool, len(next))
+		loop    []uint32
+	)
+	for _, start := range starts {
+		if visited[start] {
+			continue
+		}
+		loop = loop[:0]
+		valid := true
+		v := start
+		for {
+			if visited[v] || ambiguous[v] {
+				valid = valid && v == start && len(loop) > 0
+				break
+			}
+			visited[v] = true
+			loop = append(loop, v)
+			n, ok := next[v]
+			if !ok {
+				valid = false
+				break
+			}
+			v = n
+		}
+		if !valid || len(loop) < 3 || len(loop) > maxEdges {
+			continue
+		}
+		for i := 1; i < len(loop)-1; i++ {
+			m.Triangles.Triangle = append(m.Triangles.Triangle, go3mf.Triangle{V1: loop[0], V2: loop[i], V3: loop[i+1]})
+		}
+		count++
+	}
+	return count
+}
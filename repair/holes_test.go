@@ -0,0 +1,67 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func TestFillHoles(t *testing.T) {
+	tests := []struct {
+		name     string
+		removed  []int
+		maxEdges int
+		want     int
+		wantTris int
+	}{
+		{"closed", nil, 10, 0, 12},
+		{"disabled", []int{0}, 2, 0, 11},
+		{"triangle", []int{0}, 3, 1, 12},
+		{"quad", []int{0, 1}, 4, 1, 12},
+		{"quadTooBig", []int{0, 1}, 3, 0, 10},
+		{"two", []int{0, 2}, 3, 2, 12},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newCube(1, go3mf.Point3D{})
+			ts := m.Triangles.Triangle[:0]
+			for i, tri := range m.Triangles.Triangle {
+				keep := true
+				for _, r := range tt.removed {
+					keep = keep && r != i
+				}
+				if keep {
+					ts = append(ts, tri)
+				}
+			}
+			m.Triangles.Triangle = ts
+			if got := FillHoles(m, tt.maxEdges); got != tt.want {
+				t.Errorf("FillHoles() = %v, want %v", got, tt.want)
+			}
+			if len(m.Triangles.Triangle) != tt.wantTris {
+				t.Errorf("FillHoles() triangles = %v, want %v", len(m.Triangles.Triangle), tt.wantTris)
+			}
+			if tt.wantTris == 12 {
+				if err := m.ValidateCoherency(); err != nil {
+					t.Errorf("FillHoles() ValidateCoherency() = %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFillHoles_Bowtie(t *testing.T) {
+	// Two triangles sharing only one vertex define a non simple boundary.
+	m := &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {-1, 0, 0}, {0, -1, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2}, {V1: 0, V2: 3, V3: 4},
+		}},
+	}
+	if got := FillHoles(m, 10); got != 0 {
+		t.Errorf("FillHoles() = %v, want 0", got)
+	}
+}
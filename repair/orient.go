@@ -0,0 +1,183 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+// OrientTriangles makes the orientation of the triangles consistent
+// across each connected patch, so every manifold edge is traversed once
+// in each direction. Edges shared by more than two triangles are not
+// used to propagate the orientation.
+//
+// Within each patch the orientation of the majority is kept.
+// It returns the number of flipped triangles.
+func OrientTriangles(m *go3mf.Mesh) int {
+	ts := m.Triangles.Triangle
+	edges := make(map[uint64][]int, len(ts)*3/2)
+	for i := range ts {
+		fv := vertices(&ts[i])
+		for j := 0; j < 3; j++ {
+			key := undirectedEdgeKey(fv[j], fv[(j+1)%3])
+			edges[key] = append(edges[key], i)
+		}
+	}
+	var (
+		visited = make([]bool, len(ts))
+		flipped = make([]bool, len(ts))
+		count   int
+		queue   []int
+		patch   []int
+	)
+	for seed := range ts {
+		if visited[seed] {
+			continue
+		}
+		visited[seed] = true
+		queue = append(queue[:0], seed)
+		patch = patch[:0]
+		for len(queue) > 0 {
+			f := queue[0]
+			queue = queue[1:]
+			patch = append(patch, f)
+			fv := vertices(&ts[f])
+			if flipped[f] {
+				fv[1], fv[2] = fv[2], fv[1]
+			}
+			for j := 0; j < 3; j++ {
+				a, b := fv[j], fv[(j+1)%3]
+				neighbors := edges[undirectedEdgeKey(a, b)]
+				if len(neighbors) != 2 {
+					continue
+				}
+				g := neighbors[0]
+				if g == f {
+					g = neighbors[1]
+				}
+				if visited[g] {
+					continue
+				}
+				visited[g] = true
+				// g must traverse the shared edge from b to a.
+				flipped[g] = hasEdge(&ts[g], a, b)
+				queue = append(queue, g)
+			}
+		}
+		var n int
+		for _, f := range patch {
+			if flipped[f] {
+				n++
+			}
+		}
+		invert := n*2 > len(patch)
+		for _, f := range patch {
+			if flipped[f] != invert {
+				flip(&ts[f])
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// hasEdge returns true if t traverses the edge from a to b.
+func hasEdge(t *go3mf.Triangle, a, b uint32) bool {
+	fv := vertices(t)
+	for j := 0; j < 3; j++ {
+		if fv[j] == a && fv[(j+1)%3] == b {
+			return true
+		}
+	}
+	return false
+}
+
+// FlipInvertedShells flips the triangles of the connected shells
+// whose orientation does not match their nesting: outer shells must
+// enclose a positive volume and cavities a negative one.
+// The triangles of each shell must be consistently oriented,
+// see OrientTriangles.
+//
+// It returns the number of flipped shells.
+func FlipInvertedShells(m *go3mf.Mesh) int {
+	groups := shells(m)
+	volumes := make([]float64, len(groups))
+	for i, g := range groups {
+		volumes[i] = signedVolume(m, g)
+	}
+	var count int
+	for i, g := range groups {
+		if volumes[i] == 0 {
+			continue
+		}
+		p := m.Vertices.Vertex[m.Triangles.Triangle[g[0]].V1]
+		var depth int
+		for j, other := range groups {
+			if j != i && math.Abs(windingNumber(m, other, p)) > 0.5 {
+				depth++
+			}
+		}
+		if (volumes[i] < 0) != (depth%2 == 1) {
+			for _, f := range g {
+				flip(&m.Triangles.Triangle[f])
+			}
+			count++
+		}
+	}
+	return count
+}
+
+func signedVolume(m *go3mf.Mesh, group []int) float64 {
+	var vol float64
+	vs := m.Vertices.Vertex
+	for _, f := range group {
+		t := &m.Triangles.Triangle[f]
+		v1, v2, v3 := toVec(vs[t.V1]), toVec(vs[t.V2]), toVec(vs[t.V3])
+		vol += v1.dot(v2.cross(v3))
+	}
+	return vol / 6
+}
+
+// windingNumber returns the generalized winding number of p
+// with respect to the triangles of the group.
+func windingNumber(m *go3mf.Mesh, group []int, p go3mf.Point3D) float64 {
+	var (
+		total float64
+		vs    = m.Vertices.Vertex
+		o     = toVec(p)
+	)
+	for _, f := range group {
+		t := &m.Triangles.Triangle[f]
+		a, b, c := toVec(vs[t.V1]).sub(o), toVec(vs[t.V2]).sub(o), toVec(vs[t.V3]).sub(o)
+		la, lb, lc := a.len(), b.len(), c.len()
+		num := a.dot(b.cross(c))
+		den := la*lb*lc + a.dot(b)*lc + b.dot(c)*la + c.dot(a)*lb
+		total += 2 * math.Atan2(num, den)
+	}
+	return total / (4 * math.Pi)
+}
+
+type vec [3]float64
+
+func toVec(p go3mf.Point3D) vec {
+	return vec{float64(p.X()), float64(p.Y()), float64(p.Z())}
+}
+
+func (v vec) sub(o vec) vec {
+	return vec{v[0] - o[0], v[1] - o[1], v[2] - o[2]}
+}
+
+func (v vec) dot(o vec) float64 {
+	return v[0]*o[0] + v[1]*o[1] + v[2]*o[2]
+}
+
+func (v vec) cross(o vec) vec {
+	return vec{v[1]*o[2] - v[2]*o[1], v[2]*o[0] - v[0]*o[2], v[0]*o[1] - v[1]*o[0]}
+}
+
+func (v vec) len() float64 {
+	return math.Sqrt(v.dot(v))
+}
@@ -0,0 +1,79 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func TestOrientTriangles(t *testing.T) {
+	tests := []struct {
+		name    string
+		flipped []int
+		want    int
+	}{
+		{"none", nil, 0},
+		{"one", []int{3}, 1},
+		{"some", []int{0, 5, 7}, 3},
+		{"majority", []int{0, 1, 2, 3, 4, 5, 6, 7, 8}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newCube(1, go3mf.Point3D{})
+			m.Triangles.Triangle[3].P2 = 1
+			for _, i := range tt.flipped {
+				flip(&m.Triangles.Triangle[i])
+			}
+			if got := OrientTriangles(m); got != tt.want {
+				t.Errorf("OrientTriangles() = %v, want %v", got, tt.want)
+			}
+			if err := m.ValidateCoherency(); err != nil {
+				t.Errorf("OrientTriangles() ValidateCoherency() = %v", err)
+			}
+			if tri := m.Triangles.Triangle[3]; (tri.V2 == 7 && tri.P2 != 1) || (tri.V3 == 7 && tri.P3 != 1) {
+				t.Errorf("OrientTriangles() properties not flipped = %v", tri)
+			}
+		})
+	}
+}
+
+func TestFlipInvertedShells(t *testing.T) {
+	invert := func(m *go3mf.Mesh) *go3mf.Mesh {
+		for i := range m.Triangles.Triangle {
+			flip(&m.Triangles.Triangle[i])
+		}
+		return m
+	}
+	tests := []struct {
+		name   string
+		shells []*go3mf.Mesh
+		want   int
+	}{
+		{"empty", nil, 0},
+		{"outward", []*go3mf.Mesh{newCube(1, go3mf.Point3D{})}, 0},
+		{"inverted", []*go3mf.Mesh{invert(newCube(1, go3mf.Point3D{}))}, 1},
+		{"separated", []*go3mf.Mesh{newCube(1, go3mf.Point3D{}), invert(newCube(1, go3mf.Point3D{5, 0, 0}))}, 1},
+		{"cavity", []*go3mf.Mesh{newCube(10, go3mf.Point3D{}), invert(newCube(2, go3mf.Point3D{4, 4, 4}))}, 0},
+		{"outwardCavity", []*go3mf.Mesh{newCube(10, go3mf.Point3D{}), newCube(2, go3mf.Point3D{4, 4, 4})}, 1},
+		{"island", []*go3mf.Mesh{
+			invert(newCube(10, go3mf.Point3D{})), newCube(6, go3mf.Point3D{2, 2, 2}), newCube(2, go3mf.Point3D{4, 4, 4}),
+		}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := new(go3mf.Mesh)
+			for _, s := range tt.shells {
+				appendMesh(m, s)
+			}
+			if got := FlipInvertedShells(m); got != tt.want {
+				t.Errorf("FlipInvertedShells() = %v, want %v", got, tt.want)
+			}
+			if got := FlipInvertedShells(m); got != 0 {
+				t.Errorf("FlipInvertedShells() second pass = %v, want 0", got)
+			}
+		})
+	}
+}
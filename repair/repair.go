@@ -0,0 +1,129 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package repair implements operations that fix the most common
+// defects that make a mesh fail go3mf.Mesh.ValidateCoherency.
+//
+// Each pass modifies the mesh in place and reports how many elements it changed.
+package repair
+
+import (
+	"github.com/hpinc/go3mf"
+)
+
+// Options defines the parameters used by Mesh.
+type Options struct {
+	// Tolerance is the maximum distance between two vertices to be merged.
+	// Zero only merges vertices with the same coordinates.
+	Tolerance float32
+	// MinArea is the area under which a triangle is considered degenerate.
+	// Zero only removes triangles with zero area.
+	MinArea float32
+	// MaxHoleEdges is the maximum number of edges of a hole to be filled.
+	// Zero disables hole filling.
+	MaxHoleEdges int
+}
+
+// Report contains the changes done by each repair pass.
+type Report struct {
+	MergedVertices      int
+	DegenerateTriangles int
+	DuplicateTriangles  int
+	FlippedTriangles    int
+	FlippedShells       int
+	FilledHoles         int
+}
+
+// Changed returns true if any pass modified the mesh.
+func (r Report) Changed() bool {
+	return r != Report{}
+}
+
+// Mesh runs all the repair passes over m in the following order:
+// MergeVertices, RemoveDegenerateTriangles, RemoveDuplicateFaces,
+// OrientTriangles, FillHoles and FlipInvertedShells.
+func Mesh(m *go3mf.Mesh, opts Options) Report {
+	var r Report
+	r.MergedVertices = MergeVertices(m, opts.Tolerance)
+	r.DegenerateTriangles = RemoveDegenerateTriangles(m, opts.MinArea)
+	r.DuplicateTriangles = RemoveDuplicateFaces(m)
+	r.FlippedTriangles = OrientTriangles(m)
+	if opts.MaxHoleEdges > 0 {
+		r.FilledHoles = FillHoles(m, opts.MaxHoleEdges)
+	}
+	r.FlippedShells = FlipInvertedShells(m)
+	return r
+}
+
+func vertices(t *go3mf.Triangle) [3]uint32 {
+	return [3]uint32{t.V1, t.V2, t.V3}
+}
+
+// flip reverses the orientation of t keeping its properties
+// attached to the same vertices.
+func flip(t *go3mf.Triangle) {
+	t.V2, t.V3 = t.V3, t.V2
+	t.P2, t.P3 = t.P3, t.P2
+}
+
+func edgeKey(v1, v2 uint32) uint64 {
+	return uint64(v1)<<32 | uint64(v2)
+}
+
+func undirectedEdgeKey(v1, v2 uint32) uint64 {
+	if v1 > v2 {
+		v1, v2 = v2, v1
+	}
+	return edgeKey(v1, v2)
+}
+
+// shells returns the connected components of the mesh,
+// each one as a list of triangle indices.
+// Triangles with out of bounds indices are ignored.
+func shells(m *go3mf.Mesh) [][]int {
+	parent := make([]uint32, len(m.Vertices.Vertex))
+	for i := range parent {
+		parent[i] = uint32(i)
+	}
+	var find func(uint32) uint32
+	find = func(v uint32) uint32 {
+		for parent[v] != v {
+			parent[v] = parent[parent[v]]
+			v = parent[v]
+		}
+		return v
+	}
+	for i := range m.Triangles.Triangle {
+		t := &m.Triangles.Triangle[i]
+		if !validIndices(m, t) {
+			continue
+		}
+		r1, r2, r3 := find(t.V1), find(t.V2), find(t.V3)
+		parent[r2] = r1
+		parent[find(r3)] = r1
+	}
+	var (
+		groups [][]int
+		index  = make(map[uint32]int)
+	)
+	for i := range m.Triangles.Triangle {
+		t := &m.Triangles.Triangle[i]
+		if !validIndices(m, t) {
+			continue
+		}
+		root := find(t.V1)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	return groups
+}
+
+func validIndices(m *go3mf.Mesh, t *go3mf.Triangle) bool {
+	n := uint32(len(m.Vertices.Vertex))
+	return t.V1 < n && t.V2 < n && t.V3 < n
+}
@@ -0,0 +1,79 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+// newCube returns a closed cube with outward facing triangles.
+func newCube(size float32, offset go3mf.Point3D) *go3mf.Mesh {
+	m := new(go3mf.Mesh)
+	for _, v := range []go3mf.Point3D{
+		{0, 0, 0}, {1, 0, 0}, {1, 1, 0}, {0, 1, 0},
+		{0, 0, 1}, {1, 0, 1}, {1, 1, 1}, {0, 1, 1},
+	} {
+		m.Vertices.Vertex = append(m.Vertices.Vertex, v.Mul(size).Add(offset))
+	}
+	for _, t := range [][3]uint32{
+		{3, 2, 1}, {1, 0, 3}, {4, 5, 6}, {6, 7, 4},
+		{0, 1, 5}, {5, 4, 0}, {1, 2, 6}, {6, 5, 1},
+		{2, 3, 7}, {7, 6, 2}, {3, 0, 4}, {4, 7, 3},
+	} {
+		m.Triangles.Triangle = append(m.Triangles.Triangle, go3mf.Triangle{V1: t[0], V2: t[1], V3: t[2]})
+	}
+	return m
+}
+
+// appendMesh appends the vertices and triangles of src to dst.
+func appendMesh(dst, src *go3mf.Mesh) {
+	offset := uint32(len(dst.Vertices.Vertex))
+	dst.Vertices.Vertex = append(dst.Vertices.Vertex, src.Vertices.Vertex...)
+	for _, t := range src.Triangles.Triangle {
+		t.V1 += offset
+		t.V2 += offset
+		t.V3 += offset
+		dst.Triangles.Triangle = append(dst.Triangles.Triangle, t)
+	}
+}
+
+// soup returns a mesh where each triangle has its own vertices, as decoded from a STL.
+func soup(m *go3mf.Mesh) *go3mf.Mesh {
+	s := new(go3mf.Mesh)
+	for _, t := range m.Triangles.Triangle {
+		n := uint32(len(s.Vertices.Vertex))
+		s.Vertices.Vertex = append(s.Vertices.Vertex, m.Vertices.Vertex[t.V1], m.Vertices.Vertex[t.V2], m.Vertices.Vertex[t.V3])
+		s.Triangles.Triangle = append(s.Triangles.Triangle, go3mf.Triangle{V1: n, V2: n + 1, V3: n + 2})
+	}
+	return s
+}
+
+func TestMesh(t *testing.T) {
+	m := soup(newCube(10, go3mf.Point3D{}))
+	flip(&m.Triangles.Triangle[0])
+	flip(&m.Triangles.Triangle[1])
+	// Duplicated face and degenerated triangle.
+	m.Triangles.Triangle = append(m.Triangles.Triangle, m.Triangles.Triangle[4], go3mf.Triangle{V1: 0, V2: 0, V3: 1})
+	// Hole.
+	m.Triangles.Triangle = append(m.Triangles.Triangle[:10], m.Triangles.Triangle[11:]...)
+	if err := m.ValidateCoherency(); err == nil {
+		t.Fatal("ValidateCoherency() expected to fail")
+	}
+	got := Mesh(m, Options{Tolerance: 0.001, MaxHoleEdges: 3})
+	want := Report{MergedVertices: 28, DegenerateTriangles: 1, DuplicateTriangles: 1, FlippedTriangles: 2, FilledHoles: 1}
+	if got != want {
+		t.Errorf("Mesh() = %+v, want %+v", got, want)
+	}
+	if !got.Changed() {
+		t.Error("Report.Changed() = false, want true")
+	}
+	if err := m.ValidateCoherency(); err != nil {
+		t.Errorf("Mesh() ValidateCoherency() = %v", err)
+	}
+	if got := Mesh(m, Options{MaxHoleEdges: 3}); got.Changed() {
+		t.Errorf("Mesh() = %+v, want no changes", got)
+	}
+}
@@ -0,0 +1,75 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+// RemoveDegenerateTriangles removes the triangles that reference the same
+// vertex more than once, reference out of bounds vertices,
+// or have an area smaller or equal than minArea.
+//
+// It returns the number of removed triangles.
+func RemoveDegenerateTriangles(m *go3mf.Mesh, minArea float32) int {
+	return filterTriangles(m, func(t *go3mf.Triangle) bool {
+		if !validIndices(m, t) || t.V1 == t.V2 || t.V1 == t.V3 || t.V2 == t.V3 {
+			return false
+		}
+		vs := m.Vertices.Vertex
+		v1 := vs[t.V1]
+		area := vs[t.V2].Sub(v1).Cross(vs[t.V3].Sub(v1)).Len() / 2
+		return area > minArea
+	})
+}
+
+// RemoveDuplicateFaces removes the triangles that reference the same
+// three vertices than a previous triangle, regardless of their orientation.
+//
+// It returns the number of removed triangles.
+func RemoveDuplicateFaces(m *go3mf.Mesh) int {
+	seen := make(map[[3]uint32]struct{}, len(m.Triangles.Triangle))
+	return filterTriangles(m, func(t *go3mf.Triangle) bool {
+		key := vertices(t)
+		sort.Slice(key[:], func(i, j int) bool { return key[i] < key[j] })
+		if _, ok := seen[key]; ok {
+			return false
+		}
+		seen[key] = struct{}{}
+		return true
+	})
+}
+
+// filterTriangles keeps the triangles for which keep returns true
+// and returns the number of removed triangles.
+// Extensions in m.Any that implement spec.TriangleRemapper are updated too.
+func filterTriangles(m *go3mf.Mesh, keep func(*go3mf.Triangle) bool) int {
+	ts := m.Triangles.Triangle
+	remap := make([]uint32, len(ts))
+	n := 0
+	for i := range ts {
+		if keep(&ts[i]) {
+			ts[n] = ts[i]
+			remap[i] = uint32(n)
+			n++
+		} else {
+			remap[i] = spec.RemovedIndex
+		}
+	}
+	for i := n; i < len(ts); i++ {
+		ts[i] = go3mf.Triangle{}
+	}
+	m.Triangles.Triangle = ts[:n]
+	if n < len(ts) {
+		for _, a := range m.Any {
+			if a, ok := a.(spec.TriangleRemapper); ok {
+				a.RemapTriangles(remap)
+			}
+		}
+	}
+	return len(ts) - n
+}
@@ -0,0 +1,75 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/trianglesets"
+)
+
+func TestRemoveDegenerateTriangles(t *testing.T) {
+	m := &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {2, 0, 0}, {0.1, 0.1, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2, PID: 1},
+			{V1: 0, V2: 0, V3: 2},
+			{V1: 0, V2: 1, V3: 3},
+			{V1: 0, V2: 1, V3: 5},
+			{V1: 0, V2: 1, V3: 4, PID: 2},
+		}},
+	}
+	if got := RemoveDegenerateTriangles(m, 0); got != 3 {
+		t.Errorf("RemoveDegenerateTriangles() = %v, want %v", got, 3)
+	}
+	want := []go3mf.Triangle{{V1: 0, V2: 1, V3: 2, PID: 1}, {V1: 0, V2: 1, V3: 4, PID: 2}}
+	if diff := deep.Equal(m.Triangles.Triangle, want); diff != nil {
+		t.Errorf("RemoveDegenerateTriangles() = %v", diff)
+	}
+	if got := RemoveDegenerateTriangles(m, 0.1); got != 1 {
+		t.Errorf("RemoveDegenerateTriangles() = %v, want %v", got, 1)
+	}
+	if diff := deep.Equal(m.Triangles.Triangle, want[:1]); diff != nil {
+		t.Errorf("RemoveDegenerateTriangles() = %v", diff)
+	}
+}
+
+func TestRemoveDuplicateFaces(t *testing.T) {
+	m := &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 1, V3: 2, PID: 1},
+			{V1: 1, V2: 2, V3: 0, PID: 2},
+			{V1: 0, V2: 2, V3: 1},
+			{V1: 0, V2: 1, V3: 3},
+		}},
+	}
+	if got := RemoveDuplicateFaces(m); got != 2 {
+		t.Errorf("RemoveDuplicateFaces() = %v, want %v", got, 2)
+	}
+	want := []go3mf.Triangle{{V1: 0, V2: 1, V3: 2, PID: 1}, {V1: 0, V2: 1, V3: 3}}
+	if diff := deep.Equal(m.Triangles.Triangle, want); diff != nil {
+		t.Errorf("RemoveDuplicateFaces() = %v", diff)
+	}
+}
+
+func TestRemoveDegenerateTriangles_extensions(t *testing.T) {
+	m := &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 0, V3: 2},
+			{V1: 0, V2: 1, V3: 2},
+		}},
+	}
+	ts := &trianglesets.TriangleSets{TriangleSet: []trianglesets.TriangleSet{{Name: "a", Refs: []uint32{0, 1}}}}
+	m.Any = append(m.Any, ts)
+	if got := RemoveDegenerateTriangles(m, 0); got != 1 {
+		t.Errorf("RemoveDegenerateTriangles() = %v, want %v", got, 1)
+	}
+	if diff := deep.Equal(ts.TriangleSet[0].Refs, []uint32{0}); diff != nil {
+		t.Errorf("RemoveDegenerateTriangles() triangle sets = %v", diff)
+	}
+}
@@ -0,0 +1,83 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"math"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+type cell [3]int64
+
+// MergeVertices merges the vertices closer than tolerance and
+// updates the triangles to reference the merged vertex.
+// Extensions in m.Any that implement spec.VertexRemapper are updated too.
+// The vertex that appears first is kept.
+//
+// It returns the number of removed vertices.
+func MergeVertices(m *go3mf.Mesh, tolerance float32) int {
+	if tolerance < 0 {
+		tolerance = 0
+	}
+	var (
+		vs     = m.Vertices.Vertex
+		remap  = make([]uint32, len(vs))
+		merged = make([]go3mf.Point3D, 0, len(vs))
+		grid   = make(map[cell][]uint32)
+		size   = float64(tolerance)
+	)
+	if size == 0 {
+		size = 1
+	}
+	cellOf := func(v go3mf.Point3D) cell {
+		return cell{
+			int64(math.Floor(float64(v.X()) / size)),
+			int64(math.Floor(float64(v.Y()) / size)),
+			int64(math.Floor(float64(v.Z()) / size)),
+		}
+	}
+	for i, v := range vs {
+		c := cellOf(v)
+		found := false
+	search:
+		for dx := int64(-1); dx <= 1; dx++ {
+			for dy := int64(-1); dy <= 1; dy++ {
+				for dz := int64(-1); dz <= 1; dz++ {
+					for _, j := range grid[cell{c[0] + dx, c[1] + dy, c[2] + dz}] {
+						if merged[j].Sub(v).Len() <= tolerance {
+							remap[i] = j
+							found = true
+							break search
+						}
+					}
+				}
+			}
+		}
+		if !found {
+			remap[i] = uint32(len(merged))
+			grid[c] = append(grid[c], remap[i])
+			merged = append(merged, v)
+		}
+	}
+	removed := len(vs) - len(merged)
+	if removed == 0 {
+		return 0
+	}
+	for i := range m.Triangles.Triangle {
+		t := &m.Triangles.Triangle[i]
+		if !validIndices(m, t) {
+			continue
+		}
+		t.V1, t.V2, t.V3 = remap[t.V1], remap[t.V2], remap[t.V3]
+	}
+	m.Vertices.Vertex = merged
+	for _, a := range m.Any {
+		if a, ok := a.(spec.VertexRemapper); ok {
+			a.RemapVertices(remap)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,72 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package repair
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/beamlattice"
+)
+
+func TestMergeVertices(t *testing.T) {
+	tests := []struct {
+		name      string
+		vertices  []go3mf.Point3D
+		tolerance float32
+		want      int
+		wantVs    []go3mf.Point3D
+		wantTri   go3mf.Triangle
+	}{
+		{"none", []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, 0, 0,
+			[]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, go3mf.Triangle{V1: 0, V2: 1, V3: 2}},
+		{"exact", []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 0, 0}}, 0, 1,
+			[]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}}, go3mf.Triangle{V1: 0, V2: 1, V3: 0}},
+		{"negative", []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0, 0, 0}}, -1, 1,
+			[]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}}, go3mf.Triangle{V1: 0, V2: 1, V3: 0}},
+		{"tolerance", []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {0.99, 0.01, 0}}, 0.1, 1,
+			[]go3mf.Point3D{{0, 0, 0}, {1, 0, 0}}, go3mf.Triangle{V1: 0, V2: 1, V3: 1}},
+		{"neighborCell", []go3mf.Point3D{{0.09, 0, 0}, {0.11, 0, 0}, {5, 5, 5}}, 0.1, 1,
+			[]go3mf.Point3D{{0.09, 0, 0}, {5, 5, 5}}, go3mf.Triangle{V1: 0, V2: 0, V3: 1}},
+		{"outside", []go3mf.Point3D{{0, 0, 0}, {0.2, 0, 0}, {0, 0.2, 0}}, 0.1, 0,
+			[]go3mf.Point3D{{0, 0, 0}, {0.2, 0, 0}, {0, 0.2, 0}}, go3mf.Triangle{V1: 0, V2: 1, V3: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &go3mf.Mesh{
+				Vertices:  go3mf.Vertices{Vertex: tt.vertices},
+				Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{{V1: 0, V2: 1, V3: 2}, {V1: 0, V2: 1, V3: 9}}},
+			}
+			if got := MergeVertices(m, tt.tolerance); got != tt.want {
+				t.Errorf("MergeVertices() = %v, want %v", got, tt.want)
+			}
+			if diff := deep.Equal(m.Vertices.Vertex, tt.wantVs); diff != nil {
+				t.Errorf("MergeVertices() vertices = %v", diff)
+			}
+			if diff := deep.Equal(m.Triangles.Triangle[0], tt.wantTri); diff != nil {
+				t.Errorf("MergeVertices() triangle = %v", diff)
+			}
+			if m.Triangles.Triangle[1].V3 != 9 {
+				t.Errorf("MergeVertices() modified out of bounds triangle = %v", m.Triangles.Triangle[1])
+			}
+		})
+	}
+}
+
+func TestMergeVertices_extensions(t *testing.T) {
+	m := &go3mf.Mesh{Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {1, 0, 0}, {1, 0, 0}}}}
+	b := &beamlattice.BeamLattice{Beams: beamlattice.Beams{Beam: []beamlattice.Beam{
+		{Indices: [2]uint32{0, 2}},
+		{Indices: [2]uint32{1, 2}},
+	}}}
+	m.Any = append(m.Any, b)
+	if got := MergeVertices(m, 0); got != 1 {
+		t.Errorf("MergeVertices() = %v, want %v", got, 1)
+	}
+	want := []beamlattice.Beam{{Indices: [2]uint32{0, 1}}}
+	if diff := deep.Equal(b.Beams.Beam, want); diff != nil {
+		t.Errorf("MergeVertices() beams = %v", diff)
+	}
+}
@@ -0,0 +1,54 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting_test
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf/beamlattice"
+	"github.com/hpinc/go3mf/booleanoperations"
+	"github.com/hpinc/go3mf/displacement"
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/materials"
+	"github.com/hpinc/go3mf/production"
+	"github.com/hpinc/go3mf/reporting"
+	"github.com/hpinc/go3mf/slices"
+	"github.com/hpinc/go3mf/trianglesets"
+	"github.com/hpinc/go3mf/volumetric"
+)
+
+// TestLookup_extensions checks that the extensions register their rules when imported.
+func TestLookup_extensions(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{materials.ErrMultiColors, "MAT004"},
+		{production.ErrProdRefInNonRoot, "PROD002"},
+		{slices.ErrSliceSmallTopZ, "SLICE006"},
+		{beamlattice.ErrLatticeSameVertex, "BEAM004"},
+		{booleanoperations.ErrBooleanNoOperands, "BOOL004"},
+		{displacement.ErrZeroNormVector, "DISP006"},
+		{trianglesets.ErrRefRangeOrder, "TSET002"},
+		{volumetric.ErrDuplicatedProperty, "VOL010"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := reporting.Lookup(specerr.Wrap(tt.err, "foo")); got.ID != tt.want {
+				t.Errorf("Lookup() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+	ids := make(map[string]bool)
+	for _, r := range reporting.Rules() {
+		if ids[r.ID] {
+			t.Errorf("Rules() duplicated ID %s", r.ID)
+		}
+		ids[r.ID] = true
+	}
+	// 24 core rules and 51 extension rules.
+	if len(ids) != 75 {
+		t.Errorf("Rules() = %d rules, want 75", len(ids))
+	}
+}
@@ -0,0 +1,110 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package reporting serializes the errors returned by go3mf
+// decoding and validation into JSON and SARIF reports.
+//
+// Each finding is associated with a stable rule ID
+// derived from its sentinel error, see Lookup.
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+// Finding is a single validation error.
+type Finding struct {
+	RuleID   string   `json:"ruleId"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	Path     string   `json:"path,omitempty"`  // Model part, empty for the root model.
+	XPath    string   `json:"xpath,omitempty"` // Offending element.
+	rule     Rule
+}
+
+// NewFinding creates a finding from a single error.
+func NewFinding(err error) Finding {
+	r := Lookup(err)
+	f := Finding{RuleID: r.ID, Rule: r.Name, Severity: r.Severity, rule: r}
+	var e *specerr.Error
+	if errors.As(err, &e) {
+		f.Path = e.Path
+		f.XPath = e.XPath()
+		f.Message = e.Err.Error()
+	} else {
+		f.Message = err.Error()
+	}
+	return f
+}
+
+// Report contains the findings of a file.
+type Report struct {
+	File     string    `json:"file,omitempty"`
+	Valid    bool      `json:"valid"`
+	Findings []Finding `json:"findings"`
+}
+
+// New creates a report for file from err,
+// which is usually the result of Decode, Validate or ValidateCoherency.
+// err can be nil, an errors.List or a single error.
+func New(file string, err error) *Report {
+	r := &Report{File: file, Findings: []Finding{}}
+	r.Add(err)
+	return r
+}
+
+// Add appends the findings contained in err.
+func (r *Report) Add(err error) {
+	r.add(err)
+	r.Valid = r.errors() == 0
+}
+
+func (r *Report) add(err error) {
+	if err == nil {
+		return
+	}
+	if list, ok := err.(*specerr.List); ok {
+		for _, e := range list.Errors {
+			r.add(e)
+		}
+		return
+	}
+	r.Findings = append(r.Findings, NewFinding(err))
+}
+
+func (r *Report) errors() int {
+	var n int
+	for _, f := range r.Findings {
+		if f.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteJSON writes the JSON encoding of the report to w.
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteJSON writes a JSON array with the reports to w.
+func WriteJSON(w io.Writer, reports ...*Report) error {
+	if reports == nil {
+		reports = []*Report{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reports)
+}
+
+func sortRules(rs []Rule) {
+	sort.Slice(rs, func(i, j int) bool { return rs[i].ID < rs[j].ID })
+}
@@ -0,0 +1,103 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/go-test/deep"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want *Report
+	}{
+		{"nil", nil, &Report{File: "a.3mf", Valid: true, Findings: []Finding{}}},
+		{"single", specerr.WrapPath(specerr.WrapIndex(specerr.ErrDuplicatedID, "object", 2), "resources", "/3D/other.model"), &Report{
+			File: "a.3mf", Findings: []Finding{
+				{RuleID: "CORE002", Rule: "DuplicatedID", Severity: SeverityError, Message: specerr.ErrDuplicatedID.Error(),
+					Path: "/3D/other.model", XPath: "/resources/object[2]"},
+			},
+		}},
+		{"list", &specerr.List{Errors: []error{
+			specerr.Wrap(specerr.ErrIndexOutOfBounds, "multiproperties"),
+			specerr.NewParseAttrError("name", false),
+		}}, &Report{
+			File: "a.3mf", Findings: []Finding{
+				{RuleID: "CORE005", Rule: "IndexOutOfBounds", Severity: SeverityError, Message: specerr.ErrIndexOutOfBounds.Error(), XPath: "/multiproperties"},
+				{RuleID: "CORE102", Rule: "ParseOptionalAttr", Severity: SeverityWarning, Message: "error parsing optional attribute 'name'"},
+			},
+		}},
+		{"warnings", specerr.NewParseAttrError("name", false), &Report{
+			File: "a.3mf", Valid: true, Findings: []Finding{
+				{RuleID: "CORE102", Rule: "ParseOptionalAttr", Severity: SeverityWarning, Message: "error parsing optional attribute 'name'"},
+			},
+		}},
+		{"plain", errors.New("foo"), &Report{
+			File: "a.3mf", Findings: []Finding{{RuleID: "UNKNOWN", Rule: "Unknown", Severity: SeverityError, Message: "foo"}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(New("a.3mf", tt.err), tt.want); diff != nil {
+				t.Errorf("New() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	r := New("a.3mf", specerr.WrapIndex(specerr.ErrOPCRelTarget, "relationship", 0))
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatalf("Report.WriteJSON() error = %v", err)
+	}
+	var got map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Report.WriteJSON() invalid json = %v", err)
+	}
+	want := map[string]interface{}{
+		"file":  "a.3mf",
+		"valid": false,
+		"findings": []interface{}{map[string]interface{}{
+			"ruleId": "CORE010", "rule": "OPCRelTarget", "severity": "error",
+			"message": specerr.ErrOPCRelTarget.Error(), "xpath": "/relationship[0]",
+		}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Report.WriteJSON() = %v", diff)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		reports []*Report
+		want    int
+	}{
+		{"empty", nil, 0},
+		{"several", []*Report{New("a.3mf", nil), New("b.3mf", specerr.ErrMissingID)}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteJSON(&buf, tt.reports...); err != nil {
+				t.Fatalf("WriteJSON() error = %v", err)
+			}
+			var got []Report
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("WriteJSON() invalid json = %v", err)
+			}
+			if len(got) != tt.want {
+				t.Errorf("WriteJSON() = %d reports, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting
+
+import (
+	"errors"
+	"sync"
+
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+// Severity defines the importance of a finding.
+// The values match the SARIF result levels.
+type Severity string
+
+// Supported severities.
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule identifies a kind of validation error.
+//
+// IDs are stable across releases: a rule is never renumbered
+// and its ID is never reused for a different error.
+type Rule struct {
+	ID       string
+	Name     string
+	Severity Severity
+}
+
+// Rules for errors which are not sentinels.
+var (
+	RuleMissingField      = Rule{ID: "CORE100", Name: "MissingField", Severity: SeverityError}
+	RuleParseAttr         = Rule{ID: "CORE101", Name: "ParseAttr", Severity: SeverityError}
+	RuleParseOptionalAttr = Rule{ID: "CORE102", Name: "ParseOptionalAttr", Severity: SeverityWarning}
+	RuleUnknown           = Rule{ID: "UNKNOWN", Name: "Unknown", Severity: SeverityError}
+)
+
+var (
+	rulesMu sync.RWMutex
+	rules   = map[error]Rule{
+		// core
+		specerr.ErrMissingID:              {ID: "CORE001", Name: "MissingID", Severity: SeverityError},
+		specerr.ErrDuplicatedID:           {ID: "CORE002", Name: "DuplicatedID", Severity: SeverityError},
+		specerr.ErrMissingResource:        {ID: "CORE003", Name: "MissingResource", Severity: SeverityError},
+		specerr.ErrDuplicatedIndices:      {ID: "CORE004", Name: "DuplicatedIndices", Severity: SeverityError},
+		specerr.ErrIndexOutOfBounds:       {ID: "CORE005", Name: "IndexOutOfBounds", Severity: SeverityError},
+		specerr.ErrInsufficientVertices:   {ID: "CORE006", Name: "InsufficientVertices", Severity: SeverityError},
+		specerr.ErrInsufficientTriangles:  {ID: "CORE007", Name: "InsufficientTriangles", Severity: SeverityError},
+		specerr.ErrComponentsPID:          {ID: "CORE008", Name: "ComponentsPID", Severity: SeverityError},
+		specerr.ErrOPCPartName:            {ID: "CORE009", Name: "OPCPartName", Severity: SeverityError},
+		specerr.ErrOPCRelTarget:           {ID: "CORE010", Name: "OPCRelTarget", Severity: SeverityError},
+		specerr.ErrOPCDuplicatedRel:       {ID: "CORE011", Name: "OPCDuplicatedRel", Severity: SeverityError},
+		specerr.ErrOPCContentType:         {ID: "CORE012", Name: "OPCContentType", Severity: SeverityError},
+		specerr.ErrOPCDuplicatedTicket:    {ID: "CORE013", Name: "OPCDuplicatedTicket", Severity: SeverityError},
+		specerr.ErrOPCDuplicatedModelName: {ID: "CORE014", Name: "OPCDuplicatedModelName", Severity: SeverityError},
+		specerr.ErrMetadataName:           {ID: "CORE015", Name: "MetadataName", Severity: SeverityError},
+		specerr.ErrMetadataNamespace:      {ID: "CORE016", Name: "MetadataNamespace", Severity: SeverityError},
+		specerr.ErrMetadataDuplicated:     {ID: "CORE017", Name: "MetadataDuplicated", Severity: SeverityError},
+		specerr.ErrOtherItem:              {ID: "CORE018", Name: "OtherItem", Severity: SeverityError},
+		specerr.ErrNonObject:              {ID: "CORE019", Name: "NonObject", Severity: SeverityError},
+		specerr.ErrRequiredExt:            {ID: "CORE020", Name: "RequiredExt", Severity: SeverityError},
+		specerr.ErrEmptyResourceProps:     {ID: "CORE021", Name: "EmptyResourceProps", Severity: SeverityError},
+		specerr.ErrRecursion:              {ID: "CORE022", Name: "Recursion", Severity: SeverityError},
+		specerr.ErrInvalidObject:          {ID: "CORE023", Name: "InvalidObject", Severity: SeverityError},
+		specerr.ErrMeshConsistency:        {ID: "CORE024", Name: "MeshConsistency", Severity: SeverityError},
+	}
+)
+
+// Register associates a sentinel error with a rule,
+// so errors defined by custom specs are reported with a stable ID.
+// If Register is called twice with the same error the last rule wins.
+func Register(err error, r Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	rules[err] = r
+}
+
+// RegisterRules registers all the rules of rs.
+// Extensions call it from their init function,
+// next to spec.Register, to report their sentinel errors.
+func RegisterRules(rs map[error]Rule) {
+	rulesMu.Lock()
+	defer rulesMu.Unlock()
+	for err, r := range rs {
+		rules[err] = r
+	}
+}
+
+// Rules returns all the registered sentinel rules, sorted by ID.
+func Rules() []Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	rs := make([]Rule, 0, len(rules))
+	for _, r := range rules {
+		rs = append(rs, r)
+	}
+	sortRules(rs)
+	return rs
+}
+
+// Lookup returns the rule that matches err,
+// unwrapping it until a registered sentinel is found.
+func Lookup(err error) Rule {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if r, ok := rules[e]; ok {
+			return r
+		}
+		switch e := e.(type) {
+		case *specerr.MissingFieldError:
+			return RuleMissingField
+		case *specerr.ParseAttrError:
+			if e.Required {
+				return RuleParseAttr
+			}
+			return RuleParseOptionalAttr
+		}
+	}
+	return RuleUnknown
+}
@@ -0,0 +1,76 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting
+
+import (
+	"errors"
+	"testing"
+
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+func TestLookup(t *testing.T) {
+	custom := errors.New("custom")
+	Register(custom, Rule{ID: "CUSTOM001", Name: "Custom", Severity: SeverityWarning})
+	defer func() {
+		rulesMu.Lock()
+		delete(rules, custom)
+		rulesMu.Unlock()
+	}()
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"sentinel", specerr.ErrDuplicatedID, "CORE002"},
+		{"wrapped", specerr.WrapIndex(specerr.ErrOPCRelTarget, "object", 1), "CORE010"},
+		{"missingField", specerr.Wrap(specerr.NewMissingFieldError("id"), "object"), RuleMissingField.ID},
+		{"parseRequired", specerr.NewParseAttrError("id", true), RuleParseAttr.ID},
+		{"parseOptional", specerr.NewParseAttrError("name", false), RuleParseOptionalAttr.ID},
+		{"custom", specerr.Wrap(custom, "foo"), "CUSTOM001"},
+		{"unknown", errors.New("foo"), RuleUnknown.ID},
+		{"nil", nil, RuleUnknown.ID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Lookup(tt.err); got.ID != tt.want {
+				t.Errorf("Lookup() = %v, want %v", got.ID, tt.want)
+			}
+		})
+	}
+}
+
+func TestRules(t *testing.T) {
+	rs := Rules()
+	ids := make(map[string]bool, len(rs))
+	for i, r := range rs {
+		if r.ID == "" || r.Name == "" || r.Severity == "" {
+			t.Errorf("Rules() incomplete rule %v", r)
+		}
+		if ids[r.ID] {
+			t.Errorf("Rules() duplicated ID %s", r.ID)
+		}
+		ids[r.ID] = true
+		if i > 0 && rs[i-1].ID > r.ID {
+			t.Errorf("Rules() not sorted at %d", i)
+		}
+	}
+}
+
+func TestRegisterRules(t *testing.T) {
+	a, b := errors.New("a"), errors.New("b")
+	RegisterRules(map[error]Rule{
+		a: {ID: "CUSTOM001", Name: "A", Severity: SeverityError},
+		b: {ID: "CUSTOM002", Name: "B", Severity: SeverityWarning},
+	})
+	defer func() {
+		rulesMu.Lock()
+		delete(rules, a)
+		delete(rules, b)
+		rulesMu.Unlock()
+	}()
+	if got := Lookup(specerr.Wrap(b, "foo")); got.ID != "CUSTOM002" || got.Severity != SeverityWarning {
+		t.Errorf("Lookup() = %v", got)
+	}
+}
@@ -0,0 +1,150 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting
+
+import (
+	"encoding/json"
+	"io"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	toolName     = "go3mf"
+	toolURI      = "https://github.com/hpinc/go3mf"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	Name                 string             `json:"name"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level Severity `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     Severity        `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Properties       *sarifProperties       `json:"properties,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+type sarifProperties struct {
+	Part string `json:"part"`
+}
+
+// WriteSARIF writes the SARIF encoding of the report to w.
+func (r *Report) WriteSARIF(w io.Writer) error {
+	return WriteSARIF(w, r)
+}
+
+// WriteSARIF writes a SARIF 2.1.0 log with a single run containing
+// the findings of all the reports.
+//
+// The file of each report is used as the artifact location,
+// the XPath as the logical location and the model part, if not
+// the root one, is stored in the location properties.
+func WriteSARIF(w io.Writer, reports ...*Report) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name: toolName, InformationURI: toolURI, Rules: []sarifRule{},
+		}},
+		Results: []sarifResult{},
+	}
+	ruleIndex := make(map[string]int)
+	var used []Rule
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			if _, ok := ruleIndex[f.RuleID]; !ok {
+				ruleIndex[f.RuleID] = -1
+				used = append(used, f.rule)
+			}
+		}
+	}
+	sortRules(used)
+	for i, rule := range used {
+		ruleIndex[rule.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID: rule.ID, Name: rule.Name, DefaultConfiguration: sarifConfiguration{Level: rule.Severity},
+		})
+	}
+	for _, r := range reports {
+		for _, f := range r.Findings {
+			run.Results = append(run.Results, newSarifResult(r.File, f, ruleIndex[f.RuleID]))
+		}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+func newSarifResult(file string, f Finding, ruleIndex int) sarifResult {
+	res := sarifResult{
+		RuleID:    f.RuleID,
+		RuleIndex: ruleIndex,
+		Level:     f.Severity,
+		Message:   sarifMessage{Text: f.Message},
+	}
+	var loc sarifLocation
+	if file != "" {
+		loc.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: file}}
+	}
+	if f.XPath != "" {
+		loc.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.XPath, Kind: "element"}}
+	}
+	if f.Path != "" {
+		loc.Properties = &sarifProperties{Part: f.Path}
+	}
+	if loc.PhysicalLocation != nil || loc.LogicalLocations != nil || loc.Properties != nil {
+		res.Locations = []sarifLocation{loc}
+	}
+	return res
+}
@@ -0,0 +1,72 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package reporting
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-test/deep"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	a := New("a.3mf", &specerr.List{Errors: []error{
+		specerr.WrapPath(specerr.WrapIndex(specerr.ErrMissingID, "beam", 3), "object", "/3D/other.model"),
+		specerr.WrapIndex(specerr.ErrDuplicatedID, "object", 1),
+	}})
+	b := New("b.3mf", specerr.ErrDuplicatedID)
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, a, b, New("c.3mf", nil)); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteSARIF() invalid json = %v", err)
+	}
+	want := sarifLog{
+		Schema: sarifSchema, Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{Name: toolName, InformationURI: toolURI, Rules: []sarifRule{
+				{ID: "CORE001", Name: "MissingID", DefaultConfiguration: sarifConfiguration{Level: SeverityError}},
+				{ID: "CORE002", Name: "DuplicatedID", DefaultConfiguration: sarifConfiguration{Level: SeverityError}},
+			}}},
+			Results: []sarifResult{
+				{RuleID: "CORE001", RuleIndex: 0, Level: SeverityError, Message: sarifMessage{Text: specerr.ErrMissingID.Error()},
+					Locations: []sarifLocation{{
+						PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "a.3mf"}},
+						LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "/object/beam[3]", Kind: "element"}},
+						Properties:       &sarifProperties{Part: "/3D/other.model"},
+					}}},
+				{RuleID: "CORE002", RuleIndex: 1, Level: SeverityError, Message: sarifMessage{Text: specerr.ErrDuplicatedID.Error()},
+					Locations: []sarifLocation{{
+						PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "a.3mf"}},
+						LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: "/object[1]", Kind: "element"}},
+					}}},
+				{RuleID: "CORE002", RuleIndex: 1, Level: SeverityError, Message: sarifMessage{Text: specerr.ErrDuplicatedID.Error()},
+					Locations: []sarifLocation{{
+						PhysicalLocation: &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: "b.3mf"}},
+					}}},
+			},
+		}},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("WriteSARIF() = %v", diff)
+	}
+}
+
+func TestWriteSARIF_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf); err != nil {
+		t.Fatalf("WriteSARIF() error = %v", err)
+	}
+	var got sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteSARIF() invalid json = %v", err)
+	}
+	if len(got.Runs) != 1 || got.Runs[0].Results == nil || got.Runs[0].Tool.Driver.Rules == nil {
+		t.Errorf("WriteSARIF() = %v, want a run with empty results and rules", got)
+	}
+}
@@ -0,0 +1,149 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/hpinc/go3mf"
+)
+
+// Decrypter decrypts the protected parts of a package.
+// It implements go3mf.PartReader:
+//
+//	d := securecontent.NewDecrypter(&securecontent.RSAKeyProvider{Key: key})
+//	r.PartReader = d
+type Decrypter struct {
+	provider     KeyProvider
+	keyStorePath string
+	keyStore     *KeyStore
+	keys         [][]byte
+	keyErrs      []error
+}
+
+// NewDecrypter returns a Decrypter that unwraps the keys using provider.
+func NewDecrypter(provider KeyProvider) *Decrypter {
+	return &Decrypter{provider: provider}
+}
+
+// KeyStore returns the keystore of the last opened package,
+// or nil if it does not have one.
+func (d *Decrypter) KeyStore() *KeyStore {
+	return d.keyStore
+}
+
+// Open implements go3mf.PartReader.
+func (d *Decrypter) Open(rels []go3mf.Relationship, open func(string) (io.ReadCloser, error)) error {
+	d.keyStorePath, d.keyStore, d.keys, d.keyErrs = "", nil, nil, nil
+	for _, r := range rels {
+		if r.Type != RelTypeKeyStore {
+			continue
+		}
+		rc, err := open(r.Path)
+		if err != nil {
+			return err
+		}
+		data, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+		if d.keyStore, err = UnmarshalKeyStore(data); err != nil {
+			return err
+		}
+		d.keyStorePath = r.Path
+		break
+	}
+	if d.keyStore == nil {
+		return nil
+	}
+	d.keys = make([][]byte, len(d.keyStore.ResourceDataGroups))
+	d.keyErrs = make([]error, len(d.keyStore.ResourceDataGroups))
+	for i, g := range d.keyStore.ResourceDataGroups {
+		d.keys[i], d.keyErrs[i] = d.unwrapKey(g)
+	}
+	return nil
+}
+
+func (d *Decrypter) unwrapKey(g ResourceDataGroup) ([]byte, error) {
+	err := ErrNoConsumerKey
+	for _, a := range g.AccessRights {
+		if a.ConsumerIndex < 0 || a.ConsumerIndex >= len(d.keyStore.Consumers) {
+			continue
+		}
+		var key []byte
+		key, err = d.provider.UnwrapKey(d.keyStore.Consumers[a.ConsumerIndex], a.KEKParams, a.CipherValue)
+		if err == nil {
+			return key, nil
+		}
+	}
+	return nil, err
+}
+
+// Reader implements go3mf.PartReader.
+// The keystore part is skipped and the protected parts are decrypted.
+func (d *Decrypter) Reader(name string, r io.Reader) (io.Reader, error) {
+	if d.keyStore == nil {
+		return r, nil
+	}
+	if samePart(name, d.keyStorePath) {
+		return nil, nil
+	}
+	i, rd, ok := d.keyStore.FindResourceData(name)
+	if !ok {
+		return r, nil
+	}
+	if d.keyErrs[i] != nil {
+		return nil, d.keyErrs[i]
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err = decrypt(d.keys[i], &rd.CEKParams, data)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+func decrypt(key []byte, p *CEKParams, data []byte) ([]byte, error) {
+	if p.EncryptionAlgorithm != AlgorithmAES256GCM {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.IV) != gcm.NonceSize() {
+		return nil, fmt.Errorf("securecontent: invalid iv length %d", len(p.IV))
+	}
+	data, err = gcm.Open(nil, p.IV, append(data, p.Tag...), p.AAD)
+	if err != nil {
+		return nil, err
+	}
+	if p.Compression == CompressionDeflate {
+		fr := flate.NewReader(bytes.NewReader(data))
+		defer fr.Close()
+		return ioutil.ReadAll(fr)
+	}
+	return data, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("securecontent: invalid key length %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
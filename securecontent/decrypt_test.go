@@ -0,0 +1,117 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func newProtectedPackage(t *testing.T, c Compression) []byte {
+	t.Helper()
+	e := &Encrypter{Compression: c}
+	e.AddConsumer("printer", "", &testKey.PublicKey)
+	if err := e.Protect([]string{"printer"}, "/3D/3dmodel.model", "/Metadata/notes.txt"); err != nil {
+		t.Fatal(err)
+	}
+	return encodeProtected(t, e)
+}
+
+func decode(data []byte, r go3mf.PartReader) (*go3mf.Model, error) {
+	d := go3mf.NewDecoder(bytes.NewReader(data), int64(len(data)))
+	d.PartReader = r
+	m := new(go3mf.Model)
+	return m, d.Decode(m)
+}
+
+func TestDecrypter(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionDeflate} {
+		t.Run(c.String(), func(t *testing.T) {
+			data := newProtectedPackage(t, c)
+			d := NewDecrypter(&RSAKeyProvider{Key: testKey})
+			m, err := decode(data, d)
+			if err != nil {
+				t.Fatalf("go3mf.Decode() error = %v", err)
+			}
+			if d.KeyStore() == nil {
+				t.Error("Decrypter.KeyStore() = nil")
+			}
+			obj, ok := m.FindObject("/3D/3dmodel.model", 1)
+			if !ok || obj.Name != "secret" || len(obj.Mesh.Triangles.Triangle) != 4 {
+				t.Errorf("Decrypter: unexpected model %v", m.Resources.Objects)
+			}
+			if len(m.Attachments) != 1 || len(m.RootRelationships) != 1 {
+				t.Fatalf("Decrypter: unexpected attachments %v, %v", m.Attachments, m.RootRelationships)
+			}
+			b, _ := ioutil.ReadAll(m.Attachments[0].Stream)
+			if string(b) != "secret notes" {
+				t.Errorf("Decrypter: attachment = %s", b)
+			}
+		})
+	}
+}
+
+func TestDecrypter_noKey(t *testing.T) {
+	data := newProtectedPackage(t, CompressionNone)
+	other, _ := rsa.GenerateKey(rand.Reader, 1024)
+	tests := []struct {
+		name     string
+		provider KeyProvider
+	}{
+		{"otherConsumer", &RSAKeyProvider{ConsumerID: "viewer", Key: testKey}},
+		{"otherKey", &RSAKeyProvider{Key: other}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decode(data, NewDecrypter(tt.provider)); err == nil {
+				t.Error("go3mf.Decode() expected error")
+			}
+		})
+	}
+	if _, err := decode(data, NewDecrypter(tests[0].provider)); !errors.Is(err, ErrNoConsumerKey) {
+		t.Errorf("go3mf.Decode() error = %v, want %v", err, ErrNoConsumerKey)
+	}
+}
+
+func TestDecrypter_unprotected(t *testing.T) {
+	data := encodeProtected(t, new(Encrypter))
+	d := NewDecrypter(&RSAKeyProvider{Key: testKey})
+	m, err := decode(data, d)
+	if err != nil {
+		t.Fatalf("go3mf.Decode() error = %v", err)
+	}
+	if d.KeyStore() != nil {
+		t.Error("Decrypter.KeyStore() expected nil")
+	}
+	if _, ok := m.FindObject("/3D/3dmodel.model", 1); !ok {
+		t.Error("Decrypter: object not decoded")
+	}
+}
+
+func Test_decrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	tests := []struct {
+		name string
+		key  []byte
+		p    CEKParams
+	}{
+		{"algorithm", key, CEKParams{EncryptionAlgorithm: "aes128-cbc"}},
+		{"keyLength", key[:16], CEKParams{EncryptionAlgorithm: AlgorithmAES256GCM}},
+		{"ivLength", key, CEKParams{EncryptionAlgorithm: AlgorithmAES256GCM, IV: []byte{1}}},
+		{"tag", key, CEKParams{EncryptionAlgorithm: AlgorithmAES256GCM, IV: make([]byte, 12), Tag: make([]byte, 16)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decrypt(tt.key, &tt.p, []byte("data")); err == nil {
+				t.Error("decrypt() expected error")
+			}
+		})
+	}
+}
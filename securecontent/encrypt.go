@@ -0,0 +1,208 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/uuid"
+)
+
+// Encrypter encrypts the protected parts of a package.
+// It implements go3mf.PartWriter:
+//
+//	var e securecontent.Encrypter
+//	e.AddConsumer("printer", "", &key.PublicKey)
+//	e.Protect([]string{"printer"}, "/3D/3dmodel.model")
+//	w.PartWriter = &e
+type Encrypter struct {
+	// Rand is the source of the keys and IVs. Defaults to crypto/rand.Reader.
+	Rand io.Reader
+	// Compression applied to the parts before encrypting them.
+	Compression Compression
+	// KEKParams used to wrap the keys. Defaults to RSA-OAEP with SHA-1.
+	KEKParams KEKParams
+
+	keyStore KeyStore
+	keys     []*rsa.PublicKey
+	ceks     [][]byte
+	written  map[string]bool
+}
+
+// KeyStore returns the keystore built by the encrypter.
+// It is complete once the package is closed.
+func (e *Encrypter) KeyStore() *KeyStore {
+	return &e.keyStore
+}
+
+// AddConsumer registers a consumer that will be able to decrypt the protected parts.
+func (e *Encrypter) AddConsumer(id, keyID string, key *rsa.PublicKey) error {
+	if _, ok := e.keyStore.FindConsumer(id); ok {
+		return ErrDuplicatedConsumer
+	}
+	pem, err := MarshalPublicKey(key)
+	if err != nil {
+		return err
+	}
+	e.keyStore.Consumers = append(e.keyStore.Consumers, Consumer{ID: id, KeyID: keyID, KeyValue: pem})
+	e.keys = append(e.keys, key)
+	return nil
+}
+
+// Protect encrypts the parts stored in paths with a new key
+// that is granted to the consumers identified by consumerIDs.
+func (e *Encrypter) Protect(consumerIDs []string, paths ...string) error {
+	g := ResourceDataGroup{KeyUUID: uuid.New()}
+	for _, id := range consumerIDs {
+		i, ok := e.keyStore.FindConsumer(id)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnknownConsumer, id)
+		}
+		g.AccessRights = append(g.AccessRights, AccessRight{ConsumerIndex: i})
+	}
+	for _, path := range paths {
+		if _, _, ok := e.keyStore.FindResourceData(path); ok {
+			return fmt.Errorf("%w: %s", ErrAlreadyProtected, path)
+		}
+		g.ResourceData = append(g.ResourceData, ResourceData{Path: path, CEKParams: CEKParams{
+			EncryptionAlgorithm: AlgorithmAES256GCM,
+			Compression:         e.Compression,
+		}})
+	}
+	cek := make([]byte, 32)
+	if _, err := io.ReadFull(e.random(), cek); err != nil {
+		return err
+	}
+	e.keyStore.ResourceDataGroups = append(e.keyStore.ResourceDataGroups, g)
+	e.ceks = append(e.ceks, cek)
+	return nil
+}
+
+// Writer implements go3mf.PartWriter.
+func (e *Encrypter) Writer(name, _ string, w io.Writer) (io.WriteCloser, error) {
+	i, rd, ok := e.keyStore.FindResourceData(name)
+	if !ok {
+		return nopCloser{w}, nil
+	}
+	return &encryptedPart{e: e, w: w, key: e.ceks[i], params: &rd.CEKParams, name: name}, nil
+}
+
+// Close implements go3mf.PartWriter.
+// It wraps the keys and writes the keystore part.
+func (e *Encrypter) Close(create func(string, string) (io.Writer, error), addRel func(go3mf.Relationship)) error {
+	if len(e.keyStore.ResourceDataGroups) == 0 {
+		return nil
+	}
+	for _, g := range e.keyStore.ResourceDataGroups {
+		for _, rd := range g.ResourceData {
+			if !e.written[normalizePart(rd.Path)] {
+				return fmt.Errorf("%w: %s", ErrMissingPart, rd.Path)
+			}
+		}
+	}
+	params := e.KEKParams
+	if params.WrappingAlgorithm == "" {
+		params.WrappingAlgorithm = AlgorithmRSAOAEPP
+	}
+	for i := range e.keyStore.ResourceDataGroups {
+		g := &e.keyStore.ResourceDataGroups[i]
+		for j := range g.AccessRights {
+			a := &g.AccessRights[j]
+			cipher, err := wrapKey(e.random(), e.keys[a.ConsumerIndex], params, e.ceks[i])
+			if err != nil {
+				return err
+			}
+			a.KEKParams, a.CipherValue = params, cipher
+		}
+	}
+	if e.keyStore.UUID == "" {
+		e.keyStore.UUID = uuid.New()
+	}
+	data, err := MarshalKeyStore(&e.keyStore)
+	if err != nil {
+		return err
+	}
+	w, err := create(KeyStorePath, ContentTypeKeyStore)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	addRel(go3mf.Relationship{Path: KeyStorePath, Type: RelTypeKeyStore})
+	return nil
+}
+
+func (e *Encrypter) random() io.Reader {
+	if e.Rand == nil {
+		return rand.Reader
+	}
+	return e.Rand
+}
+
+func (e *Encrypter) markWritten(name string) {
+	if e.written == nil {
+		e.written = make(map[string]bool)
+	}
+	e.written[normalizePart(name)] = true
+}
+
+type encryptedPart struct {
+	e      *Encrypter
+	w      io.Writer
+	key    []byte
+	params *CEKParams
+	name   string
+	buf    bytes.Buffer
+}
+
+func (p *encryptedPart) Write(b []byte) (int, error) {
+	return p.buf.Write(b)
+}
+
+func (p *encryptedPart) Close() error {
+	data := p.buf.Bytes()
+	if p.params.Compression == CompressionDeflate {
+		var compressed bytes.Buffer
+		fw, err := flate.NewWriter(&compressed, flate.DefaultCompression)
+		if err != nil {
+			return err
+		}
+		if _, err = fw.Write(data); err != nil {
+			return err
+		}
+		if err = fw.Close(); err != nil {
+			return err
+		}
+		data = compressed.Bytes()
+	}
+	gcm, err := newGCM(p.key)
+	if err != nil {
+		return err
+	}
+	iv := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(p.e.random(), iv); err != nil {
+		return err
+	}
+	sealed := gcm.Seal(nil, iv, data, p.params.AAD)
+	n := len(sealed) - gcm.Overhead()
+	p.params.IV, p.params.Tag = iv, sealed[n:]
+	if _, err = p.w.Write(sealed[:n]); err != nil {
+		return err
+	}
+	p.e.markWritten(p.name)
+	return nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error { return nil }
@@ -0,0 +1,145 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func newTestModel() *go3mf.Model {
+	m := &go3mf.Model{Path: "/3D/3dmodel.model"}
+	m.Resources.Objects = append(m.Resources.Objects, &go3mf.Object{
+		ID: 1, Name: "secret",
+		Mesh: &go3mf.Mesh{
+			Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}},
+			Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+				{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+			}},
+		},
+	})
+	m.Build.Items = append(m.Build.Items, &go3mf.Item{ObjectID: 1})
+	m.Attachments = append(m.Attachments, go3mf.Attachment{
+		Path: "/Metadata/notes.txt", ContentType: "text/plain", Stream: bytes.NewBufferString("secret notes"),
+	})
+	m.RootRelationships = append(m.RootRelationships, go3mf.Relationship{Path: "/Metadata/notes.txt", Type: "notes"})
+	return m
+}
+
+func encodeProtected(t *testing.T, e *Encrypter) []byte {
+	t.Helper()
+	buff := new(bytes.Buffer)
+	enc := go3mf.NewEncoder(buff)
+	enc.PartWriter = e
+	if err := enc.Encode(newTestModel()); err != nil {
+		t.Fatalf("go3mf.Encode() error = %v", err)
+	}
+	return buff.Bytes()
+}
+
+func readPart(t *testing.T, data []byte, name string) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range zr.File {
+		if strings.EqualFold("/"+f.Name, name) {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rc.Close()
+			b, err := ioutil.ReadAll(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		}
+	}
+	return nil
+}
+
+func TestEncrypter(t *testing.T) {
+	for _, c := range []Compression{CompressionNone, CompressionDeflate} {
+		t.Run(c.String(), func(t *testing.T) {
+			e := &Encrypter{Compression: c}
+			if err := e.AddConsumer("printer", "key1", &testKey.PublicKey); err != nil {
+				t.Fatalf("Encrypter.AddConsumer() error = %v", err)
+			}
+			if err := e.Protect([]string{"printer"}, "/3D/3dmodel.model", "/Metadata/notes.txt"); err != nil {
+				t.Fatalf("Encrypter.Protect() error = %v", err)
+			}
+			data := encodeProtected(t, e)
+			if part := readPart(t, data, "/3D/3dmodel.model"); bytes.Contains(part, []byte("secret")) {
+				t.Error("Encrypter: model part is not encrypted")
+			}
+			if part := readPart(t, data, "/Metadata/notes.txt"); bytes.Contains(part, []byte("secret")) {
+				t.Error("Encrypter: attachment is not encrypted")
+			}
+			ks, err := UnmarshalKeyStore(readPart(t, data, KeyStorePath))
+			if err != nil {
+				t.Fatalf("UnmarshalKeyStore() error = %v", err)
+			}
+			if ks.UUID == "" || len(ks.ResourceDataGroups) != 1 || len(ks.ResourceDataGroups[0].AccessRights) != 1 {
+				t.Errorf("Encrypter: unexpected keystore %v", ks)
+			}
+			rd := ks.ResourceDataGroups[0].ResourceData[0]
+			if len(rd.CEKParams.IV) != 12 || len(rd.CEKParams.Tag) != 16 || rd.CEKParams.Compression != c {
+				t.Errorf("Encrypter: unexpected cek params %v", rd.CEKParams)
+			}
+		})
+	}
+}
+
+func TestEncrypter_AddConsumer(t *testing.T) {
+	var e Encrypter
+	if err := e.AddConsumer("a", "", &testKey.PublicKey); err != nil {
+		t.Fatalf("Encrypter.AddConsumer() error = %v", err)
+	}
+	if err := e.AddConsumer("a", "", &testKey.PublicKey); !errors.Is(err, ErrDuplicatedConsumer) {
+		t.Errorf("Encrypter.AddConsumer() error = %v, want %v", err, ErrDuplicatedConsumer)
+	}
+}
+
+func TestEncrypter_Protect(t *testing.T) {
+	var e Encrypter
+	e.AddConsumer("a", "", &testKey.PublicKey)
+	if err := e.Protect([]string{"b"}, "/3D/3dmodel.model"); !errors.Is(err, ErrUnknownConsumer) {
+		t.Errorf("Encrypter.Protect() error = %v, want %v", err, ErrUnknownConsumer)
+	}
+	if err := e.Protect([]string{"a"}, "/3D/3dmodel.model"); err != nil {
+		t.Fatalf("Encrypter.Protect() error = %v", err)
+	}
+	if err := e.Protect([]string{"a"}, "3d/3DMODEL.model"); !errors.Is(err, ErrAlreadyProtected) {
+		t.Errorf("Encrypter.Protect() error = %v, want %v", err, ErrAlreadyProtected)
+	}
+}
+
+func TestEncrypter_missingPart(t *testing.T) {
+	var e Encrypter
+	e.AddConsumer("a", "", &testKey.PublicKey)
+	e.Protect([]string{"a"}, "/3D/missing.model")
+	enc := go3mf.NewEncoder(new(bytes.Buffer))
+	enc.PartWriter = &e
+	if err := enc.Encode(newTestModel()); !errors.Is(err, ErrMissingPart) {
+		t.Errorf("go3mf.Encode() error = %v, want %v", err, ErrMissingPart)
+	}
+}
+
+func TestEncrypter_noProtection(t *testing.T) {
+	data := encodeProtected(t, new(Encrypter))
+	if readPart(t, data, KeyStorePath) != nil {
+		t.Error("Encrypter: unexpected keystore")
+	}
+	if part := readPart(t, data, "/3D/3dmodel.model"); !bytes.Contains(part, []byte("secret")) {
+		t.Error("Encrypter: unprotected part has been modified")
+	}
+}
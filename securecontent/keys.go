@@ -0,0 +1,115 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"hash"
+	"io"
+)
+
+// A KeyProvider unwraps the content encryption keys of the consumers it owns.
+type KeyProvider interface {
+	// UnwrapKey returns the content encryption key wrapped for consumer c.
+	// It returns ErrNoConsumerKey if it does not own the consumer key.
+	UnwrapKey(c Consumer, params KEKParams, wrapped []byte) ([]byte, error)
+}
+
+// RSAKeyProvider unwraps keys using the RSA private key of a consumer.
+// If ConsumerID is empty the key is tried with every consumer.
+type RSAKeyProvider struct {
+	ConsumerID string
+	Key        *rsa.PrivateKey
+}
+
+// UnwrapKey implements KeyProvider.
+func (p *RSAKeyProvider) UnwrapKey(c Consumer, params KEKParams, wrapped []byte) ([]byte, error) {
+	if p.ConsumerID != "" && p.ConsumerID != c.ID {
+		return nil, ErrNoConsumerKey
+	}
+	h, err := kekHash(params)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.DecryptOAEP(h, nil, p.Key, wrapped, nil)
+}
+
+// MarshalPublicKey returns the PEM encoding of key,
+// suitable for Consumer.KeyValue.
+func MarshalPublicKey(key *rsa.PublicKey) (string, error) {
+	b, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: b})), nil
+}
+
+// ParsePublicKey parses a PEM encoded RSA public key.
+func ParsePublicKey(s string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(s))
+	if block == nil {
+		return nil, errors.New("securecontent: invalid PEM public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	return rsaKey, nil
+}
+
+func wrapKey(random io.Reader, key *rsa.PublicKey, params KEKParams, cek []byte) ([]byte, error) {
+	h, err := kekHash(params)
+	if err != nil {
+		return nil, err
+	}
+	if random == nil {
+		random = rand.Reader
+	}
+	return rsa.EncryptOAEP(h, random, key, cek, nil)
+}
+
+// kekHash returns the hash used by RSA-OAEP.
+// Go uses the same hash for the digest and the mask generation,
+// so both must match.
+func kekHash(params KEKParams) (hash.Hash, error) {
+	switch params.WrappingAlgorithm {
+	case AlgorithmRSAOAEP, AlgorithmRSAOAEPP:
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	digest := crypto.SHA1
+	switch params.DigestMethod {
+	case "", AlgorithmSHA1:
+	case AlgorithmSHA256:
+		digest = crypto.SHA256
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	mgf := crypto.SHA1
+	switch params.MGFAlgorithm {
+	case "", AlgorithmMGF1SHA1:
+	case AlgorithmMGF1SHA256:
+		mgf = crypto.SHA256
+	default:
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if digest != mgf {
+		return nil, ErrUnsupportedAlgorithm
+	}
+	if digest == crypto.SHA256 {
+		return sha256.New(), nil
+	}
+	return sha1.New(), nil
+}
@@ -0,0 +1,69 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+var testKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+func TestParsePublicKey(t *testing.T) {
+	s, err := MarshalPublicKey(&testKey.PublicKey)
+	if err != nil {
+		t.Fatalf("MarshalPublicKey() error = %v", err)
+	}
+	got, err := ParsePublicKey(s)
+	if err != nil {
+		t.Fatalf("ParsePublicKey() error = %v", err)
+	}
+	if got.N.Cmp(testKey.N) != 0 || got.E != testKey.E {
+		t.Error("ParsePublicKey() returned a different key")
+	}
+	if _, err := ParsePublicKey("invalid"); err == nil {
+		t.Error("ParsePublicKey() expected error")
+	}
+}
+
+func TestRSAKeyProvider_UnwrapKey(t *testing.T) {
+	cek := bytes.Repeat([]byte{7}, 32)
+	tests := []struct {
+		name    string
+		id      string
+		params  KEKParams
+		wantErr error
+	}{
+		{"mgf1p", "", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEPP}, nil},
+		{"sha1", "c", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEP, MGFAlgorithm: AlgorithmMGF1SHA1, DigestMethod: AlgorithmSHA1}, nil},
+		{"sha256", "c", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEP, MGFAlgorithm: AlgorithmMGF1SHA256, DigestMethod: AlgorithmSHA256}, nil},
+		{"otherConsumer", "other", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEPP}, ErrNoConsumerKey},
+		{"wrapping", "", KEKParams{WrappingAlgorithm: "rsa-1_5"}, ErrUnsupportedAlgorithm},
+		{"digest", "", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEP, DigestMethod: "md5"}, ErrUnsupportedAlgorithm},
+		{"mgf", "", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEP, MGFAlgorithm: "mgf1md5"}, ErrUnsupportedAlgorithm},
+		{"mixed", "", KEKParams{WrappingAlgorithm: AlgorithmRSAOAEP, MGFAlgorithm: AlgorithmMGF1SHA1, DigestMethod: AlgorithmSHA256}, ErrUnsupportedAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped, err := wrapKey(nil, &testKey.PublicKey, tt.params, cek)
+			if err != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("wrapKey() error = %v", err)
+				}
+				return
+			}
+			p := &RSAKeyProvider{ConsumerID: tt.id, Key: testKey}
+			got, err := p.UnwrapKey(Consumer{ID: "c"}, tt.params, wrapped)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RSAKeyProvider.UnwrapKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, cek) {
+				t.Errorf("RSAKeyProvider.UnwrapKey() = %v, want %v", got, cek)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+)
+
+const nsXMLEnc = "http://www.w3.org/2001/04/xmlenc#"
+
+type xmlKeyStore struct {
+	XMLName            xml.Name               `xml:"http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07 keystore"`
+	UUID               string                 `xml:"UUID,attr"`
+	Consumers          []xmlConsumer          `xml:"consumer"`
+	ResourceDataGroups []xmlResourceDataGroup `xml:"resourcedatagroup"`
+}
+
+type xmlConsumer struct {
+	ID       string `xml:"consumerid,attr"`
+	KeyID    string `xml:"keyid,attr,omitempty"`
+	KeyValue string `xml:"keyvalue,omitempty"`
+}
+
+type xmlResourceDataGroup struct {
+	KeyUUID      string            `xml:"keyuuid,attr"`
+	AccessRights []xmlAccessRight  `xml:"accessright"`
+	ResourceData []xmlResourceData `xml:"resourcedata"`
+}
+
+type xmlAccessRight struct {
+	ConsumerIndex int           `xml:"consumerindex,attr"`
+	KEKParams     xmlKEKParams  `xml:"kekparams"`
+	CipherData    xmlCipherData `xml:"cipherdata"`
+}
+
+type xmlKEKParams struct {
+	WrappingAlgorithm string `xml:"wrappingalgorithm,attr"`
+	MGFAlgorithm      string `xml:"mgfalgorithm,attr,omitempty"`
+	DigestMethod      string `xml:"digestmethod,attr,omitempty"`
+}
+
+type xmlCipherData struct {
+	CipherValue string `xml:"http://www.w3.org/2001/04/xmlenc# CipherValue"`
+}
+
+type xmlResourceData struct {
+	Path      string       `xml:"path,attr"`
+	CEKParams xmlCEKParams `xml:"cekparams"`
+}
+
+type xmlCEKParams struct {
+	EncryptionAlgorithm string `xml:"encryptionalgorithm,attr"`
+	Compression         string `xml:"compression,attr,omitempty"`
+	IV                  string `xml:"iv"`
+	Tag                 string `xml:"tag"`
+	AAD                 string `xml:"aad,omitempty"`
+}
+
+// MarshalKeyStore returns the XML encoding of k.
+func MarshalKeyStore(k *KeyStore) ([]byte, error) {
+	x := xmlKeyStore{UUID: k.UUID}
+	for _, c := range k.Consumers {
+		x.Consumers = append(x.Consumers, xmlConsumer(c))
+	}
+	for _, g := range k.ResourceDataGroups {
+		xg := xmlResourceDataGroup{KeyUUID: g.KeyUUID}
+		for _, a := range g.AccessRights {
+			xg.AccessRights = append(xg.AccessRights, xmlAccessRight{
+				ConsumerIndex: a.ConsumerIndex,
+				KEKParams:     xmlKEKParams(a.KEKParams),
+				CipherData:    xmlCipherData{CipherValue: encodeBase64(a.CipherValue)},
+			})
+		}
+		for _, r := range g.ResourceData {
+			p := r.CEKParams
+			xr := xmlResourceData{Path: r.Path, CEKParams: xmlCEKParams{
+				EncryptionAlgorithm: p.EncryptionAlgorithm,
+				IV:                  encodeBase64(p.IV),
+				Tag:                 encodeBase64(p.Tag),
+				AAD:                 encodeBase64(p.AAD),
+			}}
+			if p.Compression != CompressionNone {
+				xr.CEKParams.Compression = p.Compression.String()
+			}
+			xg.ResourceData = append(xg.ResourceData, xr)
+		}
+		x.ResourceDataGroups = append(x.ResourceDataGroups, xg)
+	}
+	b, err := xml.MarshalIndent(&x, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// UnmarshalKeyStore parses the XML encoded keystore.
+func UnmarshalKeyStore(data []byte) (*KeyStore, error) {
+	var x xmlKeyStore
+	if err := xml.Unmarshal(data, &x); err != nil {
+		return nil, err
+	}
+	k := &KeyStore{UUID: x.UUID}
+	for _, c := range x.Consumers {
+		k.Consumers = append(k.Consumers, Consumer(c))
+	}
+	for _, xg := range x.ResourceDataGroups {
+		g := ResourceDataGroup{KeyUUID: xg.KeyUUID}
+		for _, xa := range xg.AccessRights {
+			cipher, err := decodeBase64(xa.CipherData.CipherValue)
+			if err != nil {
+				return nil, err
+			}
+			g.AccessRights = append(g.AccessRights, AccessRight{
+				ConsumerIndex: xa.ConsumerIndex,
+				KEKParams:     KEKParams(xa.KEKParams),
+				CipherValue:   cipher,
+			})
+		}
+		for _, xr := range xg.ResourceData {
+			r := ResourceData{Path: xr.Path, CEKParams: CEKParams{EncryptionAlgorithm: xr.CEKParams.EncryptionAlgorithm}}
+			if xr.CEKParams.Compression != "" {
+				var ok bool
+				if r.CEKParams.Compression, ok = newCompression(xr.CEKParams.Compression); !ok {
+					return nil, ErrUnsupportedAlgorithm
+				}
+			}
+			var err error
+			if r.CEKParams.IV, err = decodeBase64(xr.CEKParams.IV); err != nil {
+				return nil, err
+			}
+			if r.CEKParams.Tag, err = decodeBase64(xr.CEKParams.Tag); err != nil {
+				return nil, err
+			}
+			if r.CEKParams.AAD, err = decodeBase64(xr.CEKParams.AAD); err != nil {
+				return nil, err
+			}
+			g.ResourceData = append(g.ResourceData, r)
+		}
+		k.ResourceDataGroups = append(k.ResourceDataGroups, g)
+	}
+	return k, nil
+}
+
+func encodeBase64(b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,101 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+)
+
+func TestMarshalKeyStore(t *testing.T) {
+	want := &KeyStore{
+		UUID:      "b7a8c3a1-4a5f-4f0c-9a3b-0d3e6a2f6c11",
+		Consumers: []Consumer{{ID: "printer", KeyID: "key1", KeyValue: "pem"}, {ID: "viewer"}},
+		ResourceDataGroups: []ResourceDataGroup{{
+			KeyUUID: "2d4f4f0e-8c58-4f8b-9a4c-2f8d3b5c1e22",
+			AccessRights: []AccessRight{
+				{ConsumerIndex: 0, KEKParams: KEKParams{WrappingAlgorithm: AlgorithmRSAOAEPP}, CipherValue: []byte{1, 2, 3}},
+				{ConsumerIndex: 1, KEKParams: KEKParams{
+					WrappingAlgorithm: AlgorithmRSAOAEP, MGFAlgorithm: AlgorithmMGF1SHA256, DigestMethod: AlgorithmSHA256,
+				}, CipherValue: []byte{4, 5}},
+			},
+			ResourceData: []ResourceData{
+				{Path: "/3D/3dmodel.model", CEKParams: CEKParams{
+					EncryptionAlgorithm: AlgorithmAES256GCM, Compression: CompressionDeflate,
+					IV: []byte{1, 2}, Tag: []byte{3, 4}, AAD: []byte{5},
+				}},
+				{Path: "/3D/other.model", CEKParams: CEKParams{
+					EncryptionAlgorithm: AlgorithmAES256GCM, IV: []byte{6}, Tag: []byte{7},
+				}},
+			},
+		}},
+	}
+	data, err := MarshalKeyStore(want)
+	if err != nil {
+		t.Fatalf("MarshalKeyStore() error = %v", err)
+	}
+	got, err := UnmarshalKeyStore(data)
+	if err != nil {
+		t.Fatalf("UnmarshalKeyStore() error = %v", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("MarshalKeyStore() = %v", diff)
+	}
+}
+
+func TestUnmarshalKeyStore(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+	<keystore xmlns="http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07" xmlns:xenc="http://www.w3.org/2001/04/xmlenc#" UUID="ks">
+		<consumer consumerid="printer" keyid="k1"><keyvalue>pem</keyvalue></consumer>
+		<resourcedatagroup keyuuid="g1">
+			<accessright consumerindex="0">
+				<kekparams wrappingalgorithm="http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p"/>
+				<cipherdata><xenc:CipherValue>AQID</xenc:CipherValue></cipherdata>
+			</accessright>
+			<resourcedata path="/3D/3dmodel.model">
+				<cekparams encryptionalgorithm="http://www.w3.org/2009/xmlenc11#aes256-gcm" compression="deflate">
+					<iv>AQI=</iv><tag>AwQ=</tag>
+				</cekparams>
+			</resourcedata>
+		</resourcedatagroup>
+	</keystore>`
+	want := &KeyStore{
+		UUID:      "ks",
+		Consumers: []Consumer{{ID: "printer", KeyID: "k1", KeyValue: "pem"}},
+		ResourceDataGroups: []ResourceDataGroup{{
+			KeyUUID:      "g1",
+			AccessRights: []AccessRight{{KEKParams: KEKParams{WrappingAlgorithm: AlgorithmRSAOAEPP}, CipherValue: []byte{1, 2, 3}}},
+			ResourceData: []ResourceData{{Path: "/3D/3dmodel.model", CEKParams: CEKParams{
+				EncryptionAlgorithm: AlgorithmAES256GCM, Compression: CompressionDeflate, IV: []byte{1, 2}, Tag: []byte{3, 4},
+			}}},
+		}},
+	}
+	got, err := UnmarshalKeyStore([]byte(data))
+	if err != nil {
+		t.Fatalf("UnmarshalKeyStore() error = %v", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("UnmarshalKeyStore() = %v", diff)
+	}
+}
+
+func TestUnmarshalKeyStore_error(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"xml", `<keystore`},
+		{"cipher", `<keystore xmlns="http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07"><resourcedatagroup><accessright><cipherdata><CipherValue xmlns="http://www.w3.org/2001/04/xmlenc#">#</CipherValue></cipherdata></accessright></resourcedatagroup></keystore>`},
+		{"compression", `<keystore xmlns="http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07"><resourcedatagroup><resourcedata><cekparams compression="zip"/></resourcedata></resourcedatagroup></keystore>`},
+		{"iv", `<keystore xmlns="http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07"><resourcedatagroup><resourcedata><cekparams><iv>#</iv></cekparams></resourcedata></resourcedatagroup></keystore>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := UnmarshalKeyStore([]byte(tt.data)); err == nil {
+				t.Error("UnmarshalKeyStore() expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package securecontent implements the 3MF Secure Content extension.
+//
+// Model parts and attachments are encrypted with AES-256-GCM using a content
+// encryption key (CEK) per resource data group. The CEK is wrapped with
+// the RSA public key of each consumer and stored in the keystore part.
+//
+// Decrypter and Encrypter plug into go3mf.Decoder and go3mf.Encoder
+// through their PartReader and PartWriter fields.
+package securecontent
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+const (
+	// Namespace is the canonical name of this extension.
+	Namespace = "http://schemas.microsoft.com/3dmanufacturing/securecontent/2019/07"
+	// RelTypeKeyStore is the canonical keystore relationship type.
+	RelTypeKeyStore = "http://schemas.microsoft.com/3dmanufacturing/2019/07/keystore"
+	// ContentTypeKeyStore is the canonical keystore content type.
+	ContentTypeKeyStore = "application/vnd.ms-package.3dmanufacturing-keystore+xml"
+	// KeyStorePath is the default keystore part name.
+	KeyStorePath = "/Secure/keystore.xml"
+)
+
+// Supported algorithms.
+const (
+	AlgorithmAES256GCM  = "http://www.w3.org/2009/xmlenc11#aes256-gcm"
+	AlgorithmRSAOAEP    = "http://www.w3.org/2009/xmlenc11#rsa-oaep"
+	AlgorithmRSAOAEPP   = "http://www.w3.org/2001/04/xmlenc#rsa-oaep-mgf1p"
+	AlgorithmMGF1SHA1   = "http://www.w3.org/2009/xmlenc11#mgf1sha1"
+	AlgorithmMGF1SHA256 = "http://www.w3.org/2009/xmlenc11#mgf1sha256"
+	AlgorithmSHA1       = "http://www.w3.org/2000/09/xmldsig#sha1"
+	AlgorithmSHA256     = "http://www.w3.org/2001/04/xmlenc#sha256"
+)
+
+var DefaultExtension = go3mf.Extension{
+	Namespace:  Namespace,
+	LocalName:  "sc",
+	IsRequired: false,
+}
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("securecontent: unsupported algorithm")
+	ErrNoConsumerKey        = errors.New("securecontent: no key available for the consumer")
+	ErrUnknownConsumer      = errors.New("securecontent: unknown consumer")
+	ErrDuplicatedConsumer   = errors.New("securecontent: duplicated consumer")
+	ErrAlreadyProtected     = errors.New("securecontent: part is already protected")
+	ErrMissingPart          = errors.New("securecontent: protected part has not been written")
+)
+
+func init() {
+	spec.Register(Namespace, Spec{})
+}
+
+// Spec allows declaring the extension as required.
+// The keystore is not part of the model, so there is nothing to decode.
+type Spec struct{}
+
+func (Spec) NewAttrGroup(xml.Name) spec.AttrGroup {
+	return nil
+}
+
+func (Spec) NewElementDecoder(xml.Name) spec.GetterElementDecoder {
+	return nil
+}
+
+// Compression defines the compression applied before encryption.
+type Compression uint8
+
+// Supported compressions.
+const (
+	CompressionNone Compression = iota
+	CompressionDeflate
+)
+
+func newCompression(s string) (c Compression, ok bool) {
+	c, ok = map[string]Compression{
+		"none":    CompressionNone,
+		"deflate": CompressionDeflate,
+	}[s]
+	return
+}
+
+func (c Compression) String() string {
+	return map[Compression]string{
+		CompressionNone:    "none",
+		CompressionDeflate: "deflate",
+	}[c]
+}
+
+// Consumer identifies an entity that can decrypt the package.
+// KeyValue holds the PEM encoded RSA public key.
+type Consumer struct {
+	ID       string
+	KeyID    string
+	KeyValue string
+}
+
+// KEKParams defines how a content encryption key is wrapped.
+// Empty MGFAlgorithm and DigestMethod default to SHA-1.
+type KEKParams struct {
+	WrappingAlgorithm string
+	MGFAlgorithm      string
+	DigestMethod      string
+}
+
+// AccessRight contains the content encryption key of a
+// resource data group wrapped for a consumer.
+type AccessRight struct {
+	ConsumerIndex int
+	KEKParams     KEKParams
+	CipherValue   []byte
+}
+
+// CEKParams defines how a part is encrypted.
+type CEKParams struct {
+	EncryptionAlgorithm string
+	Compression         Compression
+	IV                  []byte
+	Tag                 []byte
+	AAD                 []byte
+}
+
+// ResourceData references an encrypted part.
+type ResourceData struct {
+	Path      string
+	CEKParams CEKParams
+}
+
+// ResourceDataGroup groups the parts encrypted with the same key.
+type ResourceDataGroup struct {
+	KeyUUID      string
+	AccessRights []AccessRight
+	ResourceData []ResourceData
+}
+
+// KeyStore defines the consumers and the encrypted parts of a package.
+type KeyStore struct {
+	UUID               string
+	Consumers          []Consumer
+	ResourceDataGroups []ResourceDataGroup
+}
+
+// FindConsumer returns the index of the consumer identified by id.
+func (k *KeyStore) FindConsumer(id string) (int, bool) {
+	for i, c := range k.Consumers {
+		if c.ID == id {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// FindResourceData returns the group index and the resource data of the part
+// stored in path.
+func (k *KeyStore) FindResourceData(path string) (int, *ResourceData, bool) {
+	for i := range k.ResourceDataGroups {
+		g := &k.ResourceDataGroups[i]
+		for j := range g.ResourceData {
+			if samePart(g.ResourceData[j].Path, path) {
+				return i, &g.ResourceData[j], true
+			}
+		}
+	}
+	return -1, nil, false
+}
+
+func samePart(a, b string) bool {
+	return normalizePart(a) == normalizePart(b)
+}
+
+func normalizePart(s string) string {
+	return strings.ToLower(strings.TrimPrefix(s, "/"))
+}
@@ -0,0 +1,51 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package securecontent
+
+import (
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+var _ go3mf.PartReader = new(Decrypter)
+var _ go3mf.PartWriter = new(Encrypter)
+var _ KeyProvider = new(RSAKeyProvider)
+
+func TestKeyStore_FindResourceData(t *testing.T) {
+	k := &KeyStore{ResourceDataGroups: []ResourceDataGroup{
+		{ResourceData: []ResourceData{{Path: "/3D/a.model"}}},
+		{ResourceData: []ResourceData{{Path: "/3D/b.model"}, {Path: "/3D/c.model"}}},
+	}}
+	tests := []struct {
+		name   string
+		path   string
+		want   int
+		wantOk bool
+	}{
+		{"first", "/3D/a.model", 0, true},
+		{"second", "/3D/c.model", 1, true},
+		{"noSlash", "3D/b.model", 1, true},
+		{"case", "/3d/A.MODEL", 0, true},
+		{"missing", "/3D/d.model", -1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, ok := k.FindResourceData(tt.path)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("KeyStore.FindResourceData() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestKeyStore_FindConsumer(t *testing.T) {
+	k := &KeyStore{Consumers: []Consumer{{ID: "a"}, {ID: "b"}}}
+	if i, ok := k.FindConsumer("b"); !ok || i != 1 {
+		t.Errorf("KeyStore.FindConsumer() = %v, %v", i, ok)
+	}
+	if _, ok := k.FindConsumer("c"); ok {
+		t.Error("KeyStore.FindConsumer() found an unknown consumer")
+	}
+}
@@ -0,0 +1,739 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	_ "crypto/sha1" // register the SHA-1 digests used by legacy signatures.
+	"crypto/sha256"
+	_ "crypto/sha512" // register the SHA-384 and SHA-512 digests.
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hpinc/go3mf/internal/c14n"
+	"github.com/qmuntal/opc"
+)
+
+// Digital signature errors.
+var (
+	ErrSignatureAlgorithm   = errors.New("go3mf: unsupported digital signature algorithm")
+	ErrSignatureCertificate = errors.New("go3mf: digital signature certificate or key not found")
+	ErrSignatureInvalid     = errors.New("go3mf: digital signature value does not match the signed content")
+	ErrSignatureDuplicateID = errors.New("go3mf: digital signature contains duplicated Id attributes")
+)
+
+const (
+	signatureOriginName = "/_xmlsignatures/origin.sigs"
+	signatureName       = "/_xmlsignatures/sig1.xml"
+	packageRelsName     = "/_rels/.rels"
+	signatureID         = "idPackageSignature"
+	signatureObjectID   = "idPackageObject"
+
+	contentTypeRelationships = "application/vnd.openxmlformats-package.relationships+xml"
+
+	nsDSig  = "http://www.w3.org/2000/09/xmldsig#"
+	nsMDSSI = "http://schemas.openxmlformats.org/package/2006/digital-signature"
+	nsRels  = "http://schemas.openxmlformats.org/package/2006/relationships"
+
+	algC14N                  = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+	algRelationshipTransform = "http://schemas.openxmlformats.org/package/2006/RelationshipTransform"
+	algSHA256                = "http://www.w3.org/2001/04/xmlenc#sha256"
+	algRSASHA256             = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	algECDSASHA256           = "http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha256"
+	typeObject               = "http://www.w3.org/2000/09/xmldsig#Object"
+
+	signatureTimeFormat = "YYYY-MM-DDThh:mm:ssTZD"
+)
+
+var digestMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#sha1":        crypto.SHA1,
+	algSHA256:                                       crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+}
+
+var signatureMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#rsa-sha1": crypto.SHA1,
+	algRSASHA256: crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+	"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha1": crypto.SHA1,
+	algECDSASHA256: crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmldsig-more#ecdsa-sha512": crypto.SHA512,
+}
+
+// A Signer adds an OPC digital signature to the package when the Encoder is closed.
+// The signature covers all the parts and relationships written by the Encoder.
+type Signer struct {
+	Certificate *x509.Certificate
+	// Key is the certificate private key, *rsa.PrivateKey and *ecdsa.PrivateKey are supported.
+	Key crypto.Signer
+	// Time is the signing time. If zero the current time is used.
+	Time time.Time
+}
+
+// Signature is the result of verifying an OPC digital signature.
+type Signature struct {
+	Path        string // Signature part name.
+	Certificate *x509.Certificate
+	Time        time.Time
+	Parts       []string // Signed parts, including relationship parts.
+	Modified    []string // Signed parts that are missing or whose content has changed.
+	Err         error    // Error verifying the signature value, nil if it is correct.
+}
+
+// Valid returns true if the signature value is correct and none of the signed parts has been modified.
+func (s *Signature) Valid() bool {
+	return s.Err == nil && len(s.Modified) == 0
+}
+
+// VerifySignatures verifies the OPC digital signatures of the package.
+// It returns an empty slice if the package is not signed.
+//
+// The certificates are not validated, callers should check them
+// against their trusted roots.
+func (d *Decoder) VerifySignatures() ([]Signature, error) {
+	o, ok := d.p.(*opcReader)
+	if !ok {
+		return nil, nil
+	}
+	if o.r == nil {
+		if err := o.Open(d.flate); err != nil {
+			return nil, err
+		}
+	}
+	var sigs []Signature
+	for _, r := range o.r.Relationships {
+		if r.Type != RelTypeDigitalSignatureOrigin {
+			continue
+		}
+		origin, ok := o.file(opc.ResolveRelationship("/", r.TargetURI))
+		if !ok {
+			continue
+		}
+		for _, sr := range origin.Relationships {
+			if sr.Type == RelTypeDigitalSignature {
+				sigs = append(sigs, o.verify(opc.ResolveRelationship(origin.Name, sr.TargetURI)))
+			}
+		}
+	}
+	return sigs, nil
+}
+
+func (o *opcReader) file(name string) (*opc.File, bool) {
+	for _, f := range o.r.Files {
+		if strings.EqualFold(f.Name, name) {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+func (o *opcReader) read(name string) ([]byte, string, bool) {
+	f, ok := o.file(name)
+	if !ok {
+		return nil, "", false
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, "", false
+	}
+	defer rc.Close()
+	b, err := ioutil.ReadAll(rc)
+	if err != nil {
+		return nil, "", false
+	}
+	return b, f.ContentType, true
+}
+
+type xmlAlgorithm struct {
+	Algorithm string `xml:"Algorithm,attr"`
+}
+
+type xmlTransform struct {
+	Algorithm  string `xml:"Algorithm,attr"`
+	References []struct {
+		SourceID string `xml:"SourceId,attr"`
+	} `xml:"RelationshipReference"`
+	Groups []struct {
+		SourceType string `xml:"SourceType,attr"`
+	} `xml:"RelationshipsGroupReference"`
+}
+
+type xmlReference struct {
+	URI          string         `xml:"URI,attr"`
+	Transforms   []xmlTransform `xml:"Transforms>Transform"`
+	DigestMethod xmlAlgorithm
+	DigestValue  string
+}
+
+type xmlSignature struct {
+	SignedInfo struct {
+		CanonicalizationMethod xmlAlgorithm
+		SignatureMethod        xmlAlgorithm
+		References             []xmlReference `xml:"Reference"`
+	}
+	SignatureValue string
+	Certificates   []string `xml:"KeyInfo>X509Data>X509Certificate"`
+	Objects        []struct {
+		ID         string         `xml:"Id,attr"`
+		References []xmlReference `xml:"Manifest>Reference"`
+		Times      []string       `xml:"SignatureProperties>SignatureProperty>SignatureTime>Value"`
+	} `xml:"Object"`
+}
+
+func (o *opcReader) verify(name string) Signature {
+	s := Signature{Path: name}
+	doc, _, ok := o.read(name)
+	if !ok {
+		s.Err = fmt.Errorf("go3mf: digital signature part %s not found", name)
+		return s
+	}
+	var x xmlSignature
+	if err := xml.Unmarshal(doc, &x); err != nil {
+		s.Err = err
+		return s
+	}
+	if len(x.Certificates) == 0 {
+		s.Err = ErrSignatureCertificate
+		return s
+	}
+	der, err := decodeBase64(x.Certificates[0])
+	if err == nil {
+		s.Certificate, err = x509.ParseCertificate(der)
+	}
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	// Only the objects covered by the signature value are trusted.
+	signed, err := verifySignedInfo(doc, &x, s.Certificate)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	for _, obj := range x.Objects {
+		if !signed[obj.ID] {
+			continue
+		}
+		for _, t := range obj.Times {
+			if tm, ok := parseSignatureTime(t); ok {
+				s.Time = tm
+			}
+		}
+		for _, ref := range obj.References {
+			name, modified := o.verifyManifestReference(ref)
+			s.Parts = append(s.Parts, name)
+			if modified {
+				s.Modified = append(s.Modified, name)
+			}
+		}
+	}
+	return s
+}
+
+// verifySignedInfo checks the signature value and the digest of the signed objects,
+// and returns the Id of the objects whose digest has been verified.
+func verifySignedInfo(doc []byte, x *xmlSignature, cert *x509.Certificate) (map[string]bool, error) {
+	if x.SignedInfo.CanonicalizationMethod.Algorithm != algC14N {
+		return nil, ErrSignatureAlgorithm
+	}
+	h, ok := signatureMethods[x.SignedInfo.SignatureMethod.Algorithm]
+	if !ok {
+		return nil, ErrSignatureAlgorithm
+	}
+	// References are resolved to the first element with the same Id,
+	// so a duplicated Id could hide an unsigned element behind a signed one.
+	if err := checkUniqueIDs(doc); err != nil {
+		return nil, err
+	}
+	info, err := c14n.Element(doc, func(t xml.StartElement) bool {
+		return t.Name.Space == nsDSig && t.Name.Local == "SignedInfo"
+	})
+	if err != nil {
+		return nil, err
+	}
+	value, err := decodeBase64(x.SignatureValue)
+	if err != nil {
+		return nil, err
+	}
+	hh := h.New()
+	hh.Write(info)
+	if !verifyValue(cert.PublicKey, h, hh.Sum(nil), value) {
+		return nil, ErrSignatureInvalid
+	}
+	signed := make(map[string]bool, len(x.SignedInfo.References))
+	for _, ref := range x.SignedInfo.References {
+		if !strings.HasPrefix(ref.URI, "#") {
+			return nil, ErrSignatureAlgorithm
+		}
+		id := ref.URI[1:]
+		obj, err := c14n.Element(doc, func(t xml.StartElement) bool {
+			for _, a := range t.Attr {
+				if a.Name.Space == "" && a.Name.Local == "Id" && a.Value == id {
+					return true
+				}
+			}
+			return false
+		})
+		if err != nil {
+			return nil, err
+		}
+		if ok, err := checkDigest(ref, obj); err != nil {
+			return nil, err
+		} else if !ok {
+			return nil, ErrSignatureInvalid
+		}
+		signed[id] = true
+	}
+	return signed, nil
+}
+
+// checkUniqueIDs returns ErrSignatureDuplicateID if two elements of doc have the same Id.
+func checkUniqueIDs(doc []byte) error {
+	ids := make(map[string]bool)
+	d := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		t, err := d.RawToken()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		se, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, a := range se.Attr {
+			if a.Name.Space == "" && a.Name.Local == "Id" {
+				if ids[a.Value] {
+					return ErrSignatureDuplicateID
+				}
+				ids[a.Value] = true
+			}
+		}
+	}
+}
+
+func verifyValue(pub crypto.PublicKey, h crypto.Hash, digest, value []byte) bool {
+	switch pub := pub.(type) {
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(pub, h, digest, value) == nil
+	case *ecdsa.PublicKey:
+		size := len(value) / 2
+		if size == 0 || len(value)%2 != 0 {
+			return false
+		}
+		r, s := new(big.Int).SetBytes(value[:size]), new(big.Int).SetBytes(value[size:])
+		return ecdsa.Verify(pub, digest, r, s)
+	}
+	return false
+}
+
+// verifyManifestReference returns the name of the referenced part
+// and true if its content does not match the digest.
+func (o *opcReader) verifyManifestReference(ref xmlReference) (string, bool) {
+	name := ref.URI
+	var contentType string
+	if i := strings.Index(name, "?ContentType="); i >= 0 {
+		name, contentType = name[:i], name[i+len("?ContentType="):]
+	}
+	var (
+		content []byte
+		ok      bool
+	)
+	if isRelationshipsPart(name) {
+		content, ok = o.relationshipsContent(name, ref.Transforms)
+	} else {
+		var ct string
+		content, ct, ok = o.read(name)
+		if ok && contentType != "" && ct != contentType {
+			ok = false
+		}
+		for _, t := range ref.Transforms {
+			if !ok {
+				break
+			}
+			if t.Algorithm != algC14N {
+				ok = false
+				break
+			}
+			var err error
+			if content, err = c14n.Document(content); err != nil {
+				ok = false
+			}
+		}
+	}
+	if !ok {
+		return name, true
+	}
+	match, err := checkDigest(ref, content)
+	return name, err != nil || !match
+}
+
+// relationshipsContent returns the output of the relationship transform
+// applied to the named relationships part.
+func (o *opcReader) relationshipsContent(name string, transforms []xmlTransform) ([]byte, bool) {
+	var rels []*opc.Relationship
+	if strings.EqualFold(name, packageRelsName) {
+		rels = o.r.Relationships
+	} else {
+		dir, base := path.Split(name)
+		source := path.Join(path.Dir(strings.TrimSuffix(dir, "/")), strings.TrimSuffix(base, path.Ext(base)))
+		f, ok := o.file(source)
+		if !ok {
+			return nil, false
+		}
+		rels = f.Relationships
+	}
+	var (
+		ids, types []string
+		found      bool
+	)
+	for _, t := range transforms {
+		if t.Algorithm == algRelationshipTransform {
+			found = true
+			for _, r := range t.References {
+				ids = append(ids, r.SourceID)
+			}
+			for _, g := range t.Groups {
+				types = append(types, g.SourceType)
+			}
+		} else if t.Algorithm != algC14N {
+			return nil, false
+		}
+	}
+	if !found {
+		return nil, false
+	}
+	b, err := relationshipsTransform(rels, ids, types)
+	return b, err == nil
+}
+
+func isRelationshipsPart(name string) bool {
+	return strings.HasSuffix(strings.ToLower(name), ".rels") && strings.Contains(strings.ToLower(name), "/_rels/")
+}
+
+func checkDigest(ref xmlReference, content []byte) (bool, error) {
+	h, ok := digestMethods[ref.DigestMethod.Algorithm]
+	if !ok {
+		return false, ErrSignatureAlgorithm
+	}
+	want, err := decodeBase64(ref.DigestValue)
+	if err != nil {
+		return false, err
+	}
+	hh := h.New()
+	hh.Write(content)
+	return bytes.Equal(hh.Sum(nil), want), nil
+}
+
+// relationshipsTransform implements the OPC relationship transform followed by
+// the canonicalization, selecting the relationships by id or by type.
+func relationshipsTransform(rels []*opc.Relationship, ids, types []string) ([]byte, error) {
+	selected := make([]*opc.Relationship, 0, len(rels))
+	for _, r := range rels {
+		if containsString(ids, r.ID) || containsString(types, r.Type) {
+			selected = append(selected, r)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool {
+		return selected[i].ID < selected[j].ID
+	})
+	var b xmlBuilder
+	b.start("Relationships", "xmlns", nsRels)
+	for _, r := range selected {
+		mode := "Internal"
+		if r.TargetMode == opc.ModeExternal {
+			mode = "External"
+		}
+		b.start("Relationship", "Id", r.ID, "Target", r.TargetURI, "TargetMode", mode, "Type", r.Type)
+		b.end("Relationship")
+	}
+	b.end("Relationships")
+	return c14n.Document(b.Bytes())
+}
+
+func containsString(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeBase64(s string) ([]byte, error) {
+	return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(s), ""))
+}
+
+func parseSignatureTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// signedPart is a part created by a signing opcWriter.
+type signedPart struct {
+	part   *opc.Part
+	digest hash.Hash
+}
+
+// sign writes the signature origin and the signature parts.
+// It must be called once all the other parts have been written.
+func (o *opcWriter) sign() error {
+	s := o.signer
+	if s.Certificate == nil || s.Key == nil {
+		return ErrSignatureCertificate
+	}
+	var method string
+	switch s.Key.Public().(type) {
+	case *rsa.PublicKey:
+		method = algRSASHA256
+	case *ecdsa.PublicKey:
+		method = algECDSASHA256
+	default:
+		return ErrSignatureAlgorithm
+	}
+
+	var manifest xmlBuilder
+	manifest.start("Manifest")
+	for _, p := range o.signed {
+		manifest.reference(p.part.Name+"?ContentType="+p.part.ContentType, nil, p.digest.Sum(nil))
+		if len(p.part.Relationships) == 0 {
+			continue
+		}
+		if err := manifest.relationshipsReference(relationshipsPartName(p.part.Name), p.part.Relationships); err != nil {
+			return err
+		}
+	}
+	if len(o.w.Relationships) > 0 {
+		if err := manifest.relationshipsReference(packageRelsName, o.w.Relationships); err != nil {
+			return err
+		}
+	}
+	manifest.end("Manifest")
+
+	tm := s.Time
+	if tm.IsZero() {
+		tm = time.Now()
+	}
+	var object xmlBuilder
+	object.start("Object", "Id", signatureObjectID)
+	object.Write(manifest.Bytes())
+	object.start("SignatureProperties")
+	object.start("SignatureProperty", "Id", "idSignatureTime", "Target", "#"+signatureID)
+	object.start("mdssi:SignatureTime", "xmlns:mdssi", nsMDSSI)
+	object.element("mdssi:Format", signatureTimeFormat)
+	object.element("mdssi:Value", tm.UTC().Format("2006-01-02T15:04:05Z"))
+	object.end("mdssi:SignatureTime")
+	object.end("SignatureProperty")
+	object.end("SignatureProperties")
+	object.end("Object")
+
+	objectC14N, err := canonicalSignatureChild(object.Bytes(), "Object")
+	if err != nil {
+		return err
+	}
+	var info xmlBuilder
+	info.start("SignedInfo")
+	info.start("CanonicalizationMethod", "Algorithm", algC14N)
+	info.end("CanonicalizationMethod")
+	info.start("SignatureMethod", "Algorithm", method)
+	info.end("SignatureMethod")
+	info.start("Reference", "URI", "#"+signatureObjectID, "Type", typeObject)
+	info.start("DigestMethod", "Algorithm", algSHA256)
+	info.end("DigestMethod")
+	digest := sha256.Sum256(objectC14N)
+	info.element("DigestValue", base64.StdEncoding.EncodeToString(digest[:]))
+	info.end("Reference")
+	info.end("SignedInfo")
+
+	infoC14N, err := canonicalSignatureChild(info.Bytes(), "SignedInfo")
+	if err != nil {
+		return err
+	}
+	digest = sha256.Sum256(infoC14N)
+	value, err := s.Key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return err
+	}
+	if pub, ok := s.Key.Public().(*ecdsa.PublicKey); ok {
+		if value, err = ecdsaRawSignature(value, pub); err != nil {
+			return err
+		}
+	}
+
+	var doc xmlBuilder
+	doc.WriteString(xml.Header)
+	doc.start("Signature", "xmlns", nsDSig, "Id", signatureID)
+	doc.Write(info.Bytes())
+	doc.element("SignatureValue", base64.StdEncoding.EncodeToString(value))
+	doc.start("KeyInfo")
+	doc.start("X509Data")
+	doc.element("X509Certificate", base64.StdEncoding.EncodeToString(s.Certificate.Raw))
+	doc.end("X509Data")
+	doc.end("KeyInfo")
+	doc.Write(object.Bytes())
+	doc.end("Signature")
+
+	if _, err = o.w.CreatePart(&opc.Part{
+		Name:        signatureOriginName,
+		ContentType: ContentTypeSignatureOrigin,
+		Relationships: []*opc.Relationship{
+			{ID: "rId1", Type: RelTypeDigitalSignature, TargetURI: signatureName},
+		},
+	}, opc.CompressionNormal); err != nil {
+		return err
+	}
+	w, err := o.w.CreatePart(&opc.Part{Name: signatureName, ContentType: ContentTypeSignature}, opc.CompressionNormal)
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(doc.Bytes()); err != nil {
+		return err
+	}
+	o.w.Relationships = append(o.w.Relationships, &opc.Relationship{
+		ID:        newRelationshipID(o.w.Relationships),
+		Type:      RelTypeDigitalSignatureOrigin,
+		TargetURI: signatureOriginName,
+	})
+	return nil
+}
+
+// canonicalSignatureChild returns the canonical form of the signature child element content,
+// which inherits the signature name space.
+func canonicalSignatureChild(content []byte, name string) ([]byte, error) {
+	var doc xmlBuilder
+	doc.start("Signature", "xmlns", nsDSig)
+	doc.Write(content)
+	doc.end("Signature")
+	return c14n.Element(doc.Bytes(), func(t xml.StartElement) bool {
+		return t.Name.Space == nsDSig && t.Name.Local == name
+	})
+}
+
+func ecdsaRawSignature(der []byte, pub *ecdsa.PublicKey) ([]byte, error) {
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(der, &sig); err != nil {
+		return nil, err
+	}
+	size := (pub.Curve.Params().BitSize + 7) / 8
+	b := make([]byte, 2*size)
+	r, s := sig.R.Bytes(), sig.S.Bytes()
+	copy(b[size-len(r):size], r)
+	copy(b[2*size-len(s):], s)
+	return b, nil
+}
+
+func relationshipsPartName(name string) string {
+	dir, base := path.Split(name)
+	return dir + "_rels/" + base + ".rels"
+}
+
+// newRelationshipID returns the first rIdN identifier not used in rels,
+// as the opc package does for the relationships without identifier.
+func newRelationshipID(rels []*opc.Relationship) string {
+	ids := make([]string, len(rels))
+	for i, r := range rels {
+		ids[i] = r.ID
+	}
+	for i := 0; ; i++ {
+		if id := fmt.Sprintf("rId%d", i); !containsString(ids, id) {
+			return id
+		}
+	}
+}
+
+func assignRelationshipIDs(rels []*opc.Relationship) {
+	for _, r := range rels {
+		if r.ID == "" {
+			r.ID = newRelationshipID(rels)
+		}
+	}
+}
+
+// xmlBuilder writes the signature elements,
+// attrs are pairs of attribute names and values.
+type xmlBuilder struct {
+	bytes.Buffer
+}
+
+func (b *xmlBuilder) start(name string, attrs ...string) {
+	b.WriteString("<" + name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		b.WriteString(" " + attrs[i] + `="`)
+		xml.EscapeText(b, []byte(attrs[i+1]))
+		b.WriteByte('"')
+	}
+	b.WriteByte('>')
+}
+
+func (b *xmlBuilder) end(name string) {
+	b.WriteString("</" + name + ">")
+}
+
+func (b *xmlBuilder) element(name, text string) {
+	b.start(name)
+	xml.EscapeText(b, []byte(text))
+	b.end(name)
+}
+
+func (b *xmlBuilder) reference(uri string, transforms func(), digest []byte) {
+	b.start("Reference", "URI", uri)
+	if transforms != nil {
+		b.start("Transforms")
+		transforms()
+		b.end("Transforms")
+	}
+	b.start("DigestMethod", "Algorithm", algSHA256)
+	b.end("DigestMethod")
+	b.element("DigestValue", base64.StdEncoding.EncodeToString(digest))
+	b.end("Reference")
+}
+
+func (b *xmlBuilder) relationshipsReference(name string, rels []*opc.Relationship) error {
+	assignRelationshipIDs(rels)
+	ids := make([]string, len(rels))
+	for i, r := range rels {
+		ids[i] = r.ID
+	}
+	content, err := relationshipsTransform(rels, ids, nil)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(content)
+	b.reference(name+"?ContentType="+contentTypeRelationships, func() {
+		b.start("Transform", "Algorithm", algRelationshipTransform)
+		for _, id := range ids {
+			b.start("mdssi:RelationshipReference", "xmlns:mdssi", nsMDSSI, "SourceId", id)
+			b.end("mdssi:RelationshipReference")
+		}
+		b.end("Transform")
+		b.start("Transform", "Algorithm", algC14N)
+		b.end("Transform")
+	}, digest[:])
+	return nil
+}
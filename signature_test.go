@@ -0,0 +1,296 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func newTestSigner(t *testing.T, key crypto.Signer) *Signer {
+	t.Helper()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "go3mf"},
+		NotBefore:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Signer{Certificate: cert, Key: key, Time: time.Date(2021, 6, 1, 10, 30, 0, 0, time.UTC)}
+}
+
+func newTestSignedModel() *Model {
+	return &Model{
+		Thumbnail: "/Metadata/thumbnail.png",
+		Attachments: []Attachment{
+			{ContentType: "image/png", Path: "/Metadata/thumbnail.png", Stream: bytes.NewBufferString("fake")},
+		},
+		Childs: map[string]*ChildModel{
+			"/3D/other.model": {},
+		},
+		Resources: Resources{Objects: []*Object{{ID: 1, Mesh: &Mesh{}}}},
+	}
+}
+
+func encodeSigned(t *testing.T, s *Signer) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.Signer = s
+	if err := enc.Encode(newTestSignedModel()); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// rewriteZip returns a copy of the package b with the content of the named entry
+// transformed by f, which removes the entry if it returns nil.
+func rewriteZip(t *testing.T, b []byte, name string, f func([]byte) []byte) []byte {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range r.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := ioutil.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if file.Name == name {
+			if content = f(content); content == nil {
+				continue
+			}
+		}
+		fw, err := w.Create(file.Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(content)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func verify(t *testing.T, b []byte) []Signature {
+	t.Helper()
+	sigs, err := NewDecoder(bytes.NewReader(b), int64(len(b))).VerifySignatures()
+	if err != nil {
+		t.Fatalf("Decoder.VerifySignatures() error = %v", err)
+	}
+	return sigs
+}
+
+func TestSigner(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantParts := []string{
+		"/Metadata/thumbnail.png",
+		"/3D/3dmodel.model",
+		"/3D/_rels/3dmodel.model.rels",
+		"/3D/other.model",
+		"/_rels/.rels",
+	}
+	tests := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{"rsa", rsaKey},
+		{"ecdsa", ecKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSigner(t, tt.key)
+			b := encodeSigned(t, s)
+			sigs := verify(t, b)
+			if len(sigs) != 1 {
+				t.Fatalf("Decoder.VerifySignatures() got %d signatures, want 1", len(sigs))
+			}
+			sig := sigs[0]
+			if !sig.Valid() {
+				t.Errorf("Signature.Valid() = false, err = %v, modified = %v", sig.Err, sig.Modified)
+			}
+			if sig.Path != signatureName {
+				t.Errorf("Signature.Path = %s, want %s", sig.Path, signatureName)
+			}
+			if !sig.Certificate.Equal(s.Certificate) {
+				t.Error("Signature.Certificate does not match the signer")
+			}
+			if !sig.Time.Equal(s.Time) {
+				t.Errorf("Signature.Time = %v, want %v", sig.Time, s.Time)
+			}
+			if diff := deep.Equal(sig.Parts, wantParts); diff != nil {
+				t.Errorf("Signature.Parts = %v", diff)
+			}
+
+			model := new(Model)
+			if err := NewDecoder(bytes.NewReader(b), int64(len(b))).Decode(model); err != nil {
+				t.Fatalf("Decoder.Decode() error = %v", err)
+			}
+			for _, r := range model.RootRelationships {
+				if r.Type == RelTypeDigitalSignatureOrigin {
+					t.Error("Decoder.Decode() kept the signature origin relationship")
+				}
+			}
+		})
+	}
+}
+
+func TestSigner_error(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.Signer = new(Signer)
+	if err := enc.Encode(new(Model)); err != ErrSignatureCertificate {
+		t.Errorf("Encoder.Encode() error = %v, want %v", err, ErrSignatureCertificate)
+	}
+}
+
+func TestDecoder_VerifySignatures(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := encodeSigned(t, newTestSigner(t, key))
+	var unsigned bytes.Buffer
+	if err := NewEncoder(&unsigned).Encode(newTestSignedModel()); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		b        []byte
+		want     int
+		modified []string
+		err      error
+	}{
+		{"unsigned", unsigned.Bytes(), 0, nil, nil},
+		{"valid", signed, 1, nil, nil},
+		{"part", rewriteZip(t, signed, "3D/3dmodel.model", func(b []byte) []byte {
+			return bytes.Replace(b, []byte(`id="1"`), []byte(`id="2"`), 1)
+		}), 1, []string{"/3D/3dmodel.model"}, nil},
+		{"rels", rewriteZip(t, signed, "3D/_rels/3dmodel.model.rels", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("/3D/other.model"), []byte("/3D/another.model"), 1)
+		}), 1, []string{"/3D/_rels/3dmodel.model.rels"}, nil},
+		{"attachment", rewriteZip(t, signed, "Metadata/thumbnail.png", func(b []byte) []byte {
+			return append(b, 'a')
+		}), 1, []string{"/Metadata/thumbnail.png"}, nil},
+		{"missing", rewriteZip(t, signed, "3D/other.model", func(b []byte) []byte {
+			return nil
+		}), 1, []string{"/3D/other.model"}, nil},
+		{"time", rewriteZip(t, signed, "_xmlsignatures/sig1.xml", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("2021-06-01"), []byte("2021-06-02"), 1)
+		}), 1, nil, ErrSignatureInvalid},
+		{"value", rewriteZip(t, signed, "_xmlsignatures/sig1.xml", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("ecdsa-sha256"), []byte("ecdsa-sha512"), 1)
+		}), 1, nil, ErrSignatureInvalid},
+		{"algorithm", rewriteZip(t, signed, "_xmlsignatures/sig1.xml", func(b []byte) []byte {
+			return bytes.Replace(b, []byte("ecdsa-sha256"), []byte("dsa-sha1"), 1)
+		}), 1, nil, ErrSignatureAlgorithm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sigs := verify(t, tt.b)
+			if len(sigs) != tt.want {
+				t.Fatalf("Decoder.VerifySignatures() got %d signatures, want %d", len(sigs), tt.want)
+			}
+			if tt.want == 0 {
+				return
+			}
+			sig := sigs[0]
+			if !reflect.DeepEqual(sig.Modified, tt.modified) {
+				t.Errorf("Signature.Modified = %v, want %v", sig.Modified, tt.modified)
+			}
+			if sig.Err != tt.err {
+				t.Errorf("Signature.Err = %v, want %v", sig.Err, tt.err)
+			}
+			if got := sig.Valid(); got != (tt.err == nil && tt.modified == nil) {
+				t.Errorf("Signature.Valid() = %v", got)
+			}
+		})
+	}
+}
+
+func TestDecoder_VerifySignatures_injected(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := encodeSigned(t, newTestSigner(t, key))
+	want := verify(t, signed)[0]
+	inject := func(obj string) []byte {
+		return rewriteZip(t, signed, "_xmlsignatures/sig1.xml", func(b []byte) []byte {
+			i := bytes.LastIndex(b, []byte("</Signature>"))
+			return append(append(append([]byte(nil), b[:i]...), obj...), b[i:]...)
+		})
+	}
+	// Manifests outside the signed objects do not cover any part.
+	manifest := `<Manifest><Reference URI="/Metadata/extra.png?ContentType=image/png">` +
+		`<DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></DigestMethod>` +
+		`<DigestValue>47DEQpj8HBSa+/TImW+5JCeuQeRkm5NMpJWZG3hSuFU=</DigestValue></Reference></Manifest>`
+	for _, obj := range []string{"<Object>" + manifest + "</Object>", `<Object Id="idOther">` + manifest + "</Object>"} {
+		sig := verify(t, inject(obj))[0]
+		if !sig.Valid() {
+			t.Errorf("Signature.Valid() = false, err = %v, modified = %v", sig.Err, sig.Modified)
+		}
+		if diff := deep.Equal(sig.Parts, want.Parts); diff != nil {
+			t.Errorf("Signature.Parts = %v", diff)
+		}
+	}
+	// A duplicated Id could make the references point to an unsigned object.
+	sig := verify(t, inject(`<Object Id="`+signatureObjectID+`">`+manifest+"</Object>"))[0]
+	if sig.Err != ErrSignatureDuplicateID {
+		t.Errorf("Signature.Err = %v, want %v", sig.Err, ErrSignatureDuplicateID)
+	}
+	if sig.Valid() || len(sig.Parts) != 0 {
+		t.Errorf("Signature.Parts = %v, want none", sig.Parts)
+	}
+}
+
+func Test_relationshipsTransform(t *testing.T) {
+	b, err := relationshipsTransform(nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships"></Relationships>`
+	if string(b) != want {
+		t.Errorf("relationshipsTransform() = %s, want %s", b, want)
+	}
+	if got := relationshipsPartName("/3D/3dmodel.model"); got != "/3D/_rels/3dmodel.model.rels" {
+		t.Errorf("relationshipsPartName() = %s", got)
+	}
+}
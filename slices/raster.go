@@ -0,0 +1,351 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package slices
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+)
+
+// Rasterizer errors.
+var (
+	ErrRasterDPI  = errors.New("slices: dpi must be greater than zero")
+	ErrRasterRefs = errors.New("slices: slice stack references can not be resolved")
+	ErrRasterSize = errors.New("slices: raster is too large")
+)
+
+// ContentTypeLayers is the content type of the attachment created by Rasterizer.AttachLayers.
+const ContentTypeLayers = "application/zip"
+
+// maxRasterSize limits the number of pixels of a single layer.
+const maxRasterSize = 1 << 28
+
+// graySamples is the number of samples per pixel side used to antialias grayscale layers.
+const graySamples = 4
+
+// FillRule defines how the polygons of a slice are filled.
+type FillRule uint8
+
+// Supported fill rules.
+const (
+	// FillEvenOdd fills the areas enclosed by an odd number of contours.
+	FillEvenOdd FillRule = iota
+	// FillNonZero fills the areas with a non-zero winding number.
+	FillNonZero
+)
+
+// A Rasterizer renders slices to bitmaps, as consumed by DLP and SLA printers.
+//
+// White pixels are inside the solid and black pixels outside.
+// The image top-left corner is at the minimum X and maximum Y of the covered area.
+type Rasterizer struct {
+	// DPI is the resolution of the images, in pixels per inch.
+	DPI float32
+	// Units of the slice coordinates, millimeters by default.
+	Units go3mf.Units
+	// FillRule used to fill the polygons.
+	FillRule FillRule
+	// Bilevel creates 1-bit images instead of antialiased grayscale images.
+	Bilevel bool
+	// Min and Max define the covered area.
+	// When both are zero the bounding box of the slice stack is used,
+	// so all the layers have the same size.
+	Min, Max go3mf.Point2D
+}
+
+// Rasterize returns one image per slice of st.
+// Stacks that reference other stacks can't be rasterized without a model,
+// use AttachLayers instead.
+func (r *Rasterizer) Rasterize(st *SliceStack) ([]image.Image, error) {
+	if len(st.Refs) > 0 {
+		return nil, ErrRasterRefs
+	}
+	return r.rasterize(st.Slices)
+}
+
+// RasterizeSlice returns the image of a single slice.
+func (r *Rasterizer) RasterizeSlice(s *Slice) (image.Image, error) {
+	imgs, err := r.rasterize([]Slice{*s})
+	if err != nil {
+		return nil, err
+	}
+	return imgs[0], nil
+}
+
+// AttachLayers rasterizes st, resolving its references in m,
+// and packs the layers as PNG files in a zip attachment at path.
+// The attachment is referenced from the root model with a must preserve
+// relationship, so it is kept when the package is read back.
+// The slice coordinates are assumed to be in the model units.
+func (r *Rasterizer) AttachLayers(m *go3mf.Model, path string, st *SliceStack) error {
+	slices := st.Slices
+	for _, ref := range st.Refs {
+		a, ok := m.FindAsset(ref.Path, ref.SliceStackID)
+		if !ok {
+			return ErrRasterRefs
+		}
+		rst, ok := a.(*SliceStack)
+		if !ok || len(rst.Refs) > 0 {
+			return ErrRasterRefs
+		}
+		slices = append(slices, rst.Slices...)
+	}
+	rr := *r
+	rr.Units = m.Units
+	imgs, err := rr.rasterize(slices)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err = EncodeLayers(&buf, imgs); err != nil {
+		return err
+	}
+	addRelationship(m, path)
+	for i := range m.Attachments {
+		if m.Attachments[i].Path == path {
+			m.Attachments[i].ContentType = ContentTypeLayers
+			m.Attachments[i].Stream = &buf
+			return nil
+		}
+	}
+	m.Attachments = append(m.Attachments, go3mf.Attachment{Path: path, ContentType: ContentTypeLayers, Stream: &buf})
+	return nil
+}
+
+// addRelationship adds a must preserve relationship to path
+// in the root model, if it does not already have one.
+func addRelationship(m *go3mf.Model, path string) {
+	for _, rel := range m.Relationships {
+		if rel.Path == path && rel.Type == go3mf.RelTypeMustPreserve {
+			return
+		}
+	}
+	m.Relationships = append(m.Relationships, go3mf.Relationship{Path: path, Type: go3mf.RelTypeMustPreserve})
+}
+
+// LayerName returns the file name of the layer i.
+func LayerName(i int) string {
+	return fmt.Sprintf("layer_%05d.png", i)
+}
+
+// EncodeLayers writes the images to w as a zip archive of PNG files named by LayerName.
+func EncodeLayers(w io.Writer, imgs []image.Image) error {
+	z := zip.NewWriter(w)
+	for i, img := range imgs {
+		f, err := z.Create(LayerName(i))
+		if err != nil {
+			return err
+		}
+		if err = png.Encode(f, img); err != nil {
+			return err
+		}
+	}
+	return z.Close()
+}
+
+// WriteLayers writes the images to dir as PNG files named by LayerName.
+func WriteLayers(dir string, imgs []image.Image) error {
+	for i, img := range imgs {
+		f, err := os.Create(filepath.Join(dir, LayerName(i)))
+		if err != nil {
+			return err
+		}
+		err = png.Encode(f, img)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Rasterizer) rasterize(slices []Slice) ([]image.Image, error) {
+	if !(r.DPI > 0) {
+		return nil, ErrRasterDPI
+	}
+	min, max := r.Min, r.Max
+	if min == (go3mf.Point2D{}) && max == (go3mf.Point2D{}) {
+		min, max = bounds(slices)
+	}
+	scale := float64(r.DPI) / 25.4 * r.Units.Millimeters()
+	w := int(math.Ceil(float64(max[0]-min[0]) * scale))
+	h := int(math.Ceil(float64(max[1]-min[1]) * scale))
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+	if w*h > maxRasterSize {
+		return nil, ErrRasterSize
+	}
+	samples := graySamples
+	if r.Bilevel {
+		samples = 1
+	}
+	imgs := make([]image.Image, len(slices))
+	coverage := make([]int, w)
+	for i := range slices {
+		sc := &scanner{
+			edges:    sliceEdges(&slices[i], min, max, scale),
+			fillRule: r.FillRule,
+			width:    w,
+			samples:  samples,
+		}
+		rect := image.Rect(0, 0, w, h)
+		var (
+			gray *image.Gray
+			pal  *image.Paletted
+		)
+		if r.Bilevel {
+			pal = image.NewPaletted(rect, color.Palette{color.Black, color.White})
+			imgs[i] = pal
+		} else {
+			gray = image.NewGray(rect)
+			imgs[i] = gray
+		}
+		for y := 0; y < h; y++ {
+			for x := range coverage {
+				coverage[x] = 0
+			}
+			sc.row(y, coverage)
+			for x, c := range coverage {
+				if r.Bilevel {
+					pal.Pix[y*pal.Stride+x] = uint8(c)
+				} else {
+					gray.Pix[y*gray.Stride+x] = uint8((c*255 + samples*samples/2) / (samples * samples))
+				}
+			}
+		}
+	}
+	return imgs, nil
+}
+
+// bounds returns the bounding box of the slices vertices.
+func bounds(slices []Slice) (min, max go3mf.Point2D) {
+	min = go3mf.Point2D{math.MaxFloat32, math.MaxFloat32}
+	max = go3mf.Point2D{-math.MaxFloat32, -math.MaxFloat32}
+	for i := range slices {
+		for _, v := range slices[i].Vertices.Vertex {
+			min = go3mf.Point2D{float32(math.Min(float64(min[0]), float64(v[0]))), float32(math.Min(float64(min[1]), float64(v[1])))}
+			max = go3mf.Point2D{float32(math.Max(float64(max[0]), float64(v[0]))), float32(math.Max(float64(max[1]), float64(v[1])))}
+		}
+	}
+	if min[0] > max[0] {
+		return go3mf.Point2D{}, go3mf.Point2D{}
+	}
+	return min, max
+}
+
+// edge is a polygon segment in pixel coordinates.
+type edge struct {
+	x1, y1, x2, y2 float64
+}
+
+// sliceEdges returns the polygon segments in pixel coordinates, with y pointing down.
+// Open polygons are closed with an implicit segment.
+func sliceEdges(s *Slice, min, max go3mf.Point2D, scale float64) []edge {
+	vs := s.Vertices.Vertex
+	pixel := func(i uint32) (float64, float64) {
+		v := vs[i]
+		return float64(v[0]-min[0]) * scale, float64(max[1]-v[1]) * scale
+	}
+	var edges []edge
+	for _, p := range s.Polygons {
+		if int(p.StartV) >= len(vs) || len(p.Segments) == 0 {
+			continue
+		}
+		x1, y1 := pixel(p.StartV)
+		x0, y0 := x1, y1
+		for _, seg := range p.Segments {
+			if int(seg.V2) >= len(vs) {
+				continue
+			}
+			x2, y2 := pixel(seg.V2)
+			edges = append(edges, edge{x1, y1, x2, y2})
+			x1, y1 = x2, y2
+		}
+		if x1 != x0 || y1 != y0 {
+			edges = append(edges, edge{x1, y1, x0, y0})
+		}
+	}
+	return edges
+}
+
+// crossing is the intersection of an edge with a scanline.
+type crossing struct {
+	x       float64
+	winding int
+}
+
+// scanner fills the pixels whose samples are inside the polygons.
+type scanner struct {
+	edges     []edge
+	fillRule  FillRule
+	width     int
+	samples   int
+	crossings []crossing
+}
+
+// row adds to coverage the number of samples inside the polygons for each pixel of row y.
+func (sc *scanner) row(y int, coverage []int) {
+	step := 1 / float64(sc.samples)
+	for sy := 0; sy < sc.samples; sy++ {
+		cy := float64(y) + (float64(sy)+0.5)*step
+		sc.crossings = sc.crossings[:0]
+		for _, e := range sc.edges {
+			if (e.y1 <= cy) == (e.y2 <= cy) {
+				continue
+			}
+			x := e.x1 + (cy-e.y1)*(e.x2-e.x1)/(e.y2-e.y1)
+			w := 1
+			if e.y2 < e.y1 {
+				w = -1
+			}
+			sc.crossings = append(sc.crossings, crossing{x, w})
+		}
+		sort.Slice(sc.crossings, func(i, j int) bool { return sc.crossings[i].x < sc.crossings[j].x })
+		winding := 0
+		for i, c := range sc.crossings {
+			winding += c.winding
+			inside := winding != 0
+			if sc.fillRule == FillEvenOdd {
+				inside = (i+1)%2 == 1
+			}
+			if inside && i+1 < len(sc.crossings) {
+				sc.span(c.x, sc.crossings[i+1].x, step, coverage)
+			}
+		}
+	}
+}
+
+// span adds the samples whose centers are between x1 and x2.
+func (sc *scanner) span(x1, x2, step float64, coverage []int) {
+	// Sample k is centered at (k+0.5)*step.
+	first := int(math.Ceil(x1/step - 0.5))
+	last := int(math.Ceil(x2/step-0.5)) - 1
+	if first < 0 {
+		first = 0
+	}
+	if n := sc.width*sc.samples - 1; last > n {
+		last = n
+	}
+	for k := first; k <= last; k++ {
+		coverage[k/sc.samples]++
+	}
+}
@@ -0,0 +1,223 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package slices
+
+import (
+	"archive/zip"
+	"bytes"
+	"image"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+)
+
+// squares returns a slice with an outer 10x10 square and an inner 6x6 square.
+// The inner square is clockwise when hole is true.
+func squares(hole bool) *Slice {
+	s := &Slice{TopZ: 1, Vertices: Vertices{Vertex: []go3mf.Point2D{
+		{0, 0}, {10, 0}, {10, 10}, {0, 10},
+		{2, 2}, {8, 2}, {8, 8}, {2, 8},
+	}}}
+	s.Polygons = append(s.Polygons, Polygon{StartV: 0, Segments: []Segment{{V2: 1}, {V2: 2}, {V2: 3}, {V2: 0}}})
+	if hole {
+		s.Polygons = append(s.Polygons, Polygon{StartV: 4, Segments: []Segment{{V2: 7}, {V2: 6}, {V2: 5}, {V2: 4}}})
+	} else {
+		s.Polygons = append(s.Polygons, Polygon{StartV: 4, Segments: []Segment{{V2: 5}, {V2: 6}, {V2: 7}, {V2: 4}}})
+	}
+	return s
+}
+
+// grayAt returns the gray level of the pixel, whatever the image type.
+func grayAt(img image.Image, x, y int) uint8 {
+	r, _, _, _ := img.At(x, y).RGBA()
+	return uint8(r >> 8)
+}
+
+func TestRasterizer_RasterizeSlice(t *testing.T) {
+	tests := []struct {
+		name   string
+		r      *Rasterizer
+		s      *Slice
+		w, h   int
+		center uint8
+		border uint8
+	}{
+		{"evenOddHole", &Rasterizer{DPI: 25.4}, squares(true), 10, 10, 0, 255},
+		{"evenOdd", &Rasterizer{DPI: 25.4}, squares(false), 10, 10, 0, 255},
+		{"nonZeroHole", &Rasterizer{DPI: 25.4, FillRule: FillNonZero}, squares(true), 10, 10, 0, 255},
+		{"nonZero", &Rasterizer{DPI: 25.4, FillRule: FillNonZero}, squares(false), 10, 10, 255, 255},
+		{"bilevel", &Rasterizer{DPI: 25.4, Bilevel: true}, squares(true), 10, 10, 0, 255},
+		{"dpi", &Rasterizer{DPI: 50.8}, squares(true), 20, 20, 0, 255},
+		{"units", &Rasterizer{DPI: 25.4, Units: go3mf.UnitCentimeter}, squares(true), 100, 100, 0, 255},
+		{"empty", &Rasterizer{DPI: 25.4}, new(Slice), 1, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := tt.r.RasterizeSlice(tt.s)
+			if err != nil {
+				t.Fatalf("Rasterizer.RasterizeSlice() error = %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+				t.Fatalf("Rasterizer.RasterizeSlice() size = %v, want %dx%d", b, tt.w, tt.h)
+			}
+			if got := grayAt(img, tt.w/2, tt.h/2); got != tt.center {
+				t.Errorf("Rasterizer.RasterizeSlice() center = %d, want %d", got, tt.center)
+			}
+			if got := grayAt(img, 0, tt.h-1); got != tt.border {
+				t.Errorf("Rasterizer.RasterizeSlice() border = %d, want %d", got, tt.border)
+			}
+			if _, ok := img.(*image.Paletted); ok != tt.r.Bilevel {
+				t.Errorf("Rasterizer.RasterizeSlice() type = %T", img)
+			}
+		})
+	}
+}
+
+func TestRasterizer_RasterizeSlice_antialias(t *testing.T) {
+	// The square covers half of the last column and the first row.
+	s := &Slice{Vertices: Vertices{Vertex: []go3mf.Point2D{{0, 0}, {3.5, 0}, {3.5, 3.5}, {0, 3.5}}}}
+	s.Polygons = []Polygon{{StartV: 0, Segments: []Segment{{V2: 1}, {V2: 2}, {V2: 3}, {V2: 0}}}}
+	img, err := (&Rasterizer{DPI: 25.4, Min: go3mf.Point2D{0, 0}, Max: go3mf.Point2D{4, 4}}).RasterizeSlice(s)
+	if err != nil {
+		t.Fatalf("Rasterizer.RasterizeSlice() error = %v", err)
+	}
+	var got [][]uint8
+	for y := 0; y < 4; y++ {
+		var row []uint8
+		for x := 0; x < 4; x++ {
+			row = append(row, grayAt(img, x, y))
+		}
+		got = append(got, row)
+	}
+	want := [][]uint8{
+		{128, 128, 128, 64},
+		{255, 255, 255, 128},
+		{255, 255, 255, 128},
+		{255, 255, 255, 128},
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("Rasterizer.RasterizeSlice() = %v", diff)
+	}
+}
+
+func TestRasterizer_Rasterize(t *testing.T) {
+	st := &SliceStack{Slices: []Slice{*squares(true), {TopZ: 2, Vertices: Vertices{Vertex: []go3mf.Point2D{{0, 0}, {20, 0}, {0, 5}}},
+		Polygons: []Polygon{{StartV: 0, Segments: []Segment{{V2: 1}, {V2: 2}}}}}}}
+	tests := []struct {
+		name    string
+		r       *Rasterizer
+		st      *SliceStack
+		want    int
+		wantErr error
+	}{
+		{"dpi", new(Rasterizer), st, 0, ErrRasterDPI},
+		{"refs", &Rasterizer{DPI: 25.4}, &SliceStack{Refs: []SliceRef{{SliceStackID: 1}}}, 0, ErrRasterRefs},
+		{"size", &Rasterizer{DPI: 1e6}, st, 0, ErrRasterSize},
+		{"base", &Rasterizer{DPI: 25.4}, st, 2, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.r.Rasterize(tt.st)
+			if err != tt.wantErr {
+				t.Fatalf("Rasterizer.Rasterize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("Rasterizer.Rasterize() = %d images, want %d", len(got), tt.want)
+			}
+			// All the layers cover the stack bounding box.
+			for _, img := range got {
+				if b := img.Bounds(); b.Dx() != 20 || b.Dy() != 10 {
+					t.Errorf("Rasterizer.Rasterize() size = %v", b)
+				}
+			}
+		})
+	}
+}
+
+func TestRasterizer_AttachLayers(t *testing.T) {
+	m := &go3mf.Model{Units: go3mf.UnitCentimeter}
+	m.Resources.Assets = append(m.Resources.Assets, &SliceStack{ID: 1, Slices: []Slice{*squares(true)}})
+	m.Childs = map[string]*go3mf.ChildModel{"/3D/other.model": {Resources: go3mf.Resources{
+		Assets: []go3mf.Asset{&SliceStack{ID: 1, Slices: []Slice{*squares(false), *squares(true)}}},
+	}}}
+	st := &SliceStack{ID: 2, Refs: []SliceRef{{SliceStackID: 1, Path: "/3D/other.model"}}}
+	m.Resources.Assets = append(m.Resources.Assets, st)
+	m.Attachments = append(m.Attachments, go3mf.Attachment{Path: "/Metadata/layers.zip", ContentType: "text/plain", Stream: new(bytes.Buffer)})
+	r := &Rasterizer{DPI: 2.54, Bilevel: true}
+	if err := r.AttachLayers(m, "/Metadata/layers.zip", st); err != nil {
+		t.Fatalf("Rasterizer.AttachLayers() error = %v", err)
+	}
+	if len(m.Attachments) != 1 || m.Attachments[0].ContentType != ContentTypeLayers {
+		t.Fatalf("Rasterizer.AttachLayers() attachments = %v", m.Attachments)
+	}
+	buf := m.Attachments[0].Stream.(*bytes.Buffer)
+	z, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+	var names []string
+	for _, f := range z.File {
+		names = append(names, f.Name)
+	}
+	if diff := deep.Equal(names, []string{"layer_00000.png", "layer_00001.png"}); diff != nil {
+		t.Errorf("Rasterizer.AttachLayers() = %v", diff)
+	}
+
+	// The layers survive a write/read round-trip.
+	layers := append([]byte(nil), buf.Bytes()...)
+	var pkg bytes.Buffer
+	if err := go3mf.NewEncoder(&pkg).Encode(m); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	got := new(go3mf.Model)
+	if err := go3mf.NewDecoder(bytes.NewReader(pkg.Bytes()), int64(pkg.Len())).Decode(got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	wantRels := []go3mf.Relationship{{Path: "/Metadata/layers.zip", Type: go3mf.RelTypeMustPreserve}}
+	if diff := deep.Equal(m.Relationships, wantRels); diff != nil {
+		t.Errorf("Rasterizer.AttachLayers() relationships = %v", diff)
+	}
+	if len(got.Attachments) != 1 || got.Attachments[0].Path != "/Metadata/layers.zip" || got.Attachments[0].ContentType != ContentTypeLayers {
+		t.Fatalf("Decoder.Decode() attachments = %v", got.Attachments)
+	}
+	data, err := ioutil.ReadAll(got.Attachments[0].Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, layers) {
+		t.Error("Decoder.Decode() layers do not match")
+	}
+
+	st.Refs = append(st.Refs, SliceRef{SliceStackID: 5})
+	if err := r.AttachLayers(m, "/Metadata/layers.zip", st); err != ErrRasterRefs {
+		t.Errorf("Rasterizer.AttachLayers() error = %v, want %v", err, ErrRasterRefs)
+	}
+}
+
+func TestWriteLayers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "layers")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	imgs, err := (&Rasterizer{DPI: 25.4, Bilevel: true}).Rasterize(&SliceStack{Slices: []Slice{*squares(true), *squares(false)}})
+	if err != nil {
+		t.Fatalf("Rasterizer.Rasterize() error = %v", err)
+	}
+	if err := WriteLayers(dir, imgs); err != nil {
+		t.Fatalf("WriteLayers() error = %v", err)
+	}
+	for i := range imgs {
+		if _, err := os.Stat(filepath.Join(dir, LayerName(i))); err != nil {
+			t.Errorf("WriteLayers() layer %d: %v", i, err)
+		}
+	}
+	if err := WriteLayers(filepath.Join(dir, "missing"), imgs); err == nil {
+		t.Error("WriteLayers() expected error")
+	}
+}
@@ -0,0 +1,301 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package slices
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+)
+
+// Slicer errors.
+var (
+	ErrSlicerLayerHeight = errors.New("slices: layer height must be greater than zero")
+	ErrSlicerZ           = errors.New("slices: slice heights must be increasing and above the mesh bottom")
+	ErrSlicerEmpty       = errors.New("slices: there are no triangles to slice")
+)
+
+// A Slicer cuts meshes with planes parallel to the XY plane to create slice stacks.
+//
+// Each slice covers the layer between the previous slice top, or the stack bottom,
+// and its own top, and its contours are computed at the middle of the layer.
+// Contours are oriented counterclockwise around the solid, seen from the top,
+// so holes are clockwise.
+type Slicer struct {
+	// LayerHeight is the distance between consecutive slices, used when Z is empty.
+	LayerHeight float32
+	// Z defines the top of each slice, in increasing order.
+	Z []float32
+}
+
+// Slice returns the slice stack of mesh.
+// The stack bottom is the lowest vertex of the mesh.
+func (s *Slicer) Slice(mesh *go3mf.Mesh) (*SliceStack, error) {
+	var c triangleCollector
+	c.addMesh(mesh, go3mf.Identity())
+	return s.slice(c.triangles)
+}
+
+// SliceObject returns the slice stack of the object o, defined in the model part path.
+// Components are followed recursively, applying their transforms.
+func (s *Slicer) SliceObject(m *go3mf.Model, path string, o *go3mf.Object) (*SliceStack, error) {
+	var c triangleCollector
+	m.WalkMeshes(path, o, go3mf.Identity(), func(_ string, o *go3mf.Object, t go3mf.Matrix) error {
+		c.addMesh(o.Mesh, t)
+		return nil
+	})
+	return s.slice(c.triangles)
+}
+
+// Attach slices o, adds the slice stack to the resources of the model part path
+// and references it from the object as a low resolution mesh.
+// The slice extension is marked as required, as the specification mandates.
+func (s *Slicer) Attach(m *go3mf.Model, path string, o *go3mf.Object) (*SliceStack, error) {
+	rs, ok := m.FindResources(path)
+	if !ok {
+		return nil, specerr.ErrMissingResource
+	}
+	st, err := s.SliceObject(m, path, o)
+	if err != nil {
+		return nil, err
+	}
+	st.ID = rs.UnusedID()
+	rs.Assets = append(rs.Assets, st)
+	if attr := GetObjectAttr(o); attr != nil {
+		attr.SliceStackID, attr.MeshResolution = st.ID, ResolutionLow
+	} else {
+		o.AnyAttr = append(o.AnyAttr, &ObjectAttr{SliceStackID: st.ID, MeshResolution: ResolutionLow})
+	}
+	for i := range m.Extensions {
+		if m.Extensions[i].Namespace == Namespace {
+			m.Extensions[i].IsRequired = true
+			return st, nil
+		}
+	}
+	ext := DefaultExtension
+	ext.IsRequired = true
+	m.Extensions = append(m.Extensions, ext)
+	return st, nil
+}
+
+func (s *Slicer) slice(triangles [][3]go3mf.Point3D) (*SliceStack, error) {
+	if len(s.Z) == 0 && !(s.LayerHeight > 0) {
+		return nil, ErrSlicerLayerHeight
+	}
+	if len(triangles) == 0 {
+		return nil, ErrSlicerEmpty
+	}
+	minZ, maxZ := float32(math.MaxFloat32), float32(-math.MaxFloat32)
+	for _, t := range triangles {
+		for _, v := range t {
+			minZ, maxZ = float32(math.Min(float64(minZ), float64(v[2]))), float32(math.Max(float64(maxZ), float64(v[2])))
+		}
+	}
+	tops := s.Z
+	if len(tops) == 0 {
+		n := int(math.Ceil(float64(maxZ-minZ) / float64(s.LayerHeight)))
+		if n == 0 {
+			n = 1
+		}
+		tops = make([]float32, n)
+		for i := range tops {
+			tops[i] = float32(float64(minZ) + float64(s.LayerHeight)*float64(i+1))
+		}
+	} else {
+		prev := minZ
+		for _, z := range tops {
+			if z <= prev {
+				return nil, ErrSlicerZ
+			}
+			prev = z
+		}
+	}
+
+	// Sweep the planes from the bottom, keeping the triangles that can cross them.
+	sort.Slice(triangles, func(i, j int) bool {
+		return triangleMinZ(triangles[i]) < triangleMinZ(triangles[j])
+	})
+	st := &SliceStack{BottomZ: minZ, Slices: make([]Slice, len(tops))}
+	var (
+		active []int
+		next   int
+	)
+	prev := minZ
+	for i, top := range tops {
+		z := (prev + top) / 2
+		prev = top
+		for next < len(triangles) && triangleMinZ(triangles[next]) < z {
+			active = append(active, next)
+			next++
+		}
+		b := newSliceBuilder()
+		n := 0
+		for _, t := range active {
+			if triangleMaxZ(triangles[t]) < z {
+				continue
+			}
+			active[n] = t
+			n++
+			b.addTriangle(triangles[t], z)
+		}
+		active = active[:n]
+		st.Slices[i] = b.slice(top)
+	}
+	return st, nil
+}
+
+func triangleMinZ(t [3]go3mf.Point3D) float32 {
+	return float32(math.Min(float64(t[0][2]), math.Min(float64(t[1][2]), float64(t[2][2]))))
+}
+
+func triangleMaxZ(t [3]go3mf.Point3D) float32 {
+	return float32(math.Max(float64(t[0][2]), math.Max(float64(t[1][2]), float64(t[2][2]))))
+}
+
+// triangleCollector gathers the triangles of an object in its own coordinates.
+type triangleCollector struct {
+	triangles [][3]go3mf.Point3D
+}
+
+func (c *triangleCollector) addMesh(m *go3mf.Mesh, t go3mf.Matrix) {
+	vs := m.Vertices.Vertex
+	identity := t == go3mf.Identity()
+	for _, tr := range m.Triangles.Triangle {
+		if int(tr.V1) >= len(vs) || int(tr.V2) >= len(vs) || int(tr.V3) >= len(vs) {
+			continue
+		}
+		p := [3]go3mf.Point3D{vs[tr.V1], vs[tr.V2], vs[tr.V3]}
+		if !identity {
+			p = [3]go3mf.Point3D{t.Mul3D(p[0]), t.Mul3D(p[1]), t.Mul3D(p[2])}
+		}
+		c.triangles = append(c.triangles, p)
+	}
+}
+
+// sliceBuilder creates the contours of a single slice,
+// merging the vertices with the same coordinates.
+type sliceBuilder struct {
+	vertices []go3mf.Point2D
+	indices  map[go3mf.Point2D]uint32
+	segments [][2]uint32
+}
+
+func newSliceBuilder() *sliceBuilder {
+	return &sliceBuilder{indices: make(map[go3mf.Point2D]uint32)}
+}
+
+func (b *sliceBuilder) vertex(p go3mf.Point2D) uint32 {
+	if i, ok := b.indices[p]; ok {
+		return i
+	}
+	i := uint32(len(b.vertices))
+	b.vertices = append(b.vertices, p)
+	b.indices[p] = i
+	return i
+}
+
+// addTriangle adds the segment where the plane at z cuts the triangle.
+// Vertices on the plane are considered above it, so the contours are closed
+// even when the plane goes through vertices or edges.
+func (b *sliceBuilder) addTriangle(t [3]go3mf.Point3D, z float32) {
+	var (
+		pts [2]go3mf.Point2D
+		n   int
+	)
+	for e := 0; e < 3 && n < 2; e++ {
+		v1, v2 := t[e], t[(e+1)%3]
+		if (v1[2] >= z) != (v2[2] >= z) {
+			pts[n] = intersect(v1, v2, z)
+			n++
+		}
+	}
+	if n != 2 {
+		return
+	}
+	// The solid is at the left of the segment, seen from the top.
+	normal := t[1].Sub(t[0]).Cross(t[2].Sub(t[0]))
+	dir := go3mf.Point2D{-normal[1], normal[0]}
+	if (pts[1][0]-pts[0][0])*dir[0]+(pts[1][1]-pts[0][1])*dir[1] < 0 {
+		pts[0], pts[1] = pts[1], pts[0]
+	}
+	i1, i2 := b.vertex(pts[0]), b.vertex(pts[1])
+	if i1 != i2 {
+		b.segments = append(b.segments, [2]uint32{i1, i2})
+	}
+}
+
+// intersect returns the point of the edge at z.
+// The edge ends are sorted so the shared edges of adjacent triangles
+// give exactly the same point.
+func intersect(v1, v2 go3mf.Point3D, z float32) go3mf.Point2D {
+	if v1[2] == z {
+		return go3mf.Point2D{v1[0], v1[1]}
+	}
+	if v2[2] == z {
+		return go3mf.Point2D{v2[0], v2[1]}
+	}
+	if v2[0] < v1[0] || (v2[0] == v1[0] && (v2[1] < v1[1] || (v2[1] == v1[1] && v2[2] < v1[2]))) {
+		v1, v2 = v2, v1
+	}
+	f := (float64(z) - float64(v1[2])) / (float64(v2[2]) - float64(v1[2]))
+	return go3mf.Point2D{
+		float32(float64(v1[0]) + f*(float64(v2[0])-float64(v1[0]))),
+		float32(float64(v1[1]) + f*(float64(v2[1])-float64(v1[1]))),
+	}
+}
+
+// slice chains the segments into polygons.
+// Chains that can not be closed, because the mesh is not watertight,
+// are kept as open polygons.
+func (b *sliceBuilder) slice(top float32) Slice {
+	s := Slice{TopZ: top}
+	if len(b.segments) == 0 {
+		return s
+	}
+	outgoing := make(map[uint32][]int, len(b.segments))
+	incoming := make(map[uint32]int, len(b.segments))
+	for i, seg := range b.segments {
+		outgoing[seg[0]] = append(outgoing[seg[0]], i)
+		incoming[seg[1]]++
+	}
+	used := make([]bool, len(b.segments))
+	chain := func(first int) {
+		p := Polygon{StartV: b.segments[first][0]}
+		for cur := first; ; {
+			used[cur] = true
+			end := b.segments[cur][1]
+			p.Segments = append(p.Segments, Segment{V2: end})
+			if end == p.StartV {
+				break
+			}
+			cur = -1
+			for _, j := range outgoing[end] {
+				if !used[j] {
+					cur = j
+					break
+				}
+			}
+			if cur < 0 {
+				break
+			}
+		}
+		s.Polygons = append(s.Polygons, p)
+	}
+	// Open chains are started from their first segment.
+	for i, seg := range b.segments {
+		if !used[i] && incoming[seg[0]] == 0 {
+			chain(i)
+		}
+	}
+	for i := range b.segments {
+		if !used[i] {
+			chain(i)
+		}
+	}
+	s.Vertices.Vertex = b.vertices
+	return s
+}
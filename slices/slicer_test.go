@@ -0,0 +1,170 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package slices
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/internal/meshtest"
+)
+
+// area returns the signed area of the polygon, positive if it is counterclockwise.
+func area(s *Slice, p *Polygon) float32 {
+	var a float32
+	prev := s.Vertices.Vertex[p.StartV]
+	for _, seg := range p.Segments {
+		v := s.Vertices.Vertex[seg.V2]
+		a += prev[0]*v[1] - v[0]*prev[1]
+		prev = v
+	}
+	return a / 2
+}
+
+func TestSlicer_Slice(t *testing.T) {
+	box := meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 10})
+	// A box with a square hole along the z axis.
+	frame := meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 4})
+	hole := meshtest.Box(go3mf.Point3D{3, 3, 0}, go3mf.Point3D{7, 7, 4})
+	for _, tr := range hole.Triangles.Triangle {
+		n := uint32(len(frame.Vertices.Vertex))
+		frame.Triangles.Triangle = append(frame.Triangles.Triangle, go3mf.Triangle{V1: tr.V1 + n, V2: tr.V3 + n, V3: tr.V2 + n})
+	}
+	frame.Vertices.Vertex = append(frame.Vertices.Vertex, hole.Vertices.Vertex...)
+	tests := []struct {
+		name     string
+		s        *Slicer
+		mesh     *go3mf.Mesh
+		tops     []float32
+		polygons []int
+		areas    []float32
+		wantErr  error
+	}{
+		{"noHeight", new(Slicer), box, nil, nil, nil, ErrSlicerLayerHeight},
+		{"empty", &Slicer{LayerHeight: 1}, new(go3mf.Mesh), nil, nil, nil, ErrSlicerEmpty},
+		{"badZ", &Slicer{Z: []float32{2, 1}}, box, nil, nil, nil, ErrSlicerZ},
+		{"belowBottom", &Slicer{Z: []float32{0}}, box, nil, nil, nil, ErrSlicerZ},
+		{"height", &Slicer{LayerHeight: 3}, box, []float32{3, 6, 9, 12}, []int{1, 1, 1, 0}, []float32{100, 100, 100}, nil},
+		{"z", &Slicer{Z: []float32{5, 10}}, box, []float32{5, 10}, []int{1, 1}, []float32{100, 100}, nil},
+		{"onVertices", &Slicer{Z: []float32{10, 20}}, box, []float32{10, 20}, []int{1, 0}, []float32{100}, nil},
+		{"hole", &Slicer{LayerHeight: 4}, frame, []float32{4}, []int{2}, []float32{100, -16}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.s.Slice(tt.mesh)
+			if err != tt.wantErr {
+				t.Fatalf("Slicer.Slice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.BottomZ != 0 {
+				t.Errorf("Slicer.Slice() bottom = %v", got.BottomZ)
+			}
+			var (
+				tops     []float32
+				polygons []int
+				areas    []float32
+			)
+			for i := range got.Slices {
+				s := &got.Slices[i]
+				tops = append(tops, s.TopZ)
+				polygons = append(polygons, len(s.Polygons))
+				for j := range s.Polygons {
+					p := &s.Polygons[j]
+					if p.Segments[len(p.Segments)-1].V2 != p.StartV {
+						t.Errorf("Slicer.Slice() slice %d polygon %d is open", i, j)
+					}
+					areas = append(areas, area(s, p))
+				}
+			}
+			if diff := deep.Equal(tops, tt.tops); diff != nil {
+				t.Errorf("Slicer.Slice() tops = %v", diff)
+			}
+			if diff := deep.Equal(polygons, tt.polygons); diff != nil {
+				t.Errorf("Slicer.Slice() polygons = %v", diff)
+			}
+			if diff := deep.Equal(areas, tt.areas); diff != nil {
+				t.Errorf("Slicer.Slice() areas = %v", diff)
+			}
+			if err := validateAsset(new(go3mf.Model), "", got); err != nil {
+				t.Errorf("Slicer.Slice() is not valid: %v", err)
+			}
+		})
+	}
+}
+
+func TestSlicer_Slice_open(t *testing.T) {
+	box := meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 10})
+	// Remove one of the side triangles.
+	box.Triangles.Triangle = append(box.Triangles.Triangle[:4], box.Triangles.Triangle[5:]...)
+	got, err := (&Slicer{Z: []float32{10}}).Slice(box)
+	if err != nil {
+		t.Fatalf("Slicer.Slice() error = %v", err)
+	}
+	s := got.Slices[0]
+	if len(s.Polygons) != 1 {
+		t.Fatalf("Slicer.Slice() polygons = %d, want 1", len(s.Polygons))
+	}
+	p := s.Polygons[0]
+	if p.Segments[len(p.Segments)-1].V2 == p.StartV {
+		t.Error("Slicer.Slice() polygon should be open")
+	}
+	if len(p.Segments) != len(s.Vertices.Vertex)-1 {
+		t.Errorf("Slicer.Slice() open polygon has %d segments and %d vertices", len(p.Segments), len(s.Vertices.Vertex))
+	}
+	if isSliceStackClosed(got) {
+		t.Error("isSliceStackClosed() = true")
+	}
+}
+
+func TestSlicer_Attach(t *testing.T) {
+	m := &go3mf.Model{Extensions: []go3mf.Extension{DefaultExtension}}
+	m.Resources.Objects = append(m.Resources.Objects,
+		&go3mf.Object{ID: 1, Mesh: meshtest.Box(go3mf.Point3D{0, 0, 0}, go3mf.Point3D{10, 10, 10})},
+		&go3mf.Object{ID: 2, Components: &go3mf.Components{Component: []*go3mf.Component{
+			{ObjectID: 1},
+			{ObjectID: 1, Transform: go3mf.Identity().Translate(20, 0, 5)},
+		}}},
+	)
+	m.Build.Items = append(m.Build.Items, &go3mf.Item{ObjectID: 2})
+	obj := m.Resources.Objects[1]
+	st, err := (&Slicer{LayerHeight: 5}).Attach(m, "", obj)
+	if err != nil {
+		t.Fatalf("Slicer.Attach() error = %v", err)
+	}
+	if st.ID != 3 {
+		t.Errorf("Slicer.Attach() id = %d, want 3", st.ID)
+	}
+	if got := GetObjectAttr(obj); got == nil || *got != (ObjectAttr{SliceStackID: 3, MeshResolution: ResolutionLow}) {
+		t.Errorf("Slicer.Attach() attr = %v", got)
+	}
+	if !m.Extensions[0].IsRequired || len(m.Extensions) != 1 {
+		t.Errorf("Slicer.Attach() extensions = %v", m.Extensions)
+	}
+	var polygons []int
+	for _, s := range st.Slices {
+		polygons = append(polygons, len(s.Polygons))
+	}
+	// The second box starts at z=5.
+	if diff := deep.Equal(polygons, []int{1, 2, 1}); diff != nil {
+		t.Errorf("Slicer.Attach() polygons = %v", diff)
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("Model.Validate() error = %v", err)
+	}
+
+	m2 := &go3mf.Model{}
+	m2.Resources.Objects = append(m2.Resources.Objects, &go3mf.Object{ID: 1, Mesh: meshtest.Box(go3mf.Point3D{}, go3mf.Point3D{1, 1, 1})})
+	if _, err := (&Slicer{LayerHeight: 1}).Attach(m2, "", m2.Resources.Objects[0]); err != nil {
+		t.Fatalf("Slicer.Attach() error = %v", err)
+	}
+	if len(m2.Extensions) != 1 || !m2.Extensions[0].IsRequired {
+		t.Errorf("Slicer.Attach() extensions = %v", m2.Extensions)
+	}
+	if _, err := (&Slicer{LayerHeight: 1}).Attach(m2, "/missing.model", m2.Resources.Objects[0]); err == nil {
+		t.Error("Slicer.Attach() expected error")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"errors"
 
 	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
 	"github.com/hpinc/go3mf/spec"
 )
 
@@ -22,6 +23,20 @@ var DefaultExtension = go3mf.Extension{
 
 func init() {
 	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrSliceExtRequired:          {ID: "SLICE001", Name: "SliceExtRequired", Severity: reporting.SeverityError},
+		ErrNonSliceStack:             {ID: "SLICE002", Name: "NonSliceStack", Severity: reporting.SeverityError},
+		ErrSlicesAndRefs:             {ID: "SLICE003", Name: "SlicesAndRefs", Severity: reporting.SeverityError},
+		ErrSliceRefSamePart:          {ID: "SLICE004", Name: "SliceRefSamePart", Severity: reporting.SeverityError},
+		ErrSliceRefRef:               {ID: "SLICE005", Name: "SliceRefRef", Severity: reporting.SeverityError},
+		ErrSliceSmallTopZ:            {ID: "SLICE006", Name: "SliceSmallTopZ", Severity: reporting.SeverityError},
+		ErrSliceNoMonotonic:          {ID: "SLICE007", Name: "SliceNoMonotonic", Severity: reporting.SeverityError},
+		ErrSliceInsufficientVertices: {ID: "SLICE008", Name: "SliceInsufficientVertices", Severity: reporting.SeverityError},
+		ErrSliceInsufficientPolygons: {ID: "SLICE009", Name: "SliceInsufficientPolygons", Severity: reporting.SeverityError},
+		ErrSliceInsufficientSegments: {ID: "SLICE010", Name: "SliceInsufficientSegments", Severity: reporting.SeverityError},
+		ErrSlicePolygonNotClosed:     {ID: "SLICE011", Name: "SlicePolygonNotClosed", Severity: reporting.SeverityError},
+		ErrSliceInvalidTranform:      {ID: "SLICE012", Name: "SliceInvalidTranform", Severity: reporting.SeverityError},
+	})
 }
 
 type Spec struct{}
@@ -119,6 +134,18 @@ func (SliceStack) XMLName() xml.Name {
 	return xml.Name{Space: Namespace, Local: attrSliceStack}
 }
 
+// Scale multiplies the BottomZ, TopZ and the slice vertices by factor.
+func (s *SliceStack) Scale(factor float32) {
+	s.BottomZ *= factor
+	for i := range s.Slices {
+		slice := &s.Slices[i]
+		slice.TopZ *= factor
+		for j, v := range slice.Vertices.Vertex {
+			slice.Vertices.Vertex[j] = go3mf.Point2D{v[0] * factor, v[1] * factor}
+		}
+	}
+}
+
 func GetObjectAttr(obj *go3mf.Object) *ObjectAttr {
 	for _, a := range obj.AnyAttr {
 		if a, ok := a.(*ObjectAttr); ok {
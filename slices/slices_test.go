@@ -15,6 +15,7 @@ var _ go3mf.Asset = new(SliceStack)
 var _ spec.Marshaler = new(SliceStack)
 var _ spec.Marshaler = new(ObjectAttr)
 var _ spec.Spec = new(Spec)
+var _ spec.Scaler = new(SliceStack)
 
 func TestSliceStack_Identify(t *testing.T) {
 	tests := []struct {
@@ -72,3 +73,18 @@ func Test_newMeshResolution(t *testing.T) {
 		})
 	}
 }
+
+func TestSliceStack_Scale(t *testing.T) {
+	s := &SliceStack{BottomZ: 1, Slices: []Slice{
+		{TopZ: 2, Vertices: Vertices{Vertex: []go3mf.Point2D{{1, 2}, {3, 4}}}},
+		{TopZ: 3},
+	}, Refs: []SliceRef{{SliceStackID: 1, Path: "/a.model"}}}
+	want := &SliceStack{BottomZ: 10, Slices: []Slice{
+		{TopZ: 20, Vertices: Vertices{Vertex: []go3mf.Point2D{{10, 20}, {30, 40}}}},
+		{TopZ: 30},
+	}, Refs: []SliceRef{{SliceStackID: 1, Path: "/a.model"}}}
+	s.Scale(10)
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("SliceStack.Scale() = %v, want %v", s, want)
+	}
+}
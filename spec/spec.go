@@ -57,6 +57,36 @@ type ValidateSpec interface {
 	Validate(model interface{}, path string, element interface{}) error
 }
 
+// Scaler is implemented by the spec elements and attributes
+// that contain lengths, so they can be rescaled when the model units change.
+type Scaler interface {
+	Scale(factor float32)
+}
+
+// ObjectContent is implemented by the extension elements that define
+// the content of an object instead of a mesh or components.
+type ObjectContent interface {
+	ObjectContent()
+}
+
+// RemovedIndex marks the removed elements in the remap tables
+// passed to VertexRemapper and TriangleRemapper.
+const RemovedIndex = ^uint32(0)
+
+// VertexRemapper is implemented by the mesh extension elements that
+// reference vertices by index, so they can be updated when the mesh vertices change.
+// The new index of vertex i is remap[i].
+type VertexRemapper interface {
+	RemapVertices(remap []uint32)
+}
+
+// TriangleRemapper is implemented by the mesh extension elements that
+// reference triangles by index, so they can be updated when the mesh triangles change.
+// The new index of triangle i is remap[i], or RemovedIndex if it has been removed.
+type TriangleRemapper interface {
+	RemapTriangles(remap []uint32)
+}
+
 // An XMLAttr represents an attribute in an XML element (Name=Value).
 type XMLAttr struct {
 	Name  xml.Name
@@ -123,7 +153,7 @@ type Any []Marshaler
 
 func (e Any) Marshal3MF(x Encoder, start *xml.StartElement) error {
 	for _, ext := range e {
-		if err := ext.Marshal3MF(x, start); err == nil {
+		if err := ext.Marshal3MF(x, start); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,49 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package spec
+
+import (
+	"encoding/xml"
+	"errors"
+	"testing"
+)
+
+type fakeMarshaler struct {
+	err   error
+	calls *int
+}
+
+func (f fakeMarshaler) Marshal3MF(Encoder, *xml.StartElement) error {
+	*f.calls++
+	return f.err
+}
+
+func TestAny_Marshal3MF(t *testing.T) {
+	errFake := errors.New("fake")
+	tests := []struct {
+		name      string
+		errs      []error
+		want      error
+		wantCalls int
+	}{
+		{"empty", nil, nil, 0},
+		{"all", []error{nil, nil, nil}, nil, 3},
+		{"error", []error{nil, errFake, nil}, errFake, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			var e Any
+			for _, err := range tt.errs {
+				e = append(e, fakeMarshaler{err: err, calls: &calls})
+			}
+			if err := e.Marshal3MF(nil, &xml.StartElement{}); err != tt.want {
+				t.Errorf("Any.Marshal3MF() error = %v, want %v", err, tt.want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("Any.Marshal3MF() encoded %d elements, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
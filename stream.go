@@ -0,0 +1,219 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/hpinc/go3mf/spec"
+)
+
+// ErrStreamState is returned when a StreamEncoder method is called out of order.
+var ErrStreamState = errors.New("go3mf: stream encoder method called in invalid state")
+
+type streamState uint8
+
+const (
+	streamResources streamState = iota
+	streamObject
+	streamVertices
+	streamTriangles
+	streamClosed
+)
+
+// A StreamEncoder writes the root model incrementally,
+// so mesh data does not have to be held in memory.
+//
+// The expected call sequence is:
+//
+//	StartObject, WriteVertices*, WriteTriangles*, EndObject
+//
+// repeated for every mesh object, with WriteAsset and WriteObject
+// calls allowed between objects, and a final Close.
+type StreamEncoder struct {
+	e      *Encoder
+	m      *Model
+	w      packagePart
+	x      *xmlEncoder
+	state  streamState
+	obj    *Object
+	mesh   *Mesh
+	tokens struct {
+		model, resources, object, mesh, vertices, triangles xml.StartElement
+	}
+}
+
+// Stream starts encoding m and returns a StreamEncoder that can be used to
+// write the rest of the root model resources.
+//
+// The attachments, metadata, and the resources already defined in m are
+// written immediately. The build, the model extensions elements and the child models
+// are written when the StreamEncoder is closed, so they can be filled
+// while streaming.
+func (e *Encoder) Stream(m *Model) (*StreamEncoder, error) {
+	w, x, err := e.createRootModel(m)
+	if err != nil {
+		return nil, err
+	}
+	s := &StreamEncoder{e: e, m: m, w: w, x: x}
+	s.tokens.model, err = e.modelToken(x, m, true)
+	if err != nil {
+		return nil, err
+	}
+	x.EncodeToken(s.tokens.model)
+	e.writeMetadata(x, m.Metadata)
+	s.tokens.resources = xml.StartElement{Name: xml.Name{Local: attrResources}}
+	m.Resources.AnyAttr.Marshal3MF(x, &s.tokens.resources)
+	x.EncodeToken(s.tokens.resources)
+	for _, r := range m.Resources.Assets {
+		if err = s.WriteAsset(r); err != nil {
+			return nil, err
+		}
+	}
+	for _, o := range m.Resources.Objects {
+		if err = s.writeObject(o); err != nil {
+			return nil, err
+		}
+	}
+	return s, x.Flush()
+}
+
+// WriteAsset writes a complete asset.
+// The asset is not added to the model.
+func (s *StreamEncoder) WriteAsset(r Asset) error {
+	if s.state != streamResources {
+		return ErrStreamState
+	}
+	if r, ok := r.(spec.Marshaler); ok {
+		if err := r.Marshal3MF(s.x, &s.tokens.resources); err != nil {
+			return err
+		}
+	}
+	return s.x.Flush()
+}
+
+// WriteObject writes a complete object, either a mesh or a components object.
+// The object is not added to the model.
+func (s *StreamEncoder) WriteObject(o *Object) error {
+	if s.state != streamResources {
+		return ErrStreamState
+	}
+	return s.writeObject(o)
+}
+
+func (s *StreamEncoder) writeObject(o *Object) error {
+	s.e.writeObject(s.x, o)
+	return s.x.Flush()
+}
+
+// StartObject starts a mesh object.
+// The object attributes, its metadata and, if o.Mesh is not nil,
+// the mesh, vertices and triangles attributes are written,
+// but the vertices and triangles defined in o.Mesh are ignored.
+func (s *StreamEncoder) StartObject(o *Object) error {
+	if s.state != streamResources {
+		return ErrStreamState
+	}
+	s.obj, s.mesh = o, o.Mesh
+	if s.mesh == nil {
+		s.mesh = new(Mesh)
+	}
+	s.tokens.object = s.e.objectToken(s.x, o, true)
+	s.x.EncodeToken(s.tokens.object)
+	if len(o.Metadata.Metadata) != 0 {
+		s.e.writeMetadataGroup(s.x, o.Metadata)
+	}
+	s.tokens.mesh = xml.StartElement{Name: xml.Name{Local: attrMesh}}
+	s.mesh.AnyAttr.Marshal3MF(s.x, &s.tokens.mesh)
+	s.x.EncodeToken(s.tokens.mesh)
+	s.state = streamObject
+	return nil
+}
+
+// WriteVertices writes a chunk of vertices of the current object.
+// It must be called before writing any triangle.
+func (s *StreamEncoder) WriteVertices(vs []Point3D) error {
+	switch s.state {
+	case streamObject:
+		s.startVertices()
+	case streamVertices:
+	default:
+		return ErrStreamState
+	}
+	s.e.writeVertexChunk(s.x, vs)
+	return s.x.Flush()
+}
+
+// WriteTriangles writes a chunk of triangles of the current object.
+// The triangles can only reference vertices already written.
+func (s *StreamEncoder) WriteTriangles(ts []Triangle) error {
+	switch s.state {
+	case streamObject:
+		s.startVertices()
+		fallthrough
+	case streamVertices:
+		s.startTriangles()
+	case streamTriangles:
+	default:
+		return ErrStreamState
+	}
+	s.e.writeTriangleChunk(s.x, s.obj, ts)
+	return s.x.Flush()
+}
+
+// EndObject ends the current object, writing its extension elements after the mesh.
+func (s *StreamEncoder) EndObject() error {
+	switch s.state {
+	case streamObject:
+		s.startVertices()
+		fallthrough
+	case streamVertices:
+		s.startTriangles()
+	case streamTriangles:
+	default:
+		return ErrStreamState
+	}
+	s.x.EncodeToken(s.tokens.triangles.End())
+	s.mesh.Any.Marshal3MF(s.x, &s.tokens.mesh)
+	s.x.EncodeToken(s.tokens.mesh.End())
+	s.obj.Any.Marshal3MF(s.x, &s.tokens.object)
+	s.x.EncodeToken(s.tokens.object.End())
+	s.obj, s.mesh = nil, nil
+	s.state = streamResources
+	return s.x.Flush()
+}
+
+// Close writes the build, the model extensions elements and the child models,
+// and closes the package.
+// It does not close the underlying writer.
+func (s *StreamEncoder) Close() error {
+	if s.state != streamResources {
+		return ErrStreamState
+	}
+	s.state = streamClosed
+	s.x.EncodeToken(s.tokens.resources.End())
+	s.e.writeBuild(s.x, s.m)
+	s.m.Any.Marshal3MF(s.x, &s.tokens.model)
+	s.x.EncodeToken(s.tokens.model.End())
+	if err := s.x.Flush(); err != nil {
+		return err
+	}
+	return s.e.closeRootModel(s.m, s.w, s.x)
+}
+
+func (s *StreamEncoder) startVertices() {
+	s.tokens.vertices = xml.StartElement{Name: xml.Name{Local: attrVertices}}
+	s.mesh.Vertices.AnyAttr.Marshal3MF(s.x, &s.tokens.vertices)
+	s.x.EncodeToken(s.tokens.vertices)
+	s.state = streamVertices
+}
+
+func (s *StreamEncoder) startTriangles() {
+	s.x.EncodeToken(s.tokens.vertices.End())
+	s.tokens.triangles = xml.StartElement{Name: xml.Name{Local: attrTriangles}}
+	s.mesh.Triangles.AnyAttr.Marshal3MF(s.x, &s.tokens.triangles)
+	s.x.EncodeToken(s.tokens.triangles)
+	s.state = streamTriangles
+}
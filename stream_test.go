@@ -0,0 +1,175 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestStreamEncoder_Roundtrip(t *testing.T) {
+	vertices := []Point3D{{0, 0, 0}, {100, 0, 0}, {0, 100, 0}, {0, 0, 100}}
+	triangles := []Triangle{
+		{V1: 0, V2: 2, V3: 1, PID: 1, P1: 1, P2: 1, P3: 1},
+		{V1: 0, V2: 1, V3: 3},
+		{V1: 0, V2: 3, V3: 2},
+		{V1: 1, V2: 2, V3: 3, PID: 1, P1: 0, P2: 1, P3: 0},
+	}
+	want := &Model{
+		Path: DefaultModelPath, Units: UnitMillimeter, Extensions: []Extension{fooSpec},
+		Metadata: []Metadata{{Name: xml.Name{Local: "Application"}, Value: "go3mf"}},
+		Resources: Resources{
+			Assets: []Asset{&BaseMaterials{ID: 1, Materials: []Base{
+				{Name: "a", Color: color.RGBA{R: 255, A: 255}},
+				{Name: "b", Color: color.RGBA{B: 255, A: 255}},
+			}}},
+			Objects: []*Object{
+				{ID: 2, Name: "streamed", PartNumber: "p", Mesh: &Mesh{
+					Vertices: Vertices{Vertex: vertices}, Triangles: Triangles{Triangle: triangles},
+				}},
+				{ID: 3, Metadata: MetadataGroup{Metadata: []Metadata{{Name: xml.Name{Local: "a"}, Value: "b"}}}, Mesh: &Mesh{
+					Vertices: Vertices{Vertex: vertices}, Triangles: Triangles{Triangle: triangles},
+				}, Any: spec.Any{&spec.UnknownTokens{Token: []xml.Token{
+					xml.StartElement{Name: fooName},
+					xml.EndElement{Name: fooName},
+				}}}},
+				{ID: 4, Components: &Components{Component: []*Component{{ObjectID: 2}, {ObjectID: 3}}}},
+			},
+		},
+		Build: Build{Items: []*Item{{ObjectID: 4}}},
+	}
+
+	m := &Model{
+		Extensions: want.Extensions,
+		Metadata:   want.Metadata,
+		Resources:  Resources{Assets: want.Resources.Assets},
+	}
+	buff := new(bytes.Buffer)
+	s, err := NewEncoder(buff).Stream(m)
+	if err != nil {
+		t.Fatalf("Encoder.Stream() error = %v", err)
+	}
+	for _, obj := range want.Resources.Objects[:2] {
+		streamed := *obj
+		streamed.Mesh = nil
+		if err := s.StartObject(&streamed); err != nil {
+			t.Fatalf("StreamEncoder.StartObject() error = %v", err)
+		}
+		for i := 0; i < len(vertices); i += 3 {
+			end := i + 3
+			if end > len(vertices) {
+				end = len(vertices)
+			}
+			if err := s.WriteVertices(vertices[i:end]); err != nil {
+				t.Fatalf("StreamEncoder.WriteVertices() error = %v", err)
+			}
+		}
+		for i := range triangles {
+			if err := s.WriteTriangles(triangles[i : i+1]); err != nil {
+				t.Fatalf("StreamEncoder.WriteTriangles() error = %v", err)
+			}
+		}
+		if err := s.EndObject(); err != nil {
+			t.Fatalf("StreamEncoder.EndObject() error = %v", err)
+		}
+	}
+	if err := s.WriteObject(want.Resources.Objects[2]); err != nil {
+		t.Fatalf("StreamEncoder.WriteObject() error = %v", err)
+	}
+	m.Build.Items = want.Build.Items
+	if err := s.Close(); err != nil {
+		t.Fatalf("StreamEncoder.Close() error = %v", err)
+	}
+
+	got := new(Model)
+	if err := NewDecoder(bytes.NewReader(buff.Bytes()), int64(buff.Len())).Decode(got); err != nil {
+		t.Fatalf("StreamEncoder malformed = %v", err)
+	}
+	if diff := deep.Equal(got, want); diff != nil {
+		t.Errorf("StreamEncoder = %v", diff)
+	}
+}
+
+func TestStreamEncoder_EmptyObject(t *testing.T) {
+	buff := new(bytes.Buffer)
+	s, err := NewEncoder(buff).Stream(new(Model))
+	if err != nil {
+		t.Fatalf("Encoder.Stream() error = %v", err)
+	}
+	if err := s.StartObject(&Object{ID: 1}); err != nil {
+		t.Fatalf("StreamEncoder.StartObject() error = %v", err)
+	}
+	if err := s.EndObject(); err != nil {
+		t.Fatalf("StreamEncoder.EndObject() error = %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("StreamEncoder.Close() error = %v", err)
+	}
+	got := new(Model)
+	if err := NewDecoder(bytes.NewReader(buff.Bytes()), int64(buff.Len())).Decode(got); err != nil {
+		t.Fatalf("StreamEncoder malformed = %v", err)
+	}
+	want := []*Object{{ID: 1, Mesh: new(Mesh)}}
+	if diff := deep.Equal(got.Resources.Objects, want); diff != nil {
+		t.Errorf("StreamEncoder = %v", diff)
+	}
+}
+
+func TestStreamEncoder_State(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(s *StreamEncoder) error
+	}{
+		{"vertices", func(s *StreamEncoder) error {
+			return s.WriteVertices([]Point3D{{}})
+		}},
+		{"triangles", func(s *StreamEncoder) error {
+			return s.WriteTriangles([]Triangle{{}})
+		}},
+		{"end", func(s *StreamEncoder) error {
+			return s.EndObject()
+		}},
+		{"verticesAfterTriangles", func(s *StreamEncoder) error {
+			s.StartObject(new(Object))
+			s.WriteTriangles(nil)
+			return s.WriteVertices(nil)
+		}},
+		{"startTwice", func(s *StreamEncoder) error {
+			s.StartObject(new(Object))
+			return s.StartObject(new(Object))
+		}},
+		{"assetInObject", func(s *StreamEncoder) error {
+			s.StartObject(new(Object))
+			return s.WriteAsset(new(BaseMaterials))
+		}},
+		{"objectInObject", func(s *StreamEncoder) error {
+			s.StartObject(new(Object))
+			return s.WriteObject(new(Object))
+		}},
+		{"closeInObject", func(s *StreamEncoder) error {
+			s.StartObject(new(Object))
+			return s.Close()
+		}},
+		{"closeTwice", func(s *StreamEncoder) error {
+			s.Close()
+			return s.Close()
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewEncoder(new(bytes.Buffer)).Stream(new(Model))
+			if err != nil {
+				t.Fatalf("Encoder.Stream() error = %v", err)
+			}
+			if err := tt.fn(s); err != ErrStreamState {
+				t.Errorf("StreamEncoder error = %v, want %v", err, ErrStreamState)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package thumbnail
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hpinc/go3mf"
+)
+
+const (
+	// supersampling is the number of samples per pixel in each axis.
+	supersampling = 2
+	// margin is the fraction of the image left empty at each side.
+	margin   = 0.05
+	ambient  = 0.35
+	diffuse  = 1 - ambient
+	maxColor = 255
+)
+
+// vec is a point in screen space, z is the distance to the camera.
+type vec struct {
+	x, y, z float64
+}
+
+// raster is an orthographic z-buffer rasterizer.
+type raster struct {
+	width, height int
+	forward       go3mf.Point3D
+	right, up     go3mf.Point3D
+	light         go3mf.Point3D
+	depth         []float64
+	pixels        []color.NRGBA
+	covered       []bool
+}
+
+func newRaster(width, height int, dir go3mf.Point3D) *raster {
+	forward := dir.Normalize()
+	up := go3mf.Point3D{0, 0, 1}
+	right := forward.Cross(up)
+	if right.Len() < 1e-6 {
+		// Looking along the z axis, keep y pointing up.
+		up = go3mf.Point3D{0, 1, 0}
+		right = forward.Cross(up)
+	}
+	right = right.Normalize()
+	up = right.Cross(forward).Normalize()
+	// The light comes from the top left of the camera, so adjacent faces get different shades.
+	light := forward.Mul(-1).Add(up.Mul(0.6)).Sub(right.Mul(0.4)).Normalize()
+	return &raster{
+		width: width, height: height,
+		forward: forward, right: right, up: up, light: light,
+	}
+}
+
+// draw rasterizes the triangles fitting them in the image.
+func (r *raster) draw(triangles []triangle, bg color.NRGBA) *image.NRGBA {
+	sw, sh := r.width*supersampling, r.height*supersampling
+	r.depth = make([]float64, sw*sh)
+	r.pixels = make([]color.NRGBA, sw*sh)
+	r.covered = make([]bool, sw*sh)
+	for i := range r.depth {
+		r.depth[i] = math.Inf(1)
+	}
+	if len(triangles) > 0 {
+		projected := make([][3]vec, len(triangles))
+		minX, minY := math.Inf(1), math.Inf(1)
+		maxX, maxY := math.Inf(-1), math.Inf(-1)
+		for i, t := range triangles {
+			for j, v := range t.vertices {
+				p := vec{float64(v.Dot(r.right)), float64(v.Dot(r.up)), float64(v.Dot(r.forward))}
+				projected[i][j] = p
+				minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+				minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+			}
+		}
+		scale := math.Inf(1)
+		if dx := maxX - minX; dx > 0 {
+			scale = float64(sw) * (1 - 2*margin) / dx
+		}
+		if dy := maxY - minY; dy > 0 {
+			scale = math.Min(scale, float64(sh)*(1-2*margin)/dy)
+		}
+		if math.IsInf(scale, 1) {
+			scale = 1
+		}
+		cx, cy := (minX+maxX)/2, (minY+maxY)/2
+		for i, t := range triangles {
+			var p [3]vec
+			for j, v := range projected[i] {
+				p[j] = vec{(v.x-cx)*scale + float64(sw)/2, float64(sh)/2 - (v.y-cy)*scale, v.z}
+			}
+			r.fill(p, t.colors, r.shade(t.vertices))
+		}
+	}
+	return r.resolve(bg)
+}
+
+// shade returns the light intensity of a triangle,
+// which is lit on both sides.
+func (r *raster) shade(v [3]go3mf.Point3D) float64 {
+	n := v[1].Sub(v[0]).Cross(v[2].Sub(v[0])).Normalize()
+	return ambient + diffuse*math.Abs(float64(n.Dot(r.light)))
+}
+
+func edge(a, b vec, x, y float64) float64 {
+	return (b.x-a.x)*(y-a.y) - (b.y-a.y)*(x-a.x)
+}
+
+func (r *raster) fill(p [3]vec, colors [3]color.RGBA, shade float64) {
+	area := edge(p[0], p[1], p[2].x, p[2].y)
+	if area == 0 {
+		return
+	}
+	sw, sh := r.width*supersampling, r.height*supersampling
+	minX := int(math.Max(0, math.Floor(math.Min(p[0].x, math.Min(p[1].x, p[2].x)))))
+	maxX := int(math.Min(float64(sw-1), math.Ceil(math.Max(p[0].x, math.Max(p[1].x, p[2].x)))))
+	minY := int(math.Max(0, math.Floor(math.Min(p[0].y, math.Min(p[1].y, p[2].y)))))
+	maxY := int(math.Min(float64(sh-1), math.Ceil(math.Max(p[0].y, math.Max(p[1].y, p[2].y)))))
+	for y := minY; y <= maxY; y++ {
+		py := float64(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float64(x) + 0.5
+			w0 := edge(p[1], p[2], px, py) / area
+			w1 := edge(p[2], p[0], px, py) / area
+			w2 := 1 - w0 - w1
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+			i := y*sw + x
+			z := w0*p[0].z + w1*p[1].z + w2*p[2].z
+			if z >= r.depth[i] {
+				continue
+			}
+			r.depth[i] = z
+			r.covered[i] = true
+			r.pixels[i] = color.NRGBA{
+				R: channel(shade * (w0*float64(colors[0].R) + w1*float64(colors[1].R) + w2*float64(colors[2].R))),
+				G: channel(shade * (w0*float64(colors[0].G) + w1*float64(colors[1].G) + w2*float64(colors[2].G))),
+				B: channel(shade * (w0*float64(colors[0].B) + w1*float64(colors[1].B) + w2*float64(colors[2].B))),
+				A: maxColor,
+			}
+		}
+	}
+}
+
+// resolve averages the samples of each pixel over the background.
+func (r *raster) resolve(bg color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, r.width, r.height))
+	sw := r.width * supersampling
+	const samples = supersampling * supersampling
+	for y := 0; y < r.height; y++ {
+		for x := 0; x < r.width; x++ {
+			var red, green, blue, alpha float64
+			for sy := 0; sy < supersampling; sy++ {
+				for sx := 0; sx < supersampling; sx++ {
+					i := (y*supersampling+sy)*sw + x*supersampling + sx
+					c := bg
+					if r.covered[i] {
+						c = r.pixels[i]
+					}
+					a := float64(c.A)
+					red, green, blue, alpha = red+float64(c.R)*a, green+float64(c.G)*a, blue+float64(c.B)*a, alpha+a
+				}
+			}
+			if alpha == 0 {
+				continue
+			}
+			img.SetNRGBA(x, y, color.NRGBA{
+				R: channel(red / alpha), G: channel(green / alpha), B: channel(blue / alpha),
+				A: channel(alpha / samples),
+			})
+		}
+	}
+	return img
+}
+
+func channel(v float64) uint8 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= maxColor {
+		return maxColor
+	}
+	return uint8(math.Round(v))
+}
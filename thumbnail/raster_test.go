@@ -0,0 +1,90 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package thumbnail
+
+import (
+	"image/color"
+	"math"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+)
+
+func Test_newRaster(t *testing.T) {
+	tests := []struct {
+		name string
+		dir  go3mf.Point3D
+	}{
+		{"iso", defaultDirection},
+		{"top", go3mf.Point3D{0, 0, -1}},
+		{"bottom", go3mf.Point3D{0, 0, 1}},
+		{"front", go3mf.Point3D{0, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newRaster(1, 1, tt.dir)
+			for _, v := range []go3mf.Point3D{r.forward, r.right, r.up, r.light} {
+				if math.Abs(float64(v.Len())-1) > 1e-5 {
+					t.Errorf("newRaster() vector %v is not normalized", v)
+				}
+			}
+			if d := r.forward.Dot(r.right) + r.forward.Dot(r.up) + r.right.Dot(r.up); math.Abs(float64(d)) > 1e-5 {
+				t.Errorf("newRaster() basis is not orthogonal")
+			}
+		})
+	}
+}
+
+func Test_raster_draw(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	blue := color.RGBA{B: 255, A: 255}
+	// Two overlapping squares, seen from the top, the blue one is closer to the camera.
+	square := func(z float32, c color.RGBA) []triangle {
+		cs := [3]color.RGBA{c, c, c}
+		return []triangle{
+			{vertices: [3]go3mf.Point3D{{0, 0, z}, {10, 0, z}, {10, 10, z}}, colors: cs},
+			{vertices: [3]go3mf.Point3D{{0, 0, z}, {10, 10, z}, {0, 10, z}}, colors: cs},
+		}
+	}
+	tris := append(square(0, red), square(5, blue)...)
+	img := newRaster(10, 10, go3mf.Point3D{0, 0, -1}).draw(tris, color.NRGBA{})
+	c := img.NRGBAAt(5, 5)
+	if c.B == 0 || c.R != 0 || c.A != 255 {
+		t.Errorf("raster.draw() = %v, want blue", c)
+	}
+	// Flat triangles facing the light get the full intensity.
+	want := uint8(math.Round(255 * (ambient + diffuse*math.Abs(float64(go3mf.Point3D{0, 0, 1}.Dot(newRaster(1, 1, go3mf.Point3D{0, 0, -1}).light))))))
+	if c.B != want {
+		t.Errorf("raster.draw() blue = %d, want %d", c.B, want)
+	}
+}
+
+func Test_raster_resolve(t *testing.T) {
+	r := newRaster(1, 1, defaultDirection)
+	r.pixels = make([]color.NRGBA, supersampling*supersampling)
+	r.covered = make([]bool, supersampling*supersampling)
+	r.pixels[0], r.covered[0] = color.NRGBA{R: 255, A: 255}, true
+	got := r.resolve(color.NRGBA{})
+	if want := (color.NRGBA{R: 255, A: 64}); got.NRGBAAt(0, 0) != want {
+		t.Errorf("raster.resolve() = %v, want %v", got.NRGBAAt(0, 0), want)
+	}
+	got = r.resolve(color.NRGBA{B: 255, A: 255})
+	if want := (color.NRGBA{R: 64, B: 191, A: 255}); got.NRGBAAt(0, 0) != want {
+		t.Errorf("raster.resolve() = %v, want %v", got.NRGBAAt(0, 0), want)
+	}
+}
+
+func Test_channel(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want uint8
+	}{
+		{-1, 0}, {0, 0}, {10.4, 10}, {10.6, 11}, {255, 255}, {300, 255},
+	}
+	for _, tt := range tests {
+		if got := channel(tt.v); got != tt.want {
+			t.Errorf("channel(%v) = %v, want %v", tt.v, got, tt.want)
+		}
+	}
+}
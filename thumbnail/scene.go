@@ -0,0 +1,84 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package thumbnail
+
+import (
+	"image/color"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+// triangle is a mesh triangle in build coordinates
+// with the color of each vertex.
+type triangle struct {
+	vertices [3]go3mf.Point3D
+	colors   [3]color.RGBA
+}
+
+type scene struct {
+	model     *go3mf.Model
+	color     color.RGBA
+	triangles []triangle
+}
+
+func (s *scene) addItem(item *go3mf.Item) {
+	s.model.WalkItemMeshes(item, s.addMesh)
+}
+
+func (s *scene) addObject(o *go3mf.Object, path string) {
+	s.model.WalkMeshes(path, o, go3mf.Identity(), s.addMesh)
+}
+
+func (s *scene) addMesh(path string, o *go3mf.Object, t go3mf.Matrix) error {
+	vertices := o.Mesh.Vertices.Vertex
+	for i := range o.Mesh.Triangles.Triangle {
+		tr := &o.Mesh.Triangles.Triangle[i]
+		if int(tr.V1) >= len(vertices) || int(tr.V2) >= len(vertices) || int(tr.V3) >= len(vertices) {
+			continue
+		}
+		s.triangles = append(s.triangles, triangle{
+			vertices: [3]go3mf.Point3D{t.Mul3D(vertices[tr.V1]), t.Mul3D(vertices[tr.V2]), t.Mul3D(vertices[tr.V3])},
+			colors:   s.triangleColors(o, path, tr),
+		})
+	}
+	return nil
+}
+
+// triangleColors returns the colors of the triangle vertices,
+// falling back to the object default property and then to the scene color.
+func (s *scene) triangleColors(o *go3mf.Object, path string, t *go3mf.Triangle) [3]color.RGBA {
+	def := [3]color.RGBA{s.color, s.color, s.color}
+	pid, indices := t.PID, [3]uint32{t.P1, t.P2, t.P3}
+	if pid == 0 {
+		pid, indices = o.PID, [3]uint32{o.PIndex, o.PIndex, o.PIndex}
+	}
+	if pid == 0 {
+		return def
+	}
+	a, ok := s.model.FindAsset(path, pid)
+	if !ok {
+		return def
+	}
+	var colors []color.RGBA
+	switch a := a.(type) {
+	case *go3mf.BaseMaterials:
+		colors = make([]color.RGBA, len(a.Materials))
+		for i, b := range a.Materials {
+			colors[i] = b.Color
+		}
+	case *materials.ColorGroup:
+		colors = a.Colors
+	default:
+		return def
+	}
+	var c [3]color.RGBA
+	for j, i := range indices {
+		if int(i) >= len(colors) {
+			return def
+		}
+		c[j] = colors[i]
+	}
+	return c
+}
@@ -0,0 +1,62 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package thumbnail
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+func Test_scene_triangleColors(t *testing.T) {
+	red, green, blue := color.RGBA{R: 255, A: 255}, color.RGBA{G: 255, A: 255}, color.RGBA{B: 255, A: 255}
+	m := new(go3mf.Model)
+	m.Resources.Assets = append(m.Resources.Assets,
+		&go3mf.BaseMaterials{ID: 1, Materials: []go3mf.Base{{Name: "a", Color: red}, {Name: "b", Color: green}}},
+		&materials.ColorGroup{ID: 2, Colors: []color.RGBA{red, green, blue}},
+		&materials.Texture2DGroup{ID: 3},
+	)
+	s := &scene{model: m, color: defaultColor}
+	def := [3]color.RGBA{defaultColor, defaultColor, defaultColor}
+	tests := []struct {
+		name string
+		o    *go3mf.Object
+		t    *go3mf.Triangle
+		want [3]color.RGBA
+	}{
+		{"none", new(go3mf.Object), new(go3mf.Triangle), def},
+		{"object", &go3mf.Object{PID: 1, PIndex: 1}, new(go3mf.Triangle), [3]color.RGBA{green, green, green}},
+		{"base", new(go3mf.Object), &go3mf.Triangle{PID: 1, P1: 1, P2: 0, P3: 1}, [3]color.RGBA{green, red, green}},
+		{"group", &go3mf.Object{PID: 1}, &go3mf.Triangle{PID: 2, P1: 0, P2: 1, P3: 2}, [3]color.RGBA{red, green, blue}},
+		{"texture", new(go3mf.Object), &go3mf.Triangle{PID: 3}, def},
+		{"missing", new(go3mf.Object), &go3mf.Triangle{PID: 4}, def},
+		{"outOfBounds", new(go3mf.Object), &go3mf.Triangle{PID: 2, P1: 3}, def},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(s.triangleColors(tt.o, "", tt.t), tt.want); diff != nil {
+				t.Errorf("scene.triangleColors() = %v", diff)
+			}
+		})
+	}
+}
+
+func Test_scene_addItem(t *testing.T) {
+	m := new(go3mf.Model)
+	m.Resources.Objects = append(m.Resources.Objects, createCube(1), &go3mf.Object{ID: 2, Components: &go3mf.Components{
+		Component: []*go3mf.Component{{ObjectID: 1, Transform: go3mf.Identity().Translate(0, 0, 20)}, {ObjectID: 2}, {ObjectID: 5}},
+	}})
+	s := &scene{model: m}
+	s.addItem(&go3mf.Item{ObjectID: 2})
+	// The recursive component is followed until MaxComponentDepth.
+	if want := go3mf.MaxComponentDepth * 12; len(s.triangles) != want {
+		t.Fatalf("scene.addItem() triangles = %d, want %d", len(s.triangles), want)
+	}
+	if got := s.triangles[0].vertices[0]; got != (go3mf.Point3D{0, 0, 20}) {
+		t.Errorf("scene.addItem() vertex = %v", got)
+	}
+}
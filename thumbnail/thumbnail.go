@@ -0,0 +1,135 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+// Package thumbnail renders PNG previews of 3MF models with a software rasterizer
+// and attaches them to the model as package thumbnails.
+package thumbnail
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"path"
+	"strings"
+
+	"github.com/hpinc/go3mf"
+)
+
+const (
+	// DefaultPath is the part name of the model thumbnail.
+	DefaultPath = go3mf.DefaultMetadataDir + "thumbnail.png"
+	// ObjectsDir is the directory of the object thumbnails.
+	ObjectsDir = "/3D/Thumbnails/"
+
+	contentTypePNG = "image/png"
+	defaultSize    = 256
+)
+
+var (
+	defaultColor     = color.RGBA{R: 200, G: 200, B: 200, A: 255}
+	defaultDirection = go3mf.Point3D{-1, 1, -1}
+)
+
+// A Renderer draws an orthographic view of the model meshes,
+// following the components recursively and applying the item and component transforms.
+// Triangle colors are taken from the go3mf.BaseMaterials and materials.ColorGroup properties.
+//
+// The zero value renders 256x256 images with a transparent background,
+// viewing the model from the front right top corner.
+type Renderer struct {
+	Width, Height int
+	// Background fills the pixels not covered by the model.
+	Background color.Color
+	// Color is used for the triangles without color.
+	Color color.RGBA
+	// Direction is the view direction, from the camera towards the model.
+	Direction go3mf.Point3D
+}
+
+// Render returns an image of the build items of m.
+func (r *Renderer) Render(m *go3mf.Model) *image.NRGBA {
+	s := r.scene(m)
+	for _, item := range m.Build.Items {
+		s.addItem(item)
+	}
+	return r.draw(s)
+}
+
+// RenderObject returns an image of the object o, defined in the model part path.
+func (r *Renderer) RenderObject(m *go3mf.Model, path string, o *go3mf.Object) *image.NRGBA {
+	s := r.scene(m)
+	s.addObject(o, path)
+	return r.draw(s)
+}
+
+// SetThumbnail renders the build items of m and sets the image
+// as the package thumbnail, stored as an attachment in DefaultPath.
+func (r *Renderer) SetThumbnail(m *go3mf.Model) error {
+	if err := setAttachment(m, DefaultPath, r.Render(m)); err != nil {
+		return err
+	}
+	m.Thumbnail = DefaultPath
+	return nil
+}
+
+// SetObjectThumbnail renders o and sets the image as the object thumbnail,
+// stored as an attachment in ObjectsDir.
+func (r *Renderer) SetObjectThumbnail(m *go3mf.Model, modelPath string, o *go3mf.Object) error {
+	if modelPath == "" {
+		modelPath = m.PathOrDefault()
+	}
+	base := path.Base(modelPath)
+	name := fmt.Sprintf("%s%s_%d.png", ObjectsDir, strings.TrimSuffix(base, path.Ext(base)), o.ID)
+	if err := setAttachment(m, name, r.RenderObject(m, modelPath, o)); err != nil {
+		return err
+	}
+	o.Thumbnail = name
+	return nil
+}
+
+func (r *Renderer) scene(m *go3mf.Model) *scene {
+	c := r.Color
+	if c == (color.RGBA{}) {
+		c = defaultColor
+	}
+	return &scene{model: m, color: c}
+}
+
+func (r *Renderer) draw(s *scene) *image.NRGBA {
+	w, h := r.Width, r.Height
+	if w <= 0 {
+		w = defaultSize
+	}
+	if h <= 0 {
+		h = defaultSize
+	}
+	dir := r.Direction
+	if dir == (go3mf.Point3D{}) {
+		dir = defaultDirection
+	}
+	bg := color.NRGBAModel.Convert(color.Transparent).(color.NRGBA)
+	if r.Background != nil {
+		bg = color.NRGBAModel.Convert(r.Background).(color.NRGBA)
+	}
+	return newRaster(w, h, dir).draw(s.triangles, bg)
+}
+
+// setAttachment encodes img as a PNG attachment,
+// replacing the attachment with the same path if any.
+func setAttachment(m *go3mf.Model, name string, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return err
+	}
+	for i := range m.Attachments {
+		if strings.EqualFold(m.Attachments[i].Path, name) {
+			m.Attachments[i].ContentType = contentTypePNG
+			m.Attachments[i].Stream = &buf
+			return nil
+		}
+	}
+	m.Attachments = append(m.Attachments, go3mf.Attachment{Path: name, ContentType: contentTypePNG, Stream: &buf})
+	return nil
+}
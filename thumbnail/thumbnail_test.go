@@ -0,0 +1,143 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package thumbnail
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/materials"
+)
+
+func createCube(id uint32) *go3mf.Object {
+	return &go3mf.Object{ID: id, Mesh: &go3mf.Mesh{
+		Vertices: go3mf.Vertices{Vertex: []go3mf.Point3D{
+			{0, 0, 0}, {10, 0, 0}, {10, 10, 0}, {0, 10, 0},
+			{0, 0, 10}, {10, 0, 10}, {10, 10, 10}, {0, 10, 10},
+		}},
+		Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+			{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 3, V3: 2}, {V1: 4, V2: 5, V3: 6}, {V1: 4, V2: 6, V3: 7},
+			{V1: 0, V2: 1, V3: 5}, {V1: 0, V2: 5, V3: 4}, {V1: 1, V2: 2, V3: 6}, {V1: 1, V2: 6, V3: 5},
+			{V1: 2, V2: 3, V3: 7}, {V1: 2, V2: 7, V3: 6}, {V1: 3, V2: 0, V3: 4}, {V1: 3, V2: 4, V3: 7},
+		}},
+	}}
+}
+
+func createModel() *go3mf.Model {
+	m := new(go3mf.Model)
+	cube := createCube(1)
+	cube.PID, cube.PIndex = 5, 0
+	m.Resources.Assets = append(m.Resources.Assets, &materials.ColorGroup{ID: 5, Colors: []color.RGBA{{R: 255, A: 255}}})
+	m.Resources.Objects = append(m.Resources.Objects, cube)
+	m.Build.Items = append(m.Build.Items, &go3mf.Item{ObjectID: 1, Transform: go3mf.Identity().Translate(100, 0, 0)})
+	return m
+}
+
+// isRed returns true if the color is a shade of red.
+func isRed(c color.NRGBA) bool {
+	return c.A == 255 && c.R > 0 && c.G == 0 && c.B == 0
+}
+
+func TestRenderer_Render(t *testing.T) {
+	tests := []struct {
+		name       string
+		r          *Renderer
+		m          *go3mf.Model
+		w, h       int
+		center     func(color.NRGBA) bool
+		background color.NRGBA
+	}{
+		{"empty", new(Renderer), new(go3mf.Model), 256, 256, func(c color.NRGBA) bool { return c == color.NRGBA{} }, color.NRGBA{}},
+		{"default", new(Renderer), createModel(), 256, 256, isRed, color.NRGBA{}},
+		{"size", &Renderer{Width: 64, Height: 32, Background: color.White}, createModel(), 64, 32, isRed, color.NRGBA{R: 255, G: 255, B: 255, A: 255}},
+		{"top", &Renderer{Direction: go3mf.Point3D{0, 0, -1}}, createModel(), 256, 256, isRed, color.NRGBA{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := tt.r.Render(tt.m)
+			if b := img.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+				t.Fatalf("Renderer.Render() size = %v, want %dx%d", b, tt.w, tt.h)
+			}
+			if c := img.NRGBAAt(tt.w/2, tt.h/2); !tt.center(c) {
+				t.Errorf("Renderer.Render() center = %v", c)
+			}
+			if c := img.NRGBAAt(0, 0); c != tt.background {
+				t.Errorf("Renderer.Render() corner = %v, want %v", c, tt.background)
+			}
+		})
+	}
+}
+
+func TestRenderer_RenderObject(t *testing.T) {
+	m := createModel()
+	m.Resources.Objects = append(m.Resources.Objects, &go3mf.Object{ID: 2, Components: &go3mf.Components{
+		Component: []*go3mf.Component{{ObjectID: 1}, {ObjectID: 1, Transform: go3mf.Identity().Translate(0, 0, 10)}},
+	}})
+	img := new(Renderer).RenderObject(m, "", m.Resources.Objects[1])
+	// The object is taller than wide, so the sides are empty.
+	if c := img.NRGBAAt(128, 128); !isRed(c) {
+		t.Errorf("Renderer.RenderObject() center = %v", c)
+	}
+	if c := img.NRGBAAt(10, 128); c.A != 0 {
+		t.Errorf("Renderer.RenderObject() side = %v", c)
+	}
+}
+
+func TestRenderer_SetThumbnail(t *testing.T) {
+	m := createModel()
+	m.Attachments = append(m.Attachments, go3mf.Attachment{Path: DefaultPath, ContentType: "image/jpeg", Stream: new(bytes.Buffer)})
+	if err := (&Renderer{Width: 32, Height: 32}).SetThumbnail(m); err != nil {
+		t.Fatalf("Renderer.SetThumbnail() error = %v", err)
+	}
+	if m.Thumbnail != DefaultPath {
+		t.Errorf("Renderer.SetThumbnail() thumbnail = %s", m.Thumbnail)
+	}
+	if len(m.Attachments) != 1 || m.Attachments[0].ContentType != "image/png" {
+		t.Fatalf("Renderer.SetThumbnail() attachments = %v", m.Attachments)
+	}
+
+	var buf bytes.Buffer
+	if err := go3mf.NewEncoder(&buf).Encode(m); err != nil {
+		t.Fatalf("Encoder.Encode() error = %v", err)
+	}
+	var got go3mf.Model
+	if err := go3mf.NewDecoder(bytes.NewReader(buf.Bytes()), int64(buf.Len())).Decode(&got); err != nil {
+		t.Fatalf("Decoder.Decode() error = %v", err)
+	}
+	if got.Thumbnail != DefaultPath || len(got.Attachments) != 1 {
+		t.Fatalf("Decoder.Decode() thumbnail = %s, attachments = %v", got.Thumbnail, got.Attachments)
+	}
+	img, err := png.Decode(got.Attachments[0].Stream)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 32 || b.Dy() != 32 {
+		t.Errorf("thumbnail size = %v", b)
+	}
+}
+
+func TestRenderer_SetObjectThumbnail(t *testing.T) {
+	m := createModel()
+	m.Childs = map[string]*go3mf.ChildModel{"/3D/other.model": {Resources: go3mf.Resources{Objects: []*go3mf.Object{createCube(3)}}}}
+	r := new(Renderer)
+	if err := r.SetObjectThumbnail(m, "", m.Resources.Objects[0]); err != nil {
+		t.Fatalf("Renderer.SetObjectThumbnail() error = %v", err)
+	}
+	child := m.Childs["/3D/other.model"].Resources.Objects[0]
+	if err := r.SetObjectThumbnail(m, "/3D/other.model", child); err != nil {
+		t.Fatalf("Renderer.SetObjectThumbnail() error = %v", err)
+	}
+	if want := "/3D/Thumbnails/3dmodel_1.png"; m.Resources.Objects[0].Thumbnail != want {
+		t.Errorf("Renderer.SetObjectThumbnail() = %s, want %s", m.Resources.Objects[0].Thumbnail, want)
+	}
+	if want := "/3D/Thumbnails/other_3.png"; child.Thumbnail != want {
+		t.Errorf("Renderer.SetObjectThumbnail() = %s, want %s", child.Thumbnail, want)
+	}
+	if len(m.Attachments) != 2 {
+		t.Errorf("Renderer.SetObjectThumbnail() attachments = %d, want 2", len(m.Attachments))
+	}
+}
@@ -0,0 +1,145 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func (Spec) NewAttrGroup(xml.Name) spec.AttrGroup {
+	return nil
+}
+
+func (Spec) NewElementDecoder(name xml.Name) spec.GetterElementDecoder {
+	if name.Space == Namespace && name.Local == attrTriangleSets {
+		return new(triangleSetsDecoder)
+	}
+	return nil
+}
+
+type triangleSetsDecoder struct {
+	baseDecoder
+	triangleSets TriangleSets
+}
+
+func (d *triangleSetsDecoder) Element() interface{} {
+	return &d.triangleSets
+}
+
+func (d *triangleSetsDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrTriangleSet {
+		child = &triangleSetDecoder{triangleSets: &d.triangleSets}
+		i = len(d.triangleSets.TriangleSet)
+	}
+	return
+}
+
+type triangleSetDecoder struct {
+	baseDecoder
+	triangleSets    *TriangleSets
+	triangleSet     TriangleSet
+	refDecoder      refDecoder
+	refRangeDecoder refRangeDecoder
+}
+
+func (d *triangleSetDecoder) End() {
+	d.triangleSets.TriangleSet = append(d.triangleSets.TriangleSet, d.triangleSet)
+}
+
+func (d *triangleSetDecoder) Start(attrs []spec.XMLAttr) error {
+	d.refDecoder.triangleSet = &d.triangleSet
+	d.refRangeDecoder.triangleSet = &d.triangleSet
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrName:
+			d.triangleSet.Name = string(a.Value)
+		case attrIdentifier:
+			d.triangleSet.Identifier = string(a.Value)
+		}
+	}
+	return nil
+}
+
+func (d *triangleSetDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace {
+		if name.Local == attrRef {
+			child = &d.refDecoder
+			i = len(d.triangleSet.Refs)
+		} else if name.Local == attrRefRange {
+			child = &d.refRangeDecoder
+			i = len(d.triangleSet.RefRanges)
+		}
+	}
+	return
+}
+
+type refDecoder struct {
+	baseDecoder
+	triangleSet *TriangleSet
+}
+
+func (d *refDecoder) Start(attrs []spec.XMLAttr) error {
+	var (
+		val  uint64
+		errs error
+	)
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrIndex {
+			var err error
+			val, err = strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			break
+		}
+	}
+	d.triangleSet.Refs = append(d.triangleSet.Refs, uint32(val))
+	return errs
+}
+
+type refRangeDecoder struct {
+	baseDecoder
+	triangleSet *TriangleSet
+}
+
+func (d *refRangeDecoder) Start(attrs []spec.XMLAttr) error {
+	var (
+		r    RefRange
+		errs error
+	)
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrStartIndex:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			r.Start = uint32(val)
+		case attrEndIndex:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			r.End = uint32(val)
+		}
+	}
+	d.triangleSet.RefRanges = append(d.triangleSet.RefRanges, r)
+	return errs
+}
+
+type baseDecoder struct {
+}
+
+func (d *baseDecoder) Start([]spec.XMLAttr) error { return nil }
+func (d *baseDecoder) End()                       {}
@@ -0,0 +1,138 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestDecode(t *testing.T) {
+	ts := &TriangleSets{TriangleSet: []TriangleSet{
+		{Name: "top", Identifier: "set_top", Refs: []uint32{0, 2}, RefRanges: []RefRange{{3, 4}}},
+		{Name: "empty", Identifier: "set_empty"},
+	}}
+	mesh := &go3mf.Object{
+		ID: 1, Name: "Box",
+		Mesh: &go3mf.Mesh{Any: spec.Any{ts}},
+	}
+	mesh.Mesh.Vertices.Vertex = []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}
+	mesh.Mesh.Triangles.Triangle = []go3mf.Triangle{
+		{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3}, {V1: 1, V2: 3, V3: 2},
+	}
+	want := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources: go3mf.Resources{
+			Objects: []*go3mf.Object{mesh},
+		},
+	}
+	got := &go3mf.Model{
+		Path: "/3D/3dmodel.model",
+	}
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:t="http://schemas.microsoft.com/3dmanufacturing/trianglesets/2021/07">
+		<resources>
+			<object id="1" name="Box" type="model">
+				<mesh>
+					<vertices>
+						<vertex x="0" y="0" z="0"/>
+						<vertex x="10" y="0" z="0"/>
+						<vertex x="0" y="10" z="0"/>
+						<vertex x="0" y="0" z="10"/>
+					</vertices>
+					<triangles>
+						<triangle v1="0" v2="2" v3="1"/>
+						<triangle v1="0" v2="1" v3="3"/>
+						<triangle v1="0" v2="3" v3="2"/>
+						<triangle v1="1" v2="2" v3="3"/>
+						<triangle v1="1" v2="3" v3="2"/>
+					</triangles>
+					<t:trianglesets>
+						<t:triangleset name="top" identifier="set_top">
+							<t:ref index="0"/>
+							<t:other/>
+							<t:refrange startindex="3" endindex="4"/>
+							<t:ref index="2"/>
+						</t:triangleset>
+						<t:other/>
+						<t:triangleset name="empty" identifier="set_empty"/>
+					</t:trianglesets>
+				</mesh>
+			</object>
+		</resources>
+		<build>
+		</build>
+		</model>
+		`
+
+	t.Run("base", func(t *testing.T) {
+		if err := go3mf.UnmarshalModel([]byte(rootFile), got); err != nil {
+			t.Errorf("DecodeRawModel() unexpected error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("DecodeRawModel() = %v", diff)
+			return
+		}
+	})
+}
+
+func TestDecode_warns(t *testing.T) {
+	want := []string{
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/ref[1]: %v", errors.NewParseAttrError("index", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/refrange[0]: %v", errors.NewParseAttrError("startindex", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/refrange[1]: %v", errors.NewParseAttrError("endindex", true)),
+	}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+		<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:t="http://schemas.microsoft.com/3dmanufacturing/trianglesets/2021/07">
+		<resources>
+			<object id="1" name="Box" type="model">
+				<mesh>
+					<vertices>
+						<vertex x="0" y="0" z="0"/>
+						<vertex x="10" y="0" z="0"/>
+						<vertex x="0" y="10" z="0"/>
+					</vertices>
+					<triangles>
+						<triangle v1="0" v2="2" v3="1"/>
+					</triangles>
+					<t:trianglesets>
+						<t:triangleset qm:mq="other" name="top" identifier="set_top">
+							<t:ref index="0"/>
+							<t:ref index="a"/>
+							<t:refrange startindex="-1" endindex="4"/>
+							<t:refrange startindex="1" endindex="b"/>
+						</t:triangleset>
+					</t:trianglesets>
+				</mesh>
+			</object>
+		</resources>
+		<build>
+		</build>
+		</model>
+		`
+
+	t.Run("base", func(t *testing.T) {
+		err := go3mf.UnmarshalModel([]byte(rootFile), got)
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		var errs []string
+		for _, err := range err.(*errors.List).Errors {
+			errs = append(errs, err.Error())
+		}
+		if diff := deep.Equal(errs, want); diff != nil {
+			t.Errorf("UnmarshalModel_warn() = %v", diff)
+			return
+		}
+	})
+}
@@ -0,0 +1,40 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/hpinc/go3mf/spec"
+)
+
+// Marshal3MF encodes the resource.
+func (ts *TriangleSets) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTriangleSets}}
+	x.EncodeToken(xs)
+	for _, s := range ts.TriangleSet {
+		xt := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrTriangleSet}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrName}, Value: s.Name},
+			{Name: xml.Name{Local: attrIdentifier}, Value: s.Identifier},
+		}}
+		x.EncodeToken(xt)
+		x.SetAutoClose(true)
+		for _, ref := range s.Refs {
+			x.EncodeToken(xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrRef}, Attr: []xml.Attr{
+				{Name: xml.Name{Local: attrIndex}, Value: strconv.FormatUint(uint64(ref), 10)},
+			}})
+		}
+		for _, r := range s.RefRanges {
+			x.EncodeToken(xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrRefRange}, Attr: []xml.Attr{
+				{Name: xml.Name{Local: attrStartIndex}, Value: strconv.FormatUint(uint64(r.Start), 10)},
+				{Name: xml.Name{Local: attrEndIndex}, Value: strconv.FormatUint(uint64(r.End), 10)},
+			}})
+		}
+		x.SetAutoClose(false)
+		x.EncodeToken(xt.End())
+	}
+	x.EncodeToken(xs.End())
+	return nil
+}
@@ -0,0 +1,48 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestMarshalModel(t *testing.T) {
+	mesh := &go3mf.Object{
+		ID: 1, Name: "Box",
+		Mesh: &go3mf.Mesh{Any: spec.Any{&TriangleSets{TriangleSet: []TriangleSet{
+			{Name: "top", Identifier: "set_top", Refs: []uint32{0, 2}, RefRanges: []RefRange{{3, 4}}},
+			{Name: "side", Identifier: "set_side", RefRanges: []RefRange{{1, 1}}},
+			{Name: "empty", Identifier: "set_empty"},
+		}}}},
+	}
+	mesh.Mesh.Vertices.Vertex = []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}
+	mesh.Mesh.Triangles.Triangle = []go3mf.Triangle{
+		{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3}, {V1: 1, V2: 3, V3: 2},
+	}
+	m := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources:  go3mf.Resources{Objects: []*go3mf.Object{mesh}},
+	}
+	t.Run("base", func(t *testing.T) {
+		b, err := go3mf.MarshalModel(m)
+		if err != nil {
+			t.Errorf("trianglesets.MarshalModel() error = %v", err)
+			return
+		}
+		newModel := new(go3mf.Model)
+		newModel.Path = m.Path
+		if err := go3mf.UnmarshalModel(b, newModel); err != nil {
+			t.Errorf("trianglesets.MarshalModel() error decoding = %v, s = %s", err, string(b))
+			return
+		}
+		if diff := deep.Equal(m, newModel); diff != nil {
+			t.Errorf("trianglesets.MarshalModel() = %v, s = %s", diff, string(b))
+		}
+	})
+}
@@ -0,0 +1,196 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
+	"github.com/hpinc/go3mf/spec"
+)
+
+// Namespace is the canonical name of this extension.
+const Namespace = "http://schemas.microsoft.com/3dmanufacturing/trianglesets/2021/07"
+
+var DefaultExtension = go3mf.Extension{
+	Namespace:  Namespace,
+	LocalName:  "t",
+	IsRequired: false,
+}
+
+var (
+	ErrDuplicatedIdentifier = errors.New("the identifier of a triangle set MUST be unique within the mesh")
+	ErrRefRangeOrder        = errors.New("the endindex of a refrange MUST NOT be smaller than the startindex")
+)
+
+func init() {
+	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrDuplicatedIdentifier: {ID: "TSET001", Name: "DuplicatedIdentifier", Severity: reporting.SeverityError},
+		ErrRefRangeOrder:        {ID: "TSET002", Name: "RefRangeOrder", Severity: reporting.SeverityError},
+	})
+}
+
+type Spec struct{}
+
+// TriangleSets defines the named groups of triangles of a mesh.
+type TriangleSets struct {
+	TriangleSet []TriangleSet
+}
+
+// GetTriangleSets returns the triangle sets of the mesh,
+// or nil if the mesh does not have any.
+func GetTriangleSets(mesh *go3mf.Mesh) *TriangleSets {
+	for _, a := range mesh.Any {
+		if a, ok := a.(*TriangleSets); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+// RemapTriangles updates the triangle sets after the mesh triangles change,
+// being remap[i] the new index of triangle i or spec.RemovedIndex.
+// References to removed triangles and to triangles out of remap are dropped.
+func (ts *TriangleSets) RemapTriangles(remap []uint32) {
+	for i := range ts.TriangleSet {
+		ts.TriangleSet[i].remap(remap)
+	}
+}
+
+// Find returns the triangle set with the given identifier.
+func (ts *TriangleSets) Find(identifier string) (*TriangleSet, bool) {
+	for i := range ts.TriangleSet {
+		if ts.TriangleSet[i].Identifier == identifier {
+			return &ts.TriangleSet[i], true
+		}
+	}
+	return nil, false
+}
+
+// FindByName returns the first triangle set with the given name.
+func (ts *TriangleSets) FindByName(name string) (*TriangleSet, bool) {
+	for i := range ts.TriangleSet {
+		if ts.TriangleSet[i].Name == name {
+			return &ts.TriangleSet[i], true
+		}
+	}
+	return nil, false
+}
+
+// TriangleSet defines a named group of triangles.
+// The triangles are referenced by their index in the mesh,
+// either one by one or as inclusive ranges.
+type TriangleSet struct {
+	Name       string
+	Identifier string
+	Refs       []uint32
+	RefRanges  []RefRange
+}
+
+// RefRange references all the triangles from Start to End, both included.
+type RefRange struct {
+	Start, End uint32
+}
+
+// Indices returns the sorted and deduplicated triangle indices
+// referenced by the set. The ranges are not checked against any mesh,
+// so validate the model before expanding untrusted sets.
+func (s *TriangleSet) Indices() []uint32 {
+	indices := append([]uint32(nil), s.Refs...)
+	for _, r := range s.RefRanges {
+		for i := r.Start; i >= r.Start && i <= r.End; i++ {
+			indices = append(indices, i)
+		}
+	}
+	if len(indices) == 0 {
+		return nil
+	}
+	sort.Slice(indices, func(i, j int) bool { return indices[i] < indices[j] })
+	n := 1
+	for _, v := range indices[1:] {
+		if v != indices[n-1] {
+			indices[n] = v
+			n++
+		}
+	}
+	return indices[:n]
+}
+
+// SetIndices replaces the references of the set with indices,
+// storing consecutive indices as ranges.
+func (s *TriangleSet) SetIndices(indices []uint32) {
+	s.Refs, s.RefRanges = nil, nil
+	sorted := append([]uint32(nil), indices...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1]-sorted[j] <= 1 {
+			j++
+		}
+		if sorted[i] == sorted[j] {
+			s.Refs = append(s.Refs, sorted[i])
+		} else {
+			s.RefRanges = append(s.RefRanges, RefRange{Start: sorted[i], End: sorted[j]})
+		}
+		i = j + 1
+	}
+}
+
+func (s *TriangleSet) remap(remap []uint32) {
+	n := uint32(len(remap))
+	var indices []uint32
+	add := func(i uint32) {
+		if i < n && remap[i] != spec.RemovedIndex {
+			indices = append(indices, remap[i])
+		}
+	}
+	for _, i := range s.Refs {
+		add(i)
+	}
+	for _, r := range s.RefRanges {
+		for i := r.Start; i <= r.End && i < n; i++ {
+			add(i)
+		}
+	}
+	s.SetIndices(indices)
+}
+
+// Triangles returns the mesh triangles referenced by the set,
+// in mesh order. Indices out of the mesh bounds are ignored.
+func (s *TriangleSet) Triangles(mesh *go3mf.Mesh) []go3mf.Triangle {
+	n := uint32(len(mesh.Triangles.Triangle))
+	in := make([]bool, n)
+	for _, i := range s.Refs {
+		if i < n {
+			in[i] = true
+		}
+	}
+	for _, r := range s.RefRanges {
+		for i := r.Start; i <= r.End && i < n; i++ {
+			in[i] = true
+		}
+	}
+	var tris []go3mf.Triangle
+	for i, ok := range in {
+		if ok {
+			tris = append(tris, mesh.Triangles.Triangle[i])
+		}
+	}
+	return tris
+}
+
+const (
+	attrTriangleSets = "trianglesets"
+	attrTriangleSet  = "triangleset"
+	attrName         = "name"
+	attrIdentifier   = "identifier"
+	attrRef          = "ref"
+	attrRefRange     = "refrange"
+	attrIndex        = "index"
+	attrStartIndex   = "startindex"
+	attrEndIndex     = "endindex"
+)
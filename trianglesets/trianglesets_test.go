@@ -0,0 +1,119 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+var _ spec.Marshaler = new(TriangleSets)
+var _ spec.ChildElementDecoder = new(triangleSetsDecoder)
+var _ spec.ChildElementDecoder = new(triangleSetDecoder)
+
+func TestGetTriangleSets(t *testing.T) {
+	ts := new(TriangleSets)
+	if got := GetTriangleSets(&go3mf.Mesh{}); got != nil {
+		t.Errorf("GetTriangleSets() = %v, want nil", got)
+	}
+	if got := GetTriangleSets(&go3mf.Mesh{Any: spec.Any{nil, ts}}); got != ts {
+		t.Errorf("GetTriangleSets() = %v, want %v", got, ts)
+	}
+}
+
+func TestTriangleSets_Find(t *testing.T) {
+	ts := &TriangleSets{TriangleSet: []TriangleSet{
+		{Name: "top", Identifier: "a"},
+		{Name: "bottom", Identifier: "b"},
+		{Name: "top", Identifier: "c"},
+	}}
+	if got, ok := ts.Find("c"); !ok || got != &ts.TriangleSet[2] {
+		t.Errorf("TriangleSets.Find() = %v, %v", got, ok)
+	}
+	if _, ok := ts.Find("d"); ok {
+		t.Error("TriangleSets.Find() want not found")
+	}
+	if got, ok := ts.FindByName("top"); !ok || got != &ts.TriangleSet[0] {
+		t.Errorf("TriangleSets.FindByName() = %v, %v", got, ok)
+	}
+	if _, ok := ts.FindByName("side"); ok {
+		t.Error("TriangleSets.FindByName() want not found")
+	}
+}
+
+func TestTriangleSet_Indices(t *testing.T) {
+	tests := []struct {
+		name string
+		s    *TriangleSet
+		want []uint32
+	}{
+		{"empty", &TriangleSet{}, nil},
+		{"refs", &TriangleSet{Refs: []uint32{5, 1, 5}}, []uint32{1, 5}},
+		{"ranges", &TriangleSet{Refs: []uint32{9, 3}, RefRanges: []RefRange{{2, 4}, {7, 7}}}, []uint32{2, 3, 4, 7, 9}},
+		{"invalid", &TriangleSet{RefRanges: []RefRange{{4, 2}}}, nil},
+		{"max", &TriangleSet{RefRanges: []RefRange{{4294967294, 4294967295}}}, []uint32{4294967294, 4294967295}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := deep.Equal(tt.s.Indices(), tt.want); diff != nil {
+				t.Errorf("TriangleSet.Indices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTriangleSet_SetIndices(t *testing.T) {
+	tests := []struct {
+		name    string
+		indices []uint32
+		want    *TriangleSet
+	}{
+		{"empty", nil, &TriangleSet{Name: "a"}},
+		{"single", []uint32{3, 1}, &TriangleSet{Name: "a", Refs: []uint32{1, 3}}},
+		{"ranges", []uint32{8, 1, 2, 3, 3, 5, 9, 10}, &TriangleSet{Name: "a", Refs: []uint32{5}, RefRanges: []RefRange{{1, 3}, {8, 10}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &TriangleSet{Name: "a", Refs: []uint32{100}, RefRanges: []RefRange{{200, 300}}}
+			s.SetIndices(tt.indices)
+			if diff := deep.Equal(s, tt.want); diff != nil {
+				t.Errorf("TriangleSet.SetIndices() = %v", diff)
+			}
+		})
+	}
+}
+
+func TestTriangleSet_Triangles(t *testing.T) {
+	mesh := &go3mf.Mesh{Triangles: go3mf.Triangles{Triangle: []go3mf.Triangle{
+		{V1: 0, V2: 1, V3: 2}, {V1: 1, V2: 2, V3: 3}, {V1: 2, V2: 3, V3: 4}, {V1: 3, V2: 4, V3: 5},
+	}}}
+	s := &TriangleSet{Refs: []uint32{3, 10}, RefRanges: []RefRange{{0, 1}, {1, 4294967295}}}
+	want := mesh.Triangles.Triangle
+	if diff := deep.Equal(s.Triangles(mesh), want); diff != nil {
+		t.Errorf("TriangleSet.Triangles() = %v", diff)
+	}
+	s = &TriangleSet{Refs: []uint32{2, 0}}
+	want = []go3mf.Triangle{mesh.Triangles.Triangle[0], mesh.Triangles.Triangle[2]}
+	if diff := deep.Equal(s.Triangles(mesh), want); diff != nil {
+		t.Errorf("TriangleSet.Triangles() = %v", diff)
+	}
+}
+
+func TestTriangleSets_RemapTriangles(t *testing.T) {
+	ts := &TriangleSets{TriangleSet: []TriangleSet{
+		{Name: "a", Refs: []uint32{0, 3, 9}, RefRanges: []RefRange{{Start: 4, End: 6}}},
+		{Name: "b", Refs: []uint32{1}},
+	}}
+	ts.RemapTriangles([]uint32{0, spec.RemovedIndex, 1, 2, 3, spec.RemovedIndex, 4})
+	want := &TriangleSets{TriangleSet: []TriangleSet{
+		{Name: "a", Refs: []uint32{0}, RefRanges: []RefRange{{Start: 2, End: 4}}},
+		{Name: "b"},
+	}}
+	if diff := deep.Equal(ts, want); diff != nil {
+		t.Errorf("TriangleSets.RemapTriangles() = %v", diff)
+	}
+}
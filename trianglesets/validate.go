@@ -0,0 +1,58 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+func (Spec) Validate(m interface{}, path string, obj interface{}) error {
+	if obj, ok := obj.(*go3mf.Object); ok {
+		return validateObject(obj)
+	}
+	return nil
+}
+
+func validateObject(obj *go3mf.Object) error {
+	if obj.Mesh == nil {
+		return nil
+	}
+	ts := GetTriangleSets(obj.Mesh)
+	if ts == nil {
+		return nil
+	}
+
+	var errs error
+	n := uint32(len(obj.Mesh.Triangles.Triangle))
+	identifiers := make(map[string]struct{})
+	for i, set := range ts.TriangleSet {
+		if set.Name == "" {
+			errs = errors.Append(errs, errors.WrapIndex(errors.NewMissingFieldError(attrName), attrTriangleSet, i))
+		}
+		if set.Identifier == "" {
+			errs = errors.Append(errs, errors.WrapIndex(errors.NewMissingFieldError(attrIdentifier), attrTriangleSet, i))
+		} else if _, ok := identifiers[set.Identifier]; ok {
+			errs = errors.Append(errs, errors.WrapIndex(ErrDuplicatedIdentifier, attrTriangleSet, i))
+		} else {
+			identifiers[set.Identifier] = struct{}{}
+		}
+		for j, ref := range set.Refs {
+			if ref >= n {
+				errs = errors.Append(errs, errors.WrapIndex(errors.WrapIndex(errors.ErrIndexOutOfBounds, attrRef, j), attrTriangleSet, i))
+			}
+		}
+		for j, r := range set.RefRanges {
+			if r.End < r.Start {
+				errs = errors.Append(errs, errors.WrapIndex(errors.WrapIndex(ErrRefRangeOrder, attrRefRange, j), attrTriangleSet, i))
+			} else if r.End >= n {
+				errs = errors.Append(errs, errors.WrapIndex(errors.WrapIndex(errors.ErrIndexOutOfBounds, attrRefRange, j), attrTriangleSet, i))
+			}
+		}
+	}
+	if errs != nil {
+		errs = errors.Wrap(errors.Wrap(errs, attrTriangleSets), "mesh")
+	}
+	return errs
+}
@@ -0,0 +1,67 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package trianglesets
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestValidate(t *testing.T) {
+	newMesh := func(ts *TriangleSets) *go3mf.Mesh {
+		mesh := &go3mf.Mesh{Any: spec.Any{ts}}
+		mesh.Vertices.Vertex = []go3mf.Point3D{{0, 0, 0}, {10, 0, 0}, {0, 10, 0}, {0, 0, 10}}
+		mesh.Triangles.Triangle = []go3mf.Triangle{
+			{V1: 0, V2: 2, V3: 1}, {V1: 0, V2: 1, V3: 3}, {V1: 0, V2: 3, V3: 2}, {V1: 1, V2: 2, V3: 3},
+		}
+		return mesh
+	}
+	tests := []struct {
+		name  string
+		model *go3mf.Model
+		want  []string
+	}{
+		{"error in child", &go3mf.Model{Childs: map[string]*go3mf.ChildModel{
+			"/other.model": {Resources: go3mf.Resources{Objects: []*go3mf.Object{
+				{ID: 1, Mesh: newMesh(&TriangleSets{TriangleSet: []TriangleSet{{}}})},
+			}}},
+		}}, []string{
+			fmt.Sprintf("go3mf: Path: /other.model XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]: %v", &errors.MissingFieldError{Name: attrName}),
+			fmt.Sprintf("go3mf: Path: /other.model XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]: %v", &errors.MissingFieldError{Name: attrIdentifier}),
+		}},
+		{"sets", &go3mf.Model{Resources: go3mf.Resources{Objects: []*go3mf.Object{
+			{ID: 1, Mesh: newMesh(&TriangleSets{TriangleSet: []TriangleSet{
+				{Name: "a", Identifier: "a", Refs: []uint32{0, 4, 3}, RefRanges: []RefRange{{0, 3}, {2, 1}, {3, 4}}},
+				{Name: "b", Identifier: "a"},
+				{Name: "a", Identifier: "b"},
+			}})},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/ref[1]: %v", errors.ErrIndexOutOfBounds),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/refrange[1]: %v", ErrRefRangeOrder),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[0]/refrange[2]: %v", errors.ErrIndexOutOfBounds),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh/trianglesets/triangleset[1]: %v", ErrDuplicatedIdentifier),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Extensions = []go3mf.Extension{DefaultExtension}
+			err := tt.model.Validate()
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			var errs []string
+			for _, err := range err.(*errors.List).Errors {
+				errs = append(errs, err.Error())
+			}
+			if diff := deep.Equal(errs, tt.want); diff != nil {
+				t.Errorf("Validate() = %v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"errors"
+
+	"github.com/hpinc/go3mf/spec"
+)
+
+// ErrUnits is returned when converting from or to an unknown unit.
+var ErrUnits = errors.New("go3mf: invalid units")
+
+// Valid returns true if u is one of the supported units.
+func (u Units) Valid() bool {
+	return u <= UnitMeter
+}
+
+// Millimeters returns the length of one unit in millimeters,
+// or zero if u is not valid.
+func (u Units) Millimeters() float64 {
+	return map[Units]float64{
+		UnitMillimeter: 1,
+		UnitMicrometer: 0.001,
+		UnitCentimeter: 10,
+		UnitInch:       25.4,
+		UnitFoot:       304.8,
+		UnitMeter:      1000,
+	}[u]
+}
+
+// ConvertUnits rescales the root and child models from m.Units to target
+// and updates m.Units.
+//
+// Mesh vertices and the translation of the items and components transforms are scaled,
+// as well as any spec element or attribute implementing spec.Scaler.
+// It returns ErrUnits if m.Units or target are not valid.
+func (m *Model) ConvertUnits(target Units) error {
+	if !m.Units.Valid() || !target.Valid() {
+		return ErrUnits
+	}
+	if m.Units == target {
+		return nil
+	}
+	factor := float32(m.Units.Millimeters() / target.Millimeters())
+	m.Resources.scale(factor)
+	for _, c := range m.Childs {
+		c.Resources.scale(factor)
+		scaleAny(c.Any, factor)
+	}
+	for _, item := range m.Build.Items {
+		item.Transform = item.Transform.scaleTranslation(factor)
+		scaleAnyAttr(item.AnyAttr, factor)
+	}
+	scaleAnyAttr(m.Build.AnyAttr, factor)
+	scaleAny(m.Any, factor)
+	scaleAnyAttr(m.AnyAttr, factor)
+	m.Units = target
+	return nil
+}
+
+func (rs *Resources) scale(factor float32) {
+	scaleAnyAttr(rs.AnyAttr, factor)
+	for _, a := range rs.Assets {
+		if a, ok := a.(spec.Scaler); ok {
+			a.Scale(factor)
+		}
+	}
+	for _, o := range rs.Objects {
+		scaleAnyAttr(o.AnyAttr, factor)
+		scaleAny(o.Any, factor)
+		if o.Mesh != nil {
+			o.Mesh.scale(factor)
+		}
+		if o.Components != nil {
+			scaleAnyAttr(o.Components.AnyAttr, factor)
+			for _, c := range o.Components.Component {
+				c.Transform = c.Transform.scaleTranslation(factor)
+				scaleAnyAttr(c.AnyAttr, factor)
+			}
+		}
+	}
+}
+
+func (m *Mesh) scale(factor float32) {
+	for i := range m.Vertices.Vertex {
+		m.Vertices.Vertex[i] = m.Vertices.Vertex[i].Mul(factor)
+	}
+	scaleAnyAttr(m.AnyAttr, factor)
+	scaleAny(m.Any, factor)
+}
+
+// scaleTranslation returns the matrix resulting of
+// scaling the coordinate system by factor.
+// The rotation and scale components are unit-less.
+func (m1 Matrix) scaleTranslation(factor float32) Matrix {
+	if m1 == (Matrix{}) {
+		return m1
+	}
+	m1[12] *= factor
+	m1[13] *= factor
+	m1[14] *= factor
+	return m1
+}
+
+func scaleAny(any spec.Any, factor float32) {
+	for _, a := range any {
+		if a, ok := a.(spec.Scaler); ok {
+			a.Scale(factor)
+		}
+	}
+}
+
+func scaleAnyAttr(attrs spec.AnyAttr, factor float32) {
+	for _, a := range attrs {
+		if a, ok := a.(spec.Scaler); ok {
+			a.Scale(factor)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package go3mf
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf/spec"
+)
+
+type scalerAttr struct {
+	fakeAttr
+	factor float32
+}
+
+func (s *scalerAttr) Scale(factor float32) {
+	s.factor = factor
+}
+
+func TestUnits_Millimeters(t *testing.T) {
+	tests := []struct {
+		u    Units
+		want float64
+	}{
+		{UnitMillimeter, 1},
+		{UnitMicrometer, 0.001},
+		{UnitCentimeter, 10},
+		{UnitInch, 25.4},
+		{UnitFoot, 304.8},
+		{UnitMeter, 1000},
+		{Units(9), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.u.String(), func(t *testing.T) {
+			if got := tt.u.Millimeters(); got != tt.want {
+				t.Errorf("Units.Millimeters() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnits_Valid(t *testing.T) {
+	if !UnitMeter.Valid() || !UnitMillimeter.Valid() || Units(6).Valid() {
+		t.Error("Units.Valid() is not consistent with the supported units")
+	}
+}
+
+func TestModel_ConvertUnits(t *testing.T) {
+	newModel := func(units Units) (*Model, []*scalerAttr) {
+		attrs := make([]*scalerAttr, 8)
+		for i := range attrs {
+			attrs[i] = new(scalerAttr)
+		}
+		return &Model{
+			Units:   units,
+			AnyAttr: spec.AnyAttr{attrs[0]},
+			Any:     spec.Any{attrs[1]},
+			Resources: Resources{Objects: []*Object{
+				{ID: 1, AnyAttr: spec.AnyAttr{attrs[2]}, Mesh: &Mesh{
+					Vertices: Vertices{Vertex: []Point3D{{1, 2, 3}, {0, 0, 0}}},
+					Any:      spec.Any{attrs[3]},
+				}},
+				{ID: 2, Components: &Components{Component: []*Component{
+					{ObjectID: 1},
+					{ObjectID: 1, Transform: Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 2, 3, 1}, AnyAttr: spec.AnyAttr{attrs[4]}},
+				}}, Any: spec.Any{attrs[7]}},
+			}},
+			Build: Build{Items: []*Item{
+				{ObjectID: 2, Transform: Identity().Translate(10, 0, 0)},
+				{ObjectID: 1},
+			}, AnyAttr: spec.AnyAttr{attrs[5]}},
+			Childs: map[string]*ChildModel{
+				"/other.model": {Resources: Resources{Objects: []*Object{
+					{ID: 1, Mesh: &Mesh{Vertices: Vertices{Vertex: []Point3D{{1, 1, 1}}}}},
+				}}, Any: spec.Any{attrs[6]}},
+			},
+		}, attrs
+	}
+	t.Run("same", func(t *testing.T) {
+		m, attrs := newModel(UnitInch)
+		want, _ := newModel(UnitInch)
+		if err := m.ConvertUnits(UnitInch); err != nil {
+			t.Fatalf("Model.ConvertUnits() error = %v", err)
+		}
+		if diff := deep.Equal(m, want); diff != nil {
+			t.Errorf("Model.ConvertUnits() = %v", diff)
+		}
+		for i, a := range attrs {
+			if a.factor != 0 {
+				t.Errorf("Model.ConvertUnits() attr %d scaled by %v", i, a.factor)
+			}
+		}
+	})
+	t.Run("inchToMillimeter", func(t *testing.T) {
+		m, attrs := newModel(UnitInch)
+		if err := m.ConvertUnits(UnitMillimeter); err != nil {
+			t.Fatalf("Model.ConvertUnits() error = %v", err)
+		}
+		if m.Units != UnitMillimeter {
+			t.Errorf("Model.ConvertUnits() units = %v", m.Units)
+		}
+		if diff := deep.Equal(m.Resources.Objects[0].Mesh.Vertices.Vertex, []Point3D{{25.4, 50.8, 76.2}, {0, 0, 0}}); diff != nil {
+			t.Errorf("Model.ConvertUnits() vertices = %v", diff)
+		}
+		comps := m.Resources.Objects[1].Components.Component
+		if comps[0].Transform != (Matrix{}) {
+			t.Errorf("Model.ConvertUnits() empty transform = %v", comps[0].Transform)
+		}
+		if want := (Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 25.4, 50.8, 76.2, 1}); comps[1].Transform != want {
+			t.Errorf("Model.ConvertUnits() component transform = %v, want %v", comps[1].Transform, want)
+		}
+		if want := Identity().Translate(254, 0, 0); m.Build.Items[0].Transform != want {
+			t.Errorf("Model.ConvertUnits() item transform = %v, want %v", m.Build.Items[0].Transform, want)
+		}
+		if got := m.Childs["/other.model"].Resources.Objects[0].Mesh.Vertices.Vertex[0]; got != (Point3D{25.4, 25.4, 25.4}) {
+			t.Errorf("Model.ConvertUnits() child vertices = %v", got)
+		}
+		for i, a := range attrs {
+			if a.factor != 25.4 {
+				t.Errorf("Model.ConvertUnits() attr %d scaled by %v, want 25.4", i, a.factor)
+			}
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		for _, u := range [][2]Units{{Units(9), UnitMillimeter}, {UnitMillimeter, Units(9)}, {Units(9), Units(9)}} {
+			m, attrs := newModel(u[0])
+			want, _ := newModel(u[0])
+			if err := m.ConvertUnits(u[1]); err != ErrUnits {
+				t.Errorf("Model.ConvertUnits() error = %v, want %v", err, ErrUnits)
+			}
+			if diff := deep.Equal(m, want); diff != nil {
+				t.Errorf("Model.ConvertUnits() = %v", diff)
+			}
+			if attrs[0].factor != 0 {
+				t.Errorf("Model.ConvertUnits() attr scaled by %v", attrs[0].factor)
+			}
+		}
+	})
+	t.Run("roundtrip", func(t *testing.T) {
+		m, _ := newModel(UnitMillimeter)
+		if err := m.ConvertUnits(UnitMeter); err != nil {
+			t.Fatalf("Model.ConvertUnits() error = %v", err)
+		}
+		if got := m.Resources.Objects[0].Mesh.Vertices.Vertex[0]; got != (Point3D{0.001, 0.002, 0.003}) {
+			t.Errorf("Model.ConvertUnits() vertices = %v", got)
+		}
+		if err := m.ConvertUnits(UnitMicrometer); err != nil {
+			t.Fatalf("Model.ConvertUnits() error = %v", err)
+		}
+		if got := m.Resources.Objects[0].Mesh.Vertices.Vertex[0]; got.Sub(Point3D{1000, 2000, 3000}).Len() > 1e-3 {
+			t.Errorf("Model.ConvertUnits() vertices = %v", got)
+		}
+	})
+}
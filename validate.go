@@ -200,6 +200,16 @@ func (res *Resources) validate(m *Model, path string) error {
 	return errs
 }
 
+// hasContent returns true if any extension element defines the object content.
+func (r *Object) hasContent() bool {
+	for _, a := range r.Any {
+		if _, ok := a.(spec.ObjectContent); ok {
+			return true
+		}
+	}
+	return false
+}
+
 // Validate validates that the object is compliant with 3MF specs,
 // except for the mesh coherency.
 func (r *Object) Validate(m *Model, path string) error {
@@ -211,7 +221,7 @@ func (r *Object) Validate(m *Model, path string) error {
 	if r.PIndex != 0 && r.PID == 0 {
 		errs = errors.Append(errs, errors.NewMissingFieldError(attrPID))
 	}
-	if (r.Mesh != nil && r.Components != nil) || (r.Mesh == nil && r.Components == nil) {
+	if (r.Mesh != nil && r.Components != nil) || (r.Mesh == nil && r.Components == nil && !r.hasContent()) {
 		errs = errors.Append(errs, errors.ErrInvalidObject)
 	}
 	if r.Mesh != nil {
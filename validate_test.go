@@ -15,6 +15,12 @@ import (
 	"github.com/hpinc/go3mf/spec"
 )
 
+type fakeContent struct{}
+
+func (fakeContent) ObjectContent() {}
+
+func (fakeContent) Marshal3MF(spec.Encoder, *xml.StartElement) error { return nil }
+
 func TestValidate(t *testing.T) {
 	spec.Register(fakeSpec.Namespace, new(qmExtension))
 	tests := []struct {
@@ -103,6 +109,8 @@ func TestValidate(t *testing.T) {
 					{V1: 0, V2: 2, V3: 3, PID: 5, P1: 1, P2: 1, P3: 0},
 					{V1: 1, V2: 2, V3: 3, PID: 100, P1: 0, P2: 0, P3: 0},
 				}}}},
+			{ID: 7, Any: spec.Any{&spec.UnknownTokens{}}},
+			{ID: 8, Any: spec.Any{&spec.UnknownTokens{}, new(fakeContent)}},
 		}}}, []string{
 			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]: %v", errors.ErrMissingID),
 			fmt.Sprintf("go3mf: XPath: /model/resources/object[0]: %v", errors.ErrInvalidObject),
@@ -127,6 +135,7 @@ func TestValidate(t *testing.T) {
 			fmt.Sprintf("go3mf: XPath: /model/resources/object[5]/mesh/triangle[0]: %v", errors.ErrIndexOutOfBounds),
 			fmt.Sprintf("go3mf: XPath: /model/resources/object[5]/mesh/triangle[1]: %v", errors.ErrIndexOutOfBounds),
 			fmt.Sprintf("go3mf: XPath: /model/resources/object[5]/mesh/triangle[3]: %v", errors.ErrMissingResource),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[6]: %v", errors.ErrInvalidObject),
 		}},
 	}
 	for _, tt := range tests {
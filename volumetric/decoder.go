@@ -0,0 +1,372 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func (Spec) NewElementDecoder(name xml.Name) (child spec.GetterElementDecoder) {
+	if name.Space != Namespace {
+		return
+	}
+	switch name.Local {
+	case attrImage3D:
+		child = new(image3DDecoder)
+	case attrFunctionFromImage3D:
+		child = new(functionFromImage3DDecoder)
+	case attrVolumeData:
+		child = new(volumeDataDecoder)
+	}
+	return
+}
+
+func (Spec) NewAttrGroup(parent xml.Name) spec.AttrGroup {
+	if parent.Space == go3mf.Namespace && parent.Local == "mesh" {
+		return new(MeshAttr)
+	}
+	return nil
+}
+
+func (u *MeshAttr) Unmarshal3MFAttr(a spec.XMLAttr) error {
+	if a.Name.Local == attrVolumeID {
+		val, err := strconv.ParseUint(string(a.Value), 10, 32)
+		if err != nil {
+			return specerr.NewParseAttrError(a.Name.Local, true)
+		}
+		u.VolumeID = uint32(val)
+	}
+	return nil
+}
+
+type image3DDecoder struct {
+	baseDecoder
+	resource Image3D
+}
+
+func (d *image3DDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *image3DDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrImageStack {
+		child = &imageStackDecoder{resource: &d.resource.ImageStack}
+		i = -1
+	}
+	return
+}
+
+func (d *image3DDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID:
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+		case attrName:
+			d.resource.Name = string(a.Value)
+		}
+	}
+	return
+}
+
+type imageStackDecoder struct {
+	baseDecoder
+	resource *ImageStack
+}
+
+func (d *imageStackDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrImageSheet {
+		child = &imageSheetDecoder{resource: d.resource}
+		i = len(d.resource.Sheets)
+	}
+	return
+}
+
+func (d *imageStackDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		var dst *uint32
+		switch a.Name.Local {
+		case attrRowCount:
+			dst = &d.resource.RowCount
+		case attrColumnCount:
+			dst = &d.resource.ColumnCount
+		case attrSheetCount:
+			dst = &d.resource.SheetCount
+		default:
+			continue
+		}
+		val, err := strconv.ParseUint(string(a.Value), 10, 32)
+		if err != nil {
+			errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+		}
+		*dst = uint32(val)
+	}
+	return
+}
+
+type imageSheetDecoder struct {
+	baseDecoder
+	resource *ImageStack
+}
+
+func (d *imageSheetDecoder) Start(attrs []spec.XMLAttr) error {
+	var sheet ImageSheet
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrPath {
+			sheet.Path = string(a.Value)
+			break
+		}
+	}
+	d.resource.Sheets = append(d.resource.Sheets, sheet)
+	return nil
+}
+
+type functionFromImage3DDecoder struct {
+	baseDecoder
+	resource FunctionFromImage3D
+}
+
+func (d *functionFromImage3DDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *functionFromImage3DDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	d.resource.ValueScale = 1
+	for _, a := range attrs {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case attrID, attrImage3DID:
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			if a.Name.Local == attrID {
+				d.resource.ID = uint32(val)
+			} else {
+				d.resource.Image3DID = uint32(val)
+			}
+		case attrValueOffset, attrValueScale:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+				continue
+			}
+			if a.Name.Local == attrValueOffset {
+				d.resource.ValueOffset = float32(val)
+			} else {
+				d.resource.ValueScale = float32(val)
+			}
+		case attrFilter:
+			var ok bool
+			d.resource.Filter, ok = newTextureFilter(string(a.Value))
+			if !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+		case attrTileStyleU, attrTileStyleV, attrTileStyleW:
+			style, ok := newTileStyle(string(a.Value))
+			if !ok {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			switch a.Name.Local {
+			case attrTileStyleU:
+				d.resource.TileStyleU = style
+			case attrTileStyleV:
+				d.resource.TileStyleV = style
+			case attrTileStyleW:
+				d.resource.TileStyleW = style
+			}
+		}
+	}
+	return
+}
+
+type volumeDataDecoder struct {
+	baseDecoder
+	resource VolumeData
+}
+
+func (d *volumeDataDecoder) Element() interface{} {
+	return &d.resource
+}
+
+func (d *volumeDataDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space != Namespace {
+		return
+	}
+	i = -1
+	switch name.Local {
+	case attrBoundary:
+		d.resource.Boundary = new(Boundary)
+		child = &boundaryDecoder{resource: d.resource.Boundary}
+	case attrComposite:
+		d.resource.Composite = new(Composite)
+		child = &compositeDecoder{resource: d.resource.Composite}
+	case attrColor:
+		d.resource.Color = new(Color)
+		child = &fieldDecoder{resource: &d.resource.Color.FieldReference}
+	case attrProperty:
+		child = &propertyDecoder{resource: &d.resource}
+		i = len(d.resource.Properties)
+	}
+	return
+}
+
+func (d *volumeDataDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrID {
+			id, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.ID = uint32(id)
+			break
+		}
+	}
+	return
+}
+
+// unmarshalField parses the field reference attributes,
+// returning false if a is not one of them.
+func unmarshalField(f *FieldReference, a spec.XMLAttr) (bool, error) {
+	if a.Name.Space != "" {
+		return false, nil
+	}
+	switch a.Name.Local {
+	case attrFunctionID:
+		val, err := strconv.ParseUint(string(a.Value), 10, 32)
+		if err != nil {
+			return true, specerr.NewParseAttrError(a.Name.Local, true)
+		}
+		f.FunctionID = uint32(val)
+	case attrChannel:
+		f.Channel = string(a.Value)
+	case attrTransform:
+		var ok bool
+		f.Transform, ok = spec.ParseMatrix(string(a.Value))
+		if !ok {
+			return true, specerr.NewParseAttrError(a.Name.Local, false)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+type fieldDecoder struct {
+	baseDecoder
+	resource *FieldReference
+}
+
+func (d *fieldDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		_, err := unmarshalField(d.resource, a)
+		errs = specerr.Append(errs, err)
+	}
+	return
+}
+
+type boundaryDecoder struct {
+	baseDecoder
+	resource *Boundary
+}
+
+func (d *boundaryDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if ok, err := unmarshalField(&d.resource.FieldReference, a); ok {
+			errs = specerr.Append(errs, err)
+			continue
+		}
+		switch a.Name.Local {
+		case attrMinFeatureSize:
+			val, err := strconv.ParseFloat(string(a.Value), 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.MinFeatureSize = float32(val)
+		case attrMeshBBoxOnly:
+			val, err := strconv.ParseBool(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			d.resource.MeshBBoxOnly = val
+		}
+	}
+	return
+}
+
+type compositeDecoder struct {
+	baseDecoder
+	resource *Composite
+}
+
+func (d *compositeDecoder) Child(name xml.Name) (i int, child spec.ElementDecoder) {
+	if name.Space == Namespace && name.Local == attrMaterialMapping {
+		i = len(d.resource.Mappings)
+		d.resource.Mappings = append(d.resource.Mappings, MaterialMapping{})
+		child = &fieldDecoder{resource: &d.resource.Mappings[i].FieldReference}
+	}
+	return
+}
+
+func (d *compositeDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	for _, a := range attrs {
+		if a.Name.Space == "" && a.Name.Local == attrBaseMaterialID {
+			val, err := strconv.ParseUint(string(a.Value), 10, 32)
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, true))
+			}
+			d.resource.BaseMaterialID = uint32(val)
+			break
+		}
+	}
+	return
+}
+
+type propertyDecoder struct {
+	baseDecoder
+	resource *VolumeData
+}
+
+func (d *propertyDecoder) Start(attrs []spec.XMLAttr) (errs error) {
+	var p Property
+	for _, a := range attrs {
+		if ok, err := unmarshalField(&p.FieldReference, a); ok {
+			errs = specerr.Append(errs, err)
+			continue
+		}
+		switch a.Name.Local {
+		case attrName:
+			p.Name = string(a.Value)
+		case attrRequired:
+			val, err := strconv.ParseBool(string(a.Value))
+			if err != nil {
+				errs = specerr.Append(errs, specerr.NewParseAttrError(a.Name.Local, false))
+			}
+			p.Required = val
+		}
+	}
+	d.resource.Properties = append(d.resource.Properties, p)
+	return
+}
+
+type baseDecoder struct {
+}
+
+func (d *baseDecoder) End() {}
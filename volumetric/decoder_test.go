@@ -0,0 +1,161 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	specerr "github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestDecode(t *testing.T) {
+	image3d := &Image3D{ID: 1, Name: "density", ImageStack: ImageStack{
+		RowCount: 8, ColumnCount: 16, SheetCount: 2,
+		Sheets: []ImageSheet{{Path: "/3D/volume/sheet0.png"}, {Path: "/3D/volume/sheet1.png"}},
+	}}
+	function := &FunctionFromImage3D{
+		ID: 2, Image3DID: 1, ValueOffset: 0.5, ValueScale: 2,
+		Filter: TextureFilterNearest, TileStyleU: TileClamp, TileStyleV: TileMirror, TileStyleW: TileWrap,
+	}
+	volume := &VolumeData{
+		ID: 4,
+		Boundary: &Boundary{
+			FieldReference: FieldReference{FunctionID: 2, Channel: "red", Transform: go3mf.Matrix{0.1, 0, 0, 0, 0, 0.1, 0, 0, 0, 0, 0.1, 0, 0, 0, 0, 1}},
+			MinFeatureSize: 0.25, MeshBBoxOnly: true,
+		},
+		Composite: &Composite{BaseMaterialID: 3, Mappings: []MaterialMapping{
+			{FieldReference{FunctionID: 2, Channel: "green"}},
+			{FieldReference{FunctionID: 2, Channel: "blue"}},
+		}},
+		Color: &Color{FieldReference{FunctionID: 2, Channel: "color"}},
+		Properties: []Property{
+			{FieldReference: FieldReference{FunctionID: 2, Channel: "alpha"}, Name: "temperature", Required: true},
+			{FieldReference: FieldReference{FunctionID: 2, Channel: "red"}, Name: "stiffness"},
+		},
+	}
+	meshRes := &go3mf.Object{
+		ID: 5, Name: "Box 1",
+		Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{VolumeID: 4}}},
+	}
+	want := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources: go3mf.Resources{
+			Assets: []go3mf.Asset{image3d, function, volume}, Objects: []*go3mf.Object{meshRes},
+		}}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+	<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:v="http://schemas.3mf.io/3dmanufacturing/volumetric/2022/01">
+		<resources>
+			<v:other />
+			<v:image3d id="1" name="density">
+				<v:imagestack rowcount="8" columncount="16" sheetcount="2">
+					<v:imagesheet path="/3D/volume/sheet0.png" />
+					<v:imagesheet path="/3D/volume/sheet1.png" />
+				</v:imagestack>
+			</v:image3d>
+			<v:functionfromimage3d id="2" image3did="1" valueoffset="0.5" valuescale="2" filter="nearest" tilestyleu="clamp" tilestylev="mirror" tilestylew="wrap" />
+			<v:volumedata id="4">
+				<v:boundary functionid="2" channel="red" transform="0.1 0 0 0 0.1 0 0 0 0.1 0 0 0" minfeaturesize="0.25" meshbboxonly="true" />
+				<v:composite basematerialid="3">
+					<v:materialmapping functionid="2" channel="green" />
+					<v:materialmapping functionid="2" channel="blue" />
+				</v:composite>
+				<v:color functionid="2" channel="color" />
+				<v:property functionid="2" channel="alpha" name="temperature" required="true" />
+				<v:property functionid="2" channel="red" name="stiffness" />
+			</v:volumedata>
+			<object id="5" name="Box 1">
+				<mesh v:volumeid="4">
+					<vertices>
+					</vertices>
+					<triangles>
+					</triangles>
+				</mesh>
+			</object>
+		</resources>
+		<build>
+		</build>
+	</model>`
+
+	t.Run("base", func(t *testing.T) {
+		if err := go3mf.UnmarshalModel([]byte(rootFile), got); err != nil {
+			t.Errorf("DecodeRawModel() unexpected error = %v", err)
+			return
+		}
+		if diff := deep.Equal(got, want); diff != nil {
+			t.Errorf("DecodeRawModel() = %v", diff)
+			return
+		}
+	})
+}
+
+func TestDecode_warns(t *testing.T) {
+	want := []string{
+		fmt.Sprintf("go3mf: XPath: /model/resources/image3d[0]: %v", specerr.NewParseAttrError("id", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/image3d[0]/imagestack: %v", specerr.NewParseAttrError("rowcount", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/image3d[0]/imagestack: %v", specerr.NewParseAttrError("sheetcount", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", specerr.NewParseAttrError("image3did", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", specerr.NewParseAttrError("valuescale", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", specerr.NewParseAttrError("filter", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", specerr.NewParseAttrError("tilestylew", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]: %v", specerr.NewParseAttrError("id", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]/boundary: %v", specerr.NewParseAttrError("transform", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]/boundary: %v", specerr.NewParseAttrError("minfeaturesize", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]/composite: %v", specerr.NewParseAttrError("basematerialid", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]/composite/materialmapping[0]: %v", specerr.NewParseAttrError("functionid", true)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[2]/property[0]: %v", specerr.NewParseAttrError("required", false)),
+		fmt.Sprintf("go3mf: XPath: /model/resources/object[0]/mesh: %v", specerr.NewParseAttrError("volumeid", true)),
+	}
+	got := new(go3mf.Model)
+	got.Path = "/3D/3dmodel.model"
+	rootFile := `
+	<model xmlns="http://schemas.microsoft.com/3dmanufacturing/core/2015/02" xmlns:v="http://schemas.3mf.io/3dmanufacturing/volumetric/2022/01">
+		<resources>
+			<v:image3d id="a">
+				<v:imagestack rowcount="a" columncount="16" sheetcount="-1">
+					<v:imagesheet path="/3D/volume/sheet0.png" />
+				</v:imagestack>
+			</v:image3d>
+			<v:functionfromimage3d id="2" image3did="a" valuescale="a" filter="cubic" tilestylew="repeat" />
+			<v:volumedata id="a">
+				<v:boundary functionid="2" channel="red" transform="0 0" minfeaturesize="a" />
+				<v:composite basematerialid="a">
+					<v:materialmapping functionid="a" channel="green" />
+				</v:composite>
+				<v:property functionid="2" channel="alpha" name="temperature" required="a" />
+			</v:volumedata>
+			<object id="5" name="Box 1">
+				<mesh v:volumeid="a">
+					<vertices>
+					</vertices>
+					<triangles>
+					</triangles>
+				</mesh>
+			</object>
+		</resources>
+		<build>
+		</build>
+	</model>`
+
+	t.Run("base", func(t *testing.T) {
+		err := go3mf.UnmarshalModel([]byte(rootFile), got)
+		if err == nil {
+			t.Fatal("error expected")
+		}
+		var errs []string
+		for _, err := range err.(*specerr.List).Errors {
+			errs = append(errs, err.Error())
+		}
+		if diff := deep.Equal(errs, want); diff != nil {
+			t.Errorf("UnmarshalModel_warn() = %v", diff)
+			return
+		}
+	})
+}
@@ -0,0 +1,144 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"encoding/xml"
+	"strconv"
+
+	"github.com/hpinc/go3mf/spec"
+)
+
+// Marshal3MF encodes the resource attributes.
+func (u *MeshAttr) Marshal3MF(_ spec.Encoder, start *xml.StartElement) error {
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Space: Namespace, Local: attrVolumeID}, Value: strconv.FormatUint(uint64(u.VolumeID), 10)},
+	)
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *Image3D) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrImage3D}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	if r.Name != "" {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrName}, Value: r.Name})
+	}
+	x.EncodeToken(xs)
+	st := r.ImageStack
+	xst := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrImageStack}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrRowCount}, Value: strconv.FormatUint(uint64(st.RowCount), 10)},
+		{Name: xml.Name{Local: attrColumnCount}, Value: strconv.FormatUint(uint64(st.ColumnCount), 10)},
+		{Name: xml.Name{Local: attrSheetCount}, Value: strconv.FormatUint(uint64(st.SheetCount), 10)},
+	}}
+	x.EncodeToken(xst)
+	x.SetAutoClose(true)
+	for _, s := range st.Sheets {
+		x.AddRelationship(spec.Relationship{Path: s.Path, Type: RelTypeImage3D})
+		x.EncodeToken(xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrImageSheet}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrPath}, Value: s.Path},
+		}})
+	}
+	x.SetAutoClose(false)
+	x.EncodeToken(xst.End())
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *FunctionFromImage3D) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrFunctionFromImage3D}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+		{Name: xml.Name{Local: attrImage3DID}, Value: strconv.FormatUint(uint64(r.Image3DID), 10)},
+	}}
+	if r.ValueOffset != 0 {
+		xs.Attr = append(xs.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrValueOffset},
+			Value: strconv.FormatFloat(float64(r.ValueOffset), 'f', x.FloatPresicion(), 32),
+		})
+	}
+	if r.ValueScale != 1 {
+		xs.Attr = append(xs.Attr, xml.Attr{
+			Name:  xml.Name{Local: attrValueScale},
+			Value: strconv.FormatFloat(float64(r.ValueScale), 'f', x.FloatPresicion(), 32),
+		})
+	}
+	if r.Filter != TextureFilterLinear {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrFilter}, Value: r.Filter.String()})
+	}
+	if r.TileStyleU != TileWrap {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrTileStyleU}, Value: r.TileStyleU.String()})
+	}
+	if r.TileStyleV != TileWrap {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrTileStyleV}, Value: r.TileStyleV.String()})
+	}
+	if r.TileStyleW != TileWrap {
+		xs.Attr = append(xs.Attr, xml.Attr{Name: xml.Name{Local: attrTileStyleW}, Value: r.TileStyleW.String()})
+	}
+	x.SetAutoClose(true)
+	x.EncodeToken(xs)
+	x.SetAutoClose(false)
+	return nil
+}
+
+// Marshal3MF encodes the resource.
+func (r *VolumeData) Marshal3MF(x spec.Encoder, _ *xml.StartElement) error {
+	xs := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrVolumeData}, Attr: []xml.Attr{
+		{Name: xml.Name{Local: attrID}, Value: strconv.FormatUint(uint64(r.ID), 10)},
+	}}
+	x.EncodeToken(xs)
+	if r.Boundary != nil {
+		attrs := r.Boundary.attrs()
+		if r.Boundary.MinFeatureSize != 0 {
+			attrs = append(attrs, xml.Attr{
+				Name:  xml.Name{Local: attrMinFeatureSize},
+				Value: strconv.FormatFloat(float64(r.Boundary.MinFeatureSize), 'f', x.FloatPresicion(), 32),
+			})
+		}
+		if r.Boundary.MeshBBoxOnly {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: attrMeshBBoxOnly}, Value: "true"})
+		}
+		marshalEmpty(x, attrBoundary, attrs)
+	}
+	if r.Composite != nil {
+		xc := xml.StartElement{Name: xml.Name{Space: Namespace, Local: attrComposite}, Attr: []xml.Attr{
+			{Name: xml.Name{Local: attrBaseMaterialID}, Value: strconv.FormatUint(uint64(r.Composite.BaseMaterialID), 10)},
+		}}
+		x.EncodeToken(xc)
+		for _, m := range r.Composite.Mappings {
+			marshalEmpty(x, attrMaterialMapping, m.attrs())
+		}
+		x.EncodeToken(xc.End())
+	}
+	if r.Color != nil {
+		marshalEmpty(x, attrColor, r.Color.attrs())
+	}
+	for _, p := range r.Properties {
+		attrs := append(p.attrs(), xml.Attr{Name: xml.Name{Local: attrName}, Value: p.Name})
+		if p.Required {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: attrRequired}, Value: "true"})
+		}
+		marshalEmpty(x, attrProperty, attrs)
+	}
+	x.EncodeToken(xs.End())
+	return nil
+}
+
+func (f *FieldReference) attrs() []xml.Attr {
+	attrs := []xml.Attr{
+		{Name: xml.Name{Local: attrFunctionID}, Value: strconv.FormatUint(uint64(f.FunctionID), 10)},
+		{Name: xml.Name{Local: attrChannel}, Value: f.Channel},
+	}
+	if f.HasTransform() {
+		attrs = append(attrs, xml.Attr{Name: xml.Name{Local: attrTransform}, Value: f.Transform.String()})
+	}
+	return attrs
+}
+
+func marshalEmpty(x spec.Encoder, name string, attrs []xml.Attr) {
+	x.SetAutoClose(true)
+	x.EncodeToken(xml.StartElement{Name: xml.Name{Space: Namespace, Local: name}, Attr: attrs})
+	x.SetAutoClose(false)
+}
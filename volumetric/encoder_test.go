@@ -0,0 +1,69 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestMarshalModel(t *testing.T) {
+	image3d := &Image3D{ID: 1, Name: "density", ImageStack: ImageStack{
+		RowCount: 8, ColumnCount: 16, SheetCount: 2,
+		Sheets: []ImageSheet{{Path: "/3D/volume/sheet0.png"}, {Path: "/3D/volume/sheet1.png"}},
+	}}
+	function := &FunctionFromImage3D{ID: 2, Image3DID: 1, ValueOffset: 0.5, ValueScale: 2, Filter: TextureFilterNearest, TileStyleU: TileClamp}
+	defaultFunction := &FunctionFromImage3D{ID: 6, Image3DID: 1, ValueScale: 1}
+	baseMaterial := &go3mf.BaseMaterials{ID: 3, Materials: []go3mf.Base{{Name: "a", Color: color.RGBA{R: 1}}, {Name: "b", Color: color.RGBA{R: 1}}}}
+	volume := &VolumeData{
+		ID: 4,
+		Boundary: &Boundary{
+			FieldReference: FieldReference{FunctionID: 2, Channel: "red", Transform: go3mf.Matrix{0.1, 0, 0, 0, 0, 0.1, 0, 0, 0, 0, 0.1, 0, 1, 2, 3, 1}},
+			MinFeatureSize: 0.25, MeshBBoxOnly: true,
+		},
+		Composite: &Composite{BaseMaterialID: 3, Mappings: []MaterialMapping{
+			{FieldReference{FunctionID: 2, Channel: "green"}},
+			{FieldReference{FunctionID: 6, Channel: "blue"}},
+		}},
+		Color: &Color{FieldReference{FunctionID: 2, Channel: "color"}},
+		Properties: []Property{
+			{FieldReference: FieldReference{FunctionID: 2, Channel: "alpha"}, Name: "temperature", Required: true},
+		},
+	}
+	emptyVolume := &VolumeData{ID: 7}
+	meshRes := &go3mf.Object{
+		ID: 5, Name: "Box 1",
+		Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{VolumeID: 4}}},
+	}
+
+	m := &go3mf.Model{
+		Path:       "/3D/3dmodel.model",
+		Extensions: []go3mf.Extension{DefaultExtension},
+		Resources: go3mf.Resources{
+			Assets:  []go3mf.Asset{baseMaterial, image3d, function, defaultFunction, volume, emptyVolume},
+			Objects: []*go3mf.Object{meshRes},
+		},
+	}
+
+	t.Run("base", func(t *testing.T) {
+		b, err := go3mf.MarshalModel(m)
+		if err != nil {
+			t.Errorf("volumetric.MarshalModel() error = %v", err)
+			return
+		}
+		newModel := new(go3mf.Model)
+		newModel.Path = m.Path
+		if err := go3mf.UnmarshalModel(b, newModel); err != nil {
+			t.Errorf("volumetric.MarshalModel() error decoding = %v, s = %s", err, string(b))
+			return
+		}
+		if diff := deep.Equal(m, newModel); diff != nil {
+			t.Errorf("volumetric.MarshalModel() = %v, s = %s", diff, string(b))
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"io/ioutil"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+// Sheet decodes the PNG image of the i'th sheet of the image stack,
+// which is read from the model attachments.
+//
+// The attachment stream is not consumed, so the model can still be encoded.
+func (r *Image3D) Sheet(m *go3mf.Model, i int) (image.Image, error) {
+	if i < 0 || i >= len(r.ImageStack.Sheets) {
+		return nil, errors.ErrIndexOutOfBounds
+	}
+	a, ok := findAttachment(m, r.ImageStack.Sheets[i].Path)
+	if !ok {
+		return nil, ErrMissingImageSheetPart
+	}
+	b, err := attachmentBytes(a)
+	if err != nil {
+		return nil, err
+	}
+	return png.Decode(bytes.NewReader(b))
+}
+
+// AddSheet encodes img as a PNG attachment stored in path
+// and appends it to the image stack.
+// RowCount and ColumnCount are set from the image bounds if they are zero,
+// and SheetCount is incremented.
+func (r *Image3D) AddSheet(m *go3mf.Model, path string, img image.Image) error {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return err
+	}
+	m.Attachments = append(m.Attachments, go3mf.Attachment{
+		Path:        path,
+		ContentType: ContentTypePNG,
+		Stream:      buf,
+	})
+	st := &r.ImageStack
+	size := img.Bounds().Size()
+	if st.RowCount == 0 {
+		st.RowCount = uint32(size.Y)
+	}
+	if st.ColumnCount == 0 {
+		st.ColumnCount = uint32(size.X)
+	}
+	st.Sheets = append(st.Sheets, ImageSheet{Path: path})
+	st.SheetCount++
+	return nil
+}
+
+// attachmentBytes returns the content of the attachment.
+// If the stream is not backed by an in-memory buffer it is read
+// and replaced by one, so it can be read again.
+func attachmentBytes(a *go3mf.Attachment) ([]byte, error) {
+	if b, ok := a.Stream.(*bytes.Buffer); ok {
+		return b.Bytes(), nil
+	}
+	b, err := ioutil.ReadAll(a.Stream)
+	if err != nil {
+		return nil, err
+	}
+	a.Stream = bytes.NewBuffer(b)
+	return b, nil
+}
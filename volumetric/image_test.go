@@ -0,0 +1,103 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+func newSheet(v uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, 4, 3))
+	for i := range img.Pix {
+		img.Pix[i] = v
+	}
+	return img
+}
+
+func TestImage3D_AddSheet(t *testing.T) {
+	m := new(go3mf.Model)
+	m.Extensions = []go3mf.Extension{DefaultExtension}
+	r := &Image3D{ID: 1}
+	for i := 0; i < 2; i++ {
+		if err := r.AddSheet(m, fmt.Sprintf("/3D/volume/sheet%d.png", i), newSheet(uint8(i*100))); err != nil {
+			t.Fatalf("Image3D.AddSheet() error = %v", err)
+		}
+	}
+	st := r.ImageStack
+	if st.RowCount != 3 || st.ColumnCount != 4 || st.SheetCount != 2 || len(st.Sheets) != 2 {
+		t.Errorf("Image3D.AddSheet() = %v", st)
+	}
+	m.Resources.Assets = append(m.Resources.Assets, r)
+	if err := m.Validate(); err != nil {
+		t.Errorf("Image3D.AddSheet() invalid model = %v", err)
+	}
+
+	buff := new(bytes.Buffer)
+	if err := go3mf.NewEncoder(buff).Encode(m); err != nil {
+		t.Fatalf("go3mf.Encode() error = %v", err)
+	}
+	got := new(go3mf.Model)
+	if err := go3mf.NewDecoder(bytes.NewReader(buff.Bytes()), int64(buff.Len())).Decode(got); err != nil {
+		t.Fatalf("go3mf.Decode() error = %v", err)
+	}
+	gotR, ok := got.FindAsset("", 1)
+	if !ok {
+		t.Fatal("Image3D not decoded")
+	}
+	for i := 0; i < 2; i++ {
+		img, err := gotR.(*Image3D).Sheet(got, i)
+		if err != nil {
+			t.Fatalf("Image3D.Sheet() error = %v", err)
+		}
+		if c := color.GrayModel.Convert(img.At(1, 1)).(color.Gray); c.Y != uint8(i*100) {
+			t.Errorf("Image3D.Sheet() pixel = %v, want %v", c.Y, i*100)
+		}
+		// The stream is still available after reading the sheet.
+		if _, err := gotR.(*Image3D).Sheet(got, i); err != nil {
+			t.Errorf("Image3D.Sheet() second read error = %v", err)
+		}
+	}
+}
+
+func TestImage3D_Sheet(t *testing.T) {
+	m := &go3mf.Model{Attachments: []go3mf.Attachment{
+		{Path: "/b.png", ContentType: ContentTypePNG, Stream: strings.NewReader("not a png")},
+	}}
+	r := &Image3D{ImageStack: ImageStack{Sheets: []ImageSheet{{Path: "/b.png"}, {Path: "/c.png"}}}}
+	tests := []struct {
+		name    string
+		i       int
+		want    error
+		wantErr bool
+	}{
+		{"negative", -1, errors.ErrIndexOutOfBounds, true},
+		{"outOfBounds", 2, errors.ErrIndexOutOfBounds, true},
+		{"missing", 1, ErrMissingImageSheetPart, true},
+		{"invalid", 0, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := r.Sheet(m, tt.i)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Image3D.Sheet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.want != nil && err != tt.want {
+				t.Errorf("Image3D.Sheet() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	// Non buffered streams are replaced so they can be read again.
+	if b, err := ioutil.ReadAll(m.Attachments[0].Stream); err != nil || string(b) != "not a png" {
+		t.Errorf("Image3D.Sheet() stream = %s, %v", b, err)
+	}
+}
@@ -0,0 +1,170 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"strings"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+)
+
+func (Spec) Validate(model interface{}, path string, e interface{}) error {
+	m := model.(*go3mf.Model)
+	switch e := e.(type) {
+	case *go3mf.Object:
+		return validateObject(m, path, e)
+	case *Image3D:
+		return validateImage3D(m, e)
+	case *FunctionFromImage3D:
+		return validateFunctionFromImage3D(m, path, e)
+	case *VolumeData:
+		return validateVolumeData(m, path, e)
+	}
+	return nil
+}
+
+func validateObject(m *go3mf.Model, path string, obj *go3mf.Object) error {
+	if obj.Mesh == nil {
+		return nil
+	}
+	attr := GetMeshAttr(obj.Mesh)
+	if attr == nil {
+		return nil
+	}
+	var errs error
+	if attr.VolumeID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrVolumeID))
+	} else if r, ok := m.FindAsset(path, attr.VolumeID); ok {
+		if _, ok := r.(*VolumeData); !ok {
+			errs = errors.Append(errs, ErrNonVolumeData)
+		}
+	} else {
+		errs = errors.Append(errs, errors.ErrMissingResource)
+	}
+	return errors.Wrap(errs, "mesh")
+}
+
+func validateImage3D(m *go3mf.Model, r *Image3D) error {
+	var errs error
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	st := r.ImageStack
+	var serrs error
+	if st.RowCount == 0 {
+		serrs = errors.Append(serrs, errors.NewMissingFieldError(attrRowCount))
+	}
+	if st.ColumnCount == 0 {
+		serrs = errors.Append(serrs, errors.NewMissingFieldError(attrColumnCount))
+	}
+	if st.SheetCount == 0 {
+		serrs = errors.Append(serrs, errors.NewMissingFieldError(attrSheetCount))
+	} else if int(st.SheetCount) != len(st.Sheets) {
+		serrs = errors.Append(serrs, ErrImageSheetCount)
+	}
+	for i, s := range st.Sheets {
+		if s.Path == "" {
+			serrs = errors.Append(serrs, errors.WrapIndex(errors.NewMissingFieldError(attrPath), attrImageSheet, i))
+		} else if a, ok := findAttachment(m, s.Path); !ok {
+			serrs = errors.Append(serrs, errors.WrapIndex(ErrMissingImageSheetPart, attrImageSheet, i))
+		} else if a.ContentType != ContentTypePNG {
+			serrs = errors.Append(serrs, errors.WrapIndex(ErrImageSheetContentType, attrImageSheet, i))
+		}
+	}
+	return errors.Append(errs, errors.Wrap(serrs, attrImageStack))
+}
+
+func validateFunctionFromImage3D(m *go3mf.Model, path string, r *FunctionFromImage3D) error {
+	var errs error
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	if r.Image3DID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrImage3DID))
+	} else if img, ok := m.FindAsset(path, r.Image3DID); ok {
+		if _, ok := img.(*Image3D); !ok {
+			errs = errors.Append(errs, ErrNonImage3D)
+		}
+	} else {
+		errs = errors.Append(errs, errors.ErrMissingResource)
+	}
+	return errs
+}
+
+func validateVolumeData(m *go3mf.Model, path string, r *VolumeData) error {
+	var errs error
+	if r.ID == 0 {
+		errs = errors.Append(errs, errors.ErrMissingID)
+	}
+	r.fields(func(name string, index int, f *FieldReference) {
+		errs = errors.Append(errs, errors.WrapIndex(validateField(m, path, f), name, index))
+	})
+	if r.Composite != nil {
+		errs = errors.Append(errs, errors.Wrap(validateComposite(m, path, r.Composite), attrComposite))
+	}
+	names := make(map[string]struct{})
+	for i, p := range r.Properties {
+		if p.Name == "" {
+			errs = errors.Append(errs, errors.WrapIndex(errors.NewMissingFieldError(attrName), attrProperty, i))
+			continue
+		}
+		if _, ok := names[p.Name]; ok {
+			errs = errors.Append(errs, errors.WrapIndex(ErrDuplicatedProperty, attrProperty, i))
+		}
+		names[p.Name] = struct{}{}
+	}
+	return errs
+}
+
+func validateComposite(m *go3mf.Model, path string, c *Composite) error {
+	var errs error
+	if c.BaseMaterialID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrBaseMaterialID))
+	} else if r, ok := m.FindAsset(path, c.BaseMaterialID); ok {
+		if base, ok := r.(*go3mf.BaseMaterials); ok {
+			if len(base.Materials) != len(c.Mappings) {
+				errs = errors.Append(errs, ErrVolumeCompositeMapping)
+			}
+		} else {
+			errs = errors.Append(errs, ErrVolumeCompositeBase)
+		}
+	} else {
+		errs = errors.Append(errs, errors.ErrMissingResource)
+	}
+	for i := range c.Mappings {
+		errs = errors.Append(errs, errors.WrapIndex(validateField(m, path, &c.Mappings[i].FieldReference), attrMaterialMapping, i))
+	}
+	return errs
+}
+
+func validateField(m *go3mf.Model, path string, f *FieldReference) error {
+	var errs error
+	if f.Channel == "" {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrChannel))
+	}
+	if f.FunctionID == 0 {
+		errs = errors.Append(errs, errors.NewMissingFieldError(attrFunctionID))
+	} else if r, ok := m.FindAsset(path, f.FunctionID); ok {
+		if fn, ok := r.(Function); ok {
+			if f.Channel != "" && !fn.HasOutput(f.Channel) {
+				errs = errors.Append(errs, ErrFunctionChannel)
+			}
+		} else {
+			errs = errors.Append(errs, ErrNonFunction)
+		}
+	} else {
+		errs = errors.Append(errs, errors.ErrMissingResource)
+	}
+	return errs
+}
+
+func findAttachment(m *go3mf.Model, path string) (*go3mf.Attachment, bool) {
+	for i, a := range m.Attachments {
+		if strings.EqualFold(a.Path, path) {
+			return &m.Attachments[i], true
+		}
+	}
+	return nil, false
+}
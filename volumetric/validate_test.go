@@ -0,0 +1,125 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/errors"
+	"github.com/hpinc/go3mf/spec"
+)
+
+func TestValidate(t *testing.T) {
+	sheet := func(path, contentType string) go3mf.Attachment {
+		return go3mf.Attachment{Path: path, ContentType: contentType, Stream: new(bytes.Buffer)}
+	}
+	base := &go3mf.BaseMaterials{ID: 10, Materials: []go3mf.Base{{Name: "a", Color: color.RGBA{R: 1}}, {Name: "b", Color: color.RGBA{R: 1}}}}
+	tests := []struct {
+		name  string
+		model *go3mf.Model
+		want  []string
+	}{
+		{"image3d", &go3mf.Model{
+			Attachments: []go3mf.Attachment{sheet("/3D/a.png", ContentTypePNG), sheet("/3D/b.jpg", "image/jpeg")},
+			Resources: go3mf.Resources{Assets: []go3mf.Asset{
+				&Image3D{ID: 1, ImageStack: ImageStack{RowCount: 1, ColumnCount: 1, SheetCount: 1, Sheets: []ImageSheet{{Path: "/3D/A.png"}}}},
+				&Image3D{ImageStack: ImageStack{SheetCount: 4, Sheets: []ImageSheet{{}, {Path: "/3D/b.jpg"}, {Path: "/3D/c.png"}}}},
+				&Image3D{ID: 3},
+			}},
+		}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack: %v", &errors.MissingFieldError{Name: attrRowCount}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack: %v", &errors.MissingFieldError{Name: attrColumnCount}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack: %v", ErrImageSheetCount),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack/imagesheet[0]: %v", &errors.MissingFieldError{Name: attrPath}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack/imagesheet[1]: %v", ErrImageSheetContentType),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[1]/imagestack/imagesheet[2]: %v", ErrMissingImageSheetPart),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[2]/imagestack: %v", &errors.MissingFieldError{Name: attrRowCount}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[2]/imagestack: %v", &errors.MissingFieldError{Name: attrColumnCount}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/image3d[2]/imagestack: %v", &errors.MissingFieldError{Name: attrSheetCount}),
+		}},
+		{"function", &go3mf.Model{Resources: go3mf.Resources{Assets: []go3mf.Asset{
+			base,
+			&FunctionFromImage3D{},
+			&FunctionFromImage3D{ID: 2, Image3DID: 10},
+			&FunctionFromImage3D{ID: 3, Image3DID: 100},
+		}}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[1]: %v", &errors.MissingFieldError{Name: attrImage3DID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[2]: %v", ErrNonImage3D),
+			fmt.Sprintf("go3mf: XPath: /model/resources/functionfromimage3d[3]: %v", errors.ErrMissingResource),
+		}},
+		{"volumedata", &go3mf.Model{
+			Attachments: []go3mf.Attachment{sheet("/3D/a.png", ContentTypePNG)},
+			Resources: go3mf.Resources{Assets: []go3mf.Asset{
+				base,
+				&Image3D{ID: 1, ImageStack: ImageStack{RowCount: 1, ColumnCount: 1, SheetCount: 1, Sheets: []ImageSheet{{Path: "/3D/a.png"}}}},
+				&FunctionFromImage3D{ID: 2, Image3DID: 1},
+				&VolumeData{
+					Boundary: &Boundary{FieldReference: FieldReference{FunctionID: 2, Channel: "distance"}},
+					Composite: &Composite{BaseMaterialID: 10, Mappings: []MaterialMapping{
+						{FieldReference{FunctionID: 2, Channel: "red"}},
+					}},
+					Color: &Color{FieldReference{FunctionID: 10, Channel: "color"}},
+					Properties: []Property{
+						{FieldReference: FieldReference{FunctionID: 2, Channel: "red"}, Name: "a"},
+						{FieldReference: FieldReference{FunctionID: 2, Channel: "red"}, Name: "a"},
+						{FieldReference: FieldReference{FunctionID: 100}},
+					},
+				},
+				&VolumeData{ID: 5, Composite: &Composite{BaseMaterialID: 2}},
+				&VolumeData{ID: 6, Composite: &Composite{Mappings: []MaterialMapping{{}}}},
+				&VolumeData{ID: 7, Composite: &Composite{BaseMaterialID: 100}},
+			}},
+		}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]: %v", errors.ErrMissingID),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/boundary: %v", ErrFunctionChannel),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/color: %v", ErrNonFunction),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/property[2]: %v", &errors.MissingFieldError{Name: attrChannel}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/property[2]: %v", errors.ErrMissingResource),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/composite: %v", ErrVolumeCompositeMapping),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/property[1]: %v", ErrDuplicatedProperty),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[3]/property[2]: %v", &errors.MissingFieldError{Name: attrName}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[4]/composite: %v", ErrVolumeCompositeBase),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[5]/composite: %v", &errors.MissingFieldError{Name: attrBaseMaterialID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[5]/composite/materialmapping[0]: %v", &errors.MissingFieldError{Name: attrChannel}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[5]/composite/materialmapping[0]: %v", &errors.MissingFieldError{Name: attrFunctionID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/volumedata[6]/composite: %v", errors.ErrMissingResource),
+		}},
+		{"object", &go3mf.Model{Resources: go3mf.Resources{
+			Assets: []go3mf.Asset{base, &VolumeData{ID: 2}},
+			Objects: []*go3mf.Object{
+				{ID: 3, Type: go3mf.ObjectTypeSupport, Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{VolumeID: 2}}}},
+				{ID: 4, Type: go3mf.ObjectTypeSupport, Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{}}}},
+				{ID: 5, Type: go3mf.ObjectTypeSupport, Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{VolumeID: 10}}}},
+				{ID: 6, Type: go3mf.ObjectTypeSupport, Mesh: &go3mf.Mesh{AnyAttr: spec.AnyAttr{&MeshAttr{VolumeID: 100}}}},
+			},
+		}}, []string{
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[1]/mesh: %v", &errors.MissingFieldError{Name: attrVolumeID}),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[2]/mesh: %v", ErrNonVolumeData),
+			fmt.Sprintf("go3mf: XPath: /model/resources/object[3]/mesh: %v", errors.ErrMissingResource),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.model.Extensions = []go3mf.Extension{DefaultExtension}
+			err := tt.model.Validate()
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			var errs []string
+			for _, err := range err.(*errors.List).Errors {
+				errs = append(errs, err.Error())
+			}
+			if diff := deep.Equal(errs, tt.want); diff != nil {
+				t.Errorf("Validate() = %v", diff)
+			}
+		})
+	}
+}
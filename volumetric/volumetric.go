@@ -0,0 +1,347 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/reporting"
+	"github.com/hpinc/go3mf/spec"
+)
+
+const (
+	// Namespace is the canonical name of this extension.
+	Namespace = "http://schemas.3mf.io/3dmanufacturing/volumetric/2022/01"
+	// RelTypeImage3D is the relationship type of the image sheets.
+	RelTypeImage3D = "http://schemas.microsoft.com/3dmanufacturing/2013/01/3dtexture"
+	// ContentTypePNG is the only content type supported for image sheets.
+	ContentTypePNG = "image/png"
+)
+
+var DefaultExtension = go3mf.Extension{
+	Namespace:  Namespace,
+	LocalName:  "v",
+	IsRequired: false,
+}
+
+func init() {
+	spec.Register(Namespace, Spec{})
+	reporting.RegisterRules(map[error]reporting.Rule{
+		ErrImageSheetCount:        {ID: "VOL001", Name: "ImageSheetCount", Severity: reporting.SeverityError},
+		ErrMissingImageSheetPart:  {ID: "VOL002", Name: "MissingImageSheetPart", Severity: reporting.SeverityError},
+		ErrImageSheetContentType:  {ID: "VOL003", Name: "ImageSheetContentType", Severity: reporting.SeverityError},
+		ErrNonImage3D:             {ID: "VOL004", Name: "NonImage3D", Severity: reporting.SeverityError},
+		ErrNonFunction:            {ID: "VOL005", Name: "NonFunction", Severity: reporting.SeverityError},
+		ErrFunctionChannel:        {ID: "VOL006", Name: "FunctionChannel", Severity: reporting.SeverityError},
+		ErrNonVolumeData:          {ID: "VOL007", Name: "NonVolumeData", Severity: reporting.SeverityError},
+		ErrVolumeCompositeBase:    {ID: "VOL008", Name: "VolumeCompositeBase", Severity: reporting.SeverityError},
+		ErrVolumeCompositeMapping: {ID: "VOL009", Name: "VolumeCompositeMapping", Severity: reporting.SeverityError},
+		ErrDuplicatedProperty:     {ID: "VOL010", Name: "DuplicatedProperty", Severity: reporting.SeverityError},
+	})
+}
+
+type Spec struct{}
+
+var (
+	ErrImageSheetCount        = errors.New("imagestack MUST contain exactly sheetcount imagesheet elements")
+	ErrMissingImageSheetPart  = errors.New("imagesheet part MUST be added as an attachment")
+	ErrImageSheetContentType  = errors.New("imagesheet part MUST be a PNG image")
+	ErrNonImage3D             = errors.New("image3did MUST reference an image3d resource")
+	ErrNonFunction            = errors.New("functionid MUST reference a function resource")
+	ErrFunctionChannel        = errors.New("channel MUST be an output of the referenced function")
+	ErrNonVolumeData          = errors.New("volumeid MUST reference a volumedata resource")
+	ErrVolumeCompositeBase    = errors.New("basematerialid MUST reference a basematerials resource")
+	ErrVolumeCompositeMapping = errors.New("composite MUST contain one materialmapping per base material")
+	ErrDuplicatedProperty     = errors.New("property name MUST be unique within a volumedata")
+)
+
+// TextureFilter defines the filter applied to the image3d samples.
+type TextureFilter uint8
+
+// Supported texture filters.
+const (
+	TextureFilterLinear TextureFilter = iota
+	TextureFilterNearest
+)
+
+func newTextureFilter(s string) (t TextureFilter, ok bool) {
+	t, ok = map[string]TextureFilter{
+		"linear":  TextureFilterLinear,
+		"nearest": TextureFilterNearest,
+	}[s]
+	return
+}
+
+func (t TextureFilter) String() string {
+	return map[TextureFilter]string{
+		TextureFilterLinear:  "linear",
+		TextureFilterNearest: "nearest",
+	}[t]
+}
+
+// TileStyle defines how the image3d is sampled out of the unit cube.
+type TileStyle uint8
+
+// Supported tile styles.
+const (
+	TileWrap TileStyle = iota
+	TileMirror
+	TileClamp
+)
+
+func newTileStyle(s string) (t TileStyle, ok bool) {
+	t, ok = map[string]TileStyle{
+		"wrap":   TileWrap,
+		"mirror": TileMirror,
+		"clamp":  TileClamp,
+	}[s]
+	return
+}
+
+func (t TileStyle) String() string {
+	return map[TileStyle]string{
+		TileWrap:   "wrap",
+		TileMirror: "mirror",
+		TileClamp:  "clamp",
+	}[t]
+}
+
+// ImageSheet references a PNG part that contains a single
+// slice of an image stack.
+type ImageSheet struct {
+	Path string
+}
+
+// ImageStack defines a voxel grid of RowCount x ColumnCount x SheetCount
+// samples, where each sheet is stored as a PNG attachment.
+type ImageStack struct {
+	RowCount    uint32
+	ColumnCount uint32
+	SheetCount  uint32
+	Sheets      []ImageSheet
+}
+
+// Image3D defines a 3D image resource.
+type Image3D struct {
+	ID         uint32
+	Name       string
+	ImageStack ImageStack
+}
+
+// Identify returns the unique ID of the resource.
+func (r *Image3D) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (Image3D) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrImage3D}
+}
+
+// Function is implemented by the resources that can be
+// referenced by a field, such as FunctionFromImage3D.
+// Other extensions can define their own functions by implementing it.
+type Function interface {
+	go3mf.Asset
+	// HasOutput reports whether the function provides the named output channel.
+	HasOutput(channel string) bool
+}
+
+// FunctionFromImage3D defines a function that samples an Image3D.
+// It outputs the "color" vector and the "red", "green", "blue" and "alpha" scalars,
+// computed as ValueOffset + ValueScale * sample.
+type FunctionFromImage3D struct {
+	ID          uint32
+	Image3DID   uint32
+	ValueOffset float32
+	ValueScale  float32
+	Filter      TextureFilter
+	TileStyleU  TileStyle
+	TileStyleV  TileStyle
+	TileStyleW  TileStyle
+}
+
+// Identify returns the unique ID of the resource.
+func (r *FunctionFromImage3D) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (FunctionFromImage3D) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrFunctionFromImage3D}
+}
+
+// HasOutput reports whether channel is one of the function outputs.
+func (FunctionFromImage3D) HasOutput(channel string) bool {
+	switch channel {
+	case "color", "red", "green", "blue", "alpha":
+		return true
+	}
+	return false
+}
+
+// FieldReference maps the output channel of a function into the object space.
+// Transform maps the object coordinates into the function coordinates,
+// the zero value is considered the identity.
+type FieldReference struct {
+	FunctionID uint32
+	Channel    string
+	Transform  go3mf.Matrix
+}
+
+// HasTransform returns true if the transform is different than the identity.
+func (f *FieldReference) HasTransform() bool {
+	return f.Transform != go3mf.Matrix{} && f.Transform != go3mf.Identity()
+}
+
+// Boundary defines the shape of the object as the
+// level set of a scalar field, clipped by the mesh.
+type Boundary struct {
+	FieldReference
+	MinFeatureSize float32
+	MeshBBoxOnly   bool
+}
+
+// MaterialMapping defines the weight of a base material in a Composite.
+type MaterialMapping struct {
+	FieldReference
+}
+
+// Composite defines the material distribution as a mix of base materials,
+// where each mapping provides the weight of the base material with the same index.
+type Composite struct {
+	BaseMaterialID uint32
+	Mappings       []MaterialMapping
+}
+
+// Color defines the color distribution of the object.
+type Color struct {
+	FieldReference
+}
+
+// Property defines an arbitrary named property distribution of the object.
+type Property struct {
+	FieldReference
+	Name     string
+	Required bool
+}
+
+// VolumeData defines the volumetric properties of a mesh.
+type VolumeData struct {
+	ID         uint32
+	Boundary   *Boundary
+	Composite  *Composite
+	Color      *Color
+	Properties []Property
+}
+
+// Identify returns the unique ID of the resource.
+func (r *VolumeData) Identify() uint32 {
+	return r.ID
+}
+
+// XMLName returns the xml identifier of the resource.
+func (VolumeData) XMLName() xml.Name {
+	return xml.Name{Space: Namespace, Local: attrVolumeData}
+}
+
+// Scale updates the field transforms and the minimum feature size
+// so the fields keep mapping to the same physical points
+// after the object coordinates are multiplied by factor.
+func (r *VolumeData) Scale(factor float32) {
+	if r.Boundary != nil {
+		r.Boundary.scale(factor)
+		r.Boundary.MinFeatureSize *= factor
+	}
+	if r.Composite != nil {
+		for i := range r.Composite.Mappings {
+			r.Composite.Mappings[i].scale(factor)
+		}
+	}
+	if r.Color != nil {
+		r.Color.scale(factor)
+	}
+	for i := range r.Properties {
+		r.Properties[i].scale(factor)
+	}
+}
+
+func (f *FieldReference) scale(factor float32) {
+	if factor == 0 {
+		return
+	}
+	if f.Transform == (go3mf.Matrix{}) {
+		f.Transform = go3mf.Identity()
+	}
+	inv := 1 / factor
+	for _, i := range [...]int{0, 1, 2, 4, 5, 6, 8, 9, 10} {
+		f.Transform[i] *= inv
+	}
+}
+
+// fields calls fn for every field reference, using the element name
+// and its index, -1 if the element is not repeated.
+func (r *VolumeData) fields(fn func(name string, index int, f *FieldReference)) {
+	if r.Boundary != nil {
+		fn(attrBoundary, -1, &r.Boundary.FieldReference)
+	}
+	if r.Color != nil {
+		fn(attrColor, -1, &r.Color.FieldReference)
+	}
+	for i := range r.Properties {
+		fn(attrProperty, i, &r.Properties[i].FieldReference)
+	}
+}
+
+// MeshAttr defines the attributes added to Mesh.
+type MeshAttr struct {
+	VolumeID uint32
+}
+
+func (MeshAttr) Namespace() string { return Namespace }
+
+func GetMeshAttr(mesh *go3mf.Mesh) *MeshAttr {
+	for _, a := range mesh.AnyAttr {
+		if a, ok := a.(*MeshAttr); ok {
+			return a
+		}
+	}
+	return nil
+}
+
+const (
+	attrImage3D             = "image3d"
+	attrImageStack          = "imagestack"
+	attrImageSheet          = "imagesheet"
+	attrFunctionFromImage3D = "functionfromimage3d"
+	attrVolumeData          = "volumedata"
+	attrBoundary            = "boundary"
+	attrComposite           = "composite"
+	attrMaterialMapping     = "materialmapping"
+	attrColor               = "color"
+	attrProperty            = "property"
+	attrID                  = "id"
+	attrName                = "name"
+	attrPath                = "path"
+	attrRowCount            = "rowcount"
+	attrColumnCount         = "columncount"
+	attrSheetCount          = "sheetcount"
+	attrImage3DID           = "image3did"
+	attrValueOffset         = "valueoffset"
+	attrValueScale          = "valuescale"
+	attrFilter              = "filter"
+	attrTileStyleU          = "tilestyleu"
+	attrTileStyleV          = "tilestylev"
+	attrTileStyleW          = "tilestylew"
+	attrFunctionID          = "functionid"
+	attrChannel             = "channel"
+	attrTransform           = "transform"
+	attrMinFeatureSize      = "minfeaturesize"
+	attrMeshBBoxOnly        = "meshbboxonly"
+	attrBaseMaterialID      = "basematerialid"
+	attrRequired            = "required"
+	attrVolumeID            = "volumeid"
+)
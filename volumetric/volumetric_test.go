@@ -0,0 +1,112 @@
+// © Copyright 2021 HP Development Company, L.P.
+// SPDX-License Identifier: BSD-2-Clause
+
+package volumetric
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+	"github.com/hpinc/go3mf"
+	"github.com/hpinc/go3mf/spec"
+)
+
+var _ go3mf.Asset = new(Image3D)
+var _ go3mf.Asset = new(VolumeData)
+var _ Function = new(FunctionFromImage3D)
+var _ spec.Marshaler = new(Image3D)
+var _ spec.Marshaler = new(FunctionFromImage3D)
+var _ spec.Marshaler = new(VolumeData)
+var _ spec.AttrGroup = new(MeshAttr)
+var _ spec.Spec = new(Spec)
+var _ spec.Scaler = new(VolumeData)
+
+func TestIdentify(t *testing.T) {
+	tests := []struct {
+		name string
+		r    go3mf.Asset
+		want uint32
+	}{
+		{"image3d", &Image3D{ID: 1}, 1},
+		{"functionfromimage3d", &FunctionFromImage3D{ID: 2}, 2},
+		{"volumedata", &VolumeData{ID: 3}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Identify(); got != tt.want {
+				t.Errorf("Identify() = %v, want %v", got, tt.want)
+			}
+			if got := tt.r.XMLName(); got.Space != Namespace || got.Local != tt.name {
+				t.Errorf("XMLName() = %v, want %v", got, tt.name)
+			}
+		})
+	}
+}
+
+func TestFunctionFromImage3D_HasOutput(t *testing.T) {
+	tests := []struct {
+		channel string
+		want    bool
+	}{
+		{"color", true}, {"red", true}, {"green", true}, {"blue", true}, {"alpha", true},
+		{"", false}, {"Red", false}, {"distance", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.channel, func(t *testing.T) {
+			if got := new(FunctionFromImage3D).HasOutput(tt.channel); got != tt.want {
+				t.Errorf("FunctionFromImage3D.HasOutput() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextureFilter_String(t *testing.T) {
+	for _, name := range []string{"linear", "nearest"} {
+		t.Run(name, func(t *testing.T) {
+			f, ok := newTextureFilter(name)
+			if !ok || f.String() != name {
+				t.Errorf("TextureFilter.String() = %v, want %v", f.String(), name)
+			}
+		})
+	}
+}
+
+func TestTileStyle_String(t *testing.T) {
+	for _, name := range []string{"wrap", "mirror", "clamp"} {
+		t.Run(name, func(t *testing.T) {
+			s, ok := newTileStyle(name)
+			if !ok || s.String() != name {
+				t.Errorf("TileStyle.String() = %v, want %v", s.String(), name)
+			}
+		})
+	}
+}
+
+func TestVolumeData_Scale(t *testing.T) {
+	r := &VolumeData{
+		Boundary: &Boundary{MinFeatureSize: 0.5},
+		Composite: &Composite{Mappings: []MaterialMapping{
+			{FieldReference{Transform: go3mf.Matrix{2, 0, 0, 0, 0, 2, 0, 0, 0, 0, 2, 0, 1, 2, 3, 1}}},
+		}},
+		Color:      &Color{},
+		Properties: []Property{{Name: "a"}},
+	}
+	r.Scale(10)
+	scaled := go3mf.Matrix{0.1, 0, 0, 0, 0, 0.1, 0, 0, 0, 0, 0.1, 0, 0, 0, 0, 1}
+	want := &VolumeData{
+		Boundary: &Boundary{FieldReference: FieldReference{Transform: scaled}, MinFeatureSize: 5},
+		Composite: &Composite{Mappings: []MaterialMapping{
+			{FieldReference{Transform: go3mf.Matrix{0.2, 0, 0, 0, 0, 0.2, 0, 0, 0, 0, 0.2, 0, 1, 2, 3, 1}}},
+		}},
+		Color:      &Color{FieldReference{Transform: scaled}},
+		Properties: []Property{{Name: "a", FieldReference: FieldReference{Transform: scaled}}},
+	}
+	if diff := deep.Equal(r, want); diff != nil {
+		t.Errorf("VolumeData.Scale() = %v", diff)
+	}
+	// The field maps the same physical point before and after scaling.
+	p := go3mf.Point3D{1, 2, 3}
+	if got, want := r.Color.Transform.Mul3D(p.Mul(10)), go3mf.Identity().Mul3D(p); deep.Equal(got, want) != nil {
+		t.Errorf("VolumeData.Scale() maps %v, want %v", got, want)
+	}
+}